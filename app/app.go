@@ -0,0 +1,265 @@
+// application builder shared by http server, cli commands & tests.
+//
+// every feature is a module contributing migrations, repositories, usecases & routes.
+// modules are loaded in registration order, so a module may use what earlier modules put in container.
+package app
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"net"
+	"os"
+	"path"
+	"rap-c/app/handler/middleware"
+	repocontract "rap-c/app/repository/contract"
+	filesender "rap-c/app/repository/mail/file-sender"
+	smtpsender "rap-c/app/repository/mail/smtp-sender"
+	stdoutsender "rap-c/app/repository/mail/stdout-sender"
+	metricrepository "rap-c/app/repository/mysql/metric-repository"
+	"rap-c/config"
+	"rap-c/migration"
+	"rap-c/route"
+	"regexp"
+	"time"
+
+	"github.com/gorilla/sessions"
+	"github.com/labstack/echo/v4"
+	echomiddleware "github.com/labstack/echo/v4/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"gorm.io/gorm"
+)
+
+type Module interface {
+	// unique module name, used in error message
+	Name() string
+	// sql files grouped by database driver folder like base schema, nil when module has no migration.
+	// versions are shared with base schema & other modules, so they must be unique across all of them
+	Migrations() fs.FS
+	// create repositories & usecases, put them in container
+	Load(c *Container) error
+	// add api, web & monitoring routes
+	Routes(g *route.Group, c *Container)
+}
+
+// every module of the application in dependency order
+func DefaultModules() []Module {
+	return []Module{
+		NewMailModule(),
+		NewUserModule(),
+		NewUnitModule(),
+		NewIngredientModule(),
+		NewRecipeModule(),
+		NewAuditModule(),
+		NewMonitorModule(),
+	}
+}
+
+type Builder struct {
+	cfg        *config.Config
+	db         *gorm.DB
+	router     *config.Route
+	mailSender repocontract.MailSender
+	modules    []Module
+}
+
+func New(cfg *config.Config, db *gorm.DB, router *config.Route) *Builder {
+	return &Builder{cfg: cfg, db: db, router: router}
+}
+
+// replace mail transport chosen by config, e.g. to keep sent mails in memory
+func (b *Builder) WithMailSender(mailSender repocontract.MailSender) *Builder {
+	b.mailSender = mailSender
+	return b
+}
+
+func (b *Builder) Register(modules ...Module) *Builder {
+	b.modules = append(b.modules, modules...)
+	return b
+}
+
+// migrator of base schema & registered modules, no module is loaded
+func (b *Builder) Migrator() (*migration.Migrator, error) {
+	return migration.New(b.db, b.migrations()...)
+}
+
+// load every registered module
+func (b *Builder) Build() (*App, error) {
+	names := make(map[string]bool)
+	for _, module := range b.modules {
+		if names[module.Name()] {
+			return nil, fmt.Errorf("module %s registered twice", module.Name())
+		}
+		names[module.Name()] = true
+	}
+
+	mailSender := b.mailSender
+	if mailSender == nil {
+		var err error
+		mailSender, err = NewMailSender(b.cfg, b.router)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c := &Container{
+		Config:       b.cfg,
+		DB:           b.db,
+		Route:        b.router,
+		MailSender:   mailSender,
+		SessionStore: sessions.NewCookieStore([]byte(b.cfg.SessionKey())),
+		Migrations:   b.migrations(),
+	}
+	for _, module := range b.modules {
+		err := module.Load(c)
+		if err != nil {
+			return nil, fmt.Errorf("load module %s: %v", module.Name(), err)
+		}
+	}
+	return &App{Container: c, modules: b.modules}, nil
+}
+
+// migrations of module embedded under migrations/<module name>
+func moduleMigrations(files embed.FS, name string) fs.FS {
+	result, err := fs.Sub(files, path.Join("migrations", name))
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (b *Builder) migrations() []fs.FS {
+	var result []fs.FS
+	for _, module := range b.modules {
+		if files := module.Migrations(); files != nil {
+			result = append(result, files)
+		}
+	}
+	return result
+}
+
+// mail transport chosen by config
+func NewMailSender(cfg *config.Config, router *config.Route) (repocontract.MailSender, error) {
+	switch cfg.MailTransport() {
+	case config.MailTransportSMTP:
+		return smtpsender.New(cfg), nil
+	case config.MailTransportFile:
+		log.Printf("Mail written to %s, captured mail page available at %s", cfg.MailFileDir(), router.CapturedMailWebPage.Path())
+		return filesender.New(cfg, cfg.MailFileDir()), nil
+	case config.MailTransportStdout:
+		return stdoutsender.New(os.Stdout), nil
+	}
+	return nil, fmt.Errorf("unknown mail transport `%s`, use smtp, file or stdout", cfg.MailTransport())
+}
+
+// loaded modules
+type App struct {
+	Container *Container
+	modules   []Module
+}
+
+// echo with every module route, error handler & general middleware
+func (a *App) Server(renderer *config.Renderer) (*echo.Echo, error) {
+	c := a.Container
+	registry, err := a.metrics()
+	if err != nil {
+		return nil, err
+	}
+	c.Renderer = renderer
+	if registry != nil {
+		c.Gatherer = registry
+	}
+
+	// init echo
+	e := echo.New()
+
+	e.Debug = c.Config.EnableDebug()
+	ipExtractor, err := ipExtractor(c.Config.TrustedProxies())
+	if err != nil {
+		return nil, err
+	}
+	e.IPExtractor = ipExtractor
+	// custom http error handler
+	e.HTTPErrorHandler = func(err error, ctx echo.Context) {
+		reg := regexp.MustCompile("^/api")
+		if reg.MatchString(ctx.Request().RequestURI) {
+			route.APIErrorHandler(e, err, ctx)
+		} else {
+			route.WebErrorHandler(e, err, ctx)
+		}
+	}
+	// set general middleware, request id first so every log line has it
+	e.Use(middleware.RequestID())
+	if registry != nil {
+		requestMetrics, err := middleware.RequestMetrics(registry, c.Route)
+		if err != nil {
+			return nil, fmt.Errorf("register http metrics: %v", err)
+		}
+		e.Use(requestMetrics)
+	}
+	e.Use(middleware.SetLog(c.Route.LivenessEndpoint.Path(), c.Route.ReadinessEndpoint.Path()))
+	e.Use(echomiddleware.Recover())
+	e.Use(echomiddleware.TimeoutWithConfig(echomiddleware.TimeoutConfig{
+		ErrorMessage: "request timeout",
+		Timeout:      time.Second * time.Duration(c.Config.RequestTimeoutInSeconds()),
+	}))
+
+	// set module routes
+	g := route.NewGroup(e, c.Config, c.Route, c.AuthUsecase, c.SessionUsecase)
+	route.SetHomeWebPage(g)
+	for _, module := range a.modules {
+		module.Routes(g, c)
+	}
+
+	// set template renderer
+	e.Renderer = renderer
+	return e, nil
+}
+
+// client ip from the connection, or from X-Forwarded-For only when the request comes through a trusted proxy
+func ipExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, proxy := range trustedProxies {
+		_, ipRange, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("parse trusted proxy `%s`: %v", proxy, err)
+		}
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
+
+// go runtime, process, db pool, query duration & module collectors, nil when metrics disabled
+func (a *App) metrics() (*prometheus.Registry, error) {
+	c := a.Container
+	if !c.Config.EnableMetrics() {
+		return nil, nil
+	}
+
+	sqlDB, err := c.DB.DB()
+	if err != nil {
+		return nil, fmt.Errorf("get db pool for metrics: %v", err)
+	}
+	registry := prometheus.NewRegistry()
+	runtimeCollectors := []prometheus.Collector{
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		collectors.NewDBStatsCollector(sqlDB, "rapc"),
+	}
+	for _, collector := range append(runtimeCollectors, c.Collectors...) {
+		err = registry.Register(collector)
+		if err != nil {
+			return nil, fmt.Errorf("register metrics: %v", err)
+		}
+	}
+	err = metricrepository.RegisterHooks(c.DB, registry)
+	if err != nil {
+		return nil, fmt.Errorf("register db metrics: %v", err)
+	}
+	return registry, nil
+}
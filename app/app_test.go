@@ -0,0 +1,127 @@
+package app_test
+
+import (
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"rap-c/app"
+	memorysender "rap-c/app/repository/mail/memory-sender"
+	testdatabase "rap-c/app/repository/test-database"
+	"rap-c/config"
+	"rap-c/route"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// module with migrations only, routes & container are untouched
+type migrationModule struct {
+	name  string
+	files fs.FS
+}
+
+func (m *migrationModule) Name() string                            { return m.name }
+func (m *migrationModule) Migrations() fs.FS                       { return m.files }
+func (m *migrationModule) Load(c *app.Container) error             { return nil }
+func (m *migrationModule) Routes(g *route.Group, c *app.Container) {}
+
+func Test_Build(t *testing.T) {
+	db := testdatabase.Open(t)
+	cfg := config.InitTestConfig(map[string]string{"MAIL_TRANSPORT": config.MailTransportStdout, "ENABLE_METRICS": "true"})
+	router := config.InitRoute()
+
+	t.Run("default modules", func(t *testing.T) {
+		application, err := app.New(cfg, db, router).WithMailSender(memorysender.New()).Register(app.DefaultModules()...).Build()
+		assert.Nil(t, err)
+		assert.NotNil(t, application.Container.UserUsecase)
+		assert.NotNil(t, application.Container.UnitUsecase)
+		assert.NotNil(t, application.Container.AuditUsecase)
+
+		e, err := application.Server(nil)
+		assert.Nil(t, err)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(router.LivenessEndpoint.Method(), router.LivenessEndpoint.Path(), nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("dependency registered later", func(t *testing.T) {
+		_, err := app.New(cfg, db, router).Register(app.NewUnitModule(), app.NewMailModule(), app.NewUserModule()).Build()
+		assert.EqualError(t, err, "load module unit: module unit needs user module registered first")
+	})
+
+	t.Run("module registered twice", func(t *testing.T) {
+		_, err := app.New(cfg, db, router).Register(app.NewMailModule(), app.NewMailModule()).Build()
+		assert.EqualError(t, err, "module mail registered twice")
+	})
+
+	t.Run("unknown mail transport", func(t *testing.T) {
+		cfg := config.InitTestConfig(map[string]string{"MAIL_TRANSPORT": "pigeon"})
+		_, err := app.New(cfg, db, router).Register(app.NewMailModule()).Build()
+		assert.EqualError(t, err, "unknown mail transport `pigeon`, use smtp, file or stdout")
+	})
+}
+
+func Test_Migrator(t *testing.T) {
+	db := testdatabase.Open(t)
+	cfg := config.InitTestConfig(nil)
+	router := config.InitRoute()
+	driver := db.Dialector.Name()
+
+	t.Run("module migration applied after base schema", func(t *testing.T) {
+		files := fstest.MapFS{
+			driver + "/9001_create_notes.up.sql":   {Data: []byte("CREATE TABLE notes (id integer primary key);")},
+			driver + "/9001_create_notes.down.sql": {Data: []byte("DROP TABLE notes;")},
+		}
+		migrator, err := app.New(cfg, db, router).Register(&migrationModule{"note", files}).Migrator()
+		assert.Nil(t, err)
+		pending, err := migrator.Pending()
+		assert.Nil(t, err)
+		assert.Equal(t, 1, pending)
+
+		applied, err := migrator.Up()
+		assert.Nil(t, err)
+		if assert.Len(t, applied, 1) {
+			assert.Equal(t, "create_notes", applied[0].Name)
+		}
+		assert.True(t, db.Migrator().HasTable("notes"))
+	})
+
+	t.Run("default modules bring their migrations", func(t *testing.T) {
+		db := testdatabase.OpenEmpty(t)
+		migrator, err := app.New(cfg, db, router).Register(app.DefaultModules()...).Migrator()
+		assert.Nil(t, err)
+		applied, err := migrator.Up()
+		assert.Nil(t, err)
+		var names []string
+		for _, migration := range applied {
+			names = append(names, migration.Name)
+		}
+		assert.Equal(t, []string{"initial_schema", "clear_guest_password", "create_audit_logs", "replace_enum_columns", "add_unit_dimension",
+			"add_ingredient_weight", "add_sub_recipe", "create_setup_locks", "create_mail_outboxes",
+			"add_recipe_stock_movements"}, names)
+		assert.True(t, db.Migrator().HasTable("mail_outboxes"))
+
+		// base schema alone only has initial schema & changes of tables no module owns
+		base, err := app.New(cfg, testdatabase.OpenEmpty(t), router).Migrator()
+		assert.Nil(t, err)
+		pending, err := base.Pending()
+		assert.Nil(t, err)
+		assert.Equal(t, 2, pending)
+	})
+
+	t.Run("version used by base schema", func(t *testing.T) {
+		files := fstest.MapFS{
+			driver + "/0001_duplicate.up.sql":   {Data: []byte("SELECT 1;")},
+			driver + "/0001_duplicate.down.sql": {Data: []byte("SELECT 1;")},
+		}
+		_, err := app.New(cfg, db, router).Register(&migrationModule{"duplicate", files}).Migrator()
+		assert.EqualError(t, err, "migration version 1 used by initial_schema and duplicate")
+	})
+
+	t.Run("no migration of database driver", func(t *testing.T) {
+		files := fstest.MapFS{"other/0002_other.up.sql": {Data: []byte("SELECT 1;")}}
+		_, err := app.New(cfg, db, router).Register(&migrationModule{"other", files}).Migrator()
+		assert.EqualError(t, err, "no migration for database driver "+driver)
+	})
+}
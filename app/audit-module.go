@@ -0,0 +1,48 @@
+package app
+
+import (
+	"embed"
+	"io/fs"
+	"rap-c/app/handler/api"
+	"rap-c/app/handler/web"
+	auditrepository "rap-c/app/repository/mysql/audit-repository"
+	auditusecase "rap-c/app/usecase/audit-usecase"
+	"rap-c/route"
+)
+
+//go:embed migrations/audit
+var auditMigrations embed.FS
+
+// audit log of every auditable model change, needs user module
+func NewAuditModule() Module {
+	return &auditModule{}
+}
+
+type auditModule struct{}
+
+func (m *auditModule) Name() string {
+	return "audit"
+}
+
+func (m *auditModule) Migrations() fs.FS {
+	return moduleMigrations(auditMigrations, m.Name())
+}
+
+func (m *auditModule) Load(c *Container) error {
+	if c.FormatterUsecase == nil {
+		return errMissing(m, "user")
+	}
+	// write audit log on every change of auditable models
+	err := auditrepository.RegisterHooks(c.DB)
+	if err != nil {
+		return err
+	}
+	c.AuditRepository = auditrepository.New(c.DB)
+	c.AuditUsecase = auditusecase.NewUsecase(c.Config, c.AuditRepository)
+	return nil
+}
+
+func (m *auditModule) Routes(g *route.Group, c *Container) {
+	route.SetAuditAPI(g, api.NewAuditHandler(c.Config, c.Route, c.AuditUsecase, c.FormatterUsecase))
+	route.SetAuditWebPage(g, web.NewAuditPage(c.Config, c.Route, c.AuditUsecase, c.FormatterUsecase))
+}
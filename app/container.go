@@ -0,0 +1,60 @@
+package app
+
+import (
+	"fmt"
+	"io/fs"
+	repocontract "rap-c/app/repository/contract"
+	"rap-c/app/usecase/contract"
+	"rap-c/config"
+
+	"github.com/gorilla/sessions"
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+)
+
+// shared dependencies, filled by modules in registration order.
+// module reads what earlier modules loaded & sets what it provides
+type Container struct {
+	Config       *config.Config
+	DB           *gorm.DB
+	Route        *config.Route
+	MailSender   repocontract.MailSender
+	SessionStore sessions.Store
+	// migration sources of every registered module
+	Migrations []fs.FS
+	// business collectors added to prometheus registry when metrics enabled
+	Collectors []prometheus.Collector
+	// set by server before routes are added, gatherer is nil when metrics disabled
+	Renderer *config.Renderer
+	Gatherer prometheus.Gatherer
+
+	// repositories
+	UserRepository         repocontract.UserRepository
+	AuthRepository         repocontract.AuthRepository
+	InvitationRepository   repocontract.InvitationRepository
+	UnitRepository         repocontract.UnitRepository
+	IngredientRepository   repocontract.IngredientRepository
+	RecipeRepository       repocontract.RecipeRepository
+	OutboxRepository       repocontract.OutboxRepository
+	CapturedMailRepository repocontract.CapturedMailRepository
+	AuditRepository        repocontract.AuditRepository
+	MetricRepository       repocontract.MetricRepository
+	HealthRepository       repocontract.HealthRepository
+
+	// usecases
+	FormatterUsecase  contract.FormatterUsecase
+	MailUsecase       contract.MailUsecase
+	AuthUsecase       contract.AuthUsecase
+	UserUsecase       contract.UserUsecase
+	SessionUsecase    contract.SessionUsecase
+	UnitUsecase       contract.UnitUsecase
+	IngredientUsecase contract.IngredientUsecase
+	RecipeUsecase     contract.RecipeUsecase
+	AuditUsecase      contract.AuditUsecase
+	MetricUsecase     contract.MetricUsecase
+}
+
+// error of module loaded before its dependency
+func errMissing(module Module, dependency string) error {
+	return fmt.Errorf("module %s needs %s module registered first", module.Name(), dependency)
+}
@@ -0,0 +1,41 @@
+package entity
+
+import "context"
+
+type contextKey string
+
+const (
+	requestIDContextKey contextKey = "requestID"
+	userIDContextKey    contextKey = "userID"
+	ipAddressContextKey contextKey = "ipAddress"
+)
+
+// store request id in request context, read by logger & api error handler
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, requestID)
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	result, _ := ctx.Value(requestIDContextKey).(string)
+	return result
+}
+
+// store authenticated user id in request context, read by logger
+func ContextWithUserID(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, userIDContextKey, userID)
+}
+
+func UserIDFromContext(ctx context.Context) (int, bool) {
+	result, ok := ctx.Value(userIDContextKey).(int)
+	return result, ok
+}
+
+// store client ip address in request context, read by audit log
+func ContextWithIPAddress(ctx context.Context, ipAddress string) context.Context {
+	return context.WithValue(ctx, ipAddressContextKey, ipAddress)
+}
+
+func IPAddressFromContext(ctx context.Context) string {
+	result, _ := ctx.Value(ipAddressContextKey).(string)
+	return result
+}
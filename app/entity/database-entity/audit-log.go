@@ -0,0 +1,31 @@
+package databaseentity
+
+import "time"
+
+const (
+	AuditActionCreate string = "create"
+	AuditActionUpdate string = "update"
+	AuditActionDelete string = "delete"
+)
+
+// model written into audit log on create, update & delete,
+// fields tagged `audit:"-"` are ignored and fields tagged `audit:"secret"` are masked
+type Auditable interface {
+	// entity type & human readable serial, e.g. username or unit name
+	AuditEntity() (entityType string, serial string)
+}
+
+// table audit_logs model, filled by gorm hooks in audit repository
+type AuditLog struct {
+	ID         int       `gorm:"primaryKey" json:"id"`
+	ActorID    int       `gorm:"not null;default:0;index:idx_audit_logs_actor" json:"-"`
+	Action     string    `gorm:"size:10;not null" json:"action"`
+	EntityType string    `gorm:"size:30;not null;index:idx_audit_logs_entity" json:"entityType"`
+	EntityID   int       `gorm:"not null;index:idx_audit_logs_entity" json:"entityId"`
+	Serial     string    `gorm:"size:100;not null;default:''" json:"serial"`
+	BeforeData string    `gorm:"type:text" json:"-"` // changed fields before action as json, empty on create
+	AfterData  string    `gorm:"type:text" json:"-"` // changed fields after action as json, empty on delete
+	IPAddress  string    `gorm:"size:45;not null;default:''" json:"ipAddress"`
+	RequestID  string    `gorm:"size:64;not null;default:'';index:idx_audit_logs_request" json:"requestId"`
+	CreatedAt  time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null;index:idx_audit_logs_created_at" json:"createdAt"`
+}
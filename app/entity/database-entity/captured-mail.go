@@ -0,0 +1,14 @@
+package databaseentity
+
+import "time"
+
+// mail written as .eml file by file mail transport, not a database table
+type CapturedMail struct {
+	Name     string    `json:"name"`
+	From     string    `json:"from"`
+	To       string    `json:"to"`
+	Subject  string    `json:"subject"`
+	Date     time.Time `json:"date"`
+	TextBody string    `json:"-"`
+	HTMLBody string    `json:"-"`
+}
@@ -17,3 +17,7 @@ type IngredientConvertionUnit struct {
 	UpdatedAt     time.Time   `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null" json:"updatedAt"`
 	UpdatedBy     int         `gorm:"column:updated_by;not null;default:0" json:"-"`
 }
+
+func (e *IngredientConvertionUnit) AuditEntity() (string, string) {
+	return "ingredient_conversion", e.Serial
+}
@@ -2,7 +2,8 @@ package databaseentity
 
 import "time"
 
-// table ingredients model
+// table ingredients model.
+// density in gram per milliliter & piece weight in gram bridge volume & count units into mass, 0 when unknown
 type Ingredient struct {
 	ID           int       `gorm:"primaryKey" json:"-"`
 	Serial       string    `gorm:"unique;size:11;not null" json:"serial"`
@@ -11,8 +12,14 @@ type Ingredient struct {
 	Unit         *Unit     `gorm:"foreignKey:unit_id" json:"unit"`
 	PricePerUnit float32   `gorm:"not null;type:decimal(10,2);default:0" json:"pricePerUnit"`
 	Stock        float32   `gorm:"not null;type:decimal(10,2);default:0" json:"stock"`
+	Density      float64   `gorm:"not null;type:decimal(10,4);default:0" json:"density"`
+	PieceWeight  float64   `gorm:"not null;type:decimal(10,2);default:0" json:"pieceWeight"`
 	CreatedAt    time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null" json:"createdAt"`
 	CreatedBy    int       `gorm:"column:created_by;not null;default:0" json:"-"`
 	UpdatedAt    time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null" json:"updatedAt"`
 	UpdatedBy    int       `gorm:"column:updated_by;not null;default:0" json:"-"`
 }
+
+func (e *Ingredient) AuditEntity() (string, string) {
+	return "ingredient", e.Serial
+}
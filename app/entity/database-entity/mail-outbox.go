@@ -0,0 +1,52 @@
+package databaseentity
+
+import "time"
+
+const (
+	MailStatusPending string = "pending"
+	MailStatusSent    string = "sent"
+	MailStatusDead    string = "dead"
+)
+
+// table mail_outboxes model
+type MailOutbox struct {
+	ID            int        `gorm:"primaryKey" json:"id"`
+	Recipient     string     `gorm:"size:100;not null" json:"recipient"`
+	Subject       string     `gorm:"size:255;not null" json:"subject"`
+	TextBody      string     `gorm:"type:text;not null" json:"-"`
+	HTMLBody      string     `gorm:"type:mediumtext;not null" json:"-"`
+	Status        string     `gorm:"size:10;not null;default:'pending';index:idx_mail_outboxes_status_next_attempt" json:"status"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null;index:idx_mail_outboxes_status_next_attempt" json:"nextAttemptAt"`
+	LastError     string     `gorm:"type:text" json:"lastError"`
+	SentAt        *time.Time `gorm:"type:timestamp;null" json:"sentAt"`
+	CreatedAt     time.Time  `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null" json:"createdAt"`
+	UpdatedAt     time.Time  `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null" json:"updatedAt"`
+}
+
+// record failed attempt, retry with exponential backoff until max attempts reached then mark as dead letter
+func (e *MailOutbox) Failed(err error, now time.Time, maxAttempts int, backoff time.Duration) {
+	e.Attempts++
+	e.LastError = err.Error()
+	if e.Attempts >= maxAttempts {
+		e.Status = MailStatusDead
+		return
+	}
+	e.Status = MailStatusPending
+	e.NextAttemptAt = now.Add(backoff * time.Duration(1<<(e.Attempts-1)))
+}
+
+// record successful attempt
+func (e *MailOutbox) Sent(now time.Time) {
+	e.Attempts++
+	e.Status = MailStatusSent
+	e.LastError = ""
+	e.SentAt = &now
+}
+
+// put dead letter back to queue
+func (e *MailOutbox) Requeue(now time.Time) {
+	e.Status = MailStatusPending
+	e.Attempts = 0
+	e.NextAttemptAt = now
+}
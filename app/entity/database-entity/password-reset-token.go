@@ -2,10 +2,21 @@ package databaseentity
 
 import "time"
 
+// table password_reset_tokens model
 type PasswordResetToken struct {
-	ID        int       `gorm:"primaryKey" json:"-"`
-	Email     string    `gorm:"unique;size:255;not null" json:"email"`
-	Token     string    `gorm:"size:255;null" json:"token"`
-	CreatedAt time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null" json:"createdAt"`
-	UpdatedAt time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null" json:"updatedAt"`
+	ID        int        `gorm:"primaryKey" json:"-"`
+	Email     string     `gorm:"size:255;not null;index" json:"email"`
+	Token     string     `gorm:"-" json:"-"` // plain token, only filled when the token is generated
+	TokenHash string     `gorm:"size:64;not null;index" json:"-"`
+	IPAddress string     `gorm:"size:45;not null;index" json:"-"`
+	ExpiredAt time.Time  `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null" json:"expiredAt"`
+	UsedAt    *time.Time `gorm:"type:timestamp;null" json:"usedAt"`
+	RevokedAt *time.Time `gorm:"type:timestamp;null" json:"revokedAt"`
+	CreatedAt time.Time  `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null;index" json:"createdAt"`
+	UpdatedAt time.Time  `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null" json:"updatedAt"`
+}
+
+// token can only be used once, before expired and before a newer token is issued
+func (e *PasswordResetToken) IsValid(now time.Time) bool {
+	return e.UsedAt == nil && e.RevokedAt == nil && now.Before(e.ExpiredAt)
 }
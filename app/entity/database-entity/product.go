@@ -2,6 +2,12 @@ package databaseentity
 
 import "time"
 
+const (
+	ProductStatusInProduction  string = "in production"
+	ProductStatusInSales       string = "in sales"
+	ProductStatusSentToJournal string = "sent to journal"
+)
+
 // table products model
 type Product struct {
 	ID             int       `gorm:"primaryKey" json:"-"`
@@ -13,7 +19,7 @@ type Product struct {
 	SoldQuantity   int       `gorm:"not null;default:0" json:"soldQuantity"`
 	ProfitExpected float32   `gorm:"not null;type:decimal(10,2);default:0" json:"profitExpected"`
 	ProfitGet      float32   `gorm:"not null;type:decimal(10,2);default:0" json:"profitGet"`
-	Status         string    `gorm:"type:enum('in production', 'in sales', 'sent to journal');not null;index" json:"status"`
+	Status         string    `gorm:"size:20;not null;index" json:"status"`
 	CreatedAt      time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null" json:"createdAt"`
 	CreatedBy      int       `gorm:"column:created_by;not null;default:0" json:"-"`
 	UpdatedAt      time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null" json:"updatedAt"`
@@ -2,14 +2,16 @@ package databaseentity
 
 import "time"
 
-// table recipe_ingredients model
+// table recipe_ingredients model, line uses either ingredient or sub recipe
 type RecipeIngredient struct {
 	ID           int         `gorm:"primaryKey" json:"-"`
 	Serial       string      `gorm:"unique;size:11;not null" json:"serial"`
 	RecipeID     int         `gorm:"not null" json:"-"`
 	Recipe       *Recipe     `gorm:"foreignKey:recipe_id" json:"-"`
-	IngredientID int         `gorm:"not null" json:"-"`
+	IngredientID *int        `gorm:"null" json:"-"`
 	Ingredient   *Ingredient `gorm:"foreignKey:ingredient_id" json:"ingredient"`
+	SubRecipeID  *int        `gorm:"null" json:"-"`
+	SubRecipe    *Recipe     `gorm:"foreignKey:sub_recipe_id" json:"subRecipe"`
 	UnitID       int         `gorm:"not null" json:"-"`
 	Unit         *Unit       `gorm:"foreignKey:unit_id" json:"unit"`
 	Quantity     float32     `gorm:"not null;type:decimal(10,2);default:0" json:"quantity"`
@@ -18,3 +20,11 @@ type RecipeIngredient struct {
 	UpdatedAt    time.Time   `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null" json:"updatedAt"`
 	UpdatedBy    int         `gorm:"column:updated_by;not null;default:0" json:"-"`
 }
+
+func (e *RecipeIngredient) AuditEntity() (string, string) {
+	return "recipe_ingredient", e.Serial
+}
+
+func (e *RecipeIngredient) IsSubRecipe() bool {
+	return e.SubRecipeID != nil
+}
@@ -2,12 +2,16 @@ package databaseentity
 
 import "time"
 
-// table recipes model
+// table recipes model.
+// quantity & stock are counted in unit when it is set, otherwise in portions
 type Recipe struct {
 	ID                  int       `gorm:"primaryKey" json:"-"`
 	Serial              string    `gorm:"unique;size:11;not null" json:"serial"`
 	Name                string    `gorm:"size:100;not null" json:"name"`
 	Quantity            int       `gorm:"not null;default:0" json:"quantity"`
+	UnitID              *int      `gorm:"null" json:"-"`
+	Unit                *Unit     `gorm:"foreignKey:unit_id" json:"unit"`
+	Stock               float32   `gorm:"not null;type:decimal(10,2);default:0" json:"stock"`
 	Description         string    `gorm:"type:text;null" json:"description"`
 	LaborDescription    string    `gorm:"type:text;null" json:"laborDescription"`
 	OverheadDescription string    `gorm:"type:text;null" json:"overheadDescription"`
@@ -22,7 +26,140 @@ type Recipe struct {
 	UpdatedBy           int       `gorm:"column:updated_by;not null;default:0" json:"-"`
 }
 
+func (e *Recipe) AuditEntity() (string, string) {
+	return "recipe", e.Serial
+}
+
 func (e *Recipe) SetSellingPrice() {
 	hpp := e.RawMaterialCosts + e.LaborCosts + e.OverheadCosts
 	e.SellingPrice = hpp + (float32(e.ExpectedProfit) / float32(100) * hpp)
 }
+
+// recipe cost from current ingredient price, not a table
+type RecipeCosting struct {
+	Recipe           *Recipe
+	Lines            []*RecipeCostingLine
+	RawMaterialCosts float64
+}
+
+// raw material, labor & overhead costs of one batch
+func (e *RecipeCosting) HPP() float64 {
+	return e.RawMaterialCosts + float64(e.Recipe.LaborCosts) + float64(e.Recipe.OverheadCosts)
+}
+
+// hpp of one portion, 0 when recipe has no portion
+func (e *RecipeCosting) HPPPerPortion() float64 {
+	if e.Recipe.Quantity <= 0 {
+		return 0
+	}
+	return e.HPP() / float64(e.Recipe.Quantity)
+}
+
+const (
+	// line priced by ingredient price
+	RecipeLineCostTypeRawMaterial string = "rawMaterial"
+	// sub recipe line priced by hpp per portion of sub recipe
+	RecipeLineCostTypeIntermediateProduct string = "intermediateProduct"
+)
+
+// recipe line converted into ingredient unit, cost is 0 when conversion unit of line is set to skip calculate.
+// sub recipe line is converted into yield unit of sub recipe & costs hpp per portion of sub recipe
+type RecipeCostingLine struct {
+	RecipeIngredient *RecipeIngredient
+	Conversion       *UnitConversion
+	SubRecipe        *RecipeCosting
+	SkipCalculate    bool
+	Cost             float64
+}
+
+func (e *RecipeCostingLine) CostType() string {
+	if e.SubRecipe != nil {
+		return RecipeLineCostTypeIntermediateProduct
+	}
+	return RecipeLineCostTypeRawMaterial
+}
+
+// raw material, labor & overhead making up cost of line. sub recipe line is an intermediate product, its whole cost
+// counts as raw material of the recipe using it, so labor & overhead of sub recipe are only split here to show them
+func (e *RecipeCostingLine) CostShares() (rawMaterial float64, labor float64, overhead float64) {
+	if e.SubRecipe == nil || e.Conversion == nil || e.SubRecipe.Recipe.Quantity <= 0 {
+		return e.Cost, 0, 0
+	}
+	recipe := e.SubRecipe.Recipe
+	portions := e.Conversion.Result / float64(recipe.Quantity)
+	return portions * e.SubRecipe.RawMaterialCosts, portions * float64(recipe.LaborCosts), portions * float64(recipe.OverheadCosts)
+}
+
+// recipe costing scaled into requested portions, not a table.
+// labor & overhead costs scale with raw material, so hpp per portion stays the same
+type RecipeScale struct {
+	Costing  *RecipeCosting
+	Portions int
+	Factor   float64
+	Lines    []*RecipeScaleLine
+	Warnings []*RecipeStockWarning
+}
+
+func (e *RecipeScale) RawMaterialCosts() float64 {
+	return e.Costing.RawMaterialCosts * e.Factor
+}
+
+func (e *RecipeScale) LaborCosts() float64 {
+	return float64(e.Costing.Recipe.LaborCosts) * e.Factor
+}
+
+func (e *RecipeScale) OverheadCosts() float64 {
+	return float64(e.Costing.Recipe.OverheadCosts) * e.Factor
+}
+
+func (e *RecipeScale) HPP() float64 {
+	return e.Costing.HPP() * e.Factor
+}
+
+// scaled recipe line, purchase converts ingredient quantity into the most readable unit ingredient is bought in
+type RecipeScaleLine struct {
+	CostingLine        *RecipeCostingLine
+	Quantity           float64
+	IngredientQuantity float64
+	Purchase           *UnitConversion
+	Cost               float64
+}
+
+// ingredient or sub recipe whose required quantity of every line exceeds its stock, both in ingredient or yield unit
+type RecipeStockWarning struct {
+	Ingredient *Ingredient
+	SubRecipe  *Recipe
+	Required   float64
+}
+
+func (e *RecipeStockWarning) Stock() float64 {
+	if e.SubRecipe != nil {
+		return float64(e.SubRecipe.Stock)
+	}
+	return float64(e.Ingredient.Stock)
+}
+
+func (e *RecipeStockWarning) Shortage() float64 {
+	return e.Required - e.Stock()
+}
+
+// stock taken by producing recipe, not a table.
+// sub recipe in stock is consumed first, the rest is produced from its own lines
+type RecipeProduction struct {
+	Recipe      *Recipe
+	Quantity    float64
+	Ingredients []*IngredientConsumption
+	SubRecipes  []*SubRecipeConsumption
+}
+
+// quantity in ingredient unit
+type IngredientConsumption struct {
+	Ingredient *Ingredient
+	Quantity   float64
+}
+
+// quantity in yield unit of sub recipe
+type SubRecipeConsumption struct {
+	Recipe   *Recipe
+	Quantity float64
+}
@@ -0,0 +1,12 @@
+package databaseentity
+
+import "time"
+
+// the only setup lock row, taken once by the owner created from setup wizard
+const SetupLockID int = 1
+
+// table setup_locks model
+type SetupLock struct {
+	ID        int       `gorm:"primaryKey;autoIncrement:false" json:"-"`
+	CreatedAt time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null" json:"createdAt"`
+}
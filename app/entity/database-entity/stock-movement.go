@@ -2,13 +2,23 @@ package databaseentity
 
 import "time"
 
-// table stock_movements model
+const (
+	StockMovementTypeIn  string = "in"
+	StockMovementTypeOut string = "out"
+
+	// description of movements written by producing recipe, filled with recipe serial
+	StockMovementProduceDescription string = "produce recipe %s"
+)
+
+// table stock_movements model, movement tracks stock of either an ingredient or a recipe
 type StockMovement struct {
 	ID           int         `gorm:"primaryKey" json:"-"`
-	IngredientID int         `gorm:"not null" json:"-"`
+	IngredientID *int        `gorm:"null" json:"-"`
 	Ingredient   *Ingredient `gorm:"foreignKey:ingredient_id" json:"ingredient"`
-	MovementType string      `gorm:"type:enum('in','out');not null;index" json:"movementType"`
-	Quantity     int         `gorm:"not null;default:0" json:"quantity"`
+	RecipeID     *int        `gorm:"null" json:"-"`
+	Recipe       *Recipe     `gorm:"foreignKey:recipe_id" json:"recipe"`
+	MovementType string      `gorm:"size:20;not null;index" json:"movementType"`
+	Quantity     float64     `gorm:"not null;type:decimal(10,2);default:0" json:"quantity"`
 	Description  string      `gorm:"size:100;null" json:"description"`
 	CreatedAt    time.Time   `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null" json:"createdAt"`
 	CreatedBy    int         `gorm:"column:created_by;not null;default:0" json:"-"`
@@ -2,10 +2,65 @@ package databaseentity
 
 import "time"
 
-// table units model
+const (
+	UnitDimensionMass   string = "mass"
+	UnitDimensionVolume string = "volume"
+	UnitDimensionCount  string = "count"
+)
+
+// how a quantity is converted into other unit
+const (
+	// both are the same unit
+	UnitBridgeSame string = "same"
+	// both are standard units of the same dimension
+	UnitBridgeDimension string = "dimension"
+	// through conversion units of ingredient
+	UnitBridgeIngredient string = "ingredient"
+	// volume through density of ingredient
+	UnitBridgeDensity string = "density"
+	// count through piece weight of ingredient
+	UnitBridgePieceWeight string = "pieceWeight"
+)
+
+// table units model.
+// dimension is mass, volume or count, empty for custom unit.
+// factor is size in base unit of dimension: gram, milliliter or piece
 type Unit struct {
 	ID        int       `gorm:"primaryKey" json:"-"`
 	Name      string    `gorm:"unique;size:30;not null" json:"name"`
+	Dimension string    `gorm:"size:10;not null;default:''" json:"dimension"`
+	Factor    float64   `gorm:"not null;type:decimal(18,6);default:0" json:"factor"`
 	CreatedAt time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null" json:"createdAt"`
 	CreatedBy int       `gorm:"column:created_by;not null;default:0" json:"-"`
 }
+
+func (e *Unit) AuditEntity() (string, string) {
+	return "unit", e.Name
+}
+
+// standard unit converts into every unit of the same dimension
+func (e *Unit) IsStandard() bool {
+	return e.Dimension != "" && e.Factor > 0
+}
+
+func (e *Unit) SameDimension(other *Unit) bool {
+	return e.IsStandard() && other.IsStandard() && e.Dimension == other.Dimension
+}
+
+// rows referencing a unit, not a table
+type UnitUsage struct {
+	Ingredients       []*Ingredient
+	Conversions       []*IngredientConvertionUnit
+	RecipeIngredients []*RecipeIngredient
+	Recipes           []*Recipe
+}
+
+// quantity converted into other unit, not a table.
+// bridge is same, dimension or every ingredient bridge used joined by +, e.g. ingredient+density
+type UnitConversion struct {
+	Quantity float64
+	From     *Unit
+	Result   float64
+	To       *Unit
+	Bridge   string
+}
@@ -0,0 +1,23 @@
+package databaseentity
+
+import "time"
+
+// table user_invitations model
+type UserInvitation struct {
+	ID         int        `gorm:"primaryKey" json:"-"`
+	UserID     int        `gorm:"not null;index" json:"-"`
+	Token      string     `gorm:"-" json:"-"` // plain token, only filled when the invitation is issued
+	TokenHash  string     `gorm:"size:64;not null;index" json:"-"`
+	ExpiredAt  time.Time  `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null" json:"expiredAt"`
+	AcceptedAt *time.Time `gorm:"type:timestamp;null" json:"acceptedAt"`
+	RevokedAt  *time.Time `gorm:"type:timestamp;null" json:"revokedAt"`
+	CreatedAt  time.Time  `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null" json:"createdAt"`
+	CreatedBy  int        `gorm:"column:created_by;not null;default:0" json:"-"`
+	UpdatedAt  time.Time  `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null" json:"updatedAt"`
+	UpdatedBy  int        `gorm:"column:updated_by;not null;default:0" json:"-"`
+}
+
+// invitation can only be accepted once, before expired and before it is revoked or reissued
+func (e *UserInvitation) IsValid(now time.Time) bool {
+	return e.AcceptedAt == nil && e.RevokedAt == nil && now.Before(e.ExpiredAt)
+}
@@ -13,17 +13,39 @@ const (
 
 // table users model
 type User struct {
-	ID                 int       `gorm:"primaryKey" json:"-"`
-	Username           string    `gorm:"unique;size:30;not null" json:"username"`
-	FullName           string    `gorm:"size:100;not null" json:"fullName"`
-	Email              string    `gorm:"unique;size:100;not null" json:"email"`
-	Password           string    `gorm:"size:255;not null" json:"-"`
-	PasswordMustChange bool      `gorm:"not null;default:0" json:"passwordMustChange"`
-	Disabled           bool      `gorm:"not null;default:0" json:"disabled"`
-	IsGuest            bool      `gorm:"not null;default:0" json:"isGuest"`
-	Token              string    `gorm:"not null" json:"-"`
-	CreatedAt          time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null" json:"createdAt"`
-	CreatedBy          int       `gorm:"column:created_by;not null;default:0" json:"-"`
-	UpdatedAt          time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null" json:"updatedAt"`
-	UpdatedBy          int       `gorm:"column:updated_by;not null;default:0" json:"-"`
+	ID                 int        `gorm:"primaryKey" json:"-"`
+	Username           string     `gorm:"unique;size:30;not null" json:"username"`
+	FullName           string     `gorm:"size:100;not null" json:"fullName"`
+	Email              string     `gorm:"unique;size:100;not null" json:"email"`
+	PendingEmail       string     `gorm:"size:100;not null;default:''" json:"pendingEmail"` // new email waiting for verification
+	EmailVerifiedAt    *time.Time `gorm:"type:timestamp;null" json:"emailVerifiedAt"`
+	Password           string     `gorm:"size:255;not null" json:"-" audit:"secret"`
+	PasswordMustChange bool       `gorm:"not null;default:0" json:"passwordMustChange"`
+	Disabled           bool       `gorm:"not null;default:0" json:"disabled"`
+	IsGuest            bool       `gorm:"not null;default:0" json:"isGuest"`
+	Token              string     `gorm:"not null" json:"-" audit:"-"`
+	CreatedAt          time.Time  `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null" json:"createdAt"`
+	CreatedBy          int        `gorm:"column:created_by;not null;default:0" json:"-"`
+	UpdatedAt          time.Time  `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null" json:"updatedAt"`
+	UpdatedBy          int        `gorm:"column:updated_by;not null;default:0" json:"-"`
+}
+
+func (e *User) AuditEntity() (string, string) {
+	return "user", e.Username
+}
+
+// email address waiting for verification, empty when there is nothing to verify
+func (e *User) EmailToVerify() string {
+	if e.PendingEmail != "" {
+		return e.PendingEmail
+	}
+	if e.EmailVerifiedAt == nil {
+		return e.Email
+	}
+	return ""
+}
+
+// invited user has no password until the invitation is accepted
+func (e *User) IsInvitationPending() bool {
+	return e.Password == ""
 }
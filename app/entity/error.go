@@ -16,6 +16,21 @@ const (
 	ActivatingActiveUserMessage               string = "try activating active users"
 	CreateUnitNameDuplicate                   int    = 400006
 	CreateUnitNameDuplicateMessage            string = "duplicate unit name, `%s` is already in use"
+	InvitationAlreadyAccepted                 int    = 400007
+	InvitationAlreadyAcceptedMessage          string = "user has already accepted the invitation"
+	ResendMailNotDeadLetter                   int    = 400008
+	ResendMailNotDeadLetterMessage            string = "only dead letter mail can be resent"
+	MailTemplateRenderFailed                  int    = 400009
+	MergeUnitIntoItself                       int    = 400010
+	MergeUnitIntoItselfMessage                string = "cannot merge unit into itself"
+	UnitNotConvertible                        int    = 400011
+	UnitNotConvertibleMessage                 string = "cannot convert `%s` into `%s`"
+	RecipeIngredientNotConvertible            int    = 400012
+	RecipeIngredientNotConvertibleMessage     string = "cannot convert `%s` of ingredient `%s` into `%s`"
+	ScaleRecipeWithoutPortion                 int    = 400013
+	ScaleRecipeWithoutPortionMessage          string = "recipe `%s` has no portion to scale from"
+	SubRecipeWithoutYield                     int    = 400014
+	SubRecipeWithoutYieldMessage              string = "sub recipe `%s` has no yield unit or quantity"
 	ValidatorBadRequest                       int    = 400999
 	ValidatorBadRequestMessage                string = "bad request, validator failed"
 
@@ -40,6 +55,8 @@ const (
 	MustChangePasswordForbiddenMessage string = "the password must be changed"
 	DeleteUsedUnitForbidden            int    = 403004
 	DeleteUsedUnitForbiddenMessage     string = "cannot delete used units"
+	SetupAlreadyDoneForbidden          int    = 403005
+	SetupAlreadyDoneForbiddenMessage   string = "setup is already done"
 
 	// not found
 	ResetPasswordRequestNotFound        int    = 404001
@@ -48,10 +65,43 @@ const (
 	SearchSingleUserNotFOundMessage     string = "user with `%s` = `%s` not found"
 	UnitNameNotFound                    int    = 404003
 	UnitNameNotFoundMessage             string = "unit measurement `%s` not found"
+	EmailVerificationNotFound           int    = 404004
+	EmailVerificationNotFoundMessage    string = "email verification not found or expired"
+	InvitationNotFound                  int    = 404005
+	InvitationNotFoundMessage           string = "invitation not found or expired"
+	MailNotFound                        int    = 404006
+	MailNotFoundMessage                 string = "mail with id `%d` not found"
+	CapturedMailNotFound                int    = 404007
+	CapturedMailNotFoundMessage         string = "captured mail `%s` not found"
+	IngredientNotFound                  int    = 404008
+	IngredientNotFoundMessage           string = "ingredient `%s` not found"
+	RecipeNotFound                      int    = 404009
+	RecipeNotFoundMessage               string = "recipe `%s` not found"
+	RecipeIngredientNotFound            int    = 404010
+	RecipeIngredientNotFoundMessage     string = "recipe line `%s` not found"
 
 	// conflict
-	UpdateUserNoChange        int    = 409001
-	UpdateUserNoChangeMessage string = "no change found"
+	UpdateUserNoChange                    int    = 409001
+	UpdateUserNoChangeMessage             string = "no change found"
+	EmailAlreadyVerified                  int    = 409002
+	EmailAlreadyVerifiedMessage           string = "email is already verified"
+	UpdateUnitNoChange                    int    = 409003
+	UpdateUnitNoChangeMessage             string = "no change found"
+	MergeUnitConflict                     int    = 409004
+	MergeUnitConflictMessage              string = "merging makes ingredient `%s` convert into its own or duplicate unit"
+	MergeUnitSizeConflict                 int    = 409005
+	MergeUnitSizeConflictMessage          string = "cannot merge `%s` into `%s`, standard units have different size"
+	UpdateIngredientNoChange              int    = 409006
+	UpdateIngredientNoChangeMessage       string = "no change found"
+	SubRecipeCycle                        int    = 409007
+	SubRecipeCycleMessage                 string = "recipe `%s` is used as its own sub recipe"
+	ProduceStockNotEnough                 int    = 409008
+	ProduceStockNotEnoughMessage          string = "stock of ingredient `%s` is not enough, %g %s more is needed"
+	ProduceSubRecipeStockNotEnoughMessage string = "stock of sub recipe `%s` is not enough, %g %s more is needed"
+
+	// too many requests
+	ResetPasswordRequestTooMany        int    = 429001
+	ResetPasswordRequestTooManyMessage string = "too many reset password requests, please try again later"
 
 	// internal service error
 	// auth repository
@@ -60,6 +110,8 @@ const (
 	AuthRepoValidateResetTokenError        int = 5000103
 	AuthRepoDoResetPasswordError           int = 5000104
 	AuthRepoDoRenewPasswordError           int = 5000105
+	AuthRepoGetTotalResetRequestsError     int = 5000106
+	AuthRepoGetGuestUserError              int = 5000107
 	// user repository
 	UserRepoCreateError                 int = 5000201
 	UserRepoUpdateError                 int = 5000202
@@ -67,21 +119,76 @@ const (
 	UserRepoGetTotalUsersByRequestError int = 5000204
 	UserRepoGetUsersByRequestError      int = 5000205
 	UserRepoMapUserUsernameError        int = 5000206
+	UserRepoGetTotalNonGuestUsersError  int = 5000207
+	UserRepoCreateOwnerError            int = 5000208
 	// unit repository
-	UnitRepoCreateError                 int = 5000301
-	UnitRepoGetUnitByNameError          int = 5000302
-	UnitRepoGetTotalUnitsByRequestError int = 5000303
-	UnitRepoGetUnitsByRequestError      int = 5000304
-	UnitRepoDeleteError                 int = 5000305
+	UnitRepoCreateError                   int = 5000301
+	UnitRepoGetUnitByNameError            int = 5000302
+	UnitRepoGetTotalUnitsByRequestError   int = 5000303
+	UnitRepoGetUnitsByRequestError        int = 5000304
+	UnitRepoDeleteError                   int = 5000305
+	UnitRepoUpdateError                   int = 5000306
+	UnitRepoGetUnitUsageError             int = 5000307
+	UnitRepoMergeError                    int = 5000308
+	UnitRepoGetIngredientConversionsError int = 5000309
+	UnitRepoGetUnitsByDimensionError      int = 5000310
+	// invitation repository
+	InvitationRepoCreateError     int = 5000401
+	InvitationRepoGetByTokenError int = 5000402
+	InvitationRepoAcceptError     int = 5000403
+	InvitationRepoRevokeError     int = 5000404
+	// outbox repository
+	OutboxRepoCreateError              int = 5000501
+	OutboxRepoClaimPendingMailsError   int = 5000502
+	OutboxRepoUpdateError              int = 5000503
+	OutboxRepoGetMailByIDError         int = 5000504
+	OutboxRepoGetTotalDeadLettersError int = 5000505
+	OutboxRepoGetDeadLettersError      int = 5000506
+	// captured mail repository
+	CaptureRepoGetCapturedMailsError int = 5000601
+	CaptureRepoGetCapturedMailError  int = 5000602
+	// audit repository
+	AuditRepoGetTotalAuditLogsByRequestError int = 5000701
+	AuditRepoGetAuditLogsByRequestError      int = 5000702
+	// metric repository
+	MetricRepoGetTotalActiveUsersError         int = 5000801
+	MetricRepoGetTotalLowStockIngredientsError int = 5000802
+	MetricRepoGetTotalPendingMailsError        int = 5000803
+	// health repository
+	HealthRepoPingError                      int = 5000901
+	HealthRepoGetTotalPendingMigrationsError int = 5000902
+	// ingredient repository
+	IngredientRepoGetIngredientBySerialError int = 5001001
+	IngredientRepoUpdateError                int = 5001002
+	// recipe repository
+	RecipeRepoGetRecipeBySerialError           int = 5001101
+	RecipeRepoGetRecipeIngredientsError        int = 5001102
+	RecipeRepoGetIngredientConversionsError    int = 5001103
+	RecipeRepoUpdateRawMaterialCostsError      int = 5001104
+	RecipeRepoGetParentRecipesError            int = 5001105
+	RecipeRepoProduceError                     int = 5001106
+	RecipeRepoGetRecipesByIngredientError      int = 5001107
+	RecipeRepoGetRecipesByUnitError            int = 5001108
+	RecipeRepoGetRecipeIngredientBySerialError int = 5001109
+	RecipeRepoCreateRecipeIngredientError      int = 5001110
+	RecipeRepoUpdateRecipeIngredientError      int = 5001111
 	// auth usecase
 	AuthUsecaseGenerateJwtTokenError int = 5003001
 	AuthUsecaseValidateJwtTokenError int = 5003002
 	// mail usecase
 	MailUsecaseGenerateHTMLError      int = 5003101
 	MailUsecaseGeneratePlainTextError int = 5003102
+	MailUsecaseRenderTemplateError    int = 5003103
 	// formatter usecase
-	FormatterUsecaseFormatUserError int = 5003201
-	FormatterUsecaseFormatUnitError int = 5003202
+	FormatterUsecaseFormatUserError           int = 5003201
+	FormatterUsecaseFormatUnitError           int = 5003202
+	FormatterUsecaseFormatMailError           int = 5003203
+	FormatterUsecaseFormatUnitConversionError int = 5003204
+	FormatterUsecaseFormatIngredientError     int = 5003205
+	FormatterUsecaseFormatRecipeCostingError  int = 5003206
+	FormatterUsecaseFormatRecipeScaleError    int = 5003207
+	FormatterUsecaseFormatProductionError     int = 5003208
+	FormatterUsecaseFormatRecipeLineError     int = 5003209
 	// session usecase
 	SessionUsecaseTokenInvalidType  int = 5003201
 	SessionUsecaseErrorInvalidType  int = 5003202
@@ -92,6 +199,8 @@ const (
 	SessionUsecaseLogoutError       int = 5003207
 	SessionUsecaseGetErrorError     int = 5003208
 	SessionUsecaseSetPrevRouteError int = 5003209
+	// user usecase
+	UserUsecaseGenerateVerifyTokenError int = 5003401
 	// base handler
 	BaseHandlerGetAuthorError int = 5006001
 	BaseHandlerGetTokenError  int = 5006002
@@ -101,6 +210,7 @@ const (
 	HelperGenerateTokenError          int = 5009901
 	HelperEncryptPasswordError        int = 5009902
 	HelperGenerateStrongPasswordError int = 5009903
+	HelperGenerateSerialError         int = 5009904
 )
 
 // for echo.HTTPError.Internal
@@ -1,65 +1,137 @@
 package entity
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"rap-c/config"
+	"sync"
 
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 const (
-	storagePath    string = "storage"
-	logPath        string = "log"
 	errorLogFile   string = "error.log"
 	warningLogFile string = "warning.log"
 )
 
+// shared logger, stdout only until SetupLogger is called
+var (
+	sharedLogger      = newLogger(nil)
+	sharedLoggerMutex sync.RWMutex
+)
+
+type logger struct {
+	log     *logrus.Logger
+	logMode config.LogMode
+}
+
 type RapCLog struct {
-	log               *logrus.Logger
-	uri               string
-	method            string
-	status            int
-	message           string
-	err               error
-	logMode           config.LogMode
-	enableWarnFileLog bool
+	ctx     context.Context
+	uri     string
+	method  string
+	status  int
+	message string
+	err     error
 }
 
-func InitLog(uri, method, message string, status int, err error, logMode config.LogMode, enableWarnFileLog bool) RapCLog {
-	logger := logrus.New()
-	logger.SetFormatter(&logrus.TextFormatter{
+// set shared logger from config: format, mode & rotated log files, called once on start
+func SetupLogger(cfg *config.Config) error {
+	err := os.MkdirAll(cfg.LogDir(), 0755)
+	if err != nil {
+		return fmt.Errorf("create log folder %s: %v", cfg.LogDir(), err)
+	}
+
+	l := newLogger(cfg)
+	sharedLoggerMutex.Lock()
+	sharedLogger = l
+	sharedLoggerMutex.Unlock()
+	return nil
+}
+
+func newLogger(cfg *config.Config) *logger {
+	log := logrus.New()
+	log.SetOutput(os.Stdout)
+	log.SetFormatter(&logrus.TextFormatter{
 		FullTimestamp: true,
 	})
+	if cfg == nil {
+		return &logger{log: log, logMode: config.LogModeErrorOnly}
+	}
+
+	if cfg.LogFormat() == config.LogFormatJSON {
+		log.SetFormatter(&logrus.JSONFormatter{})
+	}
+	// error log file
+	log.AddHook(&FileHook{
+		writer:    rotatedFile(cfg, errorLogFile),
+		logLevels: []logrus.Level{logrus.ErrorLevel, logrus.FatalLevel, logrus.PanicLevel},
+	})
+	// warning log file
+	if cfg.EnableWarnFileLog() {
+		log.AddHook(&FileHook{
+			writer:    rotatedFile(cfg, warningLogFile),
+			logLevels: []logrus.Level{logrus.WarnLevel},
+		})
+	}
+	return &logger{log: log, logMode: cfg.LogMode()}
+}
+
+// log file rotated by size, old files removed by age & total backups
+func rotatedFile(cfg *config.Config, name string) io.Writer {
+	return &lumberjack.Logger{
+		Filename:   filepath.Join(cfg.LogDir(), name),
+		MaxSize:    cfg.LogMaxSizeInMB(),
+		MaxAge:     cfg.LogMaxAgeInDays(),
+		MaxBackups: cfg.LogMaxBackups(),
+		LocalTime:  true,
+	}
+}
+
+// init log entry, request id & user id are read from context when exists
+func InitLog(ctx context.Context, uri, method, message string, status int, err error) RapCLog {
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	return RapCLog{
-		log:               logger,
-		uri:               uri,
-		method:            method,
-		status:            status,
-		message:           message,
-		err:               err,
-		logMode:           logMode,
-		enableWarnFileLog: enableWarnFileLog,
+		ctx:     ctx,
+		uri:     uri,
+		method:  method,
+		status:  status,
+		message: message,
+		err:     err,
 	}
 }
 
 func (e RapCLog) Log() {
+	sharedLoggerMutex.RLock()
+	l := sharedLogger
+	sharedLoggerMutex.RUnlock()
+
 	logrusFields := logrus.Fields{
 		"URI":    e.uri,
 		"Method": e.method,
 		"Status": e.status,
 		"Error":  e.err,
 	}
+	if requestID := RequestIDFromContext(e.ctx); requestID != "" {
+		logrusFields["RequestID"] = requestID
+	}
+	if userID, ok := UserIDFromContext(e.ctx); ok {
+		logrusFields["UserID"] = userID
+	}
 
 	if e.err == nil {
-		if e.logMode != config.LogModeAll {
+		if l.logMode != config.LogModeAll {
 			return
 		}
 		delete(logrusFields, "Error")
-		e.log.WithFields(logrusFields).Info(e.message)
+		l.log.WithFields(logrusFields).Info(e.message)
 	} else {
 		// set message
 		var message interface{} = fmt.Sprint(e.message)
@@ -72,56 +144,29 @@ func (e RapCLog) Log() {
 		}
 
 		if e.status < http.StatusInternalServerError {
-			if e.logMode != config.LogModeErrorAndWarnOnly && e.logMode != config.LogModeAll {
+			if l.logMode != config.LogModeErrorAndWarnOnly && l.logMode != config.LogModeAll {
 				return
 			}
-
-			// create warning log file
-			if e.enableWarnFileLog {
-				warnLog, err := os.OpenFile(filepath.Join(storagePath, logPath, warningLogFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-				if err != nil {
-					e.log.Errorf("Failed to create warning file hook: %v", err)
-				} else {
-					defer warnLog.Close()
-
-					e.log.AddHook(&FileHook{
-						writer:    warnLog,
-						logLevels: []logrus.Level{logrus.WarnLevel},
-					})
-				}
-			}
-			e.log.WithFields(logrusFields).Warn(message)
+			l.log.WithFields(logrusFields).Warn(message)
 		} else {
-			// create error log file
-			errorLog, err := os.OpenFile(filepath.Join(storagePath, logPath, errorLogFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-			if err != nil {
-				e.log.Errorf("Failed to create error file hook: %v", err)
-			} else {
-				defer errorLog.Close()
-
-				e.log.AddHook(&FileHook{
-					writer:    errorLog,
-					logLevels: []logrus.Level{logrus.ErrorLevel, logrus.FatalLevel, logrus.PanicLevel},
-				})
-			}
-			e.log.WithFields(logrusFields).Error(message)
+			l.log.WithFields(logrusFields).Error(message)
 		}
 	}
 }
 
 // logrus file hook
 type FileHook struct {
-	writer    *os.File
+	writer    io.Writer
 	logLevels []logrus.Level
 }
 
 // Fire write log ke file
 func (hook *FileHook) Fire(entry *logrus.Entry) error {
-	line, err := entry.String()
+	line, err := entry.Bytes()
 	if err != nil {
 		return err
 	}
-	_, err = hook.writer.Write([]byte(line))
+	_, err = hook.writer.Write(line)
 	return err
 }
 
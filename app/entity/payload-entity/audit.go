@@ -0,0 +1,17 @@
+package payloadentity
+
+import "rap-c/app/entity"
+
+// bind struct for get audit log list request
+type GetAuditLogListRequest struct {
+	EntityType      string            `query:"entityType" json:"entityType" validate:"omitempty,oneof=user unit ingredient ingredient_conversion recipe recipe_ingredient"`
+	Serial          string            `query:"serial" json:"serial"`
+	Actor           string            `query:"actor" json:"actor"` // actor username
+	Action          string            `query:"action" json:"action" validate:"omitempty,oneof=create update delete"`
+	RequestID       string            `query:"requestId" json:"requestId"`
+	DateFrom        string            `query:"dateFrom" json:"dateFrom" validate:"omitempty,datetime=2006-01-02"`
+	DateTo          string            `query:"dateTo" json:"dateTo" validate:"omitempty,datetime=2006-01-02"`
+	DescendingOrder bool              `query:"descendingOrder" json:"descendingOrder"`
+	Limit           int               `query:"limit" json:"limit"`
+	Page            entity.Pagination `query:"page" json:"page"`
+}
@@ -12,7 +12,8 @@ type RenewPasswordPayload struct {
 }
 
 type RequestResetPayload struct {
-	Email string `json:"email" form:"email" validate:"required,email"`
+	Email     string `json:"email" form:"email" validate:"required,email"`
+	IPAddress string `json:"-"`
 }
 
 type ValidateResetTokenPayload struct {
@@ -26,3 +27,15 @@ type ResetPasswordPayload struct {
 	Password        string `json:"password" form:"password" validate:"required,min=8"`
 	ConfirmPassword string `json:"confirmPassword" form:"confirmPassword" validate:"required,eqfield=Password"`
 }
+
+type ValidateInvitationPayload struct {
+	Email string `json:"email" form:"email" query:"email" validate:"required,email"`
+	Token string `json:"token" form:"token" query:"token" validate:"required"`
+}
+
+type AcceptInvitationPayload struct {
+	Email           string `json:"email" form:"email" query:"email" validate:"required,email"`
+	Token           string `json:"token" form:"token" query:"token" validate:"required"`
+	Password        string `json:"password" form:"password" validate:"required,min=8"`
+	ConfirmPassword string `json:"confirmPassword" form:"confirmPassword" validate:"required,eqfield=Password"`
+}
@@ -0,0 +1,8 @@
+package payloadentity
+
+// set ingredient weight payload, 0 clears density or piece weight
+type SetIngredientWeightPayload struct {
+	Serial      string  `json:"serial" form:"serial" validate:"required"`
+	Density     float64 `json:"density" form:"density" validate:"gte=0"`
+	PieceWeight float64 `json:"pieceWeight" form:"pieceWeight" validate:"gte=0"`
+}
@@ -0,0 +1,22 @@
+package payloadentity
+
+import "rap-c/app/entity"
+
+// bind struct for get dead letter mail list request
+type GetDeadLetterListRequest struct {
+	Recipient       string            `query:"recipient" json:"recipient"`
+	DescendingOrder bool              `query:"descendingOrder" json:"descendingOrder"`
+	Limit           int               `query:"limit" json:"limit"`
+	Page            entity.Pagination `query:"page" json:"page"`
+}
+
+// resend dead letter mail payload
+type ResendMailPayload struct {
+	ID int `json:"id" form:"id" validate:"required"`
+}
+
+// preview mail template payload
+type PreviewMailTemplatePayload struct {
+	Name   string `query:"name" json:"name" validate:"required,oneof=welcome reset-password update-user active-status verify-email email-change-requested"`
+	Locale string `query:"locale" json:"locale" validate:"omitempty,oneof=id en"`
+}
@@ -0,0 +1,36 @@
+package payloadentity
+
+// scale recipe request, portions is how many portions to prepare
+type ScaleRecipeRequest struct {
+	Serial   string `param:"serial" json:"serial" validate:"required"`
+	Portions int    `query:"portions" json:"portions" validate:"gt=0"`
+}
+
+// produce recipe payload, quantity is counted in yield unit of recipe or in portions when it has none
+type ProduceRecipePayload struct {
+	Serial   string  `json:"serial" form:"serial" validate:"required"`
+	Quantity float64 `json:"quantity" form:"quantity" validate:"gt=0"`
+}
+
+// refresh recipe cost payload
+type RefreshRecipeCostPayload struct {
+	Serial string `json:"serial" form:"serial" validate:"required"`
+}
+
+// create recipe line payload, line uses either ingredient or sub recipe & quantity is counted in unit
+type CreateRecipeIngredientPayload struct {
+	Serial           string  `json:"serial" form:"serial" validate:"required"`
+	IngredientSerial string  `json:"ingredientSerial" form:"ingredientSerial" validate:"required_without=SubRecipeSerial,excluded_with=SubRecipeSerial"`
+	SubRecipeSerial  string  `json:"subRecipeSerial" form:"subRecipeSerial"`
+	Unit             string  `json:"unit" form:"unit" validate:"required"`
+	Quantity         float32 `json:"quantity" form:"quantity" validate:"gt=0"`
+}
+
+// update recipe line payload, serial is serial of the line
+type UpdateRecipeIngredientPayload struct {
+	Serial           string  `json:"serial" form:"serial" validate:"required"`
+	IngredientSerial string  `json:"ingredientSerial" form:"ingredientSerial" validate:"required_without=SubRecipeSerial,excluded_with=SubRecipeSerial"`
+	SubRecipeSerial  string  `json:"subRecipeSerial" form:"subRecipeSerial"`
+	Unit             string  `json:"unit" form:"unit" validate:"required"`
+	Quantity         float32 `json:"quantity" form:"quantity" validate:"gt=0"`
+}
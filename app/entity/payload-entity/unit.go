@@ -11,12 +11,34 @@ type GetUnitListRequest struct {
 	Page            entity.Pagination `query:"page" json:"page"`
 }
 
-// create unit payload
+// create unit payload, dimension & factor are empty for custom unit
 type CreateUnitPayload struct {
-	Name string `json:"name" form:"name" validate:"required,max=30"`
+	Name      string  `json:"name" form:"name" validate:"required,max=30"`
+	Dimension string  `json:"dimension" form:"dimension" validate:"omitempty,oneof=mass volume count"`
+	Factor    float64 `json:"factor" form:"factor" validate:"required_with=Dimension,excluded_without=Dimension,gte=0"`
 }
 
 // delete unit payload
 type DeleteUnitPayload struct {
 	Name string `json:"name" form:"name" validate:"required"`
 }
+
+// rename unit payload
+type RenameUnitPayload struct {
+	Name    string `json:"name" form:"name" validate:"required"`
+	NewName string `json:"newName" form:"newName" validate:"required,max=30"`
+}
+
+// merge unit payload, every reference of unit name is moved to target unit then unit name is deleted
+type MergeUnitPayload struct {
+	Name       string `json:"name" form:"name" validate:"required"`
+	TargetName string `json:"targetName" form:"targetName" validate:"required"`
+}
+
+// bind struct for convert unit request, ingredient serial is needed to convert between dimensions
+type ConvertUnitRequest struct {
+	Quantity   float64 `query:"quantity" json:"quantity" validate:"gt=0"`
+	From       string  `query:"from" json:"from" validate:"required"`
+	To         string  `query:"to" json:"to" validate:"required"`
+	Ingredient string  `query:"ingredient" json:"ingredient"`
+}
@@ -29,6 +29,15 @@ type CreateUserPayload struct {
 	IsGuest  bool   `json:"-"`
 }
 
+// first run setup payload, owner set own password
+type CreateOwnerPayload struct {
+	Username        string `json:"username" form:"username" validate:"required,max=30,username"`
+	FullName        string `json:"fullName" form:"fullName" validate:"required"`
+	Email           string `json:"email" form:"email" validate:"required,email"`
+	Password        string `json:"password" form:"password" validate:"required,min=8"`
+	ConfirmPassword string `json:"confirmPassword" form:"confirmPassword" validate:"required,eqfield=Password"`
+}
+
 // update user payload
 type UpdateUserPayload struct {
 	Username        string `json:"username" form:"username" validate:"omitempty,max=30,username"`
@@ -42,3 +51,13 @@ type ActiveStatusPayload struct {
 	Username string `json:"username" form:"username" validate:"required"`
 	Disabled bool   `json:"disabled" form:"disabled"`
 }
+
+// verify email payload, token from verification link
+type VerifyEmailPayload struct {
+	Token string `json:"token" form:"token" query:"token" validate:"required"`
+}
+
+// resend or revoke user invitation payload
+type UserInvitationPayload struct {
+	Username string `json:"username" form:"username" validate:"required"`
+}
@@ -0,0 +1,26 @@
+package responseentity
+
+import (
+	"encoding/json"
+	payloadentity "rap-c/app/entity/payload-entity"
+	"time"
+)
+
+type AuditLogResponse struct {
+	ID         int             `json:"id"`
+	Actor      string          `json:"actor"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entityType"`
+	EntityID   int             `json:"entityId"`
+	Serial     string          `json:"serial"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	IPAddress  string          `json:"ipAddress"`
+	RequestID  string          `json:"requestId"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
+
+type GetAuditLogListResponse struct {
+	Logs    []*AuditLogResponse
+	Request *payloadentity.GetAuditLogListRequest
+}
@@ -0,0 +1,19 @@
+package responseentity
+
+const (
+	HealthStatusOK   string = "ok"
+	HealthStatusFail string = "fail"
+)
+
+type HealthCheckResponse struct {
+	Status     string  `json:"status"`
+	Optional   bool    `json:"optional"`
+	DurationMs float64 `json:"durationMs"`
+	Error      string  `json:"error,omitempty"`
+}
+
+type HealthResponse struct {
+	Status     string                          `json:"status"`
+	DurationMs float64                         `json:"durationMs"`
+	Checks     map[string]*HealthCheckResponse `json:"checks,omitempty"`
+}
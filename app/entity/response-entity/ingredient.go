@@ -0,0 +1,15 @@
+package responseentity
+
+import "time"
+
+type IngredientResponse struct {
+	Serial       string    `json:"serial"`
+	Name         string    `json:"name"`
+	Unit         string    `json:"unit"`
+	PricePerUnit float32   `json:"pricePerUnit"`
+	Stock        float32   `json:"stock"`
+	Density      float64   `json:"density"`
+	PieceWeight  float64   `json:"pieceWeight"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+	UpdatedBy    string    `json:"updatedBy"`
+}
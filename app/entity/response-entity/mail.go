@@ -0,0 +1,24 @@
+package responseentity
+
+import (
+	payloadentity "rap-c/app/entity/payload-entity"
+	"time"
+)
+
+type MailResponse struct {
+	ID            int        `json:"id"`
+	Recipient     string     `json:"recipient"`
+	Subject       string     `json:"subject"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"lastError"`
+	NextAttemptAt time.Time  `json:"nextAttemptAt"`
+	SentAt        *time.Time `json:"sentAt"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+type GetDeadLetterListResponse struct {
+	Mails   []*MailResponse
+	Request *payloadentity.GetDeadLetterListRequest
+}
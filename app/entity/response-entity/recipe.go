@@ -0,0 +1,116 @@
+package responseentity
+
+import "time"
+
+type RecipeCostingResponse struct {
+	Serial           string                       `json:"serial"`
+	Name             string                       `json:"name"`
+	Portions         int                          `json:"portions"`
+	Lines            []*RecipeCostingLineResponse `json:"lines"`
+	RawMaterialCosts float64                      `json:"rawMaterialCosts"`
+	LaborCosts       float64                      `json:"laborCosts"`
+	OverheadCosts    float64                      `json:"overheadCosts"`
+	HPP              float64                      `json:"hpp"`
+	HPPPerPortion    float64                      `json:"hppPerPortion"`
+}
+
+// recipe line quantity in recipe unit & ingredient unit, bridge tells how it is converted.
+// sub recipe line is in yield unit of sub recipe, priced by its hpp per portion & split into its raw material, labor & overhead
+type RecipeCostingLineResponse struct {
+	Serial             string  `json:"serial"`
+	IngredientSerial   string  `json:"ingredientSerial"`
+	IngredientName     string  `json:"ingredientName"`
+	SubRecipeSerial    string  `json:"subRecipeSerial"`
+	SubRecipeName      string  `json:"subRecipeName"`
+	Quantity           float64 `json:"quantity"`
+	Unit               string  `json:"unit"`
+	IngredientQuantity float64 `json:"ingredientQuantity"`
+	IngredientUnit     string  `json:"ingredientUnit"`
+	PricePerUnit       float64 `json:"pricePerUnit"`
+	CostType           string  `json:"costType"`
+	Cost               float64 `json:"cost"`
+	RawMaterialCost    float64 `json:"rawMaterialCost"`
+	LaborCost          float64 `json:"laborCost"`
+	OverheadCost       float64 `json:"overheadCost"`
+	Bridge             string  `json:"bridge"`
+	SkipCalculate      bool    `json:"skipCalculate"`
+}
+
+// recipe scaled into requested portions, warnings list ingredients whose stock is not enough
+type RecipeScaleResponse struct {
+	Serial           string                        `json:"serial"`
+	Name             string                        `json:"name"`
+	RecipePortions   int                           `json:"recipePortions"`
+	Portions         int                           `json:"portions"`
+	Factor           float64                       `json:"factor"`
+	Lines            []*RecipeScaleLineResponse    `json:"lines"`
+	RawMaterialCosts float64                       `json:"rawMaterialCosts"`
+	LaborCosts       float64                       `json:"laborCosts"`
+	OverheadCosts    float64                       `json:"overheadCosts"`
+	HPP              float64                       `json:"hpp"`
+	HPPPerPortion    float64                       `json:"hppPerPortion"`
+	Warnings         []*RecipeStockWarningResponse `json:"warnings"`
+}
+
+// scaled line quantity in recipe unit, ingredient unit & purchase unit
+type RecipeScaleLineResponse struct {
+	Serial             string  `json:"serial"`
+	IngredientSerial   string  `json:"ingredientSerial"`
+	IngredientName     string  `json:"ingredientName"`
+	SubRecipeSerial    string  `json:"subRecipeSerial"`
+	SubRecipeName      string  `json:"subRecipeName"`
+	Quantity           float64 `json:"quantity"`
+	Unit               string  `json:"unit"`
+	IngredientQuantity float64 `json:"ingredientQuantity"`
+	IngredientUnit     string  `json:"ingredientUnit"`
+	PurchaseQuantity   float64 `json:"purchaseQuantity"`
+	PurchaseUnit       string  `json:"purchaseUnit"`
+	Cost               float64 `json:"cost"`
+	SkipCalculate      bool    `json:"skipCalculate"`
+}
+
+// required, stock & shortage in ingredient unit or yield unit of sub recipe
+type RecipeStockWarningResponse struct {
+	IngredientSerial string  `json:"ingredientSerial"`
+	IngredientName   string  `json:"ingredientName"`
+	SubRecipeSerial  string  `json:"subRecipeSerial"`
+	SubRecipeName    string  `json:"subRecipeName"`
+	Required         float64 `json:"required"`
+	Stock            float64 `json:"stock"`
+	Shortage         float64 `json:"shortage"`
+	Unit             string  `json:"unit"`
+}
+
+// produced recipe with its stock after production
+type RecipeProductionResponse struct {
+	Serial      string                       `json:"serial"`
+	Name        string                       `json:"name"`
+	Quantity    float64                      `json:"quantity"`
+	Unit        string                       `json:"unit"`
+	Stock       float64                      `json:"stock"`
+	Ingredients []*RecipeConsumptionResponse `json:"ingredients"`
+	SubRecipes  []*RecipeConsumptionResponse `json:"subRecipes"`
+}
+
+// consumed quantity & stock left in ingredient unit or yield unit of sub recipe
+type RecipeConsumptionResponse struct {
+	Serial   string  `json:"serial"`
+	Name     string  `json:"name"`
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"`
+	Stock    float64 `json:"stock"`
+}
+
+// recipe line, quantity is counted in unit
+type RecipeIngredientResponse struct {
+	Serial           string    `json:"serial"`
+	RecipeSerial     string    `json:"recipeSerial"`
+	IngredientSerial string    `json:"ingredientSerial"`
+	IngredientName   string    `json:"ingredientName"`
+	SubRecipeSerial  string    `json:"subRecipeSerial"`
+	SubRecipeName    string    `json:"subRecipeName"`
+	Quantity         float64   `json:"quantity"`
+	Unit             string    `json:"unit"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+	UpdatedBy        string    `json:"updatedBy"`
+}
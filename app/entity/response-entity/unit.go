@@ -7,6 +7,8 @@ import (
 
 type UnitResponse struct {
 	Name      string    `json:"name"`
+	Dimension string    `json:"dimension"`
+	Factor    float64   `json:"factor"`
 	CreatedAt time.Time `json:"createdAt"`
 	CreatedBy string    `json:"createdBy"`
 }
@@ -15,3 +17,49 @@ type GetUnitListResponse struct {
 	Units   []*UnitResponse
 	Request *payloadentity.GetUnitListRequest
 }
+
+type UnitDetailResponse struct {
+	UnitResponse
+	Ingredients       []*UnitIngredientResponse       `json:"ingredients"`
+	Conversions       []*UnitConversionResponse       `json:"conversions"`
+	RecipeIngredients []*UnitRecipeIngredientResponse `json:"recipeIngredients"`
+	Recipes           []*UnitRecipeResponse           `json:"recipes"`
+}
+
+// ingredient measured in unit
+type UnitIngredientResponse struct {
+	Serial string `json:"serial"`
+	Name   string `json:"name"`
+}
+
+// ingredient conversion into unit
+type UnitConversionResponse struct {
+	Serial         string  `json:"serial"`
+	IngredientName string  `json:"ingredientName"`
+	Value          float32 `json:"value"`
+}
+
+// recipe line measured in unit
+type UnitRecipeIngredientResponse struct {
+	Serial         string  `json:"serial"`
+	RecipeSerial   string  `json:"recipeSerial"`
+	RecipeName     string  `json:"recipeName"`
+	IngredientName string  `json:"ingredientName"`
+	SubRecipeName  string  `json:"subRecipeName"`
+	Quantity       float32 `json:"quantity"`
+}
+
+// recipe yielding in unit
+type UnitRecipeResponse struct {
+	Serial string `json:"serial"`
+	Name   string `json:"name"`
+}
+
+// bridge is same, dimension or ingredient
+type ConvertUnitResponse struct {
+	Quantity float64 `json:"quantity"`
+	From     string  `json:"from"`
+	Result   float64 `json:"result"`
+	To       string  `json:"to"`
+	Bridge   string  `json:"bridge"`
+}
@@ -9,7 +9,10 @@ type UserResponse struct {
 	Username           string    `json:"username"`
 	FullName           string    `json:"fullName"`
 	Email              string    `json:"email"`
+	PendingEmail       string    `json:"pendingEmail"`
+	EmailVerified      bool      `json:"emailVerified"`
 	PasswordMustChange bool      `json:"passwordMustChange"`
+	InvitationPending  bool      `json:"invitationPending"`
 	Disabled           bool      `json:"disabled"`
 	IsGuest            bool      `json:"isGuest"`
 	CreatedAt          time.Time `json:"createdAt"`
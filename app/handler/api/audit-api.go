@@ -0,0 +1,90 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"rap-c/app/entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	responseentity "rap-c/app/entity/response-entity"
+	"rap-c/app/handler"
+	"rap-c/app/usecase/contract"
+	"rap-c/config"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AuditAPI interface {
+	// get audit log list
+	GetAuditLogList(e echo.Context) error
+	// get total audit log list
+	GetTotalAuditLogList(e echo.Context) error
+}
+
+func NewAuditHandler(cfg *config.Config, router *config.Route,
+	auditUsecase contract.AuditUsecase, formatterUsecase contract.FormatterUsecase) AuditAPI {
+	return &auditHandler{
+		cfg:              cfg,
+		router:           router,
+		auditUsecase:     auditUsecase,
+		formatterUsecase: formatterUsecase,
+		BaseHandler:      handler.NewBaseHandler(cfg, router),
+	}
+}
+
+type auditHandler struct {
+	cfg              *config.Config
+	router           *config.Route
+	auditUsecase     contract.AuditUsecase
+	formatterUsecase contract.FormatterUsecase
+	BaseHandler      *handler.BaseHandler
+}
+
+func (h *auditHandler) GetAuditLogList(e echo.Context) error {
+	req := new(payloadentity.GetAuditLogListRequest)
+	err := e.Bind(req)
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AllHandlerBindError, fmt.Sprintf("audit-api.GetAuditLogList bind error: %v", err)),
+		}
+	}
+	ctx := e.Request().Context()
+
+	logs, err := h.auditUsecase.GetAuditLogList(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.formatterUsecase.FormatAuditLogs(ctx, logs)
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, &responseentity.GetAuditLogListResponse{
+		Logs:    resp,
+		Request: req,
+	})
+}
+
+func (h *auditHandler) GetTotalAuditLogList(e echo.Context) error {
+	req := new(payloadentity.GetAuditLogListRequest)
+	err := e.Bind(req)
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AllHandlerBindError, fmt.Sprintf("audit-api.GetTotalAuditLogList bind error: %v", err)),
+		}
+	}
+	ctx := e.Request().Context()
+
+	total, err := h.auditUsecase.GetTotalAuditLogList(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return e.JSON(http.StatusOK, map[string]interface{}{
+		"total":   total,
+		"request": req,
+	})
+}
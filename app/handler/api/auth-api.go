@@ -23,14 +23,15 @@ type AuthAPI interface {
 	RequestResetPassword(e echo.Context) error
 	// do reset password
 	ResetPassword(e echo.Context) error
+	// accept invitation and set password
+	AcceptInvitation(e echo.Context) error
 }
 
-func NewAuthHandler(cfg *config.Config, router *config.Route, authUsecase contract.AuthUsecase, mailUsecase contract.MailUsecase) AuthAPI {
+func NewAuthHandler(cfg *config.Config, router *config.Route, authUsecase contract.AuthUsecase) AuthAPI {
 	return &authHandler{
 		cfg:         cfg,
 		router:      router,
 		authUsecase: authUsecase,
-		mailUsecase: mailUsecase,
 		BaseHandler: handler.NewBaseHandler(cfg, router),
 	}
 }
@@ -39,7 +40,6 @@ type authHandler struct {
 	cfg         *config.Config
 	router      *config.Route
 	authUsecase contract.AuthUsecase
-	mailUsecase contract.MailUsecase
 	BaseHandler *handler.BaseHandler
 }
 
@@ -125,22 +125,17 @@ func (h *authHandler) RequestResetPassword(e echo.Context) error {
 			Internal: entity.NewInternalError(entity.AllHandlerBindError, fmt.Sprintf("auth-api.RequestResetPassword bind error: %v", err)),
 		}
 	}
+	payload.IPAddress = e.RealIP()
 	ctx := e.Request().Context()
 
-	// get data
-	user, token, err := h.authUsecase.RequestResetPassword(ctx, payload)
-	if err != nil {
-		return err
-	}
-
-	// send email
-	err = h.mailUsecase.ResetPassword(user, token)
+	// reset password mail is only queued for registered email
+	err = h.authUsecase.RequestResetPassword(ctx, payload)
 	if err != nil {
 		return err
 	}
 
 	return e.JSON(http.StatusOK, map[string]interface{}{
-		"status": "email for request reset password has been sent",
+		"status": "if the email is registered, a reset password link has been sent",
 	})
 }
 
@@ -171,3 +166,31 @@ func (h *authHandler) ResetPassword(e echo.Context) error {
 		"token": token,
 	})
 }
+
+func (h *authHandler) AcceptInvitation(e echo.Context) error {
+	payload := new(payloadentity.AcceptInvitationPayload)
+	err := e.Bind(payload)
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AllHandlerBindError, fmt.Sprintf("auth-api.AcceptInvitation bind error: %v", err)),
+		}
+	}
+	ctx := e.Request().Context()
+
+	// accept invitation
+	user, err := h.authUsecase.AcceptInvitation(ctx, payload)
+	if err != nil {
+		return err
+	}
+
+	// generate token
+	token, err := h.authUsecase.GenerateJwtToken(ctx, user, false)
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, map[string]interface{}{
+		"token": token,
+	})
+}
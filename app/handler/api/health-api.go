@@ -0,0 +1,41 @@
+package api
+
+import (
+	"net/http"
+	responseentity "rap-c/app/entity/response-entity"
+	"rap-c/app/usecase/contract"
+	"rap-c/config"
+
+	"github.com/labstack/echo/v4"
+)
+
+type HealthAPI interface {
+	// process is alive
+	Liveness(e echo.Context) error
+	// service is ready to serve request
+	Readiness(e echo.Context) error
+}
+
+func NewHealthHandler(cfg *config.Config, healthUsecase contract.HealthUsecase) HealthAPI {
+	return &healthHandler{
+		cfg:           cfg,
+		healthUsecase: healthUsecase,
+	}
+}
+
+type healthHandler struct {
+	cfg           *config.Config
+	healthUsecase contract.HealthUsecase
+}
+
+func (h *healthHandler) Liveness(e echo.Context) error {
+	return e.JSON(http.StatusOK, h.healthUsecase.Liveness(e.Request().Context()))
+}
+
+func (h *healthHandler) Readiness(e echo.Context) error {
+	resp := h.healthUsecase.Readiness(e.Request().Context())
+	if resp.Status != responseentity.HealthStatusOK {
+		return e.JSON(http.StatusServiceUnavailable, resp)
+	}
+	return e.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"rap-c/app/entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	"rap-c/app/handler"
+	"rap-c/app/usecase/contract"
+	"rap-c/config"
+
+	"github.com/labstack/echo/v4"
+)
+
+type IngredientAPI interface {
+	// set ingredient density & piece weight
+	SetWeight(e echo.Context) error
+}
+
+func NewIngredientHandler(cfg *config.Config, router *config.Route,
+	ingredientUsecase contract.IngredientUsecase, formatterUsecase contract.FormatterUsecase) IngredientAPI {
+	return &ingredientHandler{
+		cfg:               cfg,
+		router:            router,
+		ingredientUsecase: ingredientUsecase,
+		formatterUsecase:  formatterUsecase,
+		BaseHandler:       handler.NewBaseHandler(cfg, router),
+	}
+}
+
+type ingredientHandler struct {
+	cfg               *config.Config
+	router            *config.Route
+	ingredientUsecase contract.IngredientUsecase
+	formatterUsecase  contract.FormatterUsecase
+	BaseHandler       *handler.BaseHandler
+}
+
+func (h *ingredientHandler) SetWeight(e echo.Context) error {
+	payload := new(payloadentity.SetIngredientWeightPayload)
+	err := e.Bind(payload)
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AllHandlerBindError, fmt.Sprintf("ingredient-api.SetWeight bind error: %v", err)),
+		}
+	}
+	ctx := e.Request().Context()
+
+	// get author
+	author, err := h.BaseHandler.GetAuthor(e)
+	if err != nil {
+		return err
+	}
+
+	// set weight
+	ingredient, err := h.ingredientUsecase.SetWeight(ctx, payload, author)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.formatterUsecase.FormatIngredient(ctx, ingredient, map[int]string{author.ID: author.Username})
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"rap-c/app/entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	responseentity "rap-c/app/entity/response-entity"
+	"rap-c/app/handler"
+	"rap-c/app/usecase/contract"
+	"rap-c/config"
+
+	"github.com/labstack/echo/v4"
+)
+
+type MailAPI interface {
+	// get dead letter mail list
+	GetDeadLetterList(e echo.Context) error
+	// get total dead letter mail list
+	GetTotalDeadLetterList(e echo.Context) error
+	// put dead letter mail back to queue
+	ResendDeadLetter(e echo.Context) error
+	// render mail template with sample data
+	PreviewTemplate(e echo.Context) error
+}
+
+func NewMailHandler(cfg *config.Config, router *config.Route,
+	mailUsecase contract.MailUsecase, formatterUsecase contract.FormatterUsecase) MailAPI {
+	return &mailHandler{
+		cfg:              cfg,
+		router:           router,
+		mailUsecase:      mailUsecase,
+		formatterUsecase: formatterUsecase,
+		BaseHandler:      handler.NewBaseHandler(cfg, router),
+	}
+}
+
+type mailHandler struct {
+	cfg              *config.Config
+	router           *config.Route
+	mailUsecase      contract.MailUsecase
+	formatterUsecase contract.FormatterUsecase
+	BaseHandler      *handler.BaseHandler
+}
+
+func (h *mailHandler) GetDeadLetterList(e echo.Context) error {
+	req := new(payloadentity.GetDeadLetterListRequest)
+	err := e.Bind(req)
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AllHandlerBindError, fmt.Sprintf("mail-api.GetDeadLetterList bind error: %v", err)),
+		}
+	}
+	ctx := e.Request().Context()
+
+	mails, err := h.mailUsecase.GetDeadLetterList(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.formatterUsecase.FormatMails(ctx, mails)
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, &responseentity.GetDeadLetterListResponse{
+		Mails:   resp,
+		Request: req,
+	})
+}
+
+func (h *mailHandler) GetTotalDeadLetterList(e echo.Context) error {
+	req := new(payloadentity.GetDeadLetterListRequest)
+	err := e.Bind(req)
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AllHandlerBindError, fmt.Sprintf("mail-api.GetTotalDeadLetterList bind error: %v", err)),
+		}
+	}
+	ctx := e.Request().Context()
+
+	total, err := h.mailUsecase.GetTotalDeadLetterList(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return e.JSON(http.StatusOK, map[string]interface{}{
+		"total":   total,
+		"request": req,
+	})
+}
+
+func (h *mailHandler) ResendDeadLetter(e echo.Context) error {
+	payload := new(payloadentity.ResendMailPayload)
+	err := e.Bind(payload)
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AllHandlerBindError, fmt.Sprintf("mail-api.ResendDeadLetter bind error: %v", err)),
+		}
+	}
+	ctx := e.Request().Context()
+
+	mail, err := h.mailUsecase.ResendDeadLetter(ctx, payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.formatterUsecase.FormatMail(ctx, mail)
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, resp)
+}
+
+func (h *mailHandler) PreviewTemplate(e echo.Context) error {
+	req := new(payloadentity.PreviewMailTemplatePayload)
+	err := e.Bind(req)
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AllHandlerBindError, fmt.Sprintf("mail-api.PreviewTemplate bind error: %v", err)),
+		}
+	}
+	ctx := e.Request().Context()
+
+	mail, err := h.mailUsecase.PreviewTemplate(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return e.JSON(http.StatusOK, map[string]interface{}{
+		"subject":  mail.Subject,
+		"textBody": mail.TextBody,
+		"htmlBody": mail.HTMLBody,
+		"request":  req,
+	})
+}
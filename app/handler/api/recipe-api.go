@@ -0,0 +1,214 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"rap-c/app/entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	responseentity "rap-c/app/entity/response-entity"
+	"rap-c/app/handler"
+	"rap-c/app/usecase/contract"
+	"rap-c/config"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RecipeAPI interface {
+	// get recipe cost breakdown
+	GetCosting(e echo.Context) error
+	// get recipe prep list for requested portions
+	Scale(e echo.Context) error
+	// produce recipe from stock
+	Produce(e echo.Context) error
+	// save raw material costs of recipe & every recipe using it
+	RefreshCost(e echo.Context) error
+	// add ingredient or sub recipe line to recipe
+	CreateLine(e echo.Context) error
+	// change ingredient or sub recipe, unit & quantity of recipe line
+	UpdateLine(e echo.Context) error
+}
+
+func NewRecipeHandler(cfg *config.Config, router *config.Route,
+	recipeUsecase contract.RecipeUsecase, formatterUsecase contract.FormatterUsecase) RecipeAPI {
+	return &recipeHandler{
+		cfg:              cfg,
+		router:           router,
+		recipeUsecase:    recipeUsecase,
+		formatterUsecase: formatterUsecase,
+		BaseHandler:      handler.NewBaseHandler(cfg, router),
+	}
+}
+
+type recipeHandler struct {
+	cfg              *config.Config
+	router           *config.Route
+	recipeUsecase    contract.RecipeUsecase
+	formatterUsecase contract.FormatterUsecase
+	BaseHandler      *handler.BaseHandler
+}
+
+func (h *recipeHandler) GetCosting(e echo.Context) error {
+	ctx := e.Request().Context()
+	costing, err := h.recipeUsecase.GetCosting(ctx, e.Param("serial"))
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.formatterUsecase.FormatRecipeCosting(ctx, costing)
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, resp)
+}
+
+func (h *recipeHandler) Scale(e echo.Context) error {
+	req := new(payloadentity.ScaleRecipeRequest)
+	err := e.Bind(req)
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AllHandlerBindError, fmt.Sprintf("recipe-api.Scale bind error: %v", err)),
+		}
+	}
+	ctx := e.Request().Context()
+
+	scale, err := h.recipeUsecase.Scale(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.formatterUsecase.FormatRecipeScale(ctx, scale)
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, resp)
+}
+
+func (h *recipeHandler) Produce(e echo.Context) error {
+	payload := new(payloadentity.ProduceRecipePayload)
+	err := e.Bind(payload)
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AllHandlerBindError, fmt.Sprintf("recipe-api.Produce bind error: %v", err)),
+		}
+	}
+	ctx := e.Request().Context()
+
+	// get author
+	author, err := h.BaseHandler.GetAuthor(e)
+	if err != nil {
+		return err
+	}
+
+	// produce
+	production, err := h.recipeUsecase.Produce(ctx, payload, author)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.formatterUsecase.FormatRecipeProduction(ctx, production)
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, resp)
+}
+
+func (h *recipeHandler) RefreshCost(e echo.Context) error {
+	payload := new(payloadentity.RefreshRecipeCostPayload)
+	err := e.Bind(payload)
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AllHandlerBindError, fmt.Sprintf("recipe-api.RefreshCost bind error: %v", err)),
+		}
+	}
+	ctx := e.Request().Context()
+
+	// get author
+	author, err := h.BaseHandler.GetAuthor(e)
+	if err != nil {
+		return err
+	}
+
+	// refresh cost
+	costings, err := h.recipeUsecase.RefreshCost(ctx, payload, author)
+	if err != nil {
+		return err
+	}
+
+	resp := []*responseentity.RecipeCostingResponse{}
+	for _, costing := range costings {
+		itm, err := h.formatterUsecase.FormatRecipeCosting(ctx, costing)
+		if err != nil {
+			return err
+		}
+		resp = append(resp, itm)
+	}
+	return e.JSON(http.StatusOK, resp)
+}
+
+func (h *recipeHandler) CreateLine(e echo.Context) error {
+	payload := new(payloadentity.CreateRecipeIngredientPayload)
+	err := e.Bind(payload)
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AllHandlerBindError, fmt.Sprintf("recipe-api.CreateLine bind error: %v", err)),
+		}
+	}
+	ctx := e.Request().Context()
+
+	// get author
+	author, err := h.BaseHandler.GetAuthor(e)
+	if err != nil {
+		return err
+	}
+
+	// create line
+	line, err := h.recipeUsecase.CreateLine(ctx, payload, author)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.formatterUsecase.FormatRecipeIngredient(ctx, line, map[int]string{author.ID: author.Username})
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, resp)
+}
+
+func (h *recipeHandler) UpdateLine(e echo.Context) error {
+	payload := new(payloadentity.UpdateRecipeIngredientPayload)
+	err := e.Bind(payload)
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AllHandlerBindError, fmt.Sprintf("recipe-api.UpdateLine bind error: %v", err)),
+		}
+	}
+	ctx := e.Request().Context()
+
+	// get author
+	author, err := h.BaseHandler.GetAuthor(e)
+	if err != nil {
+		return err
+	}
+
+	// update line
+	line, err := h.recipeUsecase.UpdateLine(ctx, payload, author)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.formatterUsecase.FormatRecipeIngredient(ctx, line, map[int]string{author.ID: author.Username})
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, resp)
+}
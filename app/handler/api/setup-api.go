@@ -0,0 +1,64 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"rap-c/app/entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	"rap-c/app/handler"
+	"rap-c/app/usecase/contract"
+	"rap-c/config"
+
+	"github.com/labstack/echo/v4"
+)
+
+type SetupAPI interface {
+	// create owner account on first run
+	CreateOwner(e echo.Context) error
+}
+
+func NewSetupHandler(cfg *config.Config, router *config.Route, userUsecase contract.UserUsecase, authUsecase contract.AuthUsecase) SetupAPI {
+	return &setupHandler{
+		cfg:         cfg,
+		router:      router,
+		userUsecase: userUsecase,
+		authUsecase: authUsecase,
+		BaseHandler: handler.NewBaseHandler(cfg, router),
+	}
+}
+
+type setupHandler struct {
+	cfg         *config.Config
+	router      *config.Route
+	userUsecase contract.UserUsecase
+	authUsecase contract.AuthUsecase
+	BaseHandler *handler.BaseHandler
+}
+
+func (h *setupHandler) CreateOwner(e echo.Context) error {
+	payload := new(payloadentity.CreateOwnerPayload)
+	err := e.Bind(payload)
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AllHandlerBindError, fmt.Sprintf("setup-api.CreateOwner bind error: %v", err)),
+		}
+	}
+	ctx := e.Request().Context()
+
+	// create owner
+	user, err := h.userUsecase.CreateOwner(ctx, payload)
+	if err != nil {
+		return err
+	}
+
+	// generate token, owner is logged in right away
+	token, err := h.authUsecase.GenerateJwtToken(ctx, user, false)
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, map[string]interface{}{
+		"token": token,
+	})
+}
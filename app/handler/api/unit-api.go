@@ -22,6 +22,14 @@ type UnitAPI interface {
 	Create(e echo.Context) error
 	// delete not used unit
 	Delete(e echo.Context) error
+	// get unit detail with rows using it
+	GetUnitDetail(e echo.Context) error
+	// rename unit
+	Rename(e echo.Context) error
+	// merge unit into other unit
+	Merge(e echo.Context) error
+	// convert quantity between units
+	Convert(e echo.Context) error
 }
 
 func NewUnitHandler(cfg *config.Config, router *config.Route,
@@ -145,3 +153,101 @@ func (h *unitHandler) Delete(e echo.Context) error {
 		"status": "ok",
 	})
 }
+
+func (h *unitHandler) GetUnitDetail(e echo.Context) error {
+	ctx := e.Request().Context()
+	unit, usage, err := h.unitUsecase.GetUnitDetail(ctx, e.Param("name"))
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.formatterUsecase.FormatUnitDetail(ctx, unit, usage)
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, resp)
+}
+
+func (h *unitHandler) Rename(e echo.Context) error {
+	payload := new(payloadentity.RenameUnitPayload)
+	err := e.Bind(payload)
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AllHandlerBindError, fmt.Sprintf("unit-api.Rename bind error: %v", err)),
+		}
+	}
+	ctx := e.Request().Context()
+
+	// rename unit
+	unit, err := h.unitUsecase.Rename(ctx, payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.formatterUsecase.FormatUnit(ctx, unit, nil)
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, resp)
+}
+
+func (h *unitHandler) Merge(e echo.Context) error {
+	payload := new(payloadentity.MergeUnitPayload)
+	err := e.Bind(payload)
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AllHandlerBindError, fmt.Sprintf("unit-api.Merge bind error: %v", err)),
+		}
+	}
+	ctx := e.Request().Context()
+
+	// get author
+	author, err := h.BaseHandler.GetAuthor(e)
+	if err != nil {
+		return err
+	}
+
+	// merge unit, target unit is returned with its usage after merge
+	target, err := h.unitUsecase.Merge(ctx, payload, author)
+	if err != nil {
+		return err
+	}
+	_, usage, err := h.unitUsecase.GetUnitDetail(ctx, target.Name)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.formatterUsecase.FormatUnitDetail(ctx, target, usage)
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, resp)
+}
+
+func (h *unitHandler) Convert(e echo.Context) error {
+	req := new(payloadentity.ConvertUnitRequest)
+	err := e.Bind(req)
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AllHandlerBindError, fmt.Sprintf("unit-api.Convert bind error: %v", err)),
+		}
+	}
+	ctx := e.Request().Context()
+
+	conversion, err := h.unitUsecase.Convert(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.formatterUsecase.FormatUnitConversion(ctx, conversion)
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, resp)
+}
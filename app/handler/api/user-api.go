@@ -26,16 +26,23 @@ type UserAPI interface {
 	Update(e echo.Context) error
 	// activate or deactivate user
 	SetActiveStatusUser(e echo.Context) error
+	// resend verification link for current user unverified or pending email
+	ResendEmailVerification(e echo.Context) error
+	// verify email from verification link token
+	VerifyEmail(e echo.Context) error
+	// resend invitation to user that has not accepted invitation
+	ResendInvitation(e echo.Context) error
+	// revoke pending invitation
+	RevokeInvitation(e echo.Context) error
 }
 
 func NewUserHandler(cfg *config.Config, router *config.Route,
-	userUsecase contract.UserUsecase, formatterUsecase contract.FormatterUsecase, mailUsecase contract.MailUsecase) UserAPI {
+	userUsecase contract.UserUsecase, formatterUsecase contract.FormatterUsecase) UserAPI {
 	return &userHandler{
 		cfg:              cfg,
 		router:           router,
 		userUsecase:      userUsecase,
 		formatterUsecase: formatterUsecase,
-		mailUsecase:      mailUsecase,
 		BaseHandler:      handler.NewBaseHandler(cfg, router),
 	}
 }
@@ -45,7 +52,6 @@ type userHandler struct {
 	router           *config.Route
 	userUsecase      contract.UserUsecase
 	formatterUsecase contract.FormatterUsecase
-	mailUsecase      contract.MailUsecase
 	BaseHandler      *handler.BaseHandler
 }
 
@@ -67,37 +73,12 @@ func (h *userHandler) Create(e echo.Context) error {
 		return err
 	}
 
-	// create user
-	user, password, err := h.userUsecase.Create(ctx, payload, author)
+	// create user, welcome mail is queued with the invitation
+	user, err := h.userUsecase.Create(ctx, payload, author)
 	if err != nil {
 		return err
 	}
 
-	// send email
-	go func() {
-		entity.InitLog(
-			e.Request().RequestURI,
-			e.Request().Method,
-			fmt.Sprintf("Send welcome email to %s", user.Email),
-			http.StatusOK,
-			nil,
-			h.cfg.LogMode(),
-			false,
-		).Log()
-		err = h.mailUsecase.Welcome(user, password)
-		if err != nil {
-			entity.InitLog(
-				e.Request().RequestURI,
-				e.Request().Method,
-				"send welcome email",
-				http.StatusOK,
-				err,
-				h.cfg.LogMode(),
-				h.cfg.EnableWarnFileLog(),
-			).Log()
-		}
-	}()
-
 	resp, err := h.formatterUsecase.FormatUser(ctx, user, map[int]string{author.ID: author.Username})
 	if err != nil {
 		return err
@@ -203,31 +184,6 @@ func (h *userHandler) Update(e echo.Context) error {
 		return err
 	}
 
-	// send email
-	go func() {
-		entity.InitLog(
-			e.Request().RequestURI,
-			e.Request().Method,
-			fmt.Sprintf("Send update user email to %s", author.Email),
-			http.StatusOK,
-			nil,
-			h.cfg.LogMode(),
-			false,
-		).Log()
-		err = h.mailUsecase.UpdateUser(author)
-		if err != nil {
-			entity.InitLog(
-				e.Request().RequestURI,
-				e.Request().Method,
-				"send update user email",
-				http.StatusOK,
-				err,
-				h.cfg.LogMode(),
-				h.cfg.EnableWarnFileLog(),
-			).Log()
-		}
-	}()
-
 	resp, err := h.formatterUsecase.FormatUser(ctx, author, map[int]string{author.ID: author.Username})
 	if err != nil {
 		return err
@@ -259,30 +215,48 @@ func (h *userHandler) SetActiveStatusUser(e echo.Context) error {
 		return err
 	}
 
-	// send email
-	go func() {
-		entity.InitLog(
-			e.Request().RequestURI,
-			e.Request().Method,
-			fmt.Sprintf("Send update user status email to %s", user.Email),
-			http.StatusOK,
-			nil,
-			h.cfg.LogMode(),
-			false,
-		).Log()
-		err = h.mailUsecase.UpdateActiveStatusUser(user)
-		if err != nil {
-			entity.InitLog(
-				e.Request().RequestURI,
-				e.Request().Method,
-				"send update user status email",
-				http.StatusOK,
-				err,
-				h.cfg.LogMode(),
-				h.cfg.EnableWarnFileLog(),
-			).Log()
+	resp, err := h.formatterUsecase.FormatUser(ctx, user, nil)
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, resp)
+}
+
+func (h *userHandler) ResendEmailVerification(e echo.Context) error {
+	// get author
+	author, err := h.BaseHandler.GetAuthor(e)
+	if err != nil {
+		return err
+	}
+
+	// queue verification mail, fail when there is no email to verify
+	ctx := e.Request().Context()
+	err = h.userUsecase.ResendEmailVerification(ctx, author)
+	if err != nil {
+		return err
+	}
+
+	return e.JSON(http.StatusOK, map[string]interface{}{
+		"status": "email verification link has been sent",
+	})
+}
+
+func (h *userHandler) VerifyEmail(e echo.Context) error {
+	payload := new(payloadentity.VerifyEmailPayload)
+	err := e.Bind(payload)
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AllHandlerBindError, fmt.Sprintf("user-api.VerifyEmail bind error: %v", err)),
 		}
-	}()
+	}
+
+	ctx := e.Request().Context()
+	user, err := h.userUsecase.VerifyEmail(ctx, payload)
+	if err != nil {
+		return err
+	}
 
 	resp, err := h.formatterUsecase.FormatUser(ctx, user, nil)
 	if err != nil {
@@ -290,3 +264,61 @@ func (h *userHandler) SetActiveStatusUser(e echo.Context) error {
 	}
 	return e.JSON(http.StatusOK, resp)
 }
+
+func (h *userHandler) ResendInvitation(e echo.Context) error {
+	payload := new(payloadentity.UserInvitationPayload)
+	err := e.Bind(payload)
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AllHandlerBindError, fmt.Sprintf("user-api.ResendInvitation bind error: %v", err)),
+		}
+	}
+
+	// get author
+	author, err := h.BaseHandler.GetAuthor(e)
+	if err != nil {
+		return err
+	}
+
+	// issue new invitation
+	ctx := e.Request().Context()
+	_, err = h.userUsecase.ResendInvitation(ctx, payload, author)
+	if err != nil {
+		return err
+	}
+
+	return e.JSON(http.StatusOK, map[string]interface{}{
+		"status": "invitation has been sent",
+	})
+}
+
+func (h *userHandler) RevokeInvitation(e echo.Context) error {
+	payload := new(payloadentity.UserInvitationPayload)
+	err := e.Bind(payload)
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AllHandlerBindError, fmt.Sprintf("user-api.RevokeInvitation bind error: %v", err)),
+		}
+	}
+
+	// get author
+	author, err := h.BaseHandler.GetAuthor(e)
+	if err != nil {
+		return err
+	}
+
+	// revoke invitation
+	ctx := e.Request().Context()
+	_, err = h.userUsecase.RevokeInvitation(ctx, payload, author)
+	if err != nil {
+		return err
+	}
+
+	return e.JSON(http.StatusOK, map[string]interface{}{
+		"status": "invitation has been revoked",
+	})
+}
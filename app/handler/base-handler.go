@@ -168,6 +168,11 @@ func (h *BaseHandler) GetSidebarMenu(activeMenu string) []*SidebarMenu {
 					Html: `<i class="fa-regular fa-user"></i> <span>Daftar User</span>`,
 					Href: "/user",
 				},
+				{
+					Key:  "audit",
+					Html: `<i class="fa-solid fa-clock-rotate-left"></i> <span>Log Audit</span>`,
+					Href: "/audit",
+				},
 				{
 					Key:  "backup",
 					Html: `<i class="fa-solid fa-cloud-arrow-up"></i> <span>Backup Data</span>`,
@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+)
+
+// readable error message for terminal, validator error is printed as json
+func ErrorMessage(err error) string {
+	herr, ok := err.(*echo.HTTPError)
+	if !ok {
+		return err.Error()
+	}
+	if msg, ok := herr.Message.(string); ok {
+		if herr.Internal != nil && herr.Code >= 500 {
+			return fmt.Sprintf("%s: %v", msg, herr.Internal)
+		}
+		return msg
+	}
+	msg, jsonErr := json.MarshalIndent(herr.Message, "", "  ")
+	if jsonErr != nil {
+		return fmt.Sprint(herr.Message)
+	}
+	return string(msg)
+}
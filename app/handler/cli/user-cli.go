@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	"rap-c/app/usecase/contract"
+	"rap-c/config"
+)
+
+// ip address recorded for reset password request from cli
+const cliIPAddress string = "cli"
+
+type UserCLI interface {
+	// create user and queue invitation mail
+	Create(ctx context.Context, args []string) error
+	// queue reset password link mail
+	ResetPassword(ctx context.Context, args []string) error
+	// disable user
+	Disable(ctx context.Context, args []string) error
+}
+
+func NewUserCLI(cfg *config.Config, out io.Writer, userUsecase contract.UserUsecase,
+	authUsecase contract.AuthUsecase, mailUsecase contract.MailUsecase) UserCLI {
+	return &userHandler{
+		cfg:         cfg,
+		out:         out,
+		userUsecase: userUsecase,
+		authUsecase: authUsecase,
+		mailUsecase: mailUsecase,
+	}
+}
+
+type userHandler struct {
+	cfg         *config.Config
+	out         io.Writer
+	userUsecase contract.UserUsecase
+	authUsecase contract.AuthUsecase
+	mailUsecase contract.MailUsecase
+}
+
+func (h *userHandler) Create(ctx context.Context, args []string) error {
+	payload := new(payloadentity.CreateUserPayload)
+	fs := flag.NewFlagSet("user create", flag.ContinueOnError)
+	fs.SetOutput(h.out)
+	fs.StringVar(&payload.Username, "username", "", "username of new user")
+	fs.StringVar(&payload.FullName, "full-name", "", "full name of new user")
+	fs.StringVar(&payload.Email, "email", "", "email of new user, invitation link is sent to this email")
+	fs.BoolVar(&payload.IsGuest, "guest", false, "create user as guest")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	user, err := h.userUsecase.Create(ctx, payload, cliAuthor())
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(h.out, "User %s created, invitation sent to %s\n", user.Username, user.Email)
+	return h.sendMails(ctx)
+}
+
+func (h *userHandler) ResetPassword(ctx context.Context, args []string) error {
+	payload := &payloadentity.RequestResetPayload{IPAddress: cliIPAddress}
+	fs := flag.NewFlagSet("user reset-password", flag.ContinueOnError)
+	fs.SetOutput(h.out)
+	fs.StringVar(&payload.Email, "email", "", "email of user, reset password link is sent to this email")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	err = h.authUsecase.RequestResetPassword(ctx, payload)
+	if err != nil {
+		return err
+	}
+	// same as api, unregistered or unverified email is not revealed
+	fmt.Fprintf(h.out, "If %s is registered, a reset password link has been sent\n", payload.Email)
+	return h.sendMails(ctx)
+}
+
+func (h *userHandler) Disable(ctx context.Context, args []string) error {
+	payload := &payloadentity.ActiveStatusPayload{Disabled: true}
+	fs := flag.NewFlagSet("user disable", flag.ContinueOnError)
+	fs.SetOutput(h.out)
+	fs.StringVar(&payload.Username, "username", "", "username of user to disable")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	user, err := h.userUsecase.UpdateActiveStatus(ctx, payload, cliAuthor())
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(h.out, "User %s disabled\n", user.Username)
+	return h.sendMails(ctx)
+}
+
+// deliver queued mails right away, server may not be running to send them
+func (h *userHandler) sendMails(ctx context.Context) error {
+	total, err := h.mailUsecase.SendPendingMails(ctx)
+	if err != nil {
+		return err
+	}
+	if total == 0 {
+		fmt.Fprintln(h.out, "Mail not sent yet, it will be retried by mail worker")
+	}
+	return nil
+}
+
+// author of change made from cli, id 0 is not a real user
+func cliAuthor() *databaseentity.User {
+	return &databaseentity.User{ID: 0, Username: "cli"}
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"errors"
+	"rap-c/app/entity"
 	"rap-c/app/usecase/contract"
 	"rap-c/config"
 
@@ -36,6 +37,7 @@ func GetUserFromJWT(authUsecase contract.AuthUsecase, guestAccepted bool) echo.M
 				return err
 			}
 			c.Set(config.EchoJwtUserContextKey, user)
+			c.SetRequest(c.Request().WithContext(entity.ContextWithUserID(ctx, user.ID)))
 			return next(c)
 		}
 	}
@@ -1,21 +1,29 @@
 package middleware
 
 import (
+	"net/http"
 	"rap-c/app/entity"
-	"rap-c/config"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 )
 
-func SetLog(logMode config.LogMode, enableWarnFileLog bool) echo.MiddlewareFunc {
+// log every request except successful request to skipped paths, e.g. frequently probed health check
+func SetLog(skipPaths ...string) echo.MiddlewareFunc {
+	skip := make(map[string]bool, len(skipPaths))
+	for _, path := range skipPaths {
+		skip[path] = true
+	}
 	return middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
 		LogURI:      true,
 		LogStatus:   true,
 		LogError:    true,
 		HandleError: true, // forwards error to the global error handler, so it can decide appropriate status code
 		LogValuesFunc: func(c echo.Context, v middleware.RequestLoggerValues) error {
-			entity.InitLog(v.URI, c.Request().Method, "request", v.Status, v.Error, logMode, enableWarnFileLog).Log()
+			if skip[c.Path()] && v.Error == nil && v.Status < http.StatusBadRequest {
+				return nil
+			}
+			entity.InitLog(c.Request().Context(), v.URI, c.Request().Method, "request", v.Status, v.Error).Log()
 			return nil
 		},
 	})
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"rap-c/config"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// route label of request outside config.Route, e.g. assets & not found page
+const unknownRouteLabel string = "Unknown"
+
+// count requests & observe duration by route name in config.Route, method and status code.
+// registered before log middleware so error is already written to response
+func RequestMetrics(registerer prometheus.Registerer, router *config.Route) (echo.MiddlewareFunc, error) {
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "rapc",
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Total http requests by route, method and status code.",
+	}, []string{"route", "method", "status"})
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "rapc",
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of http requests in seconds by route and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method"})
+	for _, collector := range []prometheus.Collector{requests, duration} {
+		err := registerer.Register(collector)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+
+			method := c.Request().Method
+			route := router.Name(method, c.Path())
+			if route == "" {
+				route = unknownRouteLabel
+			}
+			status := c.Response().Status
+			if err != nil && !c.Response().Committed {
+				status = http.StatusInternalServerError
+				if he, ok := err.(*echo.HTTPError); ok {
+					status = he.Code
+				}
+			}
+
+			requests.WithLabelValues(route, method, strconv.Itoa(status)).Inc()
+			duration.WithLabelValues(route, method).Observe(time.Since(start).Seconds())
+			return err
+		}
+	}, nil
+}
+
+// require bearer token when token is set
+func BearerToken(token string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			given := c.Request().Header.Get(echo.HeaderAuthorization)
+			if token != "" && subtle.ConstantTimeCompare([]byte(given), []byte("Bearer "+token)) != 1 {
+				return echo.NewHTTPError(http.StatusUnauthorized)
+			}
+			return next(c)
+		}
+	}
+}
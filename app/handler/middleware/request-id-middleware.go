@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"rap-c/app/entity"
+	"rap-c/app/helper"
+	"regexp"
+
+	"github.com/labstack/echo/v4"
+)
+
+// accepted incoming request id, longer or unsafe value is replaced
+var requestIDPattern = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,64}$`)
+
+// set request id from X-Request-ID header or generate new one,
+// stored in request context for log & api error response, and returned as response header.
+// client ip address is stored along for audit log
+func RequestID() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			requestID := c.Request().Header.Get(echo.HeaderXRequestID)
+			if !requestIDPattern.MatchString(requestID) {
+				requestID, _ = helper.GenerateToken(12)
+			}
+
+			ctx := entity.ContextWithRequestID(c.Request().Context(), requestID)
+			ctx = entity.ContextWithIPAddress(ctx, c.RealIP())
+			c.SetRequest(c.Request().WithContext(ctx))
+			c.Response().Header().Set(echo.HeaderXRequestID, requestID)
+			return next(c)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"rap-c/app/entity"
 	"rap-c/app/usecase/contract"
 	"rap-c/config"
 
@@ -28,6 +29,7 @@ func ValidateJwtTokenFromSession(sessionUsecase contract.SessionUsecase, route *
 			// set context
 			c.Set(config.EchoJwtUserContextKey, user)
 			c.Set(config.EchoTokenContextKey, token)
+			c.SetRequest(c.Request().WithContext(entity.ContextWithUserID(c.Request().Context(), user.ID)))
 			return next(c)
 		}
 	}
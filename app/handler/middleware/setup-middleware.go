@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+	"rap-c/app/usecase/contract"
+	"rap-c/config"
+
+	"github.com/labstack/echo/v4"
+)
+
+// redirect to setup wizard while no non guest user exists
+func RedirectToSetup(userUsecase contract.UserUsecase, route *config.Route) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			required, err := userUsecase.IsSetupRequired(c.Request().Context())
+			if err != nil {
+				return err
+			}
+			if required {
+				return c.Redirect(http.StatusFound, route.SetupWebPage.Path())
+			}
+			return next(c)
+		}
+	}
+}
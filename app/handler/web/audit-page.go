@@ -0,0 +1,110 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"rap-c/app/entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	"rap-c/app/handler"
+	"rap-c/app/usecase/contract"
+	"rap-c/config"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AuditPage interface {
+	// audit log list page
+	AuditLogs(e echo.Context) error
+}
+
+func NewAuditPage(cfg *config.Config, router *config.Route, auditUsecase contract.AuditUsecase, formatterUsecase contract.FormatterUsecase) AuditPage {
+	return &auditHandler{
+		cfg:              cfg,
+		router:           router,
+		auditUsecase:     auditUsecase,
+		formatterUsecase: formatterUsecase,
+		BaseHandler:      handler.NewBaseHandler(cfg, router),
+	}
+}
+
+type auditHandler struct {
+	cfg              *config.Config
+	router           *config.Route
+	auditUsecase     contract.AuditUsecase
+	formatterUsecase contract.FormatterUsecase
+	BaseHandler      *handler.BaseHandler
+}
+
+func (h *auditHandler) AuditLogs(e echo.Context) error {
+	// get author
+	author, err := h.BaseHandler.GetAuthor(e)
+	if err != nil {
+		return err
+	}
+
+	// get token
+	token, err := h.BaseHandler.GetToken(e)
+	if err != nil {
+		return err
+	}
+
+	// bind filter, newest log first
+	req := &payloadentity.GetAuditLogListRequest{DescendingOrder: true}
+	err = e.Bind(req)
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AllHandlerBindError,
+				fmt.Sprintf("audit-page.AuditLogs bind error: %v", err)),
+		}
+	}
+	ctx := e.Request().Context()
+
+	logs, err := h.auditUsecase.GetAuditLogList(ctx, req)
+	if err != nil {
+		return err
+	}
+	total, err := h.auditUsecase.GetTotalAuditLogList(ctx, req)
+	if err != nil {
+		return err
+	}
+	resp, err := h.formatterUsecase.FormatAuditLogs(ctx, logs)
+	if err != nil {
+		return err
+	}
+
+	// pagination link keep current filter
+	pageURL := func(page int) string {
+		query := e.QueryParams()
+		values := make(url.Values, len(query))
+		for key, value := range query {
+			values[key] = value
+		}
+		values.Set("page", strconv.Itoa(page))
+		return fmt.Sprintf("%s?%s", h.router.AuditWebPage.Path(), values.Encode())
+	}
+	page := int(req.Page)
+	var prevPage, nextPage string
+	if page > 1 {
+		prevPage = pageURL(page - 1)
+	}
+	if int64(page*req.Limit) < total {
+		nextPage = pageURL(page + 1)
+	}
+
+	return e.Render(http.StatusOK, "audit", map[string]interface{}{
+		"author":   author,
+		"token":    token,
+		"title":    "Log Audit",
+		"layouts":  h.BaseHandler.GetLayouts("audit"),
+		"logs":     resp,
+		"total":    total,
+		"request":  req,
+		"page":     page,
+		"prevPage": prevPage,
+		"nextPage": nextPage,
+	})
+}
@@ -26,6 +26,8 @@ type AuthPage interface {
 	ForgotPassword(e echo.Context) error
 	// reset password page
 	ResetPassword(e echo.Context) error
+	// accept invitation page
+	AcceptInvitation(e echo.Context) error
 }
 
 func NewAuthPage(cfg *config.Config, router *config.Route, authUsecase contract.AuthUsecase, sessionUsecase contract.SessionUsecase, mailUsecase contract.MailUsecase) AuthPage {
@@ -210,3 +212,34 @@ func (h *authHandler) ResetPassword(e echo.Context) error {
 		"submitTokenSessionAction": h.cfg.URL(h.router.SubmitTokenSessionWebPage.Path()),
 	})
 }
+
+func (h *authHandler) AcceptInvitation(e echo.Context) error {
+	// bind payload
+	payload := new(payloadentity.ValidateInvitationPayload)
+	err := e.Bind(payload)
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AllHandlerBindError,
+				fmt.Sprintf("auth-page.AcceptInvitation bind error: %v", err)),
+		}
+	}
+
+	// validate invitation
+	ctx := e.Request().Context()
+	user, err := h.authUsecase.ValidateInvitation(ctx, payload)
+	if err != nil {
+		return err
+	}
+
+	return e.Render(http.StatusOK, "accept-invitation.html", map[string]interface{}{
+		"email":                    payload.Email,
+		"token":                    payload.Token,
+		"fullName":                 user.FullName,
+		"passwordMethod":           h.router.AcceptInvitationAPI.Method(),
+		"passwordAction":           h.router.AcceptInvitationAPI.Path(),
+		"submitTokenSessionMethod": h.router.SubmitTokenSessionWebPage.Method(),
+		"submitTokenSessionAction": h.cfg.URL(h.router.SubmitTokenSessionWebPage.Path()),
+	})
+}
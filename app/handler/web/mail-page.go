@@ -0,0 +1,59 @@
+package web
+
+import (
+	"net/http"
+	"path"
+	"rap-c/app/usecase/contract"
+	"rap-c/config"
+
+	"github.com/labstack/echo/v4"
+)
+
+type MailPage interface {
+	// list of mails captured by file mail transport
+	CapturedMails(e echo.Context) error
+	// render captured mail html body
+	CapturedMailDetail(e echo.Context) error
+}
+
+func NewMailPage(cfg *config.Config, router *config.Route, mailUsecase contract.MailUsecase) MailPage {
+	return &mailHandler{
+		cfg:         cfg,
+		router:      router,
+		mailUsecase: mailUsecase,
+	}
+}
+
+type mailHandler struct {
+	cfg         *config.Config
+	router      *config.Route
+	mailUsecase contract.MailUsecase
+}
+
+func (h *mailHandler) CapturedMails(e echo.Context) error {
+	ctx := e.Request().Context()
+	mails, err := h.mailUsecase.GetCapturedMails(ctx)
+	if err != nil {
+		return err
+	}
+
+	return e.Render(http.StatusOK, "captured-mail.html", map[string]interface{}{
+		"mails":      mails,
+		"dir":        h.cfg.MailFileDir(),
+		"detailPath": path.Dir(h.router.CapturedMailDetailWebPage.Path()),
+	})
+}
+
+func (h *mailHandler) CapturedMailDetail(e echo.Context) error {
+	ctx := e.Request().Context()
+	mail, err := h.mailUsecase.GetCapturedMail(ctx, e.Param("name"))
+	if err != nil {
+		return err
+	}
+
+	// mail without html part shown as plain text
+	if mail.HTMLBody == "" {
+		return e.String(http.StatusOK, mail.TextBody)
+	}
+	return e.HTML(http.StatusOK, mail.HTMLBody)
+}
@@ -0,0 +1,50 @@
+package web
+
+import (
+	"net/http"
+	"rap-c/app/handler"
+	"rap-c/app/usecase/contract"
+	"rap-c/config"
+
+	"github.com/labstack/echo/v4"
+)
+
+type SetupPage interface {
+	// first run setup wizard, only available while no non guest user exists
+	Setup(e echo.Context) error
+}
+
+func NewSetupPage(cfg *config.Config, router *config.Route, userUsecase contract.UserUsecase) SetupPage {
+	return &setupHandler{
+		cfg:         cfg,
+		router:      router,
+		userUsecase: userUsecase,
+		BaseHandler: handler.NewBaseHandler(cfg, router),
+	}
+}
+
+type setupHandler struct {
+	cfg         *config.Config
+	router      *config.Route
+	userUsecase contract.UserUsecase
+	BaseHandler *handler.BaseHandler
+}
+
+func (h *setupHandler) Setup(e echo.Context) error {
+	// setup page disappears once owner is created
+	ctx := e.Request().Context()
+	required, err := h.userUsecase.IsSetupRequired(ctx)
+	if err != nil {
+		return err
+	}
+	if !required {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	return e.Render(http.StatusOK, "setup.html", map[string]interface{}{
+		"formMethod":               h.router.SetupAPI.Method(),
+		"formAction":               h.cfg.URL(h.router.SetupAPI.Path()),
+		"submitTokenSessionMethod": h.router.SubmitTokenSessionWebPage.Method(),
+		"submitTokenSessionAction": h.cfg.URL(h.router.SubmitTokenSessionWebPage.Path()),
+	})
+}
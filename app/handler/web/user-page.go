@@ -1,7 +1,10 @@
 package web
 
 import (
+	"fmt"
 	"net/http"
+	"rap-c/app/entity"
+	payloadentity "rap-c/app/entity/payload-entity"
 	"rap-c/app/handler"
 	"rap-c/app/usecase/contract"
 	"rap-c/config"
@@ -12,6 +15,8 @@ import (
 type UserPage interface {
 	// profile page
 	Profile(e echo.Context) error
+	// verify email from verification link
+	VerifyEmail(e echo.Context) error
 }
 
 func NewUserPage(cfg *config.Config, router *config.Route, sessionUsecase contract.SessionUsecase, userUsecase contract.UserUsecase, mailUsecase contract.MailUsecase) UserPage {
@@ -56,3 +61,30 @@ func (h *userHandler) Profile(e echo.Context) error {
 		"formUpdateAction": h.router.UpdateUserAPI.Path(),
 	})
 }
+
+func (h *userHandler) VerifyEmail(e echo.Context) error {
+	// bind payload
+	payload := new(payloadentity.VerifyEmailPayload)
+	err := e.Bind(payload)
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AllHandlerBindError,
+				fmt.Sprintf("user-page.VerifyEmail bind error: %v", err)),
+		}
+	}
+
+	// verify
+	ctx := e.Request().Context()
+	user, err := h.userUsecase.VerifyEmail(ctx, payload)
+	if err != nil {
+		return err
+	}
+
+	return e.Render(http.StatusOK, "verify-email.html", map[string]interface{}{
+		"email":     user.Email,
+		"username":  user.Username,
+		"loginPath": h.router.LoginWebPage.Path(),
+	})
+}
@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"rap-c/app/entity"
+	"rap-c/app/usecase/contract"
+	"rap-c/config"
+	"time"
+)
+
+const (
+	mailWorkerName string = "mail-worker"
+)
+
+type MailWorker interface {
+	// send pending outbox mails every interval until context is done,
+	// running batch is finished before returning so no mail is cut off in the middle
+	Run(ctx context.Context)
+}
+
+func NewMailWorker(cfg *config.Config, mailUsecase contract.MailUsecase) MailWorker {
+	return &mailWorker{cfg, mailUsecase}
+}
+
+type mailWorker struct {
+	cfg         *config.Config
+	mailUsecase contract.MailUsecase
+}
+
+func (w *mailWorker) Run(ctx context.Context) {
+	interval := w.cfg.OutboxInterval()
+	if interval < 1 {
+		interval = 1
+	}
+	ticker := time.NewTicker(time.Second * time.Duration(interval))
+	defer ticker.Stop()
+
+	// batch keeps context values but is not cancelled with context
+	batchCtx := context.WithoutCancel(ctx)
+	for {
+		w.sendPendingMails(batchCtx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *mailWorker) sendPendingMails(ctx context.Context) {
+	total, err := w.mailUsecase.SendPendingMails(ctx)
+	if err != nil {
+		entity.InitLog(
+			ctx,
+			mailWorkerName,
+			"",
+			"send pending mails",
+			http.StatusInternalServerError,
+			err,
+		).Log()
+		return
+	}
+	if total > 0 {
+		entity.InitLog(
+			ctx,
+			mailWorkerName,
+			"",
+			fmt.Sprintf("%d mails sent", total),
+			http.StatusOK,
+			nil,
+		).Log()
+	}
+}
@@ -0,0 +1,37 @@
+package helper
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// unique constraint violation on any database driver (mysql 1062, postgres 23505, sqlite unique & primary key constraint)
+func IsDuplicateKeyError(db *gorm.DB, err error) bool {
+	return isDBError(db, err, gorm.ErrDuplicatedKey)
+}
+
+// row still referenced by other table, or referenced row not exists, on any database driver
+// (mysql 1451 & 1452, postgres 23503, sqlite foreign key constraint)
+func IsForeignKeyError(db *gorm.DB, err error) bool {
+	return isDBError(db, err, gorm.ErrForeignKeyViolated)
+}
+
+// first or take query found no row
+func IsNotFoundError(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound)
+}
+
+// translate driver error with gorm dialector, original error is kept so its message can still be read
+func isDBError(db *gorm.DB, err error, target error) bool {
+	translator, ok := db.Dialector.(gorm.ErrorTranslator)
+	if !ok {
+		return errors.Is(err, target)
+	}
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if errors.Is(translator.Translate(e), target) {
+			return true
+		}
+	}
+	return false
+}
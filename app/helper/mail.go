@@ -0,0 +1,25 @@
+package helper
+
+import (
+	"net/mail"
+	databaseentity "rap-c/app/entity/database-entity"
+
+	"github.com/go-gomail/gomail"
+)
+
+// build mime message of outbox mail, shared by smtp & file mail transport
+func NewMailMessage(senderName, senderAddress string, outbox *databaseentity.MailOutbox) *gomail.Message {
+	from := mail.Address{
+		Name:    senderName,
+		Address: senderAddress,
+	}
+
+	m := gomail.NewMessage()
+	m.SetHeader("From", from.String())
+	m.SetHeader("To", outbox.Recipient)
+	m.SetHeader("Subject", outbox.Subject)
+
+	m.SetBody("text/plain", outbox.TextBody)
+	m.AddAlternative("text/html", outbox.HTMLBody)
+	return m
+}
@@ -2,7 +2,10 @@ package helper
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
+	"strings"
 )
 
 func GenerateToken(length int) (string, error) {
@@ -13,3 +16,19 @@ func GenerateToken(length int) (string, error) {
 	}
 	return base64.URLEncoding.EncodeToString(bytes), nil
 }
+
+// hash token before stored, so leaked rows cannot be used as token
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// serial of new row, random hex is appended to prefix until it has length characters
+func GenerateSerial(prefix string, length int) (string, error) {
+	bytes := make([]byte, (length-len(prefix)+1)/2)
+	_, err := rand.Read(bytes)
+	if err != nil {
+		return "", err
+	}
+	return prefix + strings.ToUpper(hex.EncodeToString(bytes))[:length-len(prefix)], nil
+}
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"embed"
+	"io/fs"
+	"rap-c/app/handler/api"
+	ingredientrepository "rap-c/app/repository/mysql/ingredient-repository"
+	ingredientusecase "rap-c/app/usecase/ingredient-usecase"
+	"rap-c/route"
+)
+
+//go:embed migrations/ingredient
+var ingredientMigrations embed.FS
+
+// ingredient weight bridging volume & count units into mass, needs unit module
+func NewIngredientModule() Module {
+	return &ingredientModule{}
+}
+
+type ingredientModule struct{}
+
+func (m *ingredientModule) Name() string {
+	return "ingredient"
+}
+
+func (m *ingredientModule) Migrations() fs.FS {
+	return moduleMigrations(ingredientMigrations, m.Name())
+}
+
+func (m *ingredientModule) Load(c *Container) error {
+	if c.UnitUsecase == nil {
+		return errMissing(m, "unit")
+	}
+	c.IngredientRepository = ingredientrepository.New(c.DB)
+	c.IngredientUsecase = ingredientusecase.NewUsecase(c.Config, c.IngredientRepository)
+	return nil
+}
+
+func (m *ingredientModule) Routes(g *route.Group, c *Container) {
+	route.SetIngredientAPI(g, api.NewIngredientHandler(c.Config, c.Route, c.IngredientUsecase, c.FormatterUsecase))
+}
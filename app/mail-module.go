@@ -0,0 +1,42 @@
+package app
+
+import (
+	"embed"
+	"io/fs"
+	"rap-c/app/handler/api"
+	"rap-c/app/handler/web"
+	filesender "rap-c/app/repository/mail/file-sender"
+	outboxrepository "rap-c/app/repository/mysql/outbox-repository"
+	mailusecase "rap-c/app/usecase/mail-usecase"
+	"rap-c/route"
+)
+
+//go:embed migrations/mail
+var mailMigrations embed.FS
+
+// mail outbox, dead letter api & captured mail pages
+func NewMailModule() Module {
+	return &mailModule{}
+}
+
+type mailModule struct{}
+
+func (m *mailModule) Name() string {
+	return "mail"
+}
+
+func (m *mailModule) Migrations() fs.FS {
+	return moduleMigrations(mailMigrations, m.Name())
+}
+
+func (m *mailModule) Load(c *Container) error {
+	c.OutboxRepository = outboxrepository.New(c.DB)
+	c.CapturedMailRepository = filesender.NewCapturedMailRepository(c.Config.MailFileDir())
+	c.MailUsecase = mailusecase.NewUsecase(c.Config, c.Route, c.OutboxRepository, c.MailSender, c.CapturedMailRepository)
+	return nil
+}
+
+func (m *mailModule) Routes(g *route.Group, c *Container) {
+	route.SetMailAPI(g, api.NewMailHandler(c.Config, c.Route, c.MailUsecase, c.FormatterUsecase))
+	route.SetDevWebPage(g, web.NewMailPage(c.Config, c.Route, c.MailUsecase))
+}
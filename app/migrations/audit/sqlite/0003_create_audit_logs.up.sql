@@ -0,0 +1,18 @@
+-- changes on auditable models, written by gorm hooks in the same transaction as the change
+CREATE TABLE IF NOT EXISTS "audit_logs" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "actor_id" bigint NOT NULL DEFAULT 0,
+    "action" varchar(10) NOT NULL,
+    "entity_type" varchar(30) NOT NULL,
+    "entity_id" bigint NOT NULL,
+    "serial" varchar(100) NOT NULL DEFAULT '',
+    "before_data" text,
+    "after_data" text,
+    "ip_address" varchar(45) NOT NULL DEFAULT '',
+    "request_id" varchar(64) NOT NULL DEFAULT '',
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_actor" ON "audit_logs" ("actor_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_entity" ON "audit_logs" ("entity_type", "entity_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_request" ON "audit_logs" ("request_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_created_at" ON "audit_logs" ("created_at");
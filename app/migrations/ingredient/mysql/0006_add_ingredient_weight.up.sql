@@ -0,0 +1,3 @@
+-- density in gram per milliliter & weight of one piece in gram, 0 when unknown
+ALTER TABLE `ingredients` ADD COLUMN `density` decimal(10,4) NOT NULL DEFAULT 0;
+ALTER TABLE `ingredients` ADD COLUMN `piece_weight` decimal(10,2) NOT NULL DEFAULT 0;
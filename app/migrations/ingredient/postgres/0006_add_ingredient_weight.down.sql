@@ -0,0 +1,2 @@
+ALTER TABLE "ingredients" DROP COLUMN "piece_weight";
+ALTER TABLE "ingredients" DROP COLUMN "density";
@@ -0,0 +1,16 @@
+-- owned by mail module, table already created by older initial schema is kept as is
+CREATE TABLE IF NOT EXISTS "mail_outboxes" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "recipient" varchar(100) NOT NULL,
+    "subject" varchar(255) NOT NULL,
+    "text_body" text NOT NULL,
+    "html_body" text NOT NULL,
+    "status" varchar(10) NOT NULL DEFAULT 'pending',
+    "attempts" bigint NOT NULL DEFAULT 0,
+    "next_attempt_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "last_error" text,
+    "sent_at" timestamp NULL,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_mail_outboxes_status_next_attempt" ON "mail_outboxes" ("status", "next_attempt_at");
@@ -0,0 +1,10 @@
+-- sub recipe lines cannot be kept without ingredient
+ALTER TABLE `recipe_ingredients` DROP CHECK `chk_recipe_ingredients_source`;
+ALTER TABLE `recipe_ingredients` DROP FOREIGN KEY `fk_recipe_ingredients_sub_recipe`;
+DELETE FROM `recipe_ingredients` WHERE `sub_recipe_id` IS NOT NULL;
+ALTER TABLE `recipe_ingredients` DROP COLUMN `sub_recipe_id`;
+ALTER TABLE `recipe_ingredients` MODIFY `ingredient_id` bigint NOT NULL;
+
+ALTER TABLE `recipes` DROP FOREIGN KEY `fk_recipes_unit`;
+ALTER TABLE `recipes` DROP COLUMN `stock`;
+ALTER TABLE `recipes` DROP COLUMN `unit_id`;
@@ -0,0 +1,7 @@
+-- recipe movements cannot be kept without ingredient
+ALTER TABLE `stock_movements` DROP CHECK `chk_stock_movements_source`;
+ALTER TABLE `stock_movements` DROP FOREIGN KEY `fk_stock_movements_recipe`;
+DELETE FROM `stock_movements` WHERE `recipe_id` IS NOT NULL;
+ALTER TABLE `stock_movements` DROP COLUMN `recipe_id`;
+ALTER TABLE `stock_movements` MODIFY `ingredient_id` bigint NOT NULL;
+ALTER TABLE `stock_movements` MODIFY `quantity` bigint NOT NULL DEFAULT 0;
@@ -0,0 +1,8 @@
+-- sub recipe lines cannot be kept without ingredient
+ALTER TABLE "recipe_ingredients" DROP CONSTRAINT "chk_recipe_ingredients_source";
+DELETE FROM "recipe_ingredients" WHERE "sub_recipe_id" IS NOT NULL;
+ALTER TABLE "recipe_ingredients" DROP COLUMN "sub_recipe_id";
+ALTER TABLE "recipe_ingredients" ALTER COLUMN "ingredient_id" SET NOT NULL;
+
+ALTER TABLE "recipes" DROP COLUMN "stock";
+ALTER TABLE "recipes" DROP COLUMN "unit_id";
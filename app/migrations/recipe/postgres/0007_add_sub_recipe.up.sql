@@ -0,0 +1,10 @@
+-- recipe quantity is counted in its yield unit, stock is what has been produced & not consumed yet
+ALTER TABLE "recipes" ADD COLUMN "unit_id" bigint NULL;
+ALTER TABLE "recipes" ADD COLUMN "stock" decimal(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE "recipes" ADD CONSTRAINT "fk_recipes_unit" FOREIGN KEY ("unit_id") REFERENCES "units"("id");
+
+-- recipe line uses either an ingredient or another recipe
+ALTER TABLE "recipe_ingredients" ALTER COLUMN "ingredient_id" DROP NOT NULL;
+ALTER TABLE "recipe_ingredients" ADD COLUMN "sub_recipe_id" bigint NULL;
+ALTER TABLE "recipe_ingredients" ADD CONSTRAINT "fk_recipe_ingredients_sub_recipe" FOREIGN KEY ("sub_recipe_id") REFERENCES "recipes"("id");
+ALTER TABLE "recipe_ingredients" ADD CONSTRAINT "chk_recipe_ingredients_source" CHECK (("ingredient_id" IS NULL) <> ("sub_recipe_id" IS NULL));
@@ -0,0 +1,6 @@
+-- recipe movements cannot be kept without ingredient
+ALTER TABLE "stock_movements" DROP CONSTRAINT "chk_stock_movements_source";
+DELETE FROM "stock_movements" WHERE "recipe_id" IS NOT NULL;
+ALTER TABLE "stock_movements" DROP COLUMN "recipe_id";
+ALTER TABLE "stock_movements" ALTER COLUMN "ingredient_id" SET NOT NULL;
+ALTER TABLE "stock_movements" ALTER COLUMN "quantity" TYPE bigint;
@@ -0,0 +1,6 @@
+-- stock movement tracks either an ingredient or a recipe, quantity keeps decimals of stock
+ALTER TABLE "stock_movements" ALTER COLUMN "ingredient_id" DROP NOT NULL;
+ALTER TABLE "stock_movements" ADD COLUMN "recipe_id" bigint NULL;
+ALTER TABLE "stock_movements" ALTER COLUMN "quantity" TYPE decimal(10,2);
+ALTER TABLE "stock_movements" ADD CONSTRAINT "fk_stock_movements_recipe" FOREIGN KEY ("recipe_id") REFERENCES "recipes"("id");
+ALTER TABLE "stock_movements" ADD CONSTRAINT "chk_stock_movements_source" CHECK (("ingredient_id" IS NULL) <> ("recipe_id" IS NULL));
@@ -0,0 +1,24 @@
+-- sub recipe lines cannot be kept without ingredient
+CREATE TABLE "recipe_ingredients_old" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "serial" varchar(11) NOT NULL,
+    "recipe_id" bigint NOT NULL,
+    "ingredient_id" bigint NOT NULL,
+    "unit_id" bigint NOT NULL,
+    "quantity" decimal(10,2) NOT NULL DEFAULT 0,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "created_by" bigint NOT NULL DEFAULT 0,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_by" bigint NOT NULL DEFAULT 0,
+    CONSTRAINT "fk_recipe_ingredients_recipe" FOREIGN KEY ("recipe_id") REFERENCES "recipes"("id"),
+    CONSTRAINT "fk_recipe_ingredients_ingredient" FOREIGN KEY ("ingredient_id") REFERENCES "ingredients"("id"),
+    CONSTRAINT "fk_recipe_ingredients_unit" FOREIGN KEY ("unit_id") REFERENCES "units"("id"),
+    CONSTRAINT "uni_recipe_ingredients_serial" UNIQUE ("serial")
+);
+INSERT INTO "recipe_ingredients_old" ("id", "serial", "recipe_id", "ingredient_id", "unit_id", "quantity", "created_at", "created_by", "updated_at", "updated_by")
+    SELECT "id", "serial", "recipe_id", "ingredient_id", "unit_id", "quantity", "created_at", "created_by", "updated_at", "updated_by" FROM "recipe_ingredients" WHERE "sub_recipe_id" IS NULL;
+DROP TABLE "recipe_ingredients";
+ALTER TABLE "recipe_ingredients_old" RENAME TO "recipe_ingredients";
+
+ALTER TABLE "recipes" DROP COLUMN "stock";
+ALTER TABLE "recipes" DROP COLUMN "unit_id";
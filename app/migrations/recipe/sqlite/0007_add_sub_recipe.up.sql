@@ -0,0 +1,28 @@
+-- recipe quantity is counted in its yield unit, stock is what has been produced & not consumed yet
+ALTER TABLE "recipes" ADD COLUMN "unit_id" bigint NULL REFERENCES "units"("id");
+ALTER TABLE "recipes" ADD COLUMN "stock" decimal(10,2) NOT NULL DEFAULT 0;
+
+-- recipe line uses either an ingredient or another recipe, sqlite cannot alter column so table is rebuilt
+CREATE TABLE "recipe_ingredients_new" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "serial" varchar(11) NOT NULL,
+    "recipe_id" bigint NOT NULL,
+    "ingredient_id" bigint NULL,
+    "sub_recipe_id" bigint NULL,
+    "unit_id" bigint NOT NULL,
+    "quantity" decimal(10,2) NOT NULL DEFAULT 0,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "created_by" bigint NOT NULL DEFAULT 0,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_by" bigint NOT NULL DEFAULT 0,
+    CONSTRAINT "fk_recipe_ingredients_recipe" FOREIGN KEY ("recipe_id") REFERENCES "recipes"("id"),
+    CONSTRAINT "fk_recipe_ingredients_ingredient" FOREIGN KEY ("ingredient_id") REFERENCES "ingredients"("id"),
+    CONSTRAINT "fk_recipe_ingredients_sub_recipe" FOREIGN KEY ("sub_recipe_id") REFERENCES "recipes"("id"),
+    CONSTRAINT "fk_recipe_ingredients_unit" FOREIGN KEY ("unit_id") REFERENCES "units"("id"),
+    CONSTRAINT "uni_recipe_ingredients_serial" UNIQUE ("serial"),
+    CONSTRAINT "chk_recipe_ingredients_source" CHECK (("ingredient_id" IS NULL) <> ("sub_recipe_id" IS NULL))
+);
+INSERT INTO "recipe_ingredients_new" ("id", "serial", "recipe_id", "ingredient_id", "unit_id", "quantity", "created_at", "created_by", "updated_at", "updated_by")
+    SELECT "id", "serial", "recipe_id", "ingredient_id", "unit_id", "quantity", "created_at", "created_by", "updated_at", "updated_by" FROM "recipe_ingredients";
+DROP TABLE "recipe_ingredients";
+ALTER TABLE "recipe_ingredients_new" RENAME TO "recipe_ingredients";
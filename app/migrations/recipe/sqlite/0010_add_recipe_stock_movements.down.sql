@@ -0,0 +1,16 @@
+-- recipe movements cannot be kept without ingredient
+CREATE TABLE "stock_movements_old" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "ingredient_id" bigint NOT NULL,
+    "movement_type" varchar(20) NOT NULL,
+    "quantity" bigint NOT NULL DEFAULT 0,
+    "description" varchar(100),
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "created_by" bigint NOT NULL DEFAULT 0,
+    CONSTRAINT "fk_stock_movements_ingredient" FOREIGN KEY ("ingredient_id") REFERENCES "ingredients"("id")
+);
+INSERT INTO "stock_movements_old" ("id", "ingredient_id", "movement_type", "quantity", "description", "created_at", "created_by")
+    SELECT "id", "ingredient_id", "movement_type", "quantity", "description", "created_at", "created_by" FROM "stock_movements" WHERE "recipe_id" IS NULL;
+DROP TABLE "stock_movements";
+ALTER TABLE "stock_movements_old" RENAME TO "stock_movements";
+CREATE INDEX IF NOT EXISTS "idx_stock_movements_movement_type" ON "stock_movements" ("movement_type");
@@ -0,0 +1,20 @@
+-- stock movement tracks either an ingredient or a recipe, quantity keeps decimals of stock.
+-- sqlite cannot alter column so table is rebuilt
+CREATE TABLE "stock_movements_new" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "ingredient_id" bigint NULL,
+    "recipe_id" bigint NULL,
+    "movement_type" varchar(20) NOT NULL,
+    "quantity" decimal(10,2) NOT NULL DEFAULT 0,
+    "description" varchar(100),
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "created_by" bigint NOT NULL DEFAULT 0,
+    CONSTRAINT "fk_stock_movements_ingredient" FOREIGN KEY ("ingredient_id") REFERENCES "ingredients"("id"),
+    CONSTRAINT "fk_stock_movements_recipe" FOREIGN KEY ("recipe_id") REFERENCES "recipes"("id"),
+    CONSTRAINT "chk_stock_movements_source" CHECK (("ingredient_id" IS NULL) <> ("recipe_id" IS NULL))
+);
+INSERT INTO "stock_movements_new" ("id", "ingredient_id", "movement_type", "quantity", "description", "created_at", "created_by")
+    SELECT "id", "ingredient_id", "movement_type", "quantity", "description", "created_at", "created_by" FROM "stock_movements";
+DROP TABLE "stock_movements";
+ALTER TABLE "stock_movements_new" RENAME TO "stock_movements";
+CREATE INDEX IF NOT EXISTS "idx_stock_movements_movement_type" ON "stock_movements" ("movement_type");
@@ -0,0 +1,3 @@
+-- standard units are kept, they may be used by ingredients & recipes
+ALTER TABLE `units` DROP COLUMN `factor`;
+ALTER TABLE `units` DROP COLUMN `dimension`;
@@ -0,0 +1,15 @@
+-- factor is size of unit in base unit of its dimension: gram, milliliter & piece
+ALTER TABLE `units` ADD COLUMN `dimension` varchar(10) NOT NULL DEFAULT '';
+ALTER TABLE `units` ADD COLUMN `factor` decimal(18,6) NOT NULL DEFAULT 0;
+
+-- standard units, existing unit with the same name becomes standard unit
+INSERT INTO `units` (`name`, `dimension`, `factor`) VALUES
+    ('g', 'mass', 1),
+    ('ons', 'mass', 100),
+    ('kg', 'mass', 1000),
+    ('ml', 'volume', 1),
+    ('sdt', 'volume', 5),
+    ('sdm', 'volume', 15),
+    ('l', 'volume', 1000),
+    ('butir', 'count', 1)
+ON DUPLICATE KEY UPDATE `dimension` = VALUES(`dimension`), `factor` = VALUES(`factor`);
@@ -0,0 +1 @@
+-- cleared guest password cannot be restored, guest login does not need it
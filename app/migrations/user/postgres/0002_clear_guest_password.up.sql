@@ -0,0 +1,2 @@
+-- guest login no longer uses shared password, clear the old one so it cannot be used on login form
+UPDATE "users" SET "password" = '' WHERE "is_guest" = true;
@@ -0,0 +1,6 @@
+-- single row taken by the setup wizard, primary key makes concurrent setup wait then fail instead of creating two owners
+CREATE TABLE IF NOT EXISTS "setup_locks" (
+    "id" bigint NOT NULL,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id")
+);
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"io/fs"
+	"rap-c/app/handler/api"
+	healthrepository "rap-c/app/repository/mysql/health-repository"
+	metricrepository "rap-c/app/repository/mysql/metric-repository"
+	healthusecase "rap-c/app/usecase/health-usecase"
+	metricusecase "rap-c/app/usecase/metric-usecase"
+	"rap-c/route"
+)
+
+// health probes & business metrics
+func NewMonitorModule() Module {
+	return &monitorModule{}
+}
+
+type monitorModule struct{}
+
+func (m *monitorModule) Name() string {
+	return "monitor"
+}
+
+func (m *monitorModule) Migrations() fs.FS {
+	return nil
+}
+
+func (m *monitorModule) Load(c *Container) error {
+	c.HealthRepository = healthrepository.New(c.DB, c.Migrations...)
+	c.MetricRepository = metricrepository.New(c.DB)
+	c.MetricUsecase = metricusecase.NewUsecase(c.Config, c.MetricRepository)
+	c.Collectors = append(c.Collectors, c.MetricUsecase)
+	return nil
+}
+
+func (m *monitorModule) Routes(g *route.Group, c *Container) {
+	healthUsecase := healthusecase.NewUsecase(c.Config, c.HealthRepository, c.MailSender, c.Renderer)
+	route.SetMonitorRoute(g, c.Gatherer, api.NewHealthHandler(c.Config, healthUsecase))
+}
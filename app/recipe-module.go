@@ -0,0 +1,44 @@
+package app
+
+import (
+	"embed"
+	"io/fs"
+	"rap-c/app/handler/api"
+	reciperepository "rap-c/app/repository/mysql/recipe-repository"
+	recipeusecase "rap-c/app/usecase/recipe-usecase"
+	"rap-c/route"
+)
+
+//go:embed migrations/recipe
+var recipeMigrations embed.FS
+
+// recipe costing, needs ingredient module
+func NewRecipeModule() Module {
+	return &recipeModule{}
+}
+
+type recipeModule struct{}
+
+func (m *recipeModule) Name() string {
+	return "recipe"
+}
+
+func (m *recipeModule) Migrations() fs.FS {
+	return moduleMigrations(recipeMigrations, m.Name())
+}
+
+func (m *recipeModule) Load(c *Container) error {
+	if c.IngredientUsecase == nil {
+		return errMissing(m, "ingredient")
+	}
+	c.RecipeRepository = reciperepository.New(c.DB)
+	c.RecipeUsecase = recipeusecase.NewUsecase(c.Config, c.RecipeRepository, c.IngredientRepository, c.UnitRepository, c.UnitUsecase)
+	// merging units & changing ingredient weight change recipe costs, saved costs follow them
+	c.UnitUsecase = recipeusecase.NewCostRefreshingUnitUsecase(c.UnitUsecase, c.RecipeUsecase)
+	c.IngredientUsecase = recipeusecase.NewCostRefreshingIngredientUsecase(c.IngredientUsecase, c.RecipeUsecase)
+	return nil
+}
+
+func (m *recipeModule) Routes(g *route.Group, c *Container) {
+	route.SetRecipeAPI(g, api.NewRecipeHandler(c.Config, c.Route, c.RecipeUsecase, c.FormatterUsecase))
+}
@@ -0,0 +1,14 @@
+package contract
+
+import (
+	"context"
+	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+)
+
+type AuditRepository interface {
+	// get total audit logs by request param
+	GetTotalAuditLogsByRequest(ctx context.Context, req *payloadentity.GetAuditLogListRequest) (int64, error)
+	// get audit logs by request param
+	GetAuditLogsByRequest(ctx context.Context, req *payloadentity.GetAuditLogListRequest) ([]*databaseentity.AuditLog, error)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	databaseentity "rap-c/app/entity/database-entity"
 	payloadentity "rap-c/app/entity/payload-entity"
+	"time"
 )
 
 type AuthRepository interface {
@@ -11,10 +12,14 @@ type AuthRepository interface {
 	DoUserLogin(ctx context.Context, payload *payloadentity.AttemptLoginPayload) (*databaseentity.User, error)
 	// renew password
 	DoRenewPassword(ctx context.Context, user *databaseentity.User, payload *payloadentity.RenewPasswordPayload) error
+	// get guest user, guest login has no password
+	GetGuestUser(ctx context.Context) (*databaseentity.User, error)
 	// get user by email
 	GetUserByEmail(ctx context.Context, email string) (*databaseentity.User, error)
-	// set user reset password
-	GenerateUserResetPassword(ctx context.Context, payload *payloadentity.RequestResetPayload) (*databaseentity.PasswordResetToken, error)
+	// save user reset password token, previous unused tokens for the same email are revoked and reset mails are queued in the same transaction
+	GenerateUserResetPassword(ctx context.Context, reset *databaseentity.PasswordResetToken, mails ...*databaseentity.MailOutbox) error
+	// get total reset password request by field: email, ip_address since given time
+	GetTotalResetRequestsByField(ctx context.Context, fieldName string, fieldValue string, since time.Time) (int64, error)
 	// validate reset password token
 	ValidateResetToken(ctx context.Context, payload *payloadentity.ValidateResetTokenPayload) (*databaseentity.PasswordResetToken, error)
 	// reset password
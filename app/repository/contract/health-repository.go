@@ -0,0 +1,10 @@
+package contract
+
+import "context"
+
+type HealthRepository interface {
+	// ping database connection
+	Ping(ctx context.Context) error
+	// get total schema migrations not applied yet
+	GetTotalPendingMigrations(ctx context.Context) (int, error)
+}
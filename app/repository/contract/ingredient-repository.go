@@ -0,0 +1,13 @@
+package contract
+
+import (
+	"context"
+	databaseentity "rap-c/app/entity/database-entity"
+)
+
+type IngredientRepository interface {
+	// get ingredient by serial, unit is preloaded
+	GetIngredientBySerial(ctx context.Context, serial string) (*databaseentity.Ingredient, error)
+	// update ingredient
+	Update(ctx context.Context, ingredient *databaseentity.Ingredient) error
+}
@@ -0,0 +1,17 @@
+package contract
+
+import (
+	"context"
+	databaseentity "rap-c/app/entity/database-entity"
+)
+
+type InvitationRepository interface {
+	// save new invitation, previous pending invitations of the user are revoked and invitation mails are queued in the same transaction
+	Create(ctx context.Context, invitation *databaseentity.UserInvitation, mails ...*databaseentity.MailOutbox) error
+	// get valid (not accepted, not revoked, not expired) invitation by plain token
+	GetValidInvitation(ctx context.Context, token string) (*databaseentity.UserInvitation, error)
+	// save user password and mark invitation as accepted
+	Accept(ctx context.Context, user *databaseentity.User, invitation *databaseentity.UserInvitation) error
+	// revoke pending invitations of user, return total revoked invitations
+	Revoke(ctx context.Context, user *databaseentity.User, author *databaseentity.User) (int64, error)
+}
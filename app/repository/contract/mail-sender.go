@@ -0,0 +1,20 @@
+package contract
+
+import (
+	"context"
+	databaseentity "rap-c/app/entity/database-entity"
+)
+
+type MailSender interface {
+	// deliver single outbox mail through configured transport
+	Send(ctx context.Context, mail *databaseentity.MailOutbox) error
+	// check transport is reachable without sending mail
+	Ping(ctx context.Context) error
+}
+
+type CapturedMailRepository interface {
+	// get mails captured by file mail transport, newest first
+	GetCapturedMails(ctx context.Context) ([]*databaseentity.CapturedMail, error)
+	// get captured mail by file name
+	GetCapturedMail(ctx context.Context, name string) (*databaseentity.CapturedMail, error)
+}
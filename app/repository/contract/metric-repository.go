@@ -0,0 +1,12 @@
+package contract
+
+import "context"
+
+type MetricRepository interface {
+	// get total active non guest users
+	GetTotalActiveUsers(ctx context.Context) (int64, error)
+	// get total ingredients with stock at or below threshold
+	GetTotalLowStockIngredients(ctx context.Context, threshold float64) (int64, error)
+	// get total mails waiting in outbox
+	GetTotalPendingMails(ctx context.Context) (int64, error)
+}
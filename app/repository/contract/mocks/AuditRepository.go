@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: audit-repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAuditRepository is a mock of AuditRepository interface.
+type MockAuditRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditRepositoryMockRecorder
+}
+
+// MockAuditRepositoryMockRecorder is the mock recorder for MockAuditRepository.
+type MockAuditRepositoryMockRecorder struct {
+	mock *MockAuditRepository
+}
+
+// NewMockAuditRepository creates a new mock instance.
+func NewMockAuditRepository(ctrl *gomock.Controller) *MockAuditRepository {
+	mock := &MockAuditRepository{ctrl: ctrl}
+	mock.recorder = &MockAuditRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditRepository) EXPECT() *MockAuditRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetAuditLogsByRequest mocks base method.
+func (m *MockAuditRepository) GetAuditLogsByRequest(ctx context.Context, req *payloadentity.GetAuditLogListRequest) ([]*databaseentity.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLogsByRequest", ctx, req)
+	ret0, _ := ret[0].([]*databaseentity.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLogsByRequest indicates an expected call of GetAuditLogsByRequest.
+func (mr *MockAuditRepositoryMockRecorder) GetAuditLogsByRequest(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogsByRequest", reflect.TypeOf((*MockAuditRepository)(nil).GetAuditLogsByRequest), ctx, req)
+}
+
+// GetTotalAuditLogsByRequest mocks base method.
+func (m *MockAuditRepository) GetTotalAuditLogsByRequest(ctx context.Context, req *payloadentity.GetAuditLogListRequest) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTotalAuditLogsByRequest", ctx, req)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTotalAuditLogsByRequest indicates an expected call of GetTotalAuditLogsByRequest.
+func (mr *MockAuditRepositoryMockRecorder) GetTotalAuditLogsByRequest(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotalAuditLogsByRequest", reflect.TypeOf((*MockAuditRepository)(nil).GetTotalAuditLogsByRequest), ctx, req)
+}
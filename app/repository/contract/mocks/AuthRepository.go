@@ -9,6 +9,7 @@ import (
 	databaseentity "rap-c/app/entity/database-entity"
 	payloadentity "rap-c/app/entity/payload-entity"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -80,18 +81,52 @@ func (mr *MockAuthRepositoryMockRecorder) DoUserLogin(ctx, payload interface{})
 }
 
 // GenerateUserResetPassword mocks base method.
-func (m *MockAuthRepository) GenerateUserResetPassword(ctx context.Context, payload *payloadentity.RequestResetPayload) (*databaseentity.PasswordResetToken, error) {
+func (m *MockAuthRepository) GenerateUserResetPassword(ctx context.Context, reset *databaseentity.PasswordResetToken, mails ...*databaseentity.MailOutbox) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GenerateUserResetPassword", ctx, payload)
-	ret0, _ := ret[0].(*databaseentity.PasswordResetToken)
+	varargs := []interface{}{ctx, reset}
+	for _, a := range mails {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GenerateUserResetPassword", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GenerateUserResetPassword indicates an expected call of GenerateUserResetPassword.
+func (mr *MockAuthRepositoryMockRecorder) GenerateUserResetPassword(ctx, reset interface{}, mails ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, reset}, mails...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateUserResetPassword", reflect.TypeOf((*MockAuthRepository)(nil).GenerateUserResetPassword), varargs...)
+}
+
+// GetGuestUser mocks base method.
+func (m *MockAuthRepository) GetGuestUser(ctx context.Context) (*databaseentity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGuestUser", ctx)
+	ret0, _ := ret[0].(*databaseentity.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GenerateUserResetPassword indicates an expected call of GenerateUserResetPassword.
-func (mr *MockAuthRepositoryMockRecorder) GenerateUserResetPassword(ctx, payload interface{}) *gomock.Call {
+// GetGuestUser indicates an expected call of GetGuestUser.
+func (mr *MockAuthRepositoryMockRecorder) GetGuestUser(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGuestUser", reflect.TypeOf((*MockAuthRepository)(nil).GetGuestUser), ctx)
+}
+
+// GetTotalResetRequestsByField mocks base method.
+func (m *MockAuthRepository) GetTotalResetRequestsByField(ctx context.Context, fieldName, fieldValue string, since time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTotalResetRequestsByField", ctx, fieldName, fieldValue, since)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTotalResetRequestsByField indicates an expected call of GetTotalResetRequestsByField.
+func (mr *MockAuthRepositoryMockRecorder) GetTotalResetRequestsByField(ctx, fieldName, fieldValue, since interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateUserResetPassword", reflect.TypeOf((*MockAuthRepository)(nil).GenerateUserResetPassword), ctx, payload)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotalResetRequestsByField", reflect.TypeOf((*MockAuthRepository)(nil).GetTotalResetRequestsByField), ctx, fieldName, fieldValue, since)
 }
 
 // GetUserByEmail mocks base method.
@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ingredient-repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	databaseentity "rap-c/app/entity/database-entity"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIngredientRepository is a mock of IngredientRepository interface.
+type MockIngredientRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIngredientRepositoryMockRecorder
+}
+
+// MockIngredientRepositoryMockRecorder is the mock recorder for MockIngredientRepository.
+type MockIngredientRepositoryMockRecorder struct {
+	mock *MockIngredientRepository
+}
+
+// NewMockIngredientRepository creates a new mock instance.
+func NewMockIngredientRepository(ctrl *gomock.Controller) *MockIngredientRepository {
+	mock := &MockIngredientRepository{ctrl: ctrl}
+	mock.recorder = &MockIngredientRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIngredientRepository) EXPECT() *MockIngredientRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetIngredientBySerial mocks base method.
+func (m *MockIngredientRepository) GetIngredientBySerial(ctx context.Context, serial string) (*databaseentity.Ingredient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngredientBySerial", ctx, serial)
+	ret0, _ := ret[0].(*databaseentity.Ingredient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIngredientBySerial indicates an expected call of GetIngredientBySerial.
+func (mr *MockIngredientRepositoryMockRecorder) GetIngredientBySerial(ctx, serial interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngredientBySerial", reflect.TypeOf((*MockIngredientRepository)(nil).GetIngredientBySerial), ctx, serial)
+}
+
+// Update mocks base method.
+func (m *MockIngredientRepository) Update(ctx context.Context, ingredient *databaseentity.Ingredient) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, ingredient)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockIngredientRepositoryMockRecorder) Update(ctx, ingredient interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockIngredientRepository)(nil).Update), ctx, ingredient)
+}
@@ -0,0 +1,117 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: mail-sender.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	databaseentity "rap-c/app/entity/database-entity"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockMailSender is a mock of MailSender interface.
+type MockMailSender struct {
+	ctrl     *gomock.Controller
+	recorder *MockMailSenderMockRecorder
+}
+
+// MockMailSenderMockRecorder is the mock recorder for MockMailSender.
+type MockMailSenderMockRecorder struct {
+	mock *MockMailSender
+}
+
+// NewMockMailSender creates a new mock instance.
+func NewMockMailSender(ctrl *gomock.Controller) *MockMailSender {
+	mock := &MockMailSender{ctrl: ctrl}
+	mock.recorder = &MockMailSenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMailSender) EXPECT() *MockMailSenderMockRecorder {
+	return m.recorder
+}
+
+// Ping mocks base method.
+func (m *MockMailSender) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockMailSenderMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockMailSender)(nil).Ping), ctx)
+}
+
+// Send mocks base method.
+func (m *MockMailSender) Send(ctx context.Context, mail *databaseentity.MailOutbox) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", ctx, mail)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockMailSenderMockRecorder) Send(ctx, mail interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockMailSender)(nil).Send), ctx, mail)
+}
+
+// MockCapturedMailRepository is a mock of CapturedMailRepository interface.
+type MockCapturedMailRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCapturedMailRepositoryMockRecorder
+}
+
+// MockCapturedMailRepositoryMockRecorder is the mock recorder for MockCapturedMailRepository.
+type MockCapturedMailRepositoryMockRecorder struct {
+	mock *MockCapturedMailRepository
+}
+
+// NewMockCapturedMailRepository creates a new mock instance.
+func NewMockCapturedMailRepository(ctrl *gomock.Controller) *MockCapturedMailRepository {
+	mock := &MockCapturedMailRepository{ctrl: ctrl}
+	mock.recorder = &MockCapturedMailRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCapturedMailRepository) EXPECT() *MockCapturedMailRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetCapturedMail mocks base method.
+func (m *MockCapturedMailRepository) GetCapturedMail(ctx context.Context, name string) (*databaseentity.CapturedMail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCapturedMail", ctx, name)
+	ret0, _ := ret[0].(*databaseentity.CapturedMail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCapturedMail indicates an expected call of GetCapturedMail.
+func (mr *MockCapturedMailRepositoryMockRecorder) GetCapturedMail(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCapturedMail", reflect.TypeOf((*MockCapturedMailRepository)(nil).GetCapturedMail), ctx, name)
+}
+
+// GetCapturedMails mocks base method.
+func (m *MockCapturedMailRepository) GetCapturedMails(ctx context.Context) ([]*databaseentity.CapturedMail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCapturedMails", ctx)
+	ret0, _ := ret[0].([]*databaseentity.CapturedMail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCapturedMails indicates an expected call of GetCapturedMails.
+func (mr *MockCapturedMailRepositoryMockRecorder) GetCapturedMails(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCapturedMails", reflect.TypeOf((*MockCapturedMailRepository)(nil).GetCapturedMails), ctx)
+}
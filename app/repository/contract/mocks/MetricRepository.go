@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: metric-repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockMetricRepository is a mock of MetricRepository interface.
+type MockMetricRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetricRepositoryMockRecorder
+}
+
+// MockMetricRepositoryMockRecorder is the mock recorder for MockMetricRepository.
+type MockMetricRepositoryMockRecorder struct {
+	mock *MockMetricRepository
+}
+
+// NewMockMetricRepository creates a new mock instance.
+func NewMockMetricRepository(ctrl *gomock.Controller) *MockMetricRepository {
+	mock := &MockMetricRepository{ctrl: ctrl}
+	mock.recorder = &MockMetricRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetricRepository) EXPECT() *MockMetricRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetTotalActiveUsers mocks base method.
+func (m *MockMetricRepository) GetTotalActiveUsers(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTotalActiveUsers", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTotalActiveUsers indicates an expected call of GetTotalActiveUsers.
+func (mr *MockMetricRepositoryMockRecorder) GetTotalActiveUsers(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotalActiveUsers", reflect.TypeOf((*MockMetricRepository)(nil).GetTotalActiveUsers), ctx)
+}
+
+// GetTotalLowStockIngredients mocks base method.
+func (m *MockMetricRepository) GetTotalLowStockIngredients(ctx context.Context, threshold float64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTotalLowStockIngredients", ctx, threshold)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTotalLowStockIngredients indicates an expected call of GetTotalLowStockIngredients.
+func (mr *MockMetricRepositoryMockRecorder) GetTotalLowStockIngredients(ctx, threshold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotalLowStockIngredients", reflect.TypeOf((*MockMetricRepository)(nil).GetTotalLowStockIngredients), ctx, threshold)
+}
+
+// GetTotalPendingMails mocks base method.
+func (m *MockMetricRepository) GetTotalPendingMails(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTotalPendingMails", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTotalPendingMails indicates an expected call of GetTotalPendingMails.
+func (mr *MockMetricRepositoryMockRecorder) GetTotalPendingMails(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotalPendingMails", reflect.TypeOf((*MockMetricRepository)(nil).GetTotalPendingMails), ctx)
+}
@@ -0,0 +1,131 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: outbox-repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOutboxRepository is a mock of OutboxRepository interface.
+type MockOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxRepositoryMockRecorder
+}
+
+// MockOutboxRepositoryMockRecorder is the mock recorder for MockOutboxRepository.
+type MockOutboxRepositoryMockRecorder struct {
+	mock *MockOutboxRepository
+}
+
+// NewMockOutboxRepository creates a new mock instance.
+func NewMockOutboxRepository(ctrl *gomock.Controller) *MockOutboxRepository {
+	mock := &MockOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxRepository) EXPECT() *MockOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimPendingMails mocks base method.
+func (m *MockOutboxRepository) ClaimPendingMails(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*databaseentity.MailOutbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimPendingMails", ctx, now, limit, lease)
+	ret0, _ := ret[0].([]*databaseentity.MailOutbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimPendingMails indicates an expected call of ClaimPendingMails.
+func (mr *MockOutboxRepositoryMockRecorder) ClaimPendingMails(ctx, now, limit, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimPendingMails", reflect.TypeOf((*MockOutboxRepository)(nil).ClaimPendingMails), ctx, now, limit, lease)
+}
+
+// Create mocks base method.
+func (m *MockOutboxRepository) Create(ctx context.Context, mails ...*databaseentity.MailOutbox) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range mails {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Create", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOutboxRepositoryMockRecorder) Create(ctx interface{}, mails ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, mails...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOutboxRepository)(nil).Create), varargs...)
+}
+
+// GetDeadLettersByRequest mocks base method.
+func (m *MockOutboxRepository) GetDeadLettersByRequest(ctx context.Context, req *payloadentity.GetDeadLetterListRequest) ([]*databaseentity.MailOutbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeadLettersByRequest", ctx, req)
+	ret0, _ := ret[0].([]*databaseentity.MailOutbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeadLettersByRequest indicates an expected call of GetDeadLettersByRequest.
+func (mr *MockOutboxRepositoryMockRecorder) GetDeadLettersByRequest(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeadLettersByRequest", reflect.TypeOf((*MockOutboxRepository)(nil).GetDeadLettersByRequest), ctx, req)
+}
+
+// GetMailByID mocks base method.
+func (m *MockOutboxRepository) GetMailByID(ctx context.Context, id int) (*databaseentity.MailOutbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMailByID", ctx, id)
+	ret0, _ := ret[0].(*databaseentity.MailOutbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMailByID indicates an expected call of GetMailByID.
+func (mr *MockOutboxRepositoryMockRecorder) GetMailByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMailByID", reflect.TypeOf((*MockOutboxRepository)(nil).GetMailByID), ctx, id)
+}
+
+// GetTotalDeadLettersByRequest mocks base method.
+func (m *MockOutboxRepository) GetTotalDeadLettersByRequest(ctx context.Context, req *payloadentity.GetDeadLetterListRequest) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTotalDeadLettersByRequest", ctx, req)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTotalDeadLettersByRequest indicates an expected call of GetTotalDeadLettersByRequest.
+func (mr *MockOutboxRepositoryMockRecorder) GetTotalDeadLettersByRequest(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotalDeadLettersByRequest", reflect.TypeOf((*MockOutboxRepository)(nil).GetTotalDeadLettersByRequest), ctx, req)
+}
+
+// Update mocks base method.
+func (m *MockOutboxRepository) Update(ctx context.Context, mail *databaseentity.MailOutbox) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, mail)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockOutboxRepositoryMockRecorder) Update(ctx, mail interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockOutboxRepository)(nil).Update), ctx, mail)
+}
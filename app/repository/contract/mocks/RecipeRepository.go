@@ -0,0 +1,197 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: recipe-repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	databaseentity "rap-c/app/entity/database-entity"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRecipeRepository is a mock of RecipeRepository interface.
+type MockRecipeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecipeRepositoryMockRecorder
+}
+
+// MockRecipeRepositoryMockRecorder is the mock recorder for MockRecipeRepository.
+type MockRecipeRepositoryMockRecorder struct {
+	mock *MockRecipeRepository
+}
+
+// NewMockRecipeRepository creates a new mock instance.
+func NewMockRecipeRepository(ctrl *gomock.Controller) *MockRecipeRepository {
+	mock := &MockRecipeRepository{ctrl: ctrl}
+	mock.recorder = &MockRecipeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecipeRepository) EXPECT() *MockRecipeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateRecipeIngredient mocks base method.
+func (m *MockRecipeRepository) CreateRecipeIngredient(ctx context.Context, line *databaseentity.RecipeIngredient) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRecipeIngredient", ctx, line)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRecipeIngredient indicates an expected call of CreateRecipeIngredient.
+func (mr *MockRecipeRepositoryMockRecorder) CreateRecipeIngredient(ctx, line interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecipeIngredient", reflect.TypeOf((*MockRecipeRepository)(nil).CreateRecipeIngredient), ctx, line)
+}
+
+// GetIngredientConversions mocks base method.
+func (m *MockRecipeRepository) GetIngredientConversions(ctx context.Context, ingredientIDs []int) ([]*databaseentity.IngredientConvertionUnit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngredientConversions", ctx, ingredientIDs)
+	ret0, _ := ret[0].([]*databaseentity.IngredientConvertionUnit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIngredientConversions indicates an expected call of GetIngredientConversions.
+func (mr *MockRecipeRepositoryMockRecorder) GetIngredientConversions(ctx, ingredientIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngredientConversions", reflect.TypeOf((*MockRecipeRepository)(nil).GetIngredientConversions), ctx, ingredientIDs)
+}
+
+// GetParentRecipes mocks base method.
+func (m *MockRecipeRepository) GetParentRecipes(ctx context.Context, recipe *databaseentity.Recipe) ([]*databaseentity.Recipe, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetParentRecipes", ctx, recipe)
+	ret0, _ := ret[0].([]*databaseentity.Recipe)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetParentRecipes indicates an expected call of GetParentRecipes.
+func (mr *MockRecipeRepositoryMockRecorder) GetParentRecipes(ctx, recipe interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParentRecipes", reflect.TypeOf((*MockRecipeRepository)(nil).GetParentRecipes), ctx, recipe)
+}
+
+// GetRecipeBySerial mocks base method.
+func (m *MockRecipeRepository) GetRecipeBySerial(ctx context.Context, serial string) (*databaseentity.Recipe, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecipeBySerial", ctx, serial)
+	ret0, _ := ret[0].(*databaseentity.Recipe)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecipeBySerial indicates an expected call of GetRecipeBySerial.
+func (mr *MockRecipeRepositoryMockRecorder) GetRecipeBySerial(ctx, serial interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipeBySerial", reflect.TypeOf((*MockRecipeRepository)(nil).GetRecipeBySerial), ctx, serial)
+}
+
+// GetRecipeIngredientBySerial mocks base method.
+func (m *MockRecipeRepository) GetRecipeIngredientBySerial(ctx context.Context, serial string) (*databaseentity.RecipeIngredient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecipeIngredientBySerial", ctx, serial)
+	ret0, _ := ret[0].(*databaseentity.RecipeIngredient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecipeIngredientBySerial indicates an expected call of GetRecipeIngredientBySerial.
+func (mr *MockRecipeRepositoryMockRecorder) GetRecipeIngredientBySerial(ctx, serial interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipeIngredientBySerial", reflect.TypeOf((*MockRecipeRepository)(nil).GetRecipeIngredientBySerial), ctx, serial)
+}
+
+// GetRecipeIngredients mocks base method.
+func (m *MockRecipeRepository) GetRecipeIngredients(ctx context.Context, recipe *databaseentity.Recipe) ([]*databaseentity.RecipeIngredient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecipeIngredients", ctx, recipe)
+	ret0, _ := ret[0].([]*databaseentity.RecipeIngredient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecipeIngredients indicates an expected call of GetRecipeIngredients.
+func (mr *MockRecipeRepositoryMockRecorder) GetRecipeIngredients(ctx, recipe interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipeIngredients", reflect.TypeOf((*MockRecipeRepository)(nil).GetRecipeIngredients), ctx, recipe)
+}
+
+// GetRecipesByIngredient mocks base method.
+func (m *MockRecipeRepository) GetRecipesByIngredient(ctx context.Context, ingredient *databaseentity.Ingredient) ([]*databaseentity.Recipe, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecipesByIngredient", ctx, ingredient)
+	ret0, _ := ret[0].([]*databaseentity.Recipe)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecipesByIngredient indicates an expected call of GetRecipesByIngredient.
+func (mr *MockRecipeRepositoryMockRecorder) GetRecipesByIngredient(ctx, ingredient interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipesByIngredient", reflect.TypeOf((*MockRecipeRepository)(nil).GetRecipesByIngredient), ctx, ingredient)
+}
+
+// GetRecipesByUnit mocks base method.
+func (m *MockRecipeRepository) GetRecipesByUnit(ctx context.Context, unit *databaseentity.Unit) ([]*databaseentity.Recipe, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecipesByUnit", ctx, unit)
+	ret0, _ := ret[0].([]*databaseentity.Recipe)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecipesByUnit indicates an expected call of GetRecipesByUnit.
+func (mr *MockRecipeRepositoryMockRecorder) GetRecipesByUnit(ctx, unit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipesByUnit", reflect.TypeOf((*MockRecipeRepository)(nil).GetRecipesByUnit), ctx, unit)
+}
+
+// Produce mocks base method.
+func (m *MockRecipeRepository) Produce(ctx context.Context, production *databaseentity.RecipeProduction, authorID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Produce", ctx, production, authorID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Produce indicates an expected call of Produce.
+func (mr *MockRecipeRepositoryMockRecorder) Produce(ctx, production, authorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Produce", reflect.TypeOf((*MockRecipeRepository)(nil).Produce), ctx, production, authorID)
+}
+
+// UpdateRawMaterialCosts mocks base method.
+func (m *MockRecipeRepository) UpdateRawMaterialCosts(ctx context.Context, recipes []*databaseentity.Recipe) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRawMaterialCosts", ctx, recipes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRawMaterialCosts indicates an expected call of UpdateRawMaterialCosts.
+func (mr *MockRecipeRepositoryMockRecorder) UpdateRawMaterialCosts(ctx, recipes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRawMaterialCosts", reflect.TypeOf((*MockRecipeRepository)(nil).UpdateRawMaterialCosts), ctx, recipes)
+}
+
+// UpdateRecipeIngredient mocks base method.
+func (m *MockRecipeRepository) UpdateRecipeIngredient(ctx context.Context, line *databaseentity.RecipeIngredient) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRecipeIngredient", ctx, line)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRecipeIngredient indicates an expected call of UpdateRecipeIngredient.
+func (mr *MockRecipeRepositoryMockRecorder) UpdateRecipeIngredient(ctx, line interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRecipeIngredient", reflect.TypeOf((*MockRecipeRepository)(nil).UpdateRecipeIngredient), ctx, line)
+}
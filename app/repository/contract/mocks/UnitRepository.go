@@ -64,6 +64,22 @@ func (mr *MockUnitRepositoryMockRecorder) Delete(ctx, unit interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUnitRepository)(nil).Delete), ctx, unit)
 }
 
+// GetIngredientConversions mocks base method.
+func (m *MockUnitRepository) GetIngredientConversions(ctx context.Context, serial string) (*databaseentity.Ingredient, []*databaseentity.IngredientConvertionUnit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngredientConversions", ctx, serial)
+	ret0, _ := ret[0].(*databaseentity.Ingredient)
+	ret1, _ := ret[1].([]*databaseentity.IngredientConvertionUnit)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetIngredientConversions indicates an expected call of GetIngredientConversions.
+func (mr *MockUnitRepositoryMockRecorder) GetIngredientConversions(ctx, serial interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngredientConversions", reflect.TypeOf((*MockUnitRepository)(nil).GetIngredientConversions), ctx, serial)
+}
+
 // GetTotalUnitsByRequest mocks base method.
 func (m *MockUnitRepository) GetTotalUnitsByRequest(ctx context.Context, req *payloadentity.GetUnitListRequest) (int64, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +110,36 @@ func (mr *MockUnitRepositoryMockRecorder) GetUnitByName(ctx, name interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnitByName", reflect.TypeOf((*MockUnitRepository)(nil).GetUnitByName), ctx, name)
 }
 
+// GetUnitUsage mocks base method.
+func (m *MockUnitRepository) GetUnitUsage(ctx context.Context, unit *databaseentity.Unit) (*databaseentity.UnitUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnitUsage", ctx, unit)
+	ret0, _ := ret[0].(*databaseentity.UnitUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnitUsage indicates an expected call of GetUnitUsage.
+func (mr *MockUnitRepositoryMockRecorder) GetUnitUsage(ctx, unit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnitUsage", reflect.TypeOf((*MockUnitRepository)(nil).GetUnitUsage), ctx, unit)
+}
+
+// GetUnitsByDimension mocks base method.
+func (m *MockUnitRepository) GetUnitsByDimension(ctx context.Context, dimension string) ([]*databaseentity.Unit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnitsByDimension", ctx, dimension)
+	ret0, _ := ret[0].([]*databaseentity.Unit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnitsByDimension indicates an expected call of GetUnitsByDimension.
+func (mr *MockUnitRepositoryMockRecorder) GetUnitsByDimension(ctx, dimension interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnitsByDimension", reflect.TypeOf((*MockUnitRepository)(nil).GetUnitsByDimension), ctx, dimension)
+}
+
 // GetUnitsByRequest mocks base method.
 func (m *MockUnitRepository) GetUnitsByRequest(ctx context.Context, req *payloadentity.GetUnitListRequest) ([]*databaseentity.Unit, error) {
 	m.ctrl.T.Helper()
@@ -108,3 +154,31 @@ func (mr *MockUnitRepositoryMockRecorder) GetUnitsByRequest(ctx, req interface{}
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnitsByRequest", reflect.TypeOf((*MockUnitRepository)(nil).GetUnitsByRequest), ctx, req)
 }
+
+// Merge mocks base method.
+func (m *MockUnitRepository) Merge(ctx context.Context, unit, target *databaseentity.Unit, authorID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, unit, target, authorID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockUnitRepositoryMockRecorder) Merge(ctx, unit, target, authorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockUnitRepository)(nil).Merge), ctx, unit, target, authorID)
+}
+
+// Update mocks base method.
+func (m *MockUnitRepository) Update(ctx context.Context, unit *databaseentity.Unit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, unit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockUnitRepositoryMockRecorder) Update(ctx, unit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUnitRepository)(nil).Update), ctx, unit)
+}
@@ -37,17 +37,51 @@ func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockUserRepository) Create(ctx context.Context, user *databaseentity.User) error {
+func (m *MockUserRepository) Create(ctx context.Context, user *databaseentity.User, invitation *databaseentity.UserInvitation, mails ...*databaseentity.MailOutbox) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, user)
+	varargs := []interface{}{ctx, user, invitation}
+	for _, a := range mails {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Create", varargs...)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockUserRepositoryMockRecorder) Create(ctx, user interface{}) *gomock.Call {
+func (mr *MockUserRepositoryMockRecorder) Create(ctx, user, invitation interface{}, mails ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserRepository)(nil).Create), ctx, user)
+	varargs := append([]interface{}{ctx, user, invitation}, mails...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserRepository)(nil).Create), varargs...)
+}
+
+// CreateOwner mocks base method.
+func (m *MockUserRepository) CreateOwner(ctx context.Context, user *databaseentity.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOwner", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOwner indicates an expected call of CreateOwner.
+func (mr *MockUserRepositoryMockRecorder) CreateOwner(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOwner", reflect.TypeOf((*MockUserRepository)(nil).CreateOwner), ctx, user)
+}
+
+// GetTotalNonGuestUsers mocks base method.
+func (m *MockUserRepository) GetTotalNonGuestUsers(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTotalNonGuestUsers", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTotalNonGuestUsers indicates an expected call of GetTotalNonGuestUsers.
+func (mr *MockUserRepositoryMockRecorder) GetTotalNonGuestUsers(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotalNonGuestUsers", reflect.TypeOf((*MockUserRepository)(nil).GetTotalNonGuestUsers), ctx)
 }
 
 // GetTotalUsersByRequest mocks base method.
@@ -111,15 +145,20 @@ func (mr *MockUserRepositoryMockRecorder) MapUserUsername(ctx, userIDs interface
 }
 
 // Update mocks base method.
-func (m *MockUserRepository) Update(ctx context.Context, user *databaseentity.User) error {
+func (m *MockUserRepository) Update(ctx context.Context, user *databaseentity.User, mails ...*databaseentity.MailOutbox) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, user)
+	varargs := []interface{}{ctx, user}
+	for _, a := range mails {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Update", varargs...)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockUserRepositoryMockRecorder) Update(ctx, user interface{}) *gomock.Call {
+func (mr *MockUserRepositoryMockRecorder) Update(ctx, user interface{}, mails ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserRepository)(nil).Update), ctx, user)
+	varargs := append([]interface{}{ctx, user}, mails...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserRepository)(nil).Update), varargs...)
 }
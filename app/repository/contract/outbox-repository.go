@@ -0,0 +1,23 @@
+package contract
+
+import (
+	"context"
+	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	"time"
+)
+
+type OutboxRepository interface {
+	// queue mails that are not part of other business change
+	Create(ctx context.Context, mails ...*databaseentity.MailOutbox) error
+	// claim pending mails due to be sent, claimed mails next attempt is postponed by lease so other worker skip them
+	ClaimPendingMails(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*databaseentity.MailOutbox, error)
+	// save mail sending result
+	Update(ctx context.Context, mail *databaseentity.MailOutbox) error
+	// get mail by id
+	GetMailByID(ctx context.Context, id int) (*databaseentity.MailOutbox, error)
+	// get total dead letter mails by request param
+	GetTotalDeadLettersByRequest(ctx context.Context, req *payloadentity.GetDeadLetterListRequest) (int64, error)
+	// get dead letter mails by request param
+	GetDeadLettersByRequest(ctx context.Context, req *payloadentity.GetDeadLetterListRequest) ([]*databaseentity.MailOutbox, error)
+}
@@ -0,0 +1,32 @@
+package contract
+
+import (
+	"context"
+	databaseentity "rap-c/app/entity/database-entity"
+)
+
+type RecipeRepository interface {
+	// get recipe by serial, yield unit is preloaded
+	GetRecipeBySerial(ctx context.Context, serial string) (*databaseentity.Recipe, error)
+	// get recipe lines, ingredient with its unit, sub recipe with its yield unit & line unit are preloaded
+	GetRecipeIngredients(ctx context.Context, recipe *databaseentity.Recipe) ([]*databaseentity.RecipeIngredient, error)
+	// get recipe line by serial, recipe, ingredient & sub recipe with their units & line unit are preloaded
+	GetRecipeIngredientBySerial(ctx context.Context, serial string) (*databaseentity.RecipeIngredient, error)
+	// create recipe line, sub recipe making a cycle is rejected in the same transaction
+	CreateRecipeIngredient(ctx context.Context, line *databaseentity.RecipeIngredient) error
+	// update ingredient or sub recipe, unit & quantity of recipe line, sub recipe making a cycle is rejected in the same transaction
+	UpdateRecipeIngredient(ctx context.Context, line *databaseentity.RecipeIngredient) error
+	// get conversion units of ingredients, units are preloaded
+	GetIngredientConversions(ctx context.Context, ingredientIDs []int) ([]*databaseentity.IngredientConvertionUnit, error)
+	// get recipes using recipe as sub recipe, yield units are preloaded
+	GetParentRecipes(ctx context.Context, recipe *databaseentity.Recipe) ([]*databaseentity.Recipe, error)
+	// get recipes having line of ingredient, yield units are preloaded
+	GetRecipesByIngredient(ctx context.Context, ingredient *databaseentity.Ingredient) ([]*databaseentity.Recipe, error)
+	// get recipes whose cost or yield depends on unit: yield unit, line unit, unit of line ingredient
+	// or unit of line ingredient conversion. yield units are preloaded
+	GetRecipesByUnit(ctx context.Context, unit *databaseentity.Unit) ([]*databaseentity.Recipe, error)
+	// save raw material costs & updated by of recipes in one transaction
+	UpdateRawMaterialCosts(ctx context.Context, recipes []*databaseentity.Recipe) error
+	// take consumed stock & add produced quantity to recipe stock in one transaction
+	Produce(ctx context.Context, production *databaseentity.RecipeProduction, authorID int) error
+}
@@ -17,4 +17,14 @@ type UnitRepository interface {
 	GetUnitsByRequest(ctx context.Context, req *payloadentity.GetUnitListRequest) ([]*databaseentity.Unit, error)
 	// delete unit by name
 	Delete(ctx context.Context, unit *databaseentity.Unit) error
+	// update unit
+	Update(ctx context.Context, unit *databaseentity.Unit) error
+	// ingredients, conversions & recipe lines using unit
+	GetUnitUsage(ctx context.Context, unit *databaseentity.Unit) (*databaseentity.UnitUsage, error)
+	// move every reference of unit to target unit then delete unit, in one transaction
+	Merge(ctx context.Context, unit *databaseentity.Unit, target *databaseentity.Unit, authorID int) error
+	// get ingredient by serial & its conversion units, units are preloaded
+	GetIngredientConversions(ctx context.Context, serial string) (*databaseentity.Ingredient, []*databaseentity.IngredientConvertionUnit, error)
+	// get standard units of dimension, smallest factor first
+	GetUnitsByDimension(ctx context.Context, dimension string) ([]*databaseentity.Unit, error)
 }
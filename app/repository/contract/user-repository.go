@@ -7,10 +7,14 @@ import (
 )
 
 type UserRepository interface {
-	// create user
-	Create(ctx context.Context, user *databaseentity.User) error
-	// update existing user
-	Update(ctx context.Context, user *databaseentity.User) error
+	// create user, its invitation & invitation mails in one transaction
+	Create(ctx context.Context, user *databaseentity.User, invitation *databaseentity.UserInvitation, mails ...*databaseentity.MailOutbox) error
+	// create first non guest user, refused when any non guest user exists
+	CreateOwner(ctx context.Context, user *databaseentity.User) error
+	// get total non guest users, including disabled & invited users
+	GetTotalNonGuestUsers(ctx context.Context) (int64, error)
+	// update existing user, notification mails are queued in the same transaction
+	Update(ctx context.Context, user *databaseentity.User, mails ...*databaseentity.MailOutbox) error
 	// get exact user by field: id, username, email
 	GetUserByField(ctx context.Context, fieldName string, fieldValue interface{}, notFoundStatus int) (*databaseentity.User, error)
 	// get total users by request param
@@ -0,0 +1,216 @@
+package filesender
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/http"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"rap-c/app/entity"
+	databaseentity "rap-c/app/entity/database-entity"
+	"rap-c/app/helper"
+	"rap-c/app/repository/contract"
+	"rap-c/config"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	emlExtension   string = ".eml"
+	fileTimeFormat string = "20060102-150405.000000000"
+)
+
+type repo struct {
+	cfg *config.Config
+	dir string
+}
+
+// write mails as .eml files into folder instead of sending them
+func New(cfg *config.Config, dir string) contract.MailSender {
+	return &repo{cfg, dir}
+}
+
+// read mails written by file mail transport
+func NewCapturedMailRepository(dir string) contract.CapturedMailRepository {
+	return &repo{dir: dir}
+}
+
+func (r *repo) Send(ctx context.Context, mail *databaseentity.MailOutbox) error {
+	err := os.MkdirAll(r.dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	// file name is sortable by time, outbox id keep it unique
+	name := fmt.Sprintf("%s-%d%s", time.Now().Format(fileTimeFormat), mail.ID, emlExtension)
+	f, err := os.Create(filepath.Join(r.dir, name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	m := helper.NewMailMessage(r.cfg.MailSenderName(), r.cfg.MailSenderAddress(), mail)
+	_, err = m.WriteTo(f)
+	return err
+}
+
+// folder is created on first mail, so only existing path must be a folder
+func (r *repo) Ping(ctx context.Context) error {
+	info, err := os.Stat(r.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a folder", r.dir)
+	}
+	return nil
+}
+
+func (r *repo) GetCapturedMails(ctx context.Context) ([]*databaseentity.CapturedMail, error) {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.CaptureRepoGetCapturedMailsError, err.Error()),
+		}
+	}
+
+	var result []*databaseentity.CapturedMail
+	for _, itm := range entries {
+		if itm.IsDir() || filepath.Ext(itm.Name()) != emlExtension {
+			continue
+		}
+		mail, err := r.readFile(itm.Name())
+		if err != nil {
+			return nil, &echo.HTTPError{
+				Code:     http.StatusInternalServerError,
+				Message:  http.StatusText(http.StatusInternalServerError),
+				Internal: entity.NewInternalError(entity.CaptureRepoGetCapturedMailsError, err.Error()),
+			}
+		}
+		result = append(result, mail)
+	}
+
+	// newest first
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name > result[j].Name
+	})
+	return result, nil
+}
+
+func (r *repo) GetCapturedMail(ctx context.Context, name string) (*databaseentity.CapturedMail, error) {
+	// only plain file name inside mail folder is accepted
+	if name != filepath.Base(name) || filepath.Ext(name) != emlExtension {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusNotFound,
+			Message:  fmt.Sprintf(entity.CapturedMailNotFoundMessage, name),
+			Internal: entity.NewInternalError(entity.CapturedMailNotFound, "invalid file name"),
+		}
+	}
+
+	result, err := r.readFile(name)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, &echo.HTTPError{
+				Code:     http.StatusNotFound,
+				Message:  fmt.Sprintf(entity.CapturedMailNotFoundMessage, name),
+				Internal: entity.NewInternalError(entity.CapturedMailNotFound, err.Error()),
+			}
+		}
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.CaptureRepoGetCapturedMailError, err.Error()),
+		}
+	}
+	return result, nil
+}
+
+// parse .eml file into captured mail
+func (r *repo) readFile(name string) (*databaseentity.CapturedMail, error) {
+	f, err := os.Open(filepath.Join(r.dir, name))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	msg, err := mail.ReadMessage(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+
+	decoder := new(mime.WordDecoder)
+	result := &databaseentity.CapturedMail{Name: name}
+	result.From, _ = decoder.DecodeHeader(msg.Header.Get("From"))
+	result.To, _ = decoder.DecodeHeader(msg.Header.Get("To"))
+	result.Subject, _ = decoder.DecodeHeader(msg.Header.Get("Subject"))
+	result.Date, _ = msg.Header.Date()
+
+	err = readBody(result, msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), msg.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return result, nil
+}
+
+// read text & html part, walking into nested multipart
+func readBody(result *databaseentity.CapturedMail, contentType, encoding string, body io.Reader) error {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			// multipart reader already decode quoted-printable part
+			err = readBody(result, part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	switch strings.ToLower(encoding) {
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	}
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	switch mediaType {
+	case "text/plain":
+		result.TextBody = string(content)
+	case "text/html":
+		result.HTMLBody = string(content)
+	}
+	return nil
+}
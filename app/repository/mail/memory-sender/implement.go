@@ -0,0 +1,51 @@
+package memorysender
+
+import (
+	"context"
+	databaseentity "rap-c/app/entity/database-entity"
+	"sync"
+)
+
+// keep sent mails in memory instead of sending them, used as mail sink of http tests
+type Sender struct {
+	mu    sync.Mutex
+	mails []*databaseentity.MailOutbox
+}
+
+func New() *Sender {
+	return &Sender{}
+}
+
+func (s *Sender) Send(ctx context.Context, mail *databaseentity.MailOutbox) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	copied := *mail
+	s.mails = append(s.mails, &copied)
+	return nil
+}
+
+func (s *Sender) Ping(ctx context.Context) error {
+	return nil
+}
+
+// sent mails in sending order
+func (s *Sender) Mails() []*databaseentity.MailOutbox {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*databaseentity.MailOutbox(nil), s.mails...)
+}
+
+// last mail sent to recipient, nil when nothing has been sent
+func (s *Sender) LastMailTo(recipient string) *databaseentity.MailOutbox {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := len(s.mails) - 1; i >= 0; i-- {
+		if s.mails[i].Recipient == recipient {
+			return s.mails[i]
+		}
+	}
+	return nil
+}
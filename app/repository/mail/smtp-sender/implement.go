@@ -0,0 +1,37 @@
+package smtpsender
+
+import (
+	"context"
+	"net"
+	databaseentity "rap-c/app/entity/database-entity"
+	"rap-c/app/helper"
+	"rap-c/app/repository/contract"
+	"rap-c/config"
+	"strconv"
+
+	"github.com/go-gomail/gomail"
+)
+
+type sender struct {
+	cfg *config.Config
+}
+
+func New(cfg *config.Config) contract.MailSender {
+	return &sender{cfg}
+}
+
+func (s *sender) Send(ctx context.Context, mail *databaseentity.MailOutbox) error {
+	m := helper.NewMailMessage(s.cfg.MailSenderName(), s.cfg.MailSenderAddress(), mail)
+	d := gomail.NewDialer(s.cfg.MailHost(), s.cfg.MailPort(), s.cfg.MailUser(), s.cfg.MailPassword())
+	return d.DialAndSend(m)
+}
+
+// open & close tcp connection to smtp server
+func (s *sender) Ping(ctx context.Context) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(s.cfg.MailHost(), strconv.Itoa(s.cfg.MailPort())))
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
@@ -0,0 +1,35 @@
+package stdoutsender
+
+import (
+	"context"
+	"fmt"
+	"io"
+	databaseentity "rap-c/app/entity/database-entity"
+	"rap-c/app/repository/contract"
+	"strings"
+	"sync"
+)
+
+type sender struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// print mails to writer (usually os.Stdout) instead of sending them
+func New(out io.Writer) contract.MailSender {
+	return &sender{out: out}
+}
+
+func (s *sender) Send(ctx context.Context, mail *databaseentity.MailOutbox) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	separator := strings.Repeat("=", 72)
+	_, err := fmt.Fprintf(s.out, "%s\nTo: %s\nSubject: %s\n\n%s\n%s\n",
+		separator, mail.Recipient, mail.Subject, strings.TrimSpace(mail.TextBody), separator)
+	return err
+}
+
+func (s *sender) Ping(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,333 @@
+package auditrepository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"rap-c/app/entity"
+	databaseentity "rap-c/app/entity/database-entity"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	beforeRowsKey  string = "audit:before_rows"
+	auditTagName   string = "audit"
+	auditTagIgnore string = "-"
+	auditTagSecret string = "secret"
+	secretMask     string = "******"
+)
+
+// register gorm callbacks writing audit log for auditable models changed by primary key,
+// audit log is written with the same connection so it is committed or rolled back along with the change.
+// registering again on the same connection does nothing, so every change is logged once
+func RegisterHooks(db *gorm.DB) error {
+	if db.Callback().Create().Get("audit:after_create") != nil {
+		return nil
+	}
+	err := db.Callback().Create().
+		After("gorm:after_create").
+		Before("gorm:commit_or_rollback_transaction").
+		Register("audit:after_create", afterCreate)
+	if err != nil {
+		return err
+	}
+
+	err = db.Callback().Update().
+		After("gorm:begin_transaction").
+		Before("gorm:update").
+		Register("audit:before_update", loadBeforeRows)
+	if err != nil {
+		return err
+	}
+	err = db.Callback().Update().
+		After("gorm:after_update").
+		Before("gorm:commit_or_rollback_transaction").
+		Register("audit:after_update", afterUpdate)
+	if err != nil {
+		return err
+	}
+
+	err = db.Callback().Delete().
+		After("gorm:begin_transaction").
+		Before("gorm:delete").
+		Register("audit:before_delete", loadBeforeRows)
+	if err != nil {
+		return err
+	}
+	return db.Callback().Delete().
+		After("gorm:after_delete").
+		Before("gorm:commit_or_rollback_transaction").
+		Register("audit:after_delete", afterDelete)
+}
+
+// current rows of changed models, keyed by primary key
+func loadBeforeRows(db *gorm.DB) {
+	if db.Error != nil || !isAuditable(db) {
+		return
+	}
+
+	rows := make(map[int]reflect.Value)
+	for _, id := range primaryKeys(db) {
+		row, err := loadRow(db, id)
+		if err != nil {
+			db.AddError(fmt.Errorf("audit load %s %d: %v", db.Statement.Schema.Table, id, err))
+			return
+		}
+		if row.IsValid() {
+			rows[id] = row
+		}
+	}
+	db.InstanceSet(beforeRowsKey, rows)
+}
+
+func afterCreate(db *gorm.DB) {
+	if db.Error != nil || db.Statement.RowsAffected == 0 || !isAuditable(db) {
+		return
+	}
+
+	for _, id := range primaryKeys(db) {
+		after, err := loadRow(db, id)
+		if err != nil {
+			db.AddError(fmt.Errorf("audit load %s %d: %v", db.Statement.Schema.Table, id, err))
+			return
+		}
+		if !after.IsValid() {
+			continue
+		}
+		err = writeLog(db, databaseentity.AuditActionCreate, id, reflect.Value{}, after)
+		if err != nil {
+			db.AddError(err)
+			return
+		}
+	}
+}
+
+func afterUpdate(db *gorm.DB) {
+	if db.Error != nil || db.Statement.RowsAffected == 0 || !isAuditable(db) {
+		return
+	}
+
+	beforeRows := getBeforeRows(db)
+	for id, before := range beforeRows {
+		after, err := loadRow(db, id)
+		if err != nil {
+			db.AddError(fmt.Errorf("audit load %s %d: %v", db.Statement.Schema.Table, id, err))
+			return
+		}
+		if !after.IsValid() {
+			continue
+		}
+		err = writeLog(db, databaseentity.AuditActionUpdate, id, before, after)
+		if err != nil {
+			db.AddError(err)
+			return
+		}
+	}
+}
+
+func afterDelete(db *gorm.DB) {
+	if db.Error != nil || db.Statement.RowsAffected == 0 || !isAuditable(db) {
+		return
+	}
+
+	beforeRows := getBeforeRows(db)
+	for id, before := range beforeRows {
+		err := writeLog(db, databaseentity.AuditActionDelete, id, before, reflect.Value{})
+		if err != nil {
+			db.AddError(err)
+			return
+		}
+	}
+}
+
+func getBeforeRows(db *gorm.DB) map[int]reflect.Value {
+	value, ok := db.InstanceGet(beforeRowsKey)
+	if !ok {
+		return nil
+	}
+	rows, _ := value.(map[int]reflect.Value)
+	return rows
+}
+
+// model with single integer primary key implementing databaseentity.Auditable
+func isAuditable(db *gorm.DB) bool {
+	sch := db.Statement.Schema
+	if sch == nil || sch.PrioritizedPrimaryField == nil {
+		return false
+	}
+	_, ok := reflect.New(sch.ModelType).Interface().(databaseentity.Auditable)
+	return ok
+}
+
+// non zero primary keys of statement struct or slice of structs
+func primaryKeys(db *gorm.DB) []int {
+	var result []int
+	ctx := db.Statement.Context
+	field := db.Statement.Schema.PrioritizedPrimaryField
+	appendKey := func(rv reflect.Value) {
+		for rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return
+			}
+			rv = rv.Elem()
+		}
+		if rv.Kind() != reflect.Struct {
+			return
+		}
+		value, isZero := field.ValueOf(ctx, rv)
+		if isZero {
+			return
+		}
+		if id, ok := toInt(value); ok {
+			result = append(result, id)
+		}
+	}
+
+	rv := db.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			appendKey(rv.Index(i))
+		}
+	default:
+		appendKey(rv)
+	}
+	return result
+}
+
+// load row by primary key with the statement connection, invalid value when row not found
+func loadRow(db *gorm.DB, id int) (reflect.Value, error) {
+	sch := db.Statement.Schema
+	row := reflect.New(sch.ModelType)
+	res := db.Session(&gorm.Session{NewDB: true}).
+		Table(sch.Table).
+		Where(fmt.Sprintf("%s = ?", sch.PrioritizedPrimaryField.DBName), id).
+		Limit(1).
+		Find(row.Interface())
+	if res.Error != nil {
+		return reflect.Value{}, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return reflect.Value{}, nil
+	}
+	return row.Elem(), nil
+}
+
+// write audit log row, update without audited change is skipped
+func writeLog(db *gorm.DB, action string, id int, before, after reflect.Value) error {
+	sch := db.Statement.Schema
+	ctx := db.Statement.Context
+	beforeData, afterData := diff(ctx, sch, before, after)
+	if action == databaseentity.AuditActionUpdate && len(afterData) == 0 {
+		return nil
+	}
+
+	current := after
+	if !current.IsValid() {
+		current = before
+	}
+	entityType, serial := current.Addr().Interface().(databaseentity.Auditable).AuditEntity()
+
+	log := databaseentity.AuditLog{
+		ActorID:    actorID(ctx, sch, action, current),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   id,
+		Serial:     serial,
+		IPAddress:  entity.IPAddressFromContext(ctx),
+		RequestID:  entity.RequestIDFromContext(ctx),
+	}
+	var err error
+	if log.BeforeData, err = marshalData(beforeData); err != nil {
+		return fmt.Errorf("audit marshal %s %d: %v", entityType, id, err)
+	}
+	if log.AfterData, err = marshalData(afterData); err != nil {
+		return fmt.Errorf("audit marshal %s %d: %v", entityType, id, err)
+	}
+
+	err = db.Session(&gorm.Session{NewDB: true}).Create(&log).Error
+	if err != nil {
+		return fmt.Errorf("audit write %s %d: %v", entityType, id, err)
+	}
+	return nil
+}
+
+// changed audited columns before & after, all audited columns on create & delete
+func diff(ctx context.Context, sch *schema.Schema, before, after reflect.Value) (map[string]interface{}, map[string]interface{}) {
+	beforeData := make(map[string]interface{})
+	afterData := make(map[string]interface{})
+	for _, field := range sch.Fields {
+		tag := field.Tag.Get(auditTagName)
+		if field.DBName == "" || field.PrimaryKey || tag == auditTagIgnore ||
+			field.AutoCreateTime > 0 || field.AutoUpdateTime > 0 {
+			continue
+		}
+
+		var beforeValue, afterValue interface{}
+		if before.IsValid() {
+			beforeValue, _ = field.ValueOf(ctx, before)
+		}
+		if after.IsValid() {
+			afterValue, _ = field.ValueOf(ctx, after)
+		}
+		if before.IsValid() && after.IsValid() && reflect.DeepEqual(beforeValue, afterValue) {
+			continue
+		}
+
+		if tag == auditTagSecret {
+			beforeValue, afterValue = secretMask, secretMask
+		}
+		if before.IsValid() {
+			beforeData[field.DBName] = beforeValue
+		}
+		if after.IsValid() {
+			afterData[field.DBName] = afterValue
+		}
+	}
+	return beforeData, afterData
+}
+
+// user from request context, fallback to updated_by or created_by of the row for unauthenticated change
+// like reset password, 0 means system
+func actorID(ctx context.Context, sch *schema.Schema, action string, row reflect.Value) int {
+	if userID, ok := entity.UserIDFromContext(ctx); ok {
+		return userID
+	}
+
+	column := "updated_by"
+	if action == databaseentity.AuditActionCreate {
+		column = "created_by"
+	}
+	field := sch.LookUpField(column)
+	if field == nil {
+		return 0
+	}
+	value, _ := field.ValueOf(ctx, row)
+	result, _ := toInt(value)
+	return result
+}
+
+func marshalData(data map[string]interface{}) (string, error) {
+	if len(data) == 0 {
+		return "", nil
+	}
+	result, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	return string(result), nil
+}
+
+func toInt(value interface{}) (int, bool) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(rv.Uint()), true
+	}
+	return 0, false
+}
@@ -0,0 +1,98 @@
+package auditrepository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"rap-c/app/entity"
+	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	"rap-c/app/repository/contract"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+const (
+	minQueryLimit int    = 10
+	maxQueryLimit int    = 100
+	dateLayout    string = "2006-01-02"
+)
+
+type repo struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) contract.AuditRepository {
+	return &repo{db}
+}
+
+func (r *repo) GetTotalAuditLogsByRequest(ctx context.Context, req *payloadentity.GetAuditLogListRequest) (int64, error) {
+	var result int64
+	qry := r.renderAuditLogsQuery(req)
+	err := qry.Model(databaseentity.AuditLog{}).Count(&result).Error
+	if err != nil {
+		return 0, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AuditRepoGetTotalAuditLogsByRequestError, err.Error()),
+		}
+	}
+	return result, nil
+}
+
+func (r *repo) GetAuditLogsByRequest(ctx context.Context, req *payloadentity.GetAuditLogListRequest) ([]*databaseentity.AuditLog, error) {
+	var result []*databaseentity.AuditLog
+	qry := r.renderAuditLogsQuery(req)
+	order := "asc"
+	if req.DescendingOrder {
+		order = "desc"
+	}
+	// validate limit
+	if req.Limit < minQueryLimit {
+		req.Limit = minQueryLimit
+	} else if req.Limit > maxQueryLimit {
+		req.Limit = maxQueryLimit
+	}
+	err := qry.Order(fmt.Sprintf("id %s", order)).
+		Limit(req.Limit).
+		Offset(req.Page.GetOffset(req.Limit)).
+		Find(&result).
+		Error
+	if err != nil {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AuditRepoGetAuditLogsByRequestError, err.Error()),
+		}
+	}
+	return result, nil
+}
+
+func (r *repo) renderAuditLogsQuery(req *payloadentity.GetAuditLogListRequest) *gorm.DB {
+	qry := r.db
+	if req.EntityType != "" {
+		qry = qry.Where("entity_type = ?", req.EntityType)
+	}
+	if req.Serial != "" {
+		qry = qry.Where("serial = ?", req.Serial)
+	}
+	if req.Actor != "" {
+		qry = qry.Where("actor_id in (?)", r.db.Model(databaseentity.User{}).Select("id").Where("username = ?", req.Actor))
+	}
+	if req.Action != "" {
+		qry = qry.Where("action = ?", req.Action)
+	}
+	if req.RequestID != "" {
+		qry = qry.Where("request_id = ?", req.RequestID)
+	}
+	// date range is validated by usecase, both dates are inclusive
+	if dateFrom, err := time.ParseInLocation(dateLayout, req.DateFrom, time.Local); err == nil {
+		qry = qry.Where("created_at >= ?", dateFrom)
+	}
+	if dateTo, err := time.ParseInLocation(dateLayout, req.DateTo, time.Local); err == nil {
+		qry = qry.Where("created_at < ?", dateTo.AddDate(0, 0, 1))
+	}
+	return qry
+}
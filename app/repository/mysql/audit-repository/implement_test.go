@@ -0,0 +1,81 @@
+package auditrepository_test
+
+import (
+	"context"
+	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	auditrepository "rap-c/app/repository/mysql/audit-repository"
+	unitrepository "rap-c/app/repository/mysql/unit-repository"
+	testdatabase "rap-c/app/repository/test-database"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GetAuditLogsByRequest(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := auditrepository.New(db)
+	unitRepo := unitrepository.New(db)
+	ctx := context.Background()
+	alice := testdatabase.User(t, db, &databaseentity.User{Username: "alice"})
+	bob := testdatabase.User(t, db, &databaseentity.User{Username: "bob"})
+
+	// audit logs are written by hooks when units are changed through repository
+	gram := &databaseentity.Unit{Name: "Gram", CreatedBy: alice.ID}
+	assert.Nil(t, unitRepo.Create(ctx, gram))
+	assert.Nil(t, unitRepo.Create(ctx, &databaseentity.Unit{Name: "Liter", CreatedBy: bob.ID}))
+	assert.Nil(t, unitRepo.Delete(ctx, gram))
+
+	tests := []struct {
+		name    string
+		req     *payloadentity.GetAuditLogListRequest
+		actions []string
+		serials []string
+	}{
+		{
+			name:    "all units",
+			req:     &payloadentity.GetAuditLogListRequest{EntityType: "unit"},
+			actions: []string{databaseentity.AuditActionCreate, databaseentity.AuditActionCreate, databaseentity.AuditActionDelete},
+			serials: []string{"Gram", "Liter", "Gram"},
+		},
+		{
+			name:    "by serial descending",
+			req:     &payloadentity.GetAuditLogListRequest{Serial: "Gram", DescendingOrder: true},
+			actions: []string{databaseentity.AuditActionDelete, databaseentity.AuditActionCreate},
+			serials: []string{"Gram", "Gram"},
+		},
+		{
+			name:    "by actor",
+			req:     &payloadentity.GetAuditLogListRequest{EntityType: "unit", Actor: "bob"},
+			actions: []string{databaseentity.AuditActionCreate},
+			serials: []string{"Liter"},
+		},
+		{
+			name:    "by action",
+			req:     &payloadentity.GetAuditLogListRequest{Action: databaseentity.AuditActionDelete},
+			actions: []string{databaseentity.AuditActionDelete},
+			serials: []string{"Gram"},
+		},
+		{
+			name: "by unknown request id",
+			req:  &payloadentity.GetAuditLogListRequest{RequestID: "unknown"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total, err := repo.GetTotalAuditLogsByRequest(ctx, tt.req)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(len(tt.actions)), total)
+
+			logs, err := repo.GetAuditLogsByRequest(ctx, tt.req)
+			assert.Nil(t, err)
+			var actions, serials []string
+			for _, log := range logs {
+				actions = append(actions, log.Action)
+				serials = append(serials, log.Serial)
+			}
+			assert.Equal(t, tt.actions, actions)
+			assert.Equal(t, tt.serials, serials)
+		})
+	}
+}
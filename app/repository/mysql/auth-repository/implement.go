@@ -2,7 +2,6 @@ package authrepository
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"net/http"
 	"rap-c/app/entity"
@@ -14,11 +13,6 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
-)
-
-const (
-	resetTokenExpiration time.Duration = time.Hour
 )
 
 type repo struct {
@@ -35,7 +29,7 @@ func (r *repo) DoUserLogin(ctx context.Context, payload *payloadentity.AttemptLo
 	// get from database
 	err := r.db.Where("email = ?", payload.Email).First(&user).Error
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		if helper.IsNotFoundError(err) {
 			return nil, &echo.HTTPError{
 				Code:     http.StatusUnauthorized,
 				Message:  fmt.Sprintf(entity.AttemptLoginFailedMessage),
@@ -92,7 +86,7 @@ func (r *repo) DoRenewPassword(ctx context.Context, user *databaseentity.User, p
 	user.Password = encryptPassword
 	user.PasswordMustChange = false
 	user.UpdatedBy = user.ID
-	err = r.db.Save(user).Error
+	err = r.db.WithContext(ctx).Save(user).Error
 	if err != nil {
 		return &echo.HTTPError{
 			Code:     http.StatusInternalServerError,
@@ -103,13 +97,45 @@ func (r *repo) DoRenewPassword(ctx context.Context, user *databaseentity.User, p
 	return nil
 }
 
+func (r *repo) GetGuestUser(ctx context.Context) (*databaseentity.User, error) {
+	var user databaseentity.User
+
+	// get from database
+	err := r.db.Where("is_guest = ?", true).First(&user).Error
+	if err != nil {
+		if helper.IsNotFoundError(err) {
+			return nil, &echo.HTTPError{
+				Code:     http.StatusUnauthorized,
+				Message:  entity.AttemptLoginFailedMessage,
+				Internal: entity.NewInternalError(entity.AttemptLoginFailed, "guest user not found"),
+			}
+		}
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AuthRepoGetGuestUserError, err.Error()),
+		}
+	}
+
+	// validate active status
+	if user.Disabled {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusUnauthorized,
+			Message:  entity.AttemptLoginUserDeactivatedMessage,
+			Internal: entity.NewInternalError(entity.AttemptLoginUserDeactivated, entity.AttemptLoginUserDeactivatedMessage),
+		}
+	}
+
+	return &user, nil
+}
+
 func (r *repo) GetUserByEmail(ctx context.Context, email string) (*databaseentity.User, error) {
 	var user databaseentity.User
 
 	// get from database
 	err := r.db.Where("email = ?", email).First(&user).Error
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		if helper.IsNotFoundError(err) {
 			return nil, &echo.HTTPError{
 				Code:     http.StatusNotFound,
 				Message:  fmt.Sprintf(entity.SearchSingleUserNotFOundMessage, "email", email),
@@ -125,43 +151,108 @@ func (r *repo) GetUserByEmail(ctx context.Context, email string) (*databaseentit
 	return &user, nil
 }
 
-func (r *repo) GenerateUserResetPassword(ctx context.Context, payload *payloadentity.RequestResetPayload) (*databaseentity.PasswordResetToken, error) {
-	token, err := helper.GenerateToken(64)
+func (r *repo) GenerateUserResetPassword(ctx context.Context, reset *databaseentity.PasswordResetToken, mails ...*databaseentity.MailOutbox) (err error) {
+	tx := r.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			err = &echo.HTTPError{
+				Code:     http.StatusInternalServerError,
+				Message:  http.StatusText(http.StatusInternalServerError),
+				Internal: entity.NewInternalError(entity.AuthRepoGenerateUserResetPasswordError, fmt.Sprint(r)),
+			}
+			tx.Rollback()
+		}
+	}()
+	if err = tx.Error; err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AuthRepoGenerateUserResetPasswordError, err.Error()),
+		}
+	}
+
+	// revoke previous tokens
+	err = tx.Model(databaseentity.PasswordResetToken{}).
+		Where("email = ? and used_at is null and revoked_at is null", reset.Email).
+		Update("revoked_at", time.Now()).Error
 	if err != nil {
-		return nil, &echo.HTTPError{
+		tx.Rollback()
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AuthRepoGenerateUserResetPasswordError, err.Error()),
+		}
+	}
+
+	// save new token
+	err = tx.Create(reset).Error
+	if err != nil {
+		tx.Rollback()
+		return &echo.HTTPError{
 			Code:     http.StatusInternalServerError,
 			Message:  http.StatusText(http.StatusInternalServerError),
-			Internal: entity.NewInternalError(entity.HelperGenerateTokenError, err.Error()),
+			Internal: entity.NewInternalError(entity.AuthRepoGenerateUserResetPasswordError, err.Error()),
 		}
 	}
 
-	result := databaseentity.PasswordResetToken{
-		Email: payload.Email,
-		Token: token,
+	// queue reset password mail
+	if len(mails) > 0 {
+		err = tx.Create(mails).Error
+		if err != nil {
+			tx.Rollback()
+			return &echo.HTTPError{
+				Code:     http.StatusInternalServerError,
+				Message:  http.StatusText(http.StatusInternalServerError),
+				Internal: entity.NewInternalError(entity.AuthRepoGenerateUserResetPasswordError, err.Error()),
+			}
+		}
 	}
-	err = r.db.
-		Clauses(clause.OnConflict{
-			Columns:   []clause.Column{{Name: "email"}},
-			DoUpdates: clause.AssignmentColumns([]string{"token", "updated_at"}),
-		}).
-		Create(&result).Error
+
+	err = tx.Commit().Error
 	if err != nil {
-		return nil, &echo.HTTPError{
+		return &echo.HTTPError{
 			Code:     http.StatusInternalServerError,
 			Message:  http.StatusText(http.StatusInternalServerError),
 			Internal: entity.NewInternalError(entity.AuthRepoGenerateUserResetPasswordError, err.Error()),
 		}
 	}
-	return &result, nil
+	return nil
+}
+
+func (r *repo) GetTotalResetRequestsByField(ctx context.Context, fieldName string, fieldValue string, since time.Time) (int64, error) {
+	acceptedFields := map[string]bool{
+		"email":      true,
+		"ip_address": true,
+	}
+	if !acceptedFields[fieldName] {
+		return 0, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AuthRepoGetTotalResetRequestsError, "invalid field name for query"),
+		}
+	}
+
+	var result int64
+	err := r.db.Model(databaseentity.PasswordResetToken{}).
+		Where(fmt.Sprintf("%s = ? and created_at >= ?", fieldName), fieldValue, since).
+		Count(&result).Error
+	if err != nil {
+		return 0, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AuthRepoGetTotalResetRequestsError, err.Error()),
+		}
+	}
+	return result, nil
 }
 
 func (r *repo) ValidateResetToken(ctx context.Context, payload *payloadentity.ValidateResetTokenPayload) (*databaseentity.PasswordResetToken, error) {
 	var result databaseentity.PasswordResetToken
 
 	// get token from db
-	err := r.db.Where("email = ?", payload.Email).First(&result).Error
+	err := r.db.Where("email = ? and token_hash = ?", payload.Email, helper.HashToken(payload.Token)).First(&result).Error
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		if helper.IsNotFoundError(err) {
 			return nil, &echo.HTTPError{
 				Code:     http.StatusNotFound,
 				Message:  entity.ResetPasswordRequestNotFoundMessage,
@@ -174,19 +265,19 @@ func (r *repo) ValidateResetToken(ctx context.Context, payload *payloadentity.Va
 			Internal: entity.NewInternalError(entity.AuthRepoValidateResetTokenError, err.Error()),
 		}
 	}
-	// validate token & expired date
-	if result.Token == "" || result.Token != payload.Token || time.Now().After(result.UpdatedAt.Add(resetTokenExpiration)) {
+	// validate used, revoked & expired date
+	if !result.IsValid(time.Now()) {
 		return nil, &echo.HTTPError{
 			Code:     http.StatusNotFound,
 			Message:  entity.ResetPasswordRequestNotFoundMessage,
-			Internal: entity.NewInternalError(entity.ResetPasswordRequestNotFound, "token expired or not match"),
+			Internal: entity.NewInternalError(entity.ResetPasswordRequestNotFound, "token expired, used or revoked"),
 		}
 	}
 	return &result, nil
 }
 
 func (r *repo) DoResetPassword(ctx context.Context, user *databaseentity.User, reset *databaseentity.PasswordResetToken) (err error) {
-	tx := r.db.Begin()
+	tx := r.db.WithContext(ctx).Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			err = &echo.HTTPError{
@@ -206,6 +297,34 @@ func (r *repo) DoResetPassword(ctx context.Context, user *databaseentity.User, r
 		return
 	}
 
+	// mark reset password as used, only one request can claim the token
+	now := time.Now()
+	if reset.UsedAt != nil {
+		now = *reset.UsedAt
+	}
+	res := tx.Model(&databaseentity.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL AND expired_at > ?", reset.ID, now).
+		Update("used_at", now)
+	if res.Error != nil {
+		tx.Rollback()
+		err = &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.AuthRepoDoResetPasswordError, res.Error.Error()),
+		}
+		return
+	}
+	if res.RowsAffected != 1 {
+		tx.Rollback()
+		err = &echo.HTTPError{
+			Code:     http.StatusNotFound,
+			Message:  entity.ResetPasswordRequestNotFoundMessage,
+			Internal: entity.NewInternalError(entity.ResetPasswordRequestNotFound, "token expired, used or revoked"),
+		}
+		return
+	}
+	reset.UsedAt = &now
+
 	// save user
 	err = tx.Save(user).Error
 	if err != nil {
@@ -218,8 +337,10 @@ func (r *repo) DoResetPassword(ctx context.Context, user *databaseentity.User, r
 		return
 	}
 
-	// save reset password
-	err = tx.Save(reset).Error
+	// revoke other outstanding tokens for the same email
+	err = tx.Model(databaseentity.PasswordResetToken{}).
+		Where("email = ? and id <> ? and used_at is null and revoked_at is null", reset.Email, reset.ID).
+		Update("revoked_at", now).Error
 	if err != nil {
 		tx.Rollback()
 		err = &echo.HTTPError{
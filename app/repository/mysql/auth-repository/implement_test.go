@@ -0,0 +1,120 @@
+package authrepository_test
+
+import (
+	"context"
+	"net/http"
+	"rap-c/app/entity"
+	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	"rap-c/app/helper"
+	authrepository "rap-c/app/repository/mysql/auth-repository"
+	testdatabase "rap-c/app/repository/test-database"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DoUserLogin(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := authrepository.New(db)
+	ctx := context.Background()
+	password, err := helper.EncryptPassword("Secret123!")
+	assert.Nil(t, err)
+	active := testdatabase.User(t, db, &databaseentity.User{Email: "active@example.com", Password: password})
+	testdatabase.User(t, db, &databaseentity.User{Email: "disabled@example.com", Password: password, Disabled: true})
+
+	tests := []struct {
+		name         string
+		payload      *payloadentity.AttemptLoginPayload
+		code         int
+		internalCode int
+	}{
+		{
+			name:    "success",
+			payload: &payloadentity.AttemptLoginPayload{Email: "active@example.com", Password: "Secret123!"},
+		},
+		{
+			name:         "unknown email",
+			payload:      &payloadentity.AttemptLoginPayload{Email: "unknown@example.com", Password: "Secret123!"},
+			code:         http.StatusUnauthorized,
+			internalCode: entity.AttemptLoginFailed,
+		},
+		{
+			name:         "wrong password",
+			payload:      &payloadentity.AttemptLoginPayload{Email: "active@example.com", Password: "wrong"},
+			code:         http.StatusUnauthorized,
+			internalCode: entity.AttemptLoginFailed,
+		},
+		{
+			name:         "disabled user",
+			payload:      &payloadentity.AttemptLoginPayload{Email: "disabled@example.com", Password: "Secret123!"},
+			code:         http.StatusUnauthorized,
+			internalCode: entity.AttemptLoginUserDeactivated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := repo.DoUserLogin(ctx, tt.payload)
+			if tt.code == 0 {
+				assert.Nil(t, err)
+				assert.Equal(t, active.ID, user.ID)
+				return
+			}
+			testdatabase.AssertHTTPError(t, err, tt.code, tt.internalCode)
+		})
+	}
+}
+
+func Test_GetGuestUser(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := authrepository.New(db)
+	ctx := context.Background()
+
+	t.Run("no guest user", func(t *testing.T) {
+		_, err := repo.GetGuestUser(ctx)
+		testdatabase.AssertHTTPError(t, err, http.StatusUnauthorized, entity.AttemptLoginFailed)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		guest := testdatabase.User(t, db, &databaseentity.User{IsGuest: true})
+		user, err := repo.GetGuestUser(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, guest.ID, user.ID)
+	})
+}
+
+func Test_DoResetPassword(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := authrepository.New(db)
+	ctx := context.Background()
+	user := testdatabase.User(t, db, &databaseentity.User{Email: "reset@example.com"})
+	reset := &databaseentity.PasswordResetToken{
+		Email:     user.Email,
+		TokenHash: helper.HashToken("token"),
+		IPAddress: "127.0.0.1",
+		ExpiredAt: time.Now().Add(time.Hour),
+	}
+	assert.Nil(t, db.Create(reset).Error)
+	payload := &payloadentity.ValidateResetTokenPayload{Email: user.Email, Token: "token"}
+
+	// both submits validate the token before either of them redeems it
+	first, err := repo.ValidateResetToken(ctx, payload)
+	assert.Nil(t, err)
+	second, err := repo.ValidateResetToken(ctx, payload)
+	assert.Nil(t, err)
+
+	firstUser := *user
+	firstUser.Password = "first"
+	assert.Nil(t, repo.DoResetPassword(ctx, &firstUser, first))
+	assert.NotNil(t, first.UsedAt)
+
+	secondUser := *user
+	secondUser.Password = "second"
+	err = repo.DoResetPassword(ctx, &secondUser, second)
+	testdatabase.AssertHTTPError(t, err, http.StatusNotFound, entity.ResetPasswordRequestNotFound)
+
+	var saved databaseentity.User
+	assert.Nil(t, db.First(&saved, user.ID).Error)
+	assert.Equal(t, "first", saved.Password)
+}
@@ -0,0 +1,58 @@
+package healthrepository
+
+import (
+	"context"
+	"io/fs"
+	"net/http"
+	"rap-c/app/entity"
+	"rap-c/app/repository/contract"
+	"rap-c/migration"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type repo struct {
+	db         *gorm.DB
+	migrations []fs.FS
+}
+
+// migrations are module sources applied on top of base schema
+func New(db *gorm.DB, migrations ...fs.FS) contract.HealthRepository {
+	return &repo{db, migrations}
+}
+
+func (r *repo) Ping(ctx context.Context) error {
+	sqlDB, err := r.db.DB()
+	if err == nil {
+		err = sqlDB.PingContext(ctx)
+	}
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.HealthRepoPingError, err.Error()),
+		}
+	}
+	return nil
+}
+
+func (r *repo) GetTotalPendingMigrations(ctx context.Context) (int, error) {
+	migrator, err := migration.New(r.db.WithContext(ctx), r.migrations...)
+	if err != nil {
+		return 0, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.HealthRepoGetTotalPendingMigrationsError, err.Error()),
+		}
+	}
+	result, err := migrator.Pending()
+	if err != nil {
+		return 0, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.HealthRepoGetTotalPendingMigrationsError, err.Error()),
+		}
+	}
+	return result, nil
+}
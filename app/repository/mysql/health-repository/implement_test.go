@@ -0,0 +1,26 @@
+package healthrepository_test
+
+import (
+	"context"
+	healthrepository "rap-c/app/repository/mysql/health-repository"
+	testdatabase "rap-c/app/repository/test-database"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Check(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := healthrepository.New(db)
+	ctx := context.Background()
+
+	t.Run("ping", func(t *testing.T) {
+		assert.Nil(t, repo.Ping(ctx))
+	})
+
+	t.Run("no pending migrations after harness migrate", func(t *testing.T) {
+		total, err := repo.GetTotalPendingMigrations(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, total)
+	})
+}
@@ -0,0 +1,62 @@
+package ingredientrepository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"rap-c/app/entity"
+	databaseentity "rap-c/app/entity/database-entity"
+	"rap-c/app/helper"
+	"rap-c/app/repository/contract"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type repo struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) contract.IngredientRepository {
+	return &repo{db}
+}
+
+func (r *repo) GetIngredientBySerial(ctx context.Context, serial string) (*databaseentity.Ingredient, error) {
+	var result databaseentity.Ingredient
+	err := r.db.WithContext(ctx).Preload("Unit").Where("serial = ?", serial).First(&result).Error
+	if err != nil {
+		if helper.IsNotFoundError(err) {
+			return nil, &echo.HTTPError{
+				Code:     http.StatusNotFound,
+				Message:  fmt.Sprintf(entity.IngredientNotFoundMessage, serial),
+				Internal: entity.NewInternalError(entity.IngredientNotFound, err.Error()),
+			}
+		}
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.IngredientRepoGetIngredientBySerialError, err.Error()),
+		}
+	}
+	return &result, nil
+}
+
+func (r *repo) Update(ctx context.Context, ingredient *databaseentity.Ingredient) error {
+	if ingredient.ID == 0 {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.IngredientRepoUpdateError, "data not found, empty primary key"),
+		}
+	}
+	// preloaded unit is not saved
+	err := r.db.WithContext(ctx).Omit("Unit").Save(ingredient).Error
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.IngredientRepoUpdateError, err.Error()),
+		}
+	}
+	return nil
+}
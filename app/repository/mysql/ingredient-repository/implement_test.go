@@ -0,0 +1,68 @@
+package ingredientrepository_test
+
+import (
+	"context"
+	"net/http"
+	"rap-c/app/entity"
+	databaseentity "rap-c/app/entity/database-entity"
+	ingredientrepository "rap-c/app/repository/mysql/ingredient-repository"
+	testdatabase "rap-c/app/repository/test-database"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GetIngredientBySerial(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := ingredientrepository.New(db)
+	ctx := context.Background()
+	ingredient := testdatabase.Ingredient(t, db, &databaseentity.Ingredient{Density: 0.53, PieceWeight: 60})
+
+	t.Run("success", func(t *testing.T) {
+		result, err := repo.GetIngredientBySerial(ctx, ingredient.Serial)
+		assert.Nil(t, err)
+		assert.Equal(t, ingredient.ID, result.ID)
+		assert.Equal(t, 0.53, result.Density)
+		assert.Equal(t, float64(60), result.PieceWeight)
+		if assert.NotNil(t, result.Unit) {
+			assert.Equal(t, ingredient.UnitID, result.Unit.ID)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := repo.GetIngredientBySerial(ctx, "ING404")
+		testdatabase.AssertHTTPError(t, err, http.StatusNotFound, entity.IngredientNotFound)
+	})
+}
+
+func Test_Update(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := ingredientrepository.New(db)
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		ingredient := testdatabase.Ingredient(t, db, &databaseentity.Ingredient{})
+		ingredient, err := repo.GetIngredientBySerial(ctx, ingredient.Serial)
+		assert.Nil(t, err)
+		ingredient.Density = 1.03
+		ingredient.UpdatedBy = 5
+		assert.Nil(t, repo.Update(ctx, ingredient))
+
+		result, err := repo.GetIngredientBySerial(ctx, ingredient.Serial)
+		assert.Nil(t, err)
+		assert.Equal(t, 1.03, result.Density)
+		assert.Equal(t, 5, result.UpdatedBy)
+
+		// weight change is audited
+		var actions []string
+		assert.Nil(t, db.Model(databaseentity.AuditLog{}).
+			Where("entity_type = ? AND entity_id = ?", "ingredient", ingredient.ID).
+			Order("id").Pluck("action", &actions).Error)
+		assert.Equal(t, []string{databaseentity.AuditActionCreate, databaseentity.AuditActionUpdate}, actions)
+	})
+
+	t.Run("empty primary key", func(t *testing.T) {
+		err := repo.Update(ctx, &databaseentity.Ingredient{})
+		testdatabase.AssertHTTPError(t, err, http.StatusInternalServerError, entity.IngredientRepoUpdateError)
+	})
+}
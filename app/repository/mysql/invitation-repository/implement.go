@@ -0,0 +1,206 @@
+package invitationrepository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"rap-c/app/entity"
+	databaseentity "rap-c/app/entity/database-entity"
+	"rap-c/app/helper"
+	"rap-c/app/repository/contract"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type repo struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) contract.InvitationRepository {
+	return &repo{db}
+}
+
+func (r *repo) Create(ctx context.Context, invitation *databaseentity.UserInvitation, mails ...*databaseentity.MailOutbox) (err error) {
+	tx := r.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			err = &echo.HTTPError{
+				Code:     http.StatusInternalServerError,
+				Message:  http.StatusText(http.StatusInternalServerError),
+				Internal: entity.NewInternalError(entity.InvitationRepoCreateError, fmt.Sprint(r)),
+			}
+			tx.Rollback()
+		}
+	}()
+	if err = tx.Error; err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.InvitationRepoCreateError, err.Error()),
+		}
+	}
+
+	// revoke previous invitations
+	err = tx.Model(databaseentity.UserInvitation{}).
+		Where("user_id = ? and accepted_at is null and revoked_at is null", invitation.UserID).
+		Updates(map[string]interface{}{"revoked_at": time.Now(), "updated_by": invitation.UpdatedBy}).Error
+	if err != nil {
+		tx.Rollback()
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.InvitationRepoCreateError, err.Error()),
+		}
+	}
+
+	// save new invitation
+	err = tx.Create(invitation).Error
+	if err != nil {
+		tx.Rollback()
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.InvitationRepoCreateError, err.Error()),
+		}
+	}
+
+	// queue invitation mail
+	if len(mails) > 0 {
+		err = tx.Create(mails).Error
+		if err != nil {
+			tx.Rollback()
+			return &echo.HTTPError{
+				Code:     http.StatusInternalServerError,
+				Message:  http.StatusText(http.StatusInternalServerError),
+				Internal: entity.NewInternalError(entity.InvitationRepoCreateError, err.Error()),
+			}
+		}
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.InvitationRepoCreateError, err.Error()),
+		}
+	}
+	return nil
+}
+
+func (r *repo) GetValidInvitation(ctx context.Context, token string) (*databaseentity.UserInvitation, error) {
+	var result databaseentity.UserInvitation
+
+	err := r.db.WithContext(ctx).Where("token_hash = ?", helper.HashToken(token)).First(&result).Error
+	if err != nil {
+		if helper.IsNotFoundError(err) {
+			return nil, &echo.HTTPError{
+				Code:     http.StatusNotFound,
+				Message:  entity.InvitationNotFoundMessage,
+				Internal: entity.NewInternalError(entity.InvitationNotFound, err.Error()),
+			}
+		}
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.InvitationRepoGetByTokenError, err.Error()),
+		}
+	}
+	// validate accepted, revoked & expired date
+	if !result.IsValid(time.Now()) {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusNotFound,
+			Message:  entity.InvitationNotFoundMessage,
+			Internal: entity.NewInternalError(entity.InvitationNotFound, "invitation expired, accepted or revoked"),
+		}
+	}
+	return &result, nil
+}
+
+func (r *repo) Accept(ctx context.Context, user *databaseentity.User, invitation *databaseentity.UserInvitation) (err error) {
+	tx := r.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			err = &echo.HTTPError{
+				Code:     http.StatusInternalServerError,
+				Message:  http.StatusText(http.StatusInternalServerError),
+				Internal: entity.NewInternalError(entity.InvitationRepoAcceptError, fmt.Sprint(r)),
+			}
+			tx.Rollback()
+		}
+	}()
+	if err = tx.Error; err != nil {
+		err = &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.InvitationRepoAcceptError, err.Error()),
+		}
+		return
+	}
+
+	// mark invitation as accepted, only one request can claim the invitation
+	now := time.Now()
+	if invitation.AcceptedAt != nil {
+		now = *invitation.AcceptedAt
+	}
+	res := tx.Model(&databaseentity.UserInvitation{}).
+		Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expired_at > ?", invitation.ID, now).
+		Updates(map[string]interface{}{"accepted_at": now, "updated_by": invitation.UpdatedBy})
+	if res.Error != nil {
+		tx.Rollback()
+		err = &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.InvitationRepoAcceptError, res.Error.Error()),
+		}
+		return
+	}
+	if res.RowsAffected != 1 {
+		tx.Rollback()
+		err = &echo.HTTPError{
+			Code:     http.StatusNotFound,
+			Message:  entity.InvitationNotFoundMessage,
+			Internal: entity.NewInternalError(entity.InvitationNotFound, "invitation expired, accepted or revoked"),
+		}
+		return
+	}
+	invitation.AcceptedAt = &now
+
+	// save user
+	err = tx.Save(user).Error
+	if err != nil {
+		tx.Rollback()
+		err = &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.InvitationRepoAcceptError, err.Error()),
+		}
+		return
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		err = &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.InvitationRepoAcceptError, err.Error()),
+		}
+	}
+	return
+}
+
+func (r *repo) Revoke(ctx context.Context, user *databaseentity.User, author *databaseentity.User) (int64, error) {
+	res := r.db.WithContext(ctx).Model(databaseentity.UserInvitation{}).
+		Where("user_id = ? and accepted_at is null and revoked_at is null", user.ID).
+		Updates(map[string]interface{}{"revoked_at": time.Now(), "updated_by": author.ID})
+	if res.Error != nil {
+		return 0, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.InvitationRepoRevokeError, res.Error.Error()),
+		}
+	}
+	return res.RowsAffected, nil
+}
@@ -0,0 +1,51 @@
+package invitationrepository_test
+
+import (
+	"context"
+	"net/http"
+	"rap-c/app/entity"
+	databaseentity "rap-c/app/entity/database-entity"
+	"rap-c/app/helper"
+	invitationrepository "rap-c/app/repository/mysql/invitation-repository"
+	testdatabase "rap-c/app/repository/test-database"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Accept(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := invitationrepository.New(db)
+	ctx := context.Background()
+	user := testdatabase.User(t, db, &databaseentity.User{Email: "invited@example.com"})
+	invitation := &databaseentity.UserInvitation{
+		UserID:    user.ID,
+		TokenHash: helper.HashToken("token"),
+		ExpiredAt: time.Now().Add(time.Hour),
+	}
+	assert.Nil(t, repo.Create(ctx, invitation))
+
+	// both submits load the invitation before either of them accepts it
+	first, err := repo.GetValidInvitation(ctx, "token")
+	assert.Nil(t, err)
+	second, err := repo.GetValidInvitation(ctx, "token")
+	assert.Nil(t, err)
+
+	firstUser := *user
+	firstUser.Password = "first"
+	assert.Nil(t, repo.Accept(ctx, &firstUser, first))
+	assert.NotNil(t, first.AcceptedAt)
+
+	secondUser := *user
+	secondUser.Password = "second"
+	err = repo.Accept(ctx, &secondUser, second)
+	testdatabase.AssertHTTPError(t, err, http.StatusNotFound, entity.InvitationNotFound)
+
+	var saved databaseentity.User
+	assert.Nil(t, db.First(&saved, user.ID).Error)
+	assert.Equal(t, "first", saved.Password)
+
+	_, err = repo.GetValidInvitation(ctx, "token")
+	testdatabase.AssertHTTPError(t, err, http.StatusNotFound, entity.InvitationNotFound)
+}
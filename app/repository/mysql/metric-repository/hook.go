@@ -0,0 +1,63 @@
+package metricrepository
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+)
+
+const startTimeKey string = "metric:start_time"
+
+// register gorm callbacks observing query duration by operation & table
+func RegisterHooks(db *gorm.DB, registerer prometheus.Registerer) error {
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "rapc",
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Duration of gorm database operations in seconds.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table", "error"})
+	err := registerer.Register(duration)
+	if err != nil {
+		return err
+	}
+
+	// start timer as first callback & observe as last callback of each operation
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("*").Register("metric:before_create", startTimer),
+		callbacks.Create().After("*").Register("metric:after_create", observeDuration(duration, "create")),
+		callbacks.Query().Before("*").Register("metric:before_query", startTimer),
+		callbacks.Query().After("*").Register("metric:after_query", observeDuration(duration, "query")),
+		callbacks.Update().Before("*").Register("metric:before_update", startTimer),
+		callbacks.Update().After("*").Register("metric:after_update", observeDuration(duration, "update")),
+		callbacks.Delete().Before("*").Register("metric:before_delete", startTimer),
+		callbacks.Delete().After("*").Register("metric:after_delete", observeDuration(duration, "delete")),
+		callbacks.Row().Before("*").Register("metric:before_row", startTimer),
+		callbacks.Row().After("*").Register("metric:after_row", observeDuration(duration, "row")),
+		callbacks.Raw().Before("*").Register("metric:before_raw", startTimer),
+		callbacks.Raw().After("*").Register("metric:after_raw", observeDuration(duration, "raw")),
+	)
+}
+
+func startTimer(db *gorm.DB) {
+	db.InstanceSet(startTimeKey, time.Now())
+}
+
+func observeDuration(duration *prometheus.HistogramVec, operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(startTimeKey)
+		if !ok {
+			return
+		}
+		start, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+		duration.WithLabelValues(operation, db.Statement.Table, strconv.FormatBool(db.Error != nil)).
+			Observe(time.Since(start).Seconds())
+	}
+}
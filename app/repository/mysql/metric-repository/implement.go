@@ -0,0 +1,68 @@
+package metricrepository
+
+import (
+	"context"
+	"net/http"
+	"rap-c/app/entity"
+	databaseentity "rap-c/app/entity/database-entity"
+	"rap-c/app/repository/contract"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type repo struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) contract.MetricRepository {
+	return &repo{db}
+}
+
+func (r *repo) GetTotalActiveUsers(ctx context.Context) (int64, error) {
+	var result int64
+	err := r.db.WithContext(ctx).
+		Model(databaseentity.User{}).
+		Where("is_guest = ? and disabled = ?", false, false).
+		Count(&result).Error
+	if err != nil {
+		return 0, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.MetricRepoGetTotalActiveUsersError, err.Error()),
+		}
+	}
+	return result, nil
+}
+
+func (r *repo) GetTotalLowStockIngredients(ctx context.Context, threshold float64) (int64, error) {
+	var result int64
+	err := r.db.WithContext(ctx).
+		Model(databaseentity.Ingredient{}).
+		Where("stock <= ?", threshold).
+		Count(&result).Error
+	if err != nil {
+		return 0, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.MetricRepoGetTotalLowStockIngredientsError, err.Error()),
+		}
+	}
+	return result, nil
+}
+
+func (r *repo) GetTotalPendingMails(ctx context.Context) (int64, error) {
+	var result int64
+	err := r.db.WithContext(ctx).
+		Model(databaseentity.MailOutbox{}).
+		Where("status = ?", databaseentity.MailStatusPending).
+		Count(&result).Error
+	if err != nil {
+		return 0, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.MetricRepoGetTotalPendingMailsError, err.Error()),
+		}
+	}
+	return result, nil
+}
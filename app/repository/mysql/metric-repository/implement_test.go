@@ -0,0 +1,54 @@
+package metricrepository_test
+
+import (
+	"context"
+	databaseentity "rap-c/app/entity/database-entity"
+	metricrepository "rap-c/app/repository/mysql/metric-repository"
+	testdatabase "rap-c/app/repository/test-database"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Totals(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := metricrepository.New(db)
+	ctx := context.Background()
+	testdatabase.User(t, db, &databaseentity.User{})
+	testdatabase.User(t, db, &databaseentity.User{Disabled: true})
+	testdatabase.User(t, db, &databaseentity.User{IsGuest: true})
+	testdatabase.Ingredient(t, db, &databaseentity.Ingredient{Stock: 1})
+	testdatabase.Ingredient(t, db, &databaseentity.Ingredient{Stock: 5})
+	testdatabase.Ingredient(t, db, &databaseentity.Ingredient{Stock: 10})
+	testdatabase.MailOutbox(t, db, &databaseentity.MailOutbox{})
+	testdatabase.MailOutbox(t, db, &databaseentity.MailOutbox{Status: databaseentity.MailStatusSent})
+
+	tests := []struct {
+		name  string
+		total func() (int64, error)
+		want  int64
+	}{
+		{
+			name:  "active users exclude disabled & guest",
+			total: func() (int64, error) { return repo.GetTotalActiveUsers(ctx) },
+			want:  1,
+		},
+		{
+			name:  "low stock ingredients include threshold",
+			total: func() (int64, error) { return repo.GetTotalLowStockIngredients(ctx, 5) },
+			want:  2,
+		},
+		{
+			name:  "pending mails",
+			total: func() (int64, error) { return repo.GetTotalPendingMails(ctx) },
+			want:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total, err := tt.total()
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, total)
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package outboxrepository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"rap-c/app/entity"
+	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	"rap-c/app/helper"
+	"rap-c/app/repository/contract"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+const (
+	minQueryLimit int = 10
+	maxQueryLimit int = 100
+)
+
+type repo struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) contract.OutboxRepository {
+	return &repo{db}
+}
+
+func (r *repo) Create(ctx context.Context, mails ...*databaseentity.MailOutbox) error {
+	if len(mails) == 0 {
+		return nil
+	}
+	err := r.db.Create(mails).Error
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.OutboxRepoCreateError, err.Error()),
+		}
+	}
+	return nil
+}
+
+func (r *repo) ClaimPendingMails(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*databaseentity.MailOutbox, error) {
+	var mails []*databaseentity.MailOutbox
+	err := r.db.Where("status = ? and next_attempt_at <= ?", databaseentity.MailStatusPending, now).
+		Order("next_attempt_at asc").
+		Limit(limit).
+		Find(&mails).
+		Error
+	if err != nil {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.OutboxRepoClaimPendingMailsError, err.Error()),
+		}
+	}
+
+	var result []*databaseentity.MailOutbox
+	for _, mail := range mails {
+		// only claim mail that has not been touched by other worker since selected
+		leaseUntil := now.Add(lease)
+		res := r.db.Model(databaseentity.MailOutbox{}).
+			Where("id = ? and status = ? and next_attempt_at = ?", mail.ID, databaseentity.MailStatusPending, mail.NextAttemptAt).
+			Update("next_attempt_at", leaseUntil)
+		if res.Error != nil {
+			return nil, &echo.HTTPError{
+				Code:     http.StatusInternalServerError,
+				Message:  http.StatusText(http.StatusInternalServerError),
+				Internal: entity.NewInternalError(entity.OutboxRepoClaimPendingMailsError, res.Error.Error()),
+			}
+		}
+		if res.RowsAffected == 1 {
+			mail.NextAttemptAt = leaseUntil
+			result = append(result, mail)
+		}
+	}
+	return result, nil
+}
+
+func (r *repo) Update(ctx context.Context, mail *databaseentity.MailOutbox) error {
+	if mail.ID == 0 {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.OutboxRepoUpdateError, "data not found, empty primary key"),
+		}
+	}
+	err := r.db.Save(mail).Error
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.OutboxRepoUpdateError, err.Error()),
+		}
+	}
+	return nil
+}
+
+func (r *repo) GetMailByID(ctx context.Context, id int) (*databaseentity.MailOutbox, error) {
+	var result databaseentity.MailOutbox
+	err := r.db.Where("id = ?", id).First(&result).Error
+	if err != nil {
+		if helper.IsNotFoundError(err) {
+			return nil, &echo.HTTPError{
+				Code:     http.StatusNotFound,
+				Message:  fmt.Sprintf(entity.MailNotFoundMessage, id),
+				Internal: entity.NewInternalError(entity.MailNotFound, err.Error()),
+			}
+		}
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.OutboxRepoGetMailByIDError, err.Error()),
+		}
+	}
+	return &result, nil
+}
+
+func (r *repo) GetTotalDeadLettersByRequest(ctx context.Context, req *payloadentity.GetDeadLetterListRequest) (int64, error) {
+	var result int64
+	qry := r.renderDeadLettersQuery(req)
+	err := qry.Model(databaseentity.MailOutbox{}).Count(&result).Error
+	if err != nil {
+		return 0, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.OutboxRepoGetTotalDeadLettersError, err.Error()),
+		}
+	}
+	return result, nil
+}
+
+func (r *repo) GetDeadLettersByRequest(ctx context.Context, req *payloadentity.GetDeadLetterListRequest) ([]*databaseentity.MailOutbox, error) {
+	var result []*databaseentity.MailOutbox
+	qry := r.renderDeadLettersQuery(req)
+	order := "asc"
+	if req.DescendingOrder {
+		order = "desc"
+	}
+	// validate limit
+	if req.Limit < minQueryLimit {
+		req.Limit = minQueryLimit
+	} else if req.Limit > maxQueryLimit {
+		req.Limit = maxQueryLimit
+	}
+	err := qry.Order(fmt.Sprintf("updated_at %s", order)).
+		Limit(req.Limit).
+		Offset(req.Page.GetOffset(req.Limit)).
+		Find(&result).
+		Error
+	if err != nil {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.OutboxRepoGetDeadLettersError, err.Error()),
+		}
+	}
+	return result, nil
+}
+
+func (r *repo) renderDeadLettersQuery(req *payloadentity.GetDeadLetterListRequest) *gorm.DB {
+	qry := r.db.Where("status = ?", databaseentity.MailStatusDead)
+	if req.Recipient != "" {
+		qry = qry.Where("lower(recipient) like lower(?)", fmt.Sprintf("%%%s%%", req.Recipient))
+	}
+	return qry
+}
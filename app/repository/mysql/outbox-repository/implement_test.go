@@ -0,0 +1,111 @@
+package outboxrepository_test
+
+import (
+	"context"
+	"net/http"
+	"rap-c/app/entity"
+	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	outboxrepository "rap-c/app/repository/mysql/outbox-repository"
+	testdatabase "rap-c/app/repository/test-database"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ClaimPendingMails(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := outboxrepository.New(db)
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	late := testdatabase.MailOutbox(t, db, &databaseentity.MailOutbox{NextAttemptAt: now.Add(-time.Minute)})
+	oldest := testdatabase.MailOutbox(t, db, &databaseentity.MailOutbox{NextAttemptAt: now.Add(-time.Hour)})
+	testdatabase.MailOutbox(t, db, &databaseentity.MailOutbox{NextAttemptAt: now.Add(time.Minute)})
+	testdatabase.MailOutbox(t, db, &databaseentity.MailOutbox{Status: databaseentity.MailStatusDead, NextAttemptAt: now.Add(-time.Hour)})
+
+	tests := []struct {
+		name  string
+		now   time.Time
+		limit int
+		ids   []int
+	}{
+		{name: "oldest due mail first", now: now, limit: 1, ids: []int{oldest.ID}},
+		{name: "claimed mail is skipped until lease ends", now: now, limit: 10, ids: []int{late.ID}},
+		{name: "nothing due", now: now, limit: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mails, err := repo.ClaimPendingMails(ctx, tt.now, tt.limit, time.Minute*5)
+			assert.Nil(t, err)
+			var ids []int
+			for _, mail := range mails {
+				ids = append(ids, mail.ID)
+				assert.True(t, mail.NextAttemptAt.Equal(tt.now.Add(time.Minute*5)))
+			}
+			assert.Equal(t, tt.ids, ids)
+		})
+	}
+}
+
+func Test_GetMailByID(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := outboxrepository.New(db)
+	ctx := context.Background()
+	mail := testdatabase.MailOutbox(t, db, &databaseentity.MailOutbox{})
+
+	t.Run("success", func(t *testing.T) {
+		result, err := repo.GetMailByID(ctx, mail.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, mail.Recipient, result.Recipient)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := repo.GetMailByID(ctx, mail.ID+1)
+		testdatabase.AssertHTTPError(t, err, http.StatusNotFound, entity.MailNotFound)
+	})
+
+	t.Run("update empty primary key", func(t *testing.T) {
+		err := repo.Update(ctx, &databaseentity.MailOutbox{})
+		testdatabase.AssertHTTPError(t, err, http.StatusInternalServerError, entity.OutboxRepoUpdateError)
+	})
+}
+
+func Test_GetDeadLettersByRequest(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := outboxrepository.New(db)
+	ctx := context.Background()
+	testdatabase.MailOutbox(t, db, &databaseentity.MailOutbox{Recipient: "pending@example.com"})
+	first := testdatabase.MailOutbox(t, db, &databaseentity.MailOutbox{Recipient: "alice@example.com", Status: databaseentity.MailStatusDead})
+	second := testdatabase.MailOutbox(t, db, &databaseentity.MailOutbox{Recipient: "bob@mail.com", Status: databaseentity.MailStatusDead})
+	// distinct update time, so sorting is deterministic
+	first.UpdatedAt = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	second.UpdatedAt = first.UpdatedAt.Add(time.Hour)
+	assert.Nil(t, db.Model(first).UpdateColumn("updated_at", first.UpdatedAt).Error)
+	assert.Nil(t, db.Model(second).UpdateColumn("updated_at", second.UpdatedAt).Error)
+
+	tests := []struct {
+		name string
+		req  *payloadentity.GetDeadLetterListRequest
+		ids  []int
+	}{
+		{name: "dead letters only", req: &payloadentity.GetDeadLetterListRequest{}, ids: []int{first.ID, second.ID}},
+		{name: "descending", req: &payloadentity.GetDeadLetterListRequest{DescendingOrder: true}, ids: []int{second.ID, first.ID}},
+		{name: "by recipient", req: &payloadentity.GetDeadLetterListRequest{Recipient: "EXAMPLE"}, ids: []int{first.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total, err := repo.GetTotalDeadLettersByRequest(ctx, tt.req)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(len(tt.ids)), total)
+
+			mails, err := repo.GetDeadLettersByRequest(ctx, tt.req)
+			assert.Nil(t, err)
+			var ids []int
+			for _, mail := range mails {
+				ids = append(ids, mail.ID)
+			}
+			assert.Equal(t, tt.ids, ids)
+		})
+	}
+}
@@ -0,0 +1,538 @@
+package reciperepository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"rap-c/app/entity"
+	databaseentity "rap-c/app/entity/database-entity"
+	"rap-c/app/helper"
+	"rap-c/app/repository/contract"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repo struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) contract.RecipeRepository {
+	return &repo{db}
+}
+
+func (r *repo) GetRecipeBySerial(ctx context.Context, serial string) (*databaseentity.Recipe, error) {
+	var result databaseentity.Recipe
+	err := r.db.WithContext(ctx).Preload("Unit").Where("serial = ?", serial).First(&result).Error
+	if err != nil {
+		if helper.IsNotFoundError(err) {
+			return nil, &echo.HTTPError{
+				Code:     http.StatusNotFound,
+				Message:  fmt.Sprintf(entity.RecipeNotFoundMessage, serial),
+				Internal: entity.NewInternalError(entity.RecipeNotFound, err.Error()),
+			}
+		}
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.RecipeRepoGetRecipeBySerialError, err.Error()),
+		}
+	}
+	return &result, nil
+}
+
+func (r *repo) GetRecipeIngredients(ctx context.Context, recipe *databaseentity.Recipe) ([]*databaseentity.RecipeIngredient, error) {
+	var result []*databaseentity.RecipeIngredient
+	err := r.db.WithContext(ctx).
+		Preload("Ingredient.Unit").
+		Preload("SubRecipe.Unit").
+		Preload("Unit").
+		Where("recipe_id = ?", recipe.ID).
+		Order("serial").
+		Find(&result).
+		Error
+	if err != nil {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.RecipeRepoGetRecipeIngredientsError, err.Error()),
+		}
+	}
+	return result, nil
+}
+
+func (r *repo) GetRecipeIngredientBySerial(ctx context.Context, serial string) (*databaseentity.RecipeIngredient, error) {
+	var result databaseentity.RecipeIngredient
+	err := r.db.WithContext(ctx).
+		Preload("Recipe.Unit").
+		Preload("Ingredient.Unit").
+		Preload("SubRecipe.Unit").
+		Preload("Unit").
+		Where("serial = ?", serial).
+		First(&result).
+		Error
+	if err != nil {
+		if helper.IsNotFoundError(err) {
+			return nil, &echo.HTTPError{
+				Code:     http.StatusNotFound,
+				Message:  fmt.Sprintf(entity.RecipeIngredientNotFoundMessage, serial),
+				Internal: entity.NewInternalError(entity.RecipeIngredientNotFound, err.Error()),
+			}
+		}
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.RecipeRepoGetRecipeIngredientBySerialError, err.Error()),
+		}
+	}
+	return &result, nil
+}
+
+func (r *repo) CreateRecipeIngredient(ctx context.Context, line *databaseentity.RecipeIngredient) (err error) {
+	tx := r.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			err = &echo.HTTPError{
+				Code:     http.StatusInternalServerError,
+				Message:  http.StatusText(http.StatusInternalServerError),
+				Internal: entity.NewInternalError(entity.RecipeRepoCreateRecipeIngredientError, fmt.Sprint(r)),
+			}
+			tx.Rollback()
+		}
+	}()
+	if err = tx.Error; err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.RecipeRepoCreateRecipeIngredientError, err.Error()),
+		}
+	}
+
+	err = checkSubRecipeCycle(tx, line, entity.RecipeRepoCreateRecipeIngredientError)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// preloaded recipe, ingredient, sub recipe & unit are not saved
+	err = tx.Omit(clause.Associations).Create(line).Error
+	if err != nil {
+		tx.Rollback()
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.RecipeRepoCreateRecipeIngredientError, err.Error()),
+		}
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.RecipeRepoCreateRecipeIngredientError, err.Error()),
+		}
+	}
+	return nil
+}
+
+func (r *repo) UpdateRecipeIngredient(ctx context.Context, line *databaseentity.RecipeIngredient) (err error) {
+	if line.ID == 0 {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.RecipeRepoUpdateRecipeIngredientError, "data not found, empty primary key"),
+		}
+	}
+	tx := r.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			err = &echo.HTTPError{
+				Code:     http.StatusInternalServerError,
+				Message:  http.StatusText(http.StatusInternalServerError),
+				Internal: entity.NewInternalError(entity.RecipeRepoUpdateRecipeIngredientError, fmt.Sprint(r)),
+			}
+			tx.Rollback()
+		}
+	}()
+	if err = tx.Error; err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.RecipeRepoUpdateRecipeIngredientError, err.Error()),
+		}
+	}
+
+	err = checkSubRecipeCycle(tx, line, entity.RecipeRepoUpdateRecipeIngredientError)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// map is used so switching between ingredient & sub recipe clears the other column
+	now := time.Now()
+	err = tx.Model(&databaseentity.RecipeIngredient{ID: line.ID}).Updates(map[string]interface{}{
+		"ingredient_id": line.IngredientID,
+		"sub_recipe_id": line.SubRecipeID,
+		"unit_id":       line.UnitID,
+		"quantity":      line.Quantity,
+		"updated_at":    now,
+		"updated_by":    line.UpdatedBy,
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.RecipeRepoUpdateRecipeIngredientError, err.Error()),
+		}
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.RecipeRepoUpdateRecipeIngredientError, err.Error()),
+		}
+	}
+	line.UpdatedAt = now
+	return nil
+}
+
+func (r *repo) GetIngredientConversions(ctx context.Context, ingredientIDs []int) ([]*databaseentity.IngredientConvertionUnit, error) {
+	var result []*databaseentity.IngredientConvertionUnit
+	if len(ingredientIDs) == 0 {
+		return result, nil
+	}
+	err := r.db.WithContext(ctx).
+		Preload("Unit").
+		Where("ingredient_id IN ?", ingredientIDs).
+		Order("serial").
+		Find(&result).
+		Error
+	if err != nil {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.RecipeRepoGetIngredientConversionsError, err.Error()),
+		}
+	}
+	return result, nil
+}
+
+func (r *repo) GetParentRecipes(ctx context.Context, recipe *databaseentity.Recipe) ([]*databaseentity.Recipe, error) {
+	var result []*databaseentity.Recipe
+	db := r.db.WithContext(ctx)
+	err := db.
+		Preload("Unit").
+		Where("id IN (?)", db.Model(databaseentity.RecipeIngredient{}).Select("recipe_id").Where("sub_recipe_id = ?", recipe.ID)).
+		Order("serial").
+		Find(&result).
+		Error
+	if err != nil {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.RecipeRepoGetParentRecipesError, err.Error()),
+		}
+	}
+	return result, nil
+}
+
+func (r *repo) GetRecipesByIngredient(ctx context.Context, ingredient *databaseentity.Ingredient) ([]*databaseentity.Recipe, error) {
+	var result []*databaseentity.Recipe
+	db := r.db.WithContext(ctx)
+	err := db.
+		Preload("Unit").
+		Where("id IN (?)", db.Model(databaseentity.RecipeIngredient{}).Select("recipe_id").Where("ingredient_id = ?", ingredient.ID)).
+		Order("serial").
+		Find(&result).
+		Error
+	if err != nil {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.RecipeRepoGetRecipesByIngredientError, err.Error()),
+		}
+	}
+	return result, nil
+}
+
+func (r *repo) GetRecipesByUnit(ctx context.Context, unit *databaseentity.Unit) ([]*databaseentity.Recipe, error) {
+	var result []*databaseentity.Recipe
+	db := r.db.WithContext(ctx)
+	ingredients := db.Model(databaseentity.Ingredient{}).Select("id").Where("unit_id = ?", unit.ID)
+	conversions := db.Model(databaseentity.IngredientConvertionUnit{}).Select("ingredient_id").Where("unit_id = ?", unit.ID)
+	lines := db.Model(databaseentity.RecipeIngredient{}).
+		Select("recipe_id").
+		Where("unit_id = ? OR ingredient_id IN (?) OR ingredient_id IN (?)", unit.ID, ingredients, conversions)
+	err := db.
+		Preload("Unit").
+		Where("unit_id = ? OR id IN (?)", unit.ID, lines).
+		Order("serial").
+		Find(&result).
+		Error
+	if err != nil {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.RecipeRepoGetRecipesByUnitError, err.Error()),
+		}
+	}
+	return result, nil
+}
+
+func (r *repo) UpdateRawMaterialCosts(ctx context.Context, recipes []*databaseentity.Recipe) (err error) {
+	tx := r.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			err = &echo.HTTPError{
+				Code:     http.StatusInternalServerError,
+				Message:  http.StatusText(http.StatusInternalServerError),
+				Internal: entity.NewInternalError(entity.RecipeRepoUpdateRawMaterialCostsError, fmt.Sprint(r)),
+			}
+			tx.Rollback()
+		}
+	}()
+	if err = tx.Error; err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.RecipeRepoUpdateRawMaterialCostsError, err.Error()),
+		}
+	}
+
+	// costs are updated by primary key, so every change is written to audit log
+	now := time.Now()
+	for _, recipe := range recipes {
+		if recipe.ID == 0 {
+			tx.Rollback()
+			return &echo.HTTPError{
+				Code:     http.StatusInternalServerError,
+				Message:  http.StatusText(http.StatusInternalServerError),
+				Internal: entity.NewInternalError(entity.RecipeRepoUpdateRawMaterialCostsError, "data not found, empty primary key"),
+			}
+		}
+		err = tx.Model(&databaseentity.Recipe{ID: recipe.ID}).Updates(map[string]interface{}{
+			"raw_material_costs": recipe.RawMaterialCosts,
+			"updated_at":         now,
+			"updated_by":         recipe.UpdatedBy,
+		}).Error
+		if err != nil {
+			tx.Rollback()
+			return &echo.HTTPError{
+				Code:     http.StatusInternalServerError,
+				Message:  http.StatusText(http.StatusInternalServerError),
+				Internal: entity.NewInternalError(entity.RecipeRepoUpdateRawMaterialCostsError, err.Error()),
+			}
+		}
+	}
+	err = tx.Commit().Error
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.RecipeRepoUpdateRawMaterialCostsError, err.Error()),
+		}
+	}
+
+	// update time in memory follows the database
+	for _, recipe := range recipes {
+		recipe.UpdatedAt = now
+	}
+	return nil
+}
+
+func (r *repo) Produce(ctx context.Context, production *databaseentity.RecipeProduction, authorID int) (err error) {
+	tx := r.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			err = &echo.HTTPError{
+				Code:     http.StatusInternalServerError,
+				Message:  http.StatusText(http.StatusInternalServerError),
+				Internal: entity.NewInternalError(entity.RecipeRepoProduceError, fmt.Sprint(r)),
+			}
+			tx.Rollback()
+		}
+	}()
+	if err = tx.Error; err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.RecipeRepoProduceError, err.Error()),
+		}
+	}
+
+	// stock is taken only when enough is left, the update checks it so concurrent productions cannot take
+	// the same stock twice. stocks are updated by primary key, so every change is written to audit log.
+	// quantities are rounded like stock columns, so what is checked is what is taken
+	now := time.Now()
+	description := fmt.Sprintf(databaseentity.StockMovementProduceDescription, production.Recipe.Serial)
+	var movements []*databaseentity.StockMovement
+	for _, itm := range production.Ingredients {
+		ingredient := itm.Ingredient
+		itm.Quantity = roundStock(itm.Quantity)
+		stock, enough, err := takeStock(tx, &databaseentity.Ingredient{ID: ingredient.ID}, ingredient.ID, itm.Quantity, now, authorID)
+		if err != nil {
+			tx.Rollback()
+			return &echo.HTTPError{
+				Code:     http.StatusInternalServerError,
+				Message:  http.StatusText(http.StatusInternalServerError),
+				Internal: entity.NewInternalError(entity.RecipeRepoProduceError, err.Error()),
+			}
+		}
+		if !enough {
+			tx.Rollback()
+			return stockNotEnoughError(entity.ProduceStockNotEnoughMessage, ingredient.Name, itm.Quantity-stock, ingredient.Unit)
+		}
+		if itm.Quantity > 0 {
+			movements = append(movements, &databaseentity.StockMovement{IngredientID: &ingredient.ID, MovementType: databaseentity.StockMovementTypeOut,
+				Quantity: itm.Quantity, Description: description, CreatedAt: now, CreatedBy: authorID})
+		}
+	}
+	for _, itm := range production.SubRecipes {
+		recipe := itm.Recipe
+		itm.Quantity = roundStock(itm.Quantity)
+		stock, enough, err := takeStock(tx, &databaseentity.Recipe{ID: recipe.ID}, recipe.ID, itm.Quantity, now, authorID)
+		if err != nil {
+			tx.Rollback()
+			return &echo.HTTPError{
+				Code:     http.StatusInternalServerError,
+				Message:  http.StatusText(http.StatusInternalServerError),
+				Internal: entity.NewInternalError(entity.RecipeRepoProduceError, err.Error()),
+			}
+		}
+		if !enough {
+			tx.Rollback()
+			return stockNotEnoughError(entity.ProduceSubRecipeStockNotEnoughMessage, recipe.Name, itm.Quantity-stock, recipe.Unit)
+		}
+		if itm.Quantity > 0 {
+			movements = append(movements, &databaseentity.StockMovement{RecipeID: &recipe.ID, MovementType: databaseentity.StockMovementTypeOut,
+				Quantity: itm.Quantity, Description: description, CreatedAt: now, CreatedBy: authorID})
+		}
+	}
+	production.Quantity = roundStock(production.Quantity)
+	movements = append(movements, &databaseentity.StockMovement{RecipeID: &production.Recipe.ID, MovementType: databaseentity.StockMovementTypeIn,
+		Quantity: production.Quantity, Description: description, CreatedAt: now, CreatedBy: authorID})
+	err = tx.Model(&databaseentity.Recipe{ID: production.Recipe.ID}).Updates(map[string]interface{}{
+		"stock":      gorm.Expr("stock + ?", production.Quantity),
+		"updated_at": now,
+		"updated_by": authorID,
+	}).Error
+	if err == nil {
+		err = tx.Omit(clause.Associations).Create(movements).Error
+	}
+	if err == nil {
+		err = tx.Commit().Error
+	} else {
+		tx.Rollback()
+	}
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.RecipeRepoProduceError, err.Error()),
+		}
+	}
+
+	// stock in memory follows the database
+	for _, itm := range production.Ingredients {
+		itm.Ingredient.Stock -= float32(itm.Quantity)
+	}
+	for _, itm := range production.SubRecipes {
+		itm.Recipe.Stock -= float32(itm.Quantity)
+	}
+	production.Recipe.Stock += float32(production.Quantity)
+	return nil
+}
+
+// take quantity from stock of ingredient or recipe only when enough is left,
+// current stock is returned when it is not enough
+func takeStock(tx *gorm.DB, model interface{}, id int, quantity float64, now time.Time, authorID int) (float64, bool, error) {
+	if quantity <= 0 {
+		return 0, true, nil
+	}
+	res := tx.Model(model).Where("stock >= ?", quantity).Updates(map[string]interface{}{
+		"stock":      gorm.Expr("stock - ?", quantity),
+		"updated_at": now,
+		"updated_by": authorID,
+	})
+	if res.Error != nil {
+		return 0, false, res.Error
+	}
+	if res.RowsAffected > 0 {
+		return 0, true, nil
+	}
+	var stocks []float64
+	err := tx.Model(model).Where("id = ?", id).Pluck("stock", &stocks).Error
+	if err != nil {
+		return 0, false, err
+	}
+	if len(stocks) == 0 {
+		return 0, false, nil
+	}
+	return stocks[0], false, nil
+}
+
+// stock columns keep 2 decimals
+func roundStock(quantity float64) float64 {
+	return math.Round(quantity*100) / 100
+}
+
+func stockNotEnoughError(format, name string, shortage float64, unit *databaseentity.Unit) error {
+	var unitName string
+	if unit != nil {
+		unitName = unit.Name
+	}
+	message := fmt.Sprintf(format, name, shortage, unitName)
+	return &echo.HTTPError{
+		Code:     http.StatusConflict,
+		Message:  message,
+		Internal: entity.NewInternalError(entity.ProduceStockNotEnough, message),
+	}
+}
+
+// sub recipe makes a cycle when it is the recipe itself or one of recipes using it directly or through other sub recipes.
+// recipes using the line recipe are read inside the transaction writing the line
+func checkSubRecipeCycle(tx *gorm.DB, line *databaseentity.RecipeIngredient, errorCode int) error {
+	if line.SubRecipeID == nil {
+		return nil
+	}
+	queue := []int{line.RecipeID}
+	queued := map[int]bool{line.RecipeID: true}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == *line.SubRecipeID {
+			serial := ""
+			if line.SubRecipe != nil {
+				serial = line.SubRecipe.Serial
+			}
+			return &echo.HTTPError{
+				Code:     http.StatusConflict,
+				Message:  fmt.Sprintf(entity.SubRecipeCycleMessage, serial),
+				Internal: entity.NewInternalError(entity.SubRecipeCycle, entity.SubRecipeCycleMessage),
+			}
+		}
+		var parentIDs []int
+		err := tx.Model(databaseentity.RecipeIngredient{}).Where("sub_recipe_id = ?", current).Distinct().Pluck("recipe_id", &parentIDs).Error
+		if err != nil {
+			return &echo.HTTPError{
+				Code:     http.StatusInternalServerError,
+				Message:  http.StatusText(http.StatusInternalServerError),
+				Internal: entity.NewInternalError(errorCode, err.Error()),
+			}
+		}
+		for _, parentID := range parentIDs {
+			if !queued[parentID] {
+				queued[parentID] = true
+				queue = append(queue, parentID)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,548 @@
+package reciperepository_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"rap-c/app/entity"
+	databaseentity "rap-c/app/entity/database-entity"
+	reciperepository "rap-c/app/repository/mysql/recipe-repository"
+	testdatabase "rap-c/app/repository/test-database"
+	"sync"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GetRecipeBySerial(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := reciperepository.New(db)
+	ctx := context.Background()
+	unit := testdatabase.Unit(t, db, &databaseentity.Unit{})
+	recipe := testdatabase.Recipe(t, db, &databaseentity.Recipe{Quantity: 10, UnitID: &unit.ID})
+
+	t.Run("success", func(t *testing.T) {
+		result, err := repo.GetRecipeBySerial(ctx, recipe.Serial)
+		assert.Nil(t, err)
+		assert.Equal(t, recipe.ID, result.ID)
+		assert.Equal(t, 10, result.Quantity)
+		if assert.NotNil(t, result.Unit) {
+			assert.Equal(t, unit.Name, result.Unit.Name)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := repo.GetRecipeBySerial(ctx, "RCP404")
+		testdatabase.AssertHTTPError(t, err, http.StatusNotFound, entity.RecipeNotFound)
+	})
+}
+
+func Test_GetRecipeIngredients(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := reciperepository.New(db)
+	ctx := context.Background()
+	recipe := testdatabase.Recipe(t, db, &databaseentity.Recipe{})
+	ingredient := testdatabase.Ingredient(t, db, &databaseentity.Ingredient{})
+	testdatabase.RecipeIngredient(t, db, &databaseentity.RecipeIngredient{Serial: "RCI02", RecipeID: recipe.ID, IngredientID: &ingredient.ID, Quantity: 2})
+	testdatabase.RecipeIngredient(t, db, &databaseentity.RecipeIngredient{Serial: "RCI01", RecipeID: recipe.ID})
+	unit := testdatabase.Unit(t, db, &databaseentity.Unit{})
+	sauce := testdatabase.Recipe(t, db, &databaseentity.Recipe{UnitID: &unit.ID})
+	testdatabase.RecipeIngredient(t, db, &databaseentity.RecipeIngredient{Serial: "RCI03", RecipeID: recipe.ID, SubRecipeID: &sauce.ID})
+	// other recipe
+	testdatabase.RecipeIngredient(t, db, &databaseentity.RecipeIngredient{})
+
+	lines, err := repo.GetRecipeIngredients(ctx, recipe)
+	assert.Nil(t, err)
+	if assert.Len(t, lines, 3) {
+		assert.Nil(t, lines[2].Ingredient)
+		if assert.NotNil(t, lines[2].SubRecipe) && assert.NotNil(t, lines[2].SubRecipe.Unit) {
+			assert.Equal(t, sauce.ID, lines[2].SubRecipe.ID)
+			assert.Equal(t, unit.ID, lines[2].SubRecipe.Unit.ID)
+		}
+		assert.True(t, lines[2].IsSubRecipe())
+		assert.Equal(t, "RCI01", lines[0].Serial)
+		assert.Equal(t, "RCI02", lines[1].Serial)
+		assert.Equal(t, float32(2), lines[1].Quantity)
+		if assert.NotNil(t, lines[1].Ingredient) && assert.NotNil(t, lines[1].Ingredient.Unit) {
+			assert.Equal(t, ingredient.UnitID, lines[1].Ingredient.Unit.ID)
+		}
+		if assert.NotNil(t, lines[1].Unit) {
+			assert.Equal(t, lines[1].UnitID, lines[1].Unit.ID)
+		}
+	}
+}
+
+func Test_GetRecipeIngredientBySerial(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := reciperepository.New(db)
+	ctx := context.Background()
+	unit := testdatabase.Unit(t, db, &databaseentity.Unit{})
+	recipe := testdatabase.Recipe(t, db, &databaseentity.Recipe{UnitID: &unit.ID})
+	line := testdatabase.RecipeIngredient(t, db, &databaseentity.RecipeIngredient{RecipeID: recipe.ID, Quantity: 3})
+
+	t.Run("success", func(t *testing.T) {
+		result, err := repo.GetRecipeIngredientBySerial(ctx, line.Serial)
+		assert.Nil(t, err)
+		assert.Equal(t, line.ID, result.ID)
+		assert.Equal(t, float32(3), result.Quantity)
+		if assert.NotNil(t, result.Recipe) && assert.NotNil(t, result.Recipe.Unit) {
+			assert.Equal(t, recipe.ID, result.Recipe.ID)
+			assert.Equal(t, unit.ID, result.Recipe.Unit.ID)
+		}
+		if assert.NotNil(t, result.Ingredient) {
+			assert.NotNil(t, result.Ingredient.Unit)
+		}
+		assert.Nil(t, result.SubRecipe)
+		if assert.NotNil(t, result.Unit) {
+			assert.Equal(t, line.UnitID, result.Unit.ID)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := repo.GetRecipeIngredientBySerial(ctx, "RCI404")
+		testdatabase.AssertHTTPError(t, err, http.StatusNotFound, entity.RecipeIngredientNotFound)
+	})
+}
+
+func Test_CreateRecipeIngredient(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := reciperepository.New(db)
+	ctx := context.Background()
+	recipe := testdatabase.Recipe(t, db, &databaseentity.Recipe{})
+	sauce := testdatabase.Recipe(t, db, &databaseentity.Recipe{})
+	unit := testdatabase.Unit(t, db, &databaseentity.Unit{})
+
+	line := &databaseentity.RecipeIngredient{
+		Serial:      "RCI01",
+		RecipeID:    recipe.ID,
+		Recipe:      recipe,
+		SubRecipeID: &sauce.ID,
+		SubRecipe:   sauce,
+		UnitID:      unit.ID,
+		Unit:        unit,
+		Quantity:    2,
+		CreatedBy:   5,
+		UpdatedBy:   5,
+	}
+	assert.Nil(t, repo.CreateRecipeIngredient(ctx, line))
+	assert.NotZero(t, line.ID)
+
+	result, err := repo.GetRecipeIngredientBySerial(ctx, "RCI01")
+	assert.Nil(t, err)
+	assert.Nil(t, result.IngredientID)
+	assert.Equal(t, &sauce.ID, result.SubRecipeID)
+	assert.Equal(t, float32(2), result.Quantity)
+	assert.Equal(t, 5, result.CreatedBy)
+
+	// new line is audited
+	var total int64
+	assert.Nil(t, db.Model(databaseentity.AuditLog{}).
+		Where("entity_type = ? AND entity_id = ? AND action = ?", "recipe_ingredient", line.ID, databaseentity.AuditActionCreate).
+		Count(&total).Error)
+	assert.Equal(t, int64(1), total)
+
+	t.Run("sub recipe cycle", func(t *testing.T) {
+		for _, subRecipe := range []*databaseentity.Recipe{sauce, recipe} {
+			err := repo.CreateRecipeIngredient(ctx, &databaseentity.RecipeIngredient{
+				Serial:      "RCI02",
+				RecipeID:    sauce.ID,
+				SubRecipeID: &subRecipe.ID,
+				SubRecipe:   subRecipe,
+				UnitID:      unit.ID,
+				Quantity:    1,
+			})
+			testdatabase.AssertHTTPError(t, err, http.StatusConflict, entity.SubRecipeCycle)
+		}
+		_, err := repo.GetRecipeIngredientBySerial(ctx, "RCI02")
+		testdatabase.AssertHTTPError(t, err, http.StatusNotFound, entity.RecipeIngredientNotFound)
+	})
+}
+
+func Test_UpdateRecipeIngredient(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := reciperepository.New(db)
+	ctx := context.Background()
+	sauce := testdatabase.Recipe(t, db, &databaseentity.Recipe{})
+	unit := testdatabase.Unit(t, db, &databaseentity.Unit{})
+	line := testdatabase.RecipeIngredient(t, db, &databaseentity.RecipeIngredient{Quantity: 1})
+
+	t.Run("switch to sub recipe", func(t *testing.T) {
+		line.IngredientID = nil
+		line.SubRecipeID = &sauce.ID
+		line.UnitID = unit.ID
+		line.Quantity = 4
+		line.UpdatedBy = 5
+		assert.Nil(t, repo.UpdateRecipeIngredient(ctx, line))
+
+		result, err := repo.GetRecipeIngredientBySerial(ctx, line.Serial)
+		assert.Nil(t, err)
+		assert.Nil(t, result.IngredientID)
+		assert.Equal(t, &sauce.ID, result.SubRecipeID)
+		assert.Equal(t, unit.ID, result.UnitID)
+		assert.Equal(t, float32(4), result.Quantity)
+		assert.Equal(t, 5, result.UpdatedBy)
+
+		// change is audited
+		var actions []string
+		assert.Nil(t, db.Model(databaseentity.AuditLog{}).
+			Where("entity_type = ? AND entity_id = ?", "recipe_ingredient", line.ID).
+			Order("id").Pluck("action", &actions).Error)
+		assert.Equal(t, []string{databaseentity.AuditActionCreate, databaseentity.AuditActionUpdate}, actions)
+	})
+
+	t.Run("sub recipe cycle", func(t *testing.T) {
+		sauceLine := testdatabase.RecipeIngredient(t, db, &databaseentity.RecipeIngredient{RecipeID: sauce.ID, Quantity: 1})
+		ingredientID := sauceLine.IngredientID
+		sauceLine.IngredientID = nil
+		sauceLine.SubRecipeID = &line.RecipeID
+		err := repo.UpdateRecipeIngredient(ctx, sauceLine)
+		testdatabase.AssertHTTPError(t, err, http.StatusConflict, entity.SubRecipeCycle)
+
+		result, err := repo.GetRecipeIngredientBySerial(ctx, sauceLine.Serial)
+		assert.Nil(t, err)
+		assert.Equal(t, ingredientID, result.IngredientID)
+		assert.Nil(t, result.SubRecipeID)
+	})
+
+	t.Run("empty primary key", func(t *testing.T) {
+		err := repo.UpdateRecipeIngredient(ctx, &databaseentity.RecipeIngredient{})
+		testdatabase.AssertHTTPError(t, err, http.StatusInternalServerError, entity.RecipeRepoUpdateRecipeIngredientError)
+	})
+}
+
+func Test_GetIngredientConversions(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := reciperepository.New(db)
+	ctx := context.Background()
+	flour := testdatabase.IngredientConvertionUnit(t, db, &databaseentity.IngredientConvertionUnit{Serial: "CNV01"})
+	sugar := testdatabase.IngredientConvertionUnit(t, db, &databaseentity.IngredientConvertionUnit{Serial: "CNV02"})
+	// other ingredient
+	testdatabase.IngredientConvertionUnit(t, db, &databaseentity.IngredientConvertionUnit{})
+
+	t.Run("success", func(t *testing.T) {
+		conversions, err := repo.GetIngredientConversions(ctx, []int{sugar.IngredientID, flour.IngredientID})
+		assert.Nil(t, err)
+		if assert.Len(t, conversions, 2) {
+			assert.Equal(t, flour.ID, conversions[0].ID)
+			assert.Equal(t, sugar.ID, conversions[1].ID)
+			assert.NotNil(t, conversions[0].Unit)
+		}
+	})
+
+	t.Run("no ingredient", func(t *testing.T) {
+		conversions, err := repo.GetIngredientConversions(ctx, nil)
+		assert.Nil(t, err)
+		assert.Empty(t, conversions)
+	})
+}
+
+func Test_RecipeIngredientSource(t *testing.T) {
+	db := testdatabase.Open(t)
+	recipe := testdatabase.Recipe(t, db, &databaseentity.Recipe{})
+	sauce := testdatabase.Recipe(t, db, &databaseentity.Recipe{})
+	ingredient := testdatabase.Ingredient(t, db, &databaseentity.Ingredient{})
+	unit := testdatabase.Unit(t, db, &databaseentity.Unit{})
+
+	// line uses either ingredient or sub recipe
+	err := db.Create(&databaseentity.RecipeIngredient{Serial: "RCI01", RecipeID: recipe.ID, IngredientID: &ingredient.ID, SubRecipeID: &sauce.ID, UnitID: unit.ID}).Error
+	assert.NotNil(t, err)
+	err = db.Create(&databaseentity.RecipeIngredient{Serial: "RCI02", RecipeID: recipe.ID, UnitID: unit.ID}).Error
+	assert.NotNil(t, err)
+}
+
+func Test_GetParentRecipes(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := reciperepository.New(db)
+	ctx := context.Background()
+	sauce := testdatabase.Recipe(t, db, &databaseentity.Recipe{})
+	pasta := testdatabase.Recipe(t, db, &databaseentity.Recipe{Serial: "RCP02"})
+	pizza := testdatabase.Recipe(t, db, &databaseentity.Recipe{Serial: "RCP01"})
+	testdatabase.RecipeIngredient(t, db, &databaseentity.RecipeIngredient{RecipeID: pasta.ID, SubRecipeID: &sauce.ID})
+	testdatabase.RecipeIngredient(t, db, &databaseentity.RecipeIngredient{RecipeID: pizza.ID, SubRecipeID: &sauce.ID})
+	testdatabase.RecipeIngredient(t, db, &databaseentity.RecipeIngredient{RecipeID: pizza.ID, SubRecipeID: &sauce.ID})
+	// ingredient line & other recipe
+	testdatabase.RecipeIngredient(t, db, &databaseentity.RecipeIngredient{RecipeID: sauce.ID})
+	testdatabase.RecipeIngredient(t, db, &databaseentity.RecipeIngredient{RecipeID: pasta.ID, SubRecipeID: &pizza.ID})
+
+	parents, err := repo.GetParentRecipes(ctx, sauce)
+	assert.Nil(t, err)
+	if assert.Len(t, parents, 2) {
+		assert.Equal(t, pizza.ID, parents[0].ID)
+		assert.Equal(t, pasta.ID, parents[1].ID)
+	}
+
+	parents, err = repo.GetParentRecipes(ctx, pasta)
+	assert.Nil(t, err)
+	assert.Empty(t, parents)
+}
+
+func Test_GetRecipesByIngredient(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := reciperepository.New(db)
+	ctx := context.Background()
+	flour := testdatabase.Ingredient(t, db, &databaseentity.Ingredient{})
+	bread := testdatabase.Recipe(t, db, &databaseentity.Recipe{Serial: "RCP02"})
+	cake := testdatabase.Recipe(t, db, &databaseentity.Recipe{Serial: "RCP01"})
+	testdatabase.RecipeIngredient(t, db, &databaseentity.RecipeIngredient{RecipeID: bread.ID, IngredientID: &flour.ID})
+	testdatabase.RecipeIngredient(t, db, &databaseentity.RecipeIngredient{RecipeID: cake.ID, IngredientID: &flour.ID})
+	testdatabase.RecipeIngredient(t, db, &databaseentity.RecipeIngredient{RecipeID: cake.ID, IngredientID: &flour.ID})
+	// other ingredient
+	testdatabase.RecipeIngredient(t, db, &databaseentity.RecipeIngredient{})
+
+	recipes, err := repo.GetRecipesByIngredient(ctx, flour)
+	assert.Nil(t, err)
+	if assert.Len(t, recipes, 2) {
+		assert.Equal(t, cake.ID, recipes[0].ID)
+		assert.Equal(t, bread.ID, recipes[1].ID)
+	}
+}
+
+func Test_GetRecipesByUnit(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := reciperepository.New(db)
+	ctx := context.Background()
+	kg := testdatabase.Unit(t, db, &databaseentity.Unit{})
+	flour := testdatabase.Ingredient(t, db, &databaseentity.Ingredient{UnitID: kg.ID})
+	egg := testdatabase.Ingredient(t, db, &databaseentity.Ingredient{})
+	testdatabase.IngredientConvertionUnit(t, db, &databaseentity.IngredientConvertionUnit{IngredientID: egg.ID, UnitID: kg.ID})
+
+	byYield := testdatabase.Recipe(t, db, &databaseentity.Recipe{Serial: "RCP01", UnitID: &kg.ID})
+	byLine := testdatabase.Recipe(t, db, &databaseentity.Recipe{Serial: "RCP02"})
+	testdatabase.RecipeIngredient(t, db, &databaseentity.RecipeIngredient{RecipeID: byLine.ID, UnitID: kg.ID})
+	byIngredient := testdatabase.Recipe(t, db, &databaseentity.Recipe{Serial: "RCP03"})
+	testdatabase.RecipeIngredient(t, db, &databaseentity.RecipeIngredient{RecipeID: byIngredient.ID, IngredientID: &flour.ID})
+	byConversion := testdatabase.Recipe(t, db, &databaseentity.Recipe{Serial: "RCP04"})
+	testdatabase.RecipeIngredient(t, db, &databaseentity.RecipeIngredient{RecipeID: byConversion.ID, IngredientID: &egg.ID})
+	// other unit
+	testdatabase.RecipeIngredient(t, db, &databaseentity.RecipeIngredient{})
+
+	recipes, err := repo.GetRecipesByUnit(ctx, kg)
+	assert.Nil(t, err)
+	var ids []int
+	for _, recipe := range recipes {
+		ids = append(ids, recipe.ID)
+	}
+	assert.Equal(t, []int{byYield.ID, byLine.ID, byIngredient.ID, byConversion.ID}, ids)
+	if assert.NotEmpty(t, recipes) && assert.NotNil(t, recipes[0].Unit) {
+		assert.Equal(t, kg.Name, recipes[0].Unit.Name)
+	}
+}
+
+func Test_UpdateRawMaterialCosts(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := reciperepository.New(db)
+	ctx := context.Background()
+	sauce := testdatabase.Recipe(t, db, &databaseentity.Recipe{})
+	pasta := testdatabase.Recipe(t, db, &databaseentity.Recipe{})
+
+	t.Run("success", func(t *testing.T) {
+		sauce.RawMaterialCosts = 1500
+		sauce.UpdatedBy = 5
+		pasta.RawMaterialCosts = 4000
+		pasta.UpdatedBy = 5
+		assert.Nil(t, repo.UpdateRawMaterialCosts(ctx, []*databaseentity.Recipe{sauce, pasta}))
+
+		for _, recipe := range []*databaseentity.Recipe{sauce, pasta} {
+			result, err := repo.GetRecipeBySerial(ctx, recipe.Serial)
+			assert.Nil(t, err)
+			assert.Equal(t, recipe.RawMaterialCosts, result.RawMaterialCosts)
+			assert.Equal(t, 5, result.UpdatedBy)
+
+			// cost change is audited
+			var actions []string
+			assert.Nil(t, db.Model(databaseentity.AuditLog{}).
+				Where("entity_type = ? AND entity_id = ?", "recipe", recipe.ID).
+				Order("id").Pluck("action", &actions).Error)
+			assert.Equal(t, []string{databaseentity.AuditActionCreate, databaseentity.AuditActionUpdate}, actions)
+		}
+	})
+
+	t.Run("failed recipe rolls back every cost", func(t *testing.T) {
+		sauce.RawMaterialCosts = 2000
+		err := repo.UpdateRawMaterialCosts(ctx, []*databaseentity.Recipe{sauce, {}})
+		testdatabase.AssertHTTPError(t, err, http.StatusInternalServerError, entity.RecipeRepoUpdateRawMaterialCostsError)
+
+		result, err := repo.GetRecipeBySerial(ctx, sauce.Serial)
+		assert.Nil(t, err)
+		assert.Equal(t, float32(1500), result.RawMaterialCosts)
+	})
+}
+
+func Test_Produce(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := reciperepository.New(db)
+	ctx := context.Background()
+	flour := testdatabase.Ingredient(t, db, &databaseentity.Ingredient{Stock: 10})
+	sauce := testdatabase.Recipe(t, db, &databaseentity.Recipe{Stock: 3})
+	pizza := testdatabase.Recipe(t, db, &databaseentity.Recipe{Stock: 1})
+
+	production := &databaseentity.RecipeProduction{
+		Recipe:      pizza,
+		Quantity:    4,
+		Ingredients: []*databaseentity.IngredientConsumption{{Ingredient: flour, Quantity: 2.5}},
+		SubRecipes:  []*databaseentity.SubRecipeConsumption{{Recipe: sauce, Quantity: 1.5}},
+	}
+	assert.Nil(t, repo.Produce(ctx, production, 5))
+	assert.Equal(t, float32(7.5), flour.Stock)
+	assert.Equal(t, float32(1.5), sauce.Stock)
+	assert.Equal(t, float32(5), pizza.Stock)
+
+	// stock in database follows
+	var ingredient databaseentity.Ingredient
+	assert.Nil(t, db.First(&ingredient, flour.ID).Error)
+	assert.Equal(t, float32(7.5), ingredient.Stock)
+	assert.Equal(t, 5, ingredient.UpdatedBy)
+	for _, recipe := range []*databaseentity.Recipe{sauce, pizza} {
+		result, err := repo.GetRecipeBySerial(ctx, recipe.Serial)
+		assert.Nil(t, err)
+		assert.Equal(t, recipe.Stock, result.Stock)
+	}
+
+	// stock change is audited
+	var actions []string
+	assert.Nil(t, db.Model(databaseentity.AuditLog{}).
+		Where("entity_type = ? AND entity_id = ?", "ingredient", flour.ID).
+		Order("id").Pluck("action", &actions).Error)
+	assert.Equal(t, []string{databaseentity.AuditActionCreate, databaseentity.AuditActionUpdate}, actions)
+
+	// consumed & produced stock is written to stock movements
+	var movements []*databaseentity.StockMovement
+	assert.Nil(t, db.Order("id").Find(&movements).Error)
+	if assert.Len(t, movements, 3) {
+		assert.Equal(t, &flour.ID, movements[0].IngredientID)
+		assert.Nil(t, movements[0].RecipeID)
+		assert.Equal(t, databaseentity.StockMovementTypeOut, movements[0].MovementType)
+		assert.Equal(t, 2.5, movements[0].Quantity)
+		assert.Equal(t, &sauce.ID, movements[1].RecipeID)
+		assert.Equal(t, databaseentity.StockMovementTypeOut, movements[1].MovementType)
+		assert.Equal(t, 1.5, movements[1].Quantity)
+		assert.Equal(t, &pizza.ID, movements[2].RecipeID)
+		assert.Equal(t, databaseentity.StockMovementTypeIn, movements[2].MovementType)
+		assert.Equal(t, float64(4), movements[2].Quantity)
+		for _, movement := range movements {
+			assert.Equal(t, "produce recipe "+pizza.Serial, movement.Description)
+			assert.Equal(t, 5, movement.CreatedBy)
+		}
+	}
+}
+
+// quantities are rounded to 2 decimals like stock, so stock left after rounding is enough
+func Test_ProduceRounded(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := reciperepository.New(db)
+	ctx := context.Background()
+	flour := testdatabase.Ingredient(t, db, &databaseentity.Ingredient{Stock: 1})
+	pizza := testdatabase.Recipe(t, db, &databaseentity.Recipe{})
+
+	production := &databaseentity.RecipeProduction{
+		Recipe:      pizza,
+		Quantity:    1.333333,
+		Ingredients: []*databaseentity.IngredientConsumption{{Ingredient: flour, Quantity: 1.004}},
+	}
+	assert.Nil(t, repo.Produce(ctx, production, 5))
+	assert.Equal(t, float64(1), production.Ingredients[0].Quantity)
+	assert.Equal(t, 1.33, production.Quantity)
+
+	var ingredient databaseentity.Ingredient
+	assert.Nil(t, db.First(&ingredient, flour.ID).Error)
+	assert.Equal(t, float32(0), ingredient.Stock)
+	var quantities []float64
+	assert.Nil(t, db.Model(databaseentity.StockMovement{}).Order("id").Pluck("quantity", &quantities).Error)
+	assert.Equal(t, []float64{1, 1.33}, quantities)
+}
+
+func Test_ProduceStockNotEnough(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := reciperepository.New(db)
+	ctx := context.Background()
+	kg := testdatabase.Unit(t, db, &databaseentity.Unit{})
+	flour := testdatabase.Ingredient(t, db, &databaseentity.Ingredient{Name: "Flour", UnitID: kg.ID, Stock: 10})
+	flour.Unit = kg
+	liter := testdatabase.Unit(t, db, &databaseentity.Unit{})
+	sauce := testdatabase.Recipe(t, db, &databaseentity.Recipe{Name: "Sauce", UnitID: &liter.ID, Stock: 1})
+	sauce.Unit = liter
+	pizza := testdatabase.Recipe(t, db, &databaseentity.Recipe{Stock: 1})
+
+	tests := []struct {
+		name        string
+		ingredients []*databaseentity.IngredientConsumption
+		subRecipes  []*databaseentity.SubRecipeConsumption
+		message     string
+	}{
+		{
+			name:        "ingredient",
+			ingredients: []*databaseentity.IngredientConsumption{{Ingredient: flour, Quantity: 12}},
+			message:     fmt.Sprintf("stock of ingredient `Flour` is not enough, 2 %s more is needed", kg.Name),
+		},
+		{
+			name:        "sub recipe",
+			ingredients: []*databaseentity.IngredientConsumption{{Ingredient: flour, Quantity: 2}},
+			subRecipes:  []*databaseentity.SubRecipeConsumption{{Recipe: sauce, Quantity: 1.5}},
+			message:     fmt.Sprintf("stock of sub recipe `Sauce` is not enough, 0.5 %s more is needed", liter.Name),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			production := &databaseentity.RecipeProduction{Recipe: pizza, Quantity: 4, Ingredients: tt.ingredients, SubRecipes: tt.subRecipes}
+			err := repo.Produce(ctx, production, 5)
+			testdatabase.AssertHTTPError(t, err, http.StatusConflict, entity.ProduceStockNotEnough)
+			assert.Equal(t, tt.message, err.(*echo.HTTPError).Message)
+
+			// nothing is taken nor produced
+			var ingredient databaseentity.Ingredient
+			assert.Nil(t, db.First(&ingredient, flour.ID).Error)
+			assert.Equal(t, float32(10), ingredient.Stock)
+			for _, recipe := range []*databaseentity.Recipe{sauce, pizza} {
+				var result databaseentity.Recipe
+				assert.Nil(t, db.First(&result, recipe.ID).Error)
+				assert.Equal(t, float32(1), result.Stock)
+			}
+			var total int64
+			assert.Nil(t, db.Model(databaseentity.StockMovement{}).Count(&total).Error)
+			assert.Zero(t, total)
+		})
+	}
+}
+
+// every production passes its own stock check, only those fitting the stock left may take it
+func Test_ProduceConcurrent(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := reciperepository.New(db)
+	ctx := context.Background()
+	flour := testdatabase.Ingredient(t, db, &databaseentity.Ingredient{Stock: 10})
+	pizza := testdatabase.Recipe(t, db, &databaseentity.Recipe{})
+
+	const total = 5
+	var wg sync.WaitGroup
+	errs := make([]error, total)
+	for i := 0; i < total; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			production := &databaseentity.RecipeProduction{
+				Recipe:      &databaseentity.Recipe{ID: pizza.ID},
+				Quantity:    1,
+				Ingredients: []*databaseentity.IngredientConsumption{{Ingredient: &databaseentity.Ingredient{ID: flour.ID, Stock: flour.Stock}, Quantity: 3}},
+			}
+			errs[i] = repo.Produce(ctx, production, 5)
+		}(i)
+	}
+	wg.Wait()
+
+	var produced int
+	for _, err := range errs {
+		if err == nil {
+			produced++
+			continue
+		}
+		testdatabase.AssertHTTPError(t, err, http.StatusConflict, entity.ProduceStockNotEnough)
+	}
+	assert.Equal(t, 3, produced, fmt.Sprint(errs))
+	var ingredient databaseentity.Ingredient
+	assert.Nil(t, db.First(&ingredient, flour.ID).Error)
+	assert.Equal(t, float32(1), ingredient.Stock)
+	var recipe databaseentity.Recipe
+	assert.Nil(t, db.First(&recipe, pizza.ID).Error)
+	assert.Equal(t, float32(3), recipe.Stock)
+}
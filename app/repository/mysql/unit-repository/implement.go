@@ -2,24 +2,22 @@ package unitrepository
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"net/http"
 	"rap-c/app/entity"
 	databaseentity "rap-c/app/entity/database-entity"
 	payloadentity "rap-c/app/entity/payload-entity"
+	"rap-c/app/helper"
 	"rap-c/app/repository/contract"
+	"time"
 
-	"github.com/go-sql-driver/mysql"
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 )
 
 const (
-	minQueryLimit             int    = 10
-	maxQueryLimit             int    = 100
-	mysqlDuplicateErrorNum    uint16 = 1062
-	mysqlForeignKeyReferences uint16 = 1451
+	minQueryLimit int = 10
+	maxQueryLimit int = 100
 )
 
 type repo struct {
@@ -31,9 +29,9 @@ func New(db *gorm.DB) contract.UnitRepository {
 }
 
 func (r *repo) Create(ctx context.Context, unit *databaseentity.Unit) error {
-	err := r.db.Save(unit).Error
+	err := r.db.WithContext(ctx).Save(unit).Error
 	if err != nil {
-		if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr != nil && mysqlErr.Number == mysqlDuplicateErrorNum {
+		if helper.IsDuplicateKeyError(r.db, err) {
 			return &echo.HTTPError{
 				Code:     http.StatusBadRequest,
 				Message:  fmt.Sprintf(entity.CreateUnitNameDuplicateMessage, unit.Name),
@@ -53,7 +51,7 @@ func (r *repo) GetUnitByName(ctx context.Context, name string) (*databaseentity.
 	var result databaseentity.Unit
 	err := r.db.Where("name = ?", name).First(&result).Error
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		if helper.IsNotFoundError(err) {
 			return nil, &echo.HTTPError{
 				Code:     http.StatusNotFound,
 				Message:  fmt.Sprintf(entity.UnitNameNotFoundMessage, name),
@@ -89,7 +87,7 @@ func (r *repo) GetUnitsByRequest(ctx context.Context, req *payloadentity.GetUnit
 	// validate sort
 	validSort := map[string]string{
 		"name":      "name",
-		"createdAt": "creted_at",
+		"createdAt": "created_at",
 	}
 	sort := validSort[req.SortField]
 	if sort == "" {
@@ -122,9 +120,9 @@ func (r *repo) GetUnitsByRequest(ctx context.Context, req *payloadentity.GetUnit
 }
 
 func (r *repo) Delete(ctx context.Context, unit *databaseentity.Unit) error {
-	err := r.db.Delete(unit).Error
+	err := r.db.WithContext(ctx).Delete(unit).Error
 	if err != nil {
-		if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr != nil && mysqlErr.Number == mysqlForeignKeyReferences {
+		if helper.IsForeignKeyError(r.db, err) {
 			return &echo.HTTPError{
 				Code:     http.StatusForbidden,
 				Message:  entity.DeleteUsedUnitForbiddenMessage,
@@ -140,10 +138,209 @@ func (r *repo) Delete(ctx context.Context, unit *databaseentity.Unit) error {
 	return nil
 }
 
+func (r *repo) Update(ctx context.Context, unit *databaseentity.Unit) error {
+	if unit.ID == 0 {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.UnitRepoUpdateError, "data not found, empty primary key"),
+		}
+	}
+	err := r.db.WithContext(ctx).Save(unit).Error
+	if err != nil {
+		if helper.IsDuplicateKeyError(r.db, err) {
+			return &echo.HTTPError{
+				Code:     http.StatusBadRequest,
+				Message:  fmt.Sprintf(entity.CreateUnitNameDuplicateMessage, unit.Name),
+				Internal: entity.NewInternalError(entity.CreateUnitNameDuplicate, err.Error()),
+			}
+		}
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.UnitRepoUpdateError, err.Error()),
+		}
+	}
+	return nil
+}
+
+func (r *repo) GetUnitUsage(ctx context.Context, unit *databaseentity.Unit) (*databaseentity.UnitUsage, error) {
+	var result databaseentity.UnitUsage
+	db := r.db.WithContext(ctx)
+	err := db.Where("unit_id = ?", unit.ID).Order("serial").Find(&result.Ingredients).Error
+	if err == nil {
+		err = db.Preload("Ingredient").Where("unit_id = ?", unit.ID).Order("serial").Find(&result.Conversions).Error
+	}
+	if err == nil {
+		err = db.Preload("Recipe").Preload("Ingredient").Preload("SubRecipe").Where("unit_id = ?", unit.ID).Order("serial").Find(&result.RecipeIngredients).Error
+	}
+	if err == nil {
+		err = db.Where("unit_id = ?", unit.ID).Order("serial").Find(&result.Recipes).Error
+	}
+	if err != nil {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.UnitRepoGetUnitUsageError, err.Error()),
+		}
+	}
+	return &result, nil
+}
+
+func (r *repo) Merge(ctx context.Context, unit *databaseentity.Unit, target *databaseentity.Unit, authorID int) (err error) {
+	tx := r.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			err = &echo.HTTPError{
+				Code:     http.StatusInternalServerError,
+				Message:  http.StatusText(http.StatusInternalServerError),
+				Internal: entity.NewInternalError(entity.UnitRepoMergeError, fmt.Sprint(r)),
+			}
+			tx.Rollback()
+		}
+	}()
+	if err = tx.Error; err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.UnitRepoMergeError, err.Error()),
+		}
+	}
+
+	// after merge, ingredient may convert into its own unit or have two conversions into target
+	conflict, err := r.getMergeConflict(tx, unit, target)
+	if err == nil && conflict != "" {
+		tx.Rollback()
+		return &echo.HTTPError{
+			Code:     http.StatusConflict,
+			Message:  fmt.Sprintf(entity.MergeUnitConflictMessage, conflict),
+			Internal: entity.NewInternalError(entity.MergeUnitConflict, "conversion conflict"),
+		}
+	}
+
+	// every row is updated by primary key, so each change is written to audit log
+	now := time.Now()
+	changes := map[string]interface{}{
+		"unit_id":    target.ID,
+		"updated_at": now,
+		"updated_by": authorID,
+	}
+	var ingredients []*databaseentity.Ingredient
+	var recipes []*databaseentity.Recipe
+	var conversions []*databaseentity.IngredientConvertionUnit
+	var lines []*databaseentity.RecipeIngredient
+	for _, rows := range []interface{}{&ingredients, &recipes, &conversions, &lines} {
+		if err != nil {
+			break
+		}
+		found := tx.Where("unit_id = ?", unit.ID).Find(rows)
+		err = found.Error
+		if err == nil && found.RowsAffected > 0 {
+			err = tx.Model(rows).Updates(changes).Error
+		}
+	}
+	if err == nil {
+		err = tx.Delete(unit).Error
+	}
+	if err == nil {
+		err = tx.Commit().Error
+	} else {
+		tx.Rollback()
+	}
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.UnitRepoMergeError, err.Error()),
+		}
+	}
+	return nil
+}
+
+func (r *repo) GetIngredientConversions(ctx context.Context, serial string) (*databaseentity.Ingredient, []*databaseentity.IngredientConvertionUnit, error) {
+	var ingredient databaseentity.Ingredient
+	db := r.db.WithContext(ctx)
+	err := db.Preload("Unit").Where("serial = ?", serial).First(&ingredient).Error
+	if err != nil {
+		if helper.IsNotFoundError(err) {
+			return nil, nil, &echo.HTTPError{
+				Code:     http.StatusNotFound,
+				Message:  fmt.Sprintf(entity.IngredientNotFoundMessage, serial),
+				Internal: entity.NewInternalError(entity.IngredientNotFound, err.Error()),
+			}
+		}
+		return nil, nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.UnitRepoGetIngredientConversionsError, err.Error()),
+		}
+	}
+	var conversions []*databaseentity.IngredientConvertionUnit
+	err = db.Preload("Unit").Where("ingredient_id = ?", ingredient.ID).Order("serial").Find(&conversions).Error
+	if err != nil {
+		return nil, nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.UnitRepoGetIngredientConversionsError, err.Error()),
+		}
+	}
+	return &ingredient, conversions, nil
+}
+
+// serial of first ingredient whose conversions clash once unit becomes target, empty when none
+func (r *repo) GetUnitsByDimension(ctx context.Context, dimension string) ([]*databaseentity.Unit, error) {
+	var result []*databaseentity.Unit
+	err := r.db.WithContext(ctx).
+		Where("dimension = ? AND factor > 0", dimension).
+		Order("factor").
+		Find(&result).
+		Error
+	if err != nil {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.UnitRepoGetUnitsByDimensionError, err.Error()),
+		}
+	}
+	return result, nil
+}
+
+func (r *repo) getMergeConflict(tx *gorm.DB, unit *databaseentity.Unit, target *databaseentity.Unit) (string, error) {
+	unitIDs := []int{unit.ID, target.ID}
+	var serials []string
+	// conversion into ingredient own unit
+	err := tx.Model(databaseentity.IngredientConvertionUnit{}).
+		Joins("JOIN ingredients ON ingredients.id = ingredient_convertion_units.ingredient_id").
+		Where("ingredient_convertion_units.unit_id IN ? AND ingredients.unit_id IN ?", unitIDs, unitIDs).
+		Order("ingredients.serial").
+		Limit(1).
+		Pluck("ingredients.serial", &serials).Error
+	if err != nil || len(serials) > 0 {
+		return firstSerial(serials), err
+	}
+	// two conversions into target
+	err = tx.Model(databaseentity.IngredientConvertionUnit{}).
+		Joins("JOIN ingredients ON ingredients.id = ingredient_convertion_units.ingredient_id").
+		Where("ingredient_convertion_units.unit_id IN ?", unitIDs).
+		Group("ingredients.serial").
+		Having("count(*) > 1").
+		Order("ingredients.serial").
+		Limit(1).
+		Pluck("ingredients.serial", &serials).Error
+	return firstSerial(serials), err
+}
+
+func firstSerial(serials []string) string {
+	if len(serials) == 0 {
+		return ""
+	}
+	return serials[0]
+}
+
 func (r *repo) renderUnitsQuery(req *payloadentity.GetUnitListRequest) *gorm.DB {
 	qry := r.db
 	if req.Name != "" {
-		qry = qry.Where("name like ?", fmt.Sprintf("%%%s%%", req.Name))
+		qry = qry.Where("lower(name) like lower(?)", fmt.Sprintf("%%%s%%", req.Name))
 	}
 	return qry
 }
@@ -0,0 +1,367 @@
+package unitrepository_test
+
+import (
+	"context"
+	"net/http"
+	"rap-c/app/entity"
+	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	unitrepository "rap-c/app/repository/mysql/unit-repository"
+	testdatabase "rap-c/app/repository/test-database"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Create(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := unitrepository.New(db)
+	ctx := context.Background()
+	testdatabase.Unit(t, db, &databaseentity.Unit{Name: "Kilogram"})
+
+	t.Run("success", func(t *testing.T) {
+		unit := &databaseentity.Unit{Name: "Gram", CreatedBy: 1}
+		err := repo.Create(ctx, unit)
+		assert.Nil(t, err)
+		assert.NotZero(t, unit.ID)
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		err := repo.Create(ctx, &databaseentity.Unit{Name: "Kilogram", CreatedBy: 1})
+		testdatabase.AssertHTTPError(t, err, http.StatusBadRequest, entity.CreateUnitNameDuplicate)
+	})
+}
+
+func Test_GetUnitByName(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := unitrepository.New(db)
+	ctx := context.Background()
+	unit := testdatabase.Unit(t, db, &databaseentity.Unit{Name: "Kilogram"})
+
+	t.Run("success", func(t *testing.T) {
+		result, err := repo.GetUnitByName(ctx, "Kilogram")
+		assert.Nil(t, err)
+		assert.Equal(t, unit.ID, result.ID)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := repo.GetUnitByName(ctx, "Liter")
+		testdatabase.AssertHTTPError(t, err, http.StatusNotFound, entity.UnitNameNotFound)
+	})
+}
+
+func Test_GetUnitsByRequest(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := unitrepository.New(db)
+	ctx := context.Background()
+	// start without standard units seeded by migration
+	assert.Nil(t, db.Where("dimension <> ''").Delete(&databaseentity.Unit{}).Error)
+	// distinct timestamps, so sorting by time is deterministic
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, name := range []string{"Liter", "Gram", "Kilogram", "Mililiter"} {
+		testdatabase.Unit(t, db, &databaseentity.Unit{Name: name, CreatedAt: day.AddDate(0, 0, i)})
+	}
+
+	tests := []struct {
+		name  string
+		req   *payloadentity.GetUnitListRequest
+		units []string
+	}{
+		{
+			name:  "default sort by created at",
+			req:   &payloadentity.GetUnitListRequest{},
+			units: []string{"Liter", "Gram", "Kilogram", "Mililiter"},
+		},
+		{
+			name:  "filter name case insensitive",
+			req:   &payloadentity.GetUnitListRequest{Name: "LITER"},
+			units: []string{"Liter", "Mililiter"},
+		},
+		{
+			name:  "sort name",
+			req:   &payloadentity.GetUnitListRequest{SortField: "name"},
+			units: []string{"Gram", "Kilogram", "Liter", "Mililiter"},
+		},
+		{
+			name:  "sort created at descending",
+			req:   &payloadentity.GetUnitListRequest{SortField: "createdAt", DescendingOrder: true},
+			units: []string{"Mililiter", "Kilogram", "Gram", "Liter"},
+		},
+		{
+			name:  "limit below minimum is raised",
+			req:   &payloadentity.GetUnitListRequest{Limit: 1, Page: 2},
+			units: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			units, err := repo.GetUnitsByRequest(ctx, tt.req)
+			assert.Nil(t, err)
+			var names []string
+			for _, unit := range units {
+				names = append(names, unit.Name)
+			}
+			assert.Equal(t, tt.units, names)
+		})
+	}
+
+	t.Run("total", func(t *testing.T) {
+		total, err := repo.GetTotalUnitsByRequest(ctx, &payloadentity.GetUnitListRequest{Name: "gram"})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), total)
+	})
+}
+
+func Test_Delete(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := unitrepository.New(db)
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		unit := testdatabase.Unit(t, db, &databaseentity.Unit{})
+		err := repo.Delete(ctx, unit)
+		assert.Nil(t, err)
+		_, err = repo.GetUnitByName(ctx, unit.Name)
+		testdatabase.AssertHTTPError(t, err, http.StatusNotFound, entity.UnitNameNotFound)
+	})
+
+	t.Run("used by ingredient", func(t *testing.T) {
+		ingredient := testdatabase.Ingredient(t, db, &databaseentity.Ingredient{})
+		err := repo.Delete(ctx, &databaseentity.Unit{ID: ingredient.UnitID})
+		testdatabase.AssertHTTPError(t, err, http.StatusForbidden, entity.DeleteUsedUnitForbidden)
+	})
+}
+
+func Test_Update(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := unitrepository.New(db)
+	ctx := context.Background()
+	testdatabase.Unit(t, db, &databaseentity.Unit{Name: "Gram"})
+
+	t.Run("empty primary key", func(t *testing.T) {
+		err := repo.Update(ctx, &databaseentity.Unit{Name: "Liter"})
+		testdatabase.AssertHTTPError(t, err, http.StatusInternalServerError, entity.UnitRepoUpdateError)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		unit := testdatabase.Unit(t, db, &databaseentity.Unit{Name: "Kilogrm"})
+		unit.Name = "Kilogram"
+		assert.Nil(t, repo.Update(ctx, unit))
+
+		saved, err := repo.GetUnitByName(ctx, "Kilogram")
+		assert.Nil(t, err)
+		assert.Equal(t, unit.ID, saved.ID)
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		unit := testdatabase.Unit(t, db, &databaseentity.Unit{})
+		unit.Name = "Gram"
+		err := repo.Update(ctx, unit)
+		testdatabase.AssertHTTPError(t, err, http.StatusBadRequest, entity.CreateUnitNameDuplicate)
+	})
+}
+
+func Test_GetUnitUsage(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := unitrepository.New(db)
+	ctx := context.Background()
+	unit := testdatabase.Unit(t, db, &databaseentity.Unit{Name: "Gram"})
+	ingredient := testdatabase.Ingredient(t, db, &databaseentity.Ingredient{Name: "Sugar", UnitID: unit.ID})
+	conversion := testdatabase.IngredientConvertionUnit(t, db, &databaseentity.IngredientConvertionUnit{UnitID: unit.ID})
+	recipe := testdatabase.Recipe(t, db, &databaseentity.Recipe{Name: "Cake"})
+	line := testdatabase.RecipeIngredient(t, db, &databaseentity.RecipeIngredient{RecipeID: recipe.ID, IngredientID: &ingredient.ID, UnitID: unit.ID})
+	sauce := testdatabase.Recipe(t, db, &databaseentity.Recipe{Name: "Sauce", UnitID: &unit.ID})
+	subLine := testdatabase.RecipeIngredient(t, db, &databaseentity.RecipeIngredient{RecipeID: recipe.ID, SubRecipeID: &sauce.ID, UnitID: unit.ID})
+	// other unit rows are not listed
+	testdatabase.Ingredient(t, db, &databaseentity.Ingredient{})
+
+	t.Run("used unit", func(t *testing.T) {
+		usage, err := repo.GetUnitUsage(ctx, unit)
+		assert.Nil(t, err)
+		if assert.Len(t, usage.Ingredients, 1) {
+			assert.Equal(t, ingredient.Serial, usage.Ingredients[0].Serial)
+		}
+		if assert.Len(t, usage.Conversions, 1) {
+			assert.Equal(t, conversion.Serial, usage.Conversions[0].Serial)
+			assert.Equal(t, conversion.IngredientID, usage.Conversions[0].Ingredient.ID)
+		}
+		if assert.Len(t, usage.RecipeIngredients, 2) {
+			assert.Equal(t, line.Serial, usage.RecipeIngredients[0].Serial)
+			assert.Equal(t, "Cake", usage.RecipeIngredients[0].Recipe.Name)
+			assert.Equal(t, "Sugar", usage.RecipeIngredients[0].Ingredient.Name)
+			assert.Equal(t, subLine.Serial, usage.RecipeIngredients[1].Serial)
+			assert.Equal(t, "Sauce", usage.RecipeIngredients[1].SubRecipe.Name)
+		}
+		if assert.Len(t, usage.Recipes, 1) {
+			assert.Equal(t, sauce.Serial, usage.Recipes[0].Serial)
+		}
+	})
+
+	t.Run("unused unit", func(t *testing.T) {
+		usage, err := repo.GetUnitUsage(ctx, testdatabase.Unit(t, db, &databaseentity.Unit{}))
+		assert.Nil(t, err)
+		assert.Empty(t, usage.Ingredients)
+		assert.Empty(t, usage.Conversions)
+		assert.Empty(t, usage.RecipeIngredients)
+		assert.Empty(t, usage.Recipes)
+	})
+}
+
+func Test_Merge(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := unitrepository.New(db)
+	ctx := context.Background()
+
+	t.Run("every reference moved", func(t *testing.T) {
+		unit := testdatabase.Unit(t, db, &databaseentity.Unit{Name: "Kg"})
+		target := testdatabase.Unit(t, db, &databaseentity.Unit{Name: "Kilogram"})
+		ingredient := testdatabase.Ingredient(t, db, &databaseentity.Ingredient{UnitID: unit.ID})
+		conversion := testdatabase.IngredientConvertionUnit(t, db, &databaseentity.IngredientConvertionUnit{UnitID: unit.ID})
+		line := testdatabase.RecipeIngredient(t, db, &databaseentity.RecipeIngredient{UnitID: unit.ID})
+		recipe := testdatabase.Recipe(t, db, &databaseentity.Recipe{UnitID: &unit.ID})
+
+		assert.Nil(t, repo.Merge(ctx, unit, target, 9))
+
+		_, err := repo.GetUnitByName(ctx, "Kg")
+		testdatabase.AssertHTTPError(t, err, http.StatusNotFound, entity.UnitNameNotFound)
+		usage, err := repo.GetUnitUsage(ctx, target)
+		assert.Nil(t, err)
+		if assert.Len(t, usage.Ingredients, 1) {
+			assert.Equal(t, ingredient.ID, usage.Ingredients[0].ID)
+			assert.Equal(t, 9, usage.Ingredients[0].UpdatedBy)
+		}
+		if assert.Len(t, usage.Conversions, 1) {
+			assert.Equal(t, conversion.ID, usage.Conversions[0].ID)
+		}
+		if assert.Len(t, usage.RecipeIngredients, 1) {
+			assert.Equal(t, line.ID, usage.RecipeIngredients[0].ID)
+		}
+		if assert.Len(t, usage.Recipes, 1) {
+			assert.Equal(t, recipe.ID, usage.Recipes[0].ID)
+			assert.Equal(t, 9, usage.Recipes[0].UpdatedBy)
+		}
+
+		// ingredient change & unit deletion are audited
+		var actions []string
+		assert.Nil(t, db.Model(databaseentity.AuditLog{}).
+			Where("(entity_type = ? AND entity_id = ?) OR (entity_type = ? AND entity_id = ?)", "ingredient", ingredient.ID, "unit", unit.ID).
+			Order("id").Pluck("action", &actions).Error)
+		assert.Equal(t, []string{databaseentity.AuditActionCreate, databaseentity.AuditActionCreate, databaseentity.AuditActionUpdate, databaseentity.AuditActionDelete}, actions)
+
+		// moved conversion & recipe line are audited too
+		for entityType, id := range map[string]int{"ingredient_conversion": conversion.ID, "recipe_ingredient": line.ID} {
+			var logs []*databaseentity.AuditLog
+			assert.Nil(t, db.Where("entity_type = ? AND entity_id = ? AND action = ?", entityType, id, databaseentity.AuditActionUpdate).Find(&logs).Error)
+			if assert.Len(t, logs, 1, entityType) {
+				assert.Equal(t, 9, logs[0].ActorID, entityType)
+				assert.Contains(t, logs[0].AfterData, "unit_id", entityType)
+			}
+		}
+	})
+
+	t.Run("conversion into ingredient own unit", func(t *testing.T) {
+		unit := testdatabase.Unit(t, db, &databaseentity.Unit{Name: "Ltr"})
+		target := testdatabase.Unit(t, db, &databaseentity.Unit{Name: "Liter"})
+		ingredient := testdatabase.Ingredient(t, db, &databaseentity.Ingredient{Serial: "MILK", UnitID: target.ID})
+		testdatabase.IngredientConvertionUnit(t, db, &databaseentity.IngredientConvertionUnit{IngredientID: ingredient.ID, UnitID: unit.ID})
+
+		err := repo.Merge(ctx, unit, target, 9)
+		testdatabase.AssertHTTPError(t, err, http.StatusConflict, entity.MergeUnitConflict)
+		_, err = repo.GetUnitByName(ctx, "Ltr")
+		assert.Nil(t, err)
+	})
+
+	t.Run("duplicate conversion", func(t *testing.T) {
+		unit := testdatabase.Unit(t, db, &databaseentity.Unit{Name: "Tbsp"})
+		target := testdatabase.Unit(t, db, &databaseentity.Unit{Name: "Tablespoon"})
+		ingredient := testdatabase.Ingredient(t, db, &databaseentity.Ingredient{Serial: "FLOUR"})
+		testdatabase.IngredientConvertionUnit(t, db, &databaseentity.IngredientConvertionUnit{IngredientID: ingredient.ID, UnitID: unit.ID})
+		testdatabase.IngredientConvertionUnit(t, db, &databaseentity.IngredientConvertionUnit{IngredientID: ingredient.ID, UnitID: target.ID})
+		other := testdatabase.Ingredient(t, db, &databaseentity.Ingredient{UnitID: unit.ID})
+
+		err := repo.Merge(ctx, unit, target, 9)
+		testdatabase.AssertHTTPError(t, err, http.StatusConflict, entity.MergeUnitConflict)
+
+		// nothing is moved
+		var unitID int
+		assert.Nil(t, db.Model(databaseentity.Ingredient{}).Where("id = ?", other.ID).Pluck("unit_id", &unitID).Error)
+		assert.Equal(t, unit.ID, unitID)
+	})
+}
+
+func Test_StandardUnits(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := unitrepository.New(db)
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		dimension string
+		factor    float64
+	}{
+		{"g", databaseentity.UnitDimensionMass, 1},
+		{"ons", databaseentity.UnitDimensionMass, 100},
+		{"kg", databaseentity.UnitDimensionMass, 1000},
+		{"ml", databaseentity.UnitDimensionVolume, 1},
+		{"sdt", databaseentity.UnitDimensionVolume, 5},
+		{"sdm", databaseentity.UnitDimensionVolume, 15},
+		{"l", databaseentity.UnitDimensionVolume, 1000},
+		{"butir", databaseentity.UnitDimensionCount, 1},
+	}
+	for _, tt := range tests {
+		unit, err := repo.GetUnitByName(ctx, tt.name)
+		if assert.Nil(t, err, tt.name) {
+			assert.Equal(t, tt.dimension, unit.Dimension, tt.name)
+			assert.Equal(t, tt.factor, unit.Factor, tt.name)
+		}
+	}
+}
+
+func Test_GetUnitsByDimension(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := unitrepository.New(db)
+	ctx := context.Background()
+	testdatabase.Unit(t, db, &databaseentity.Unit{Name: "pinch"})
+
+	units, err := repo.GetUnitsByDimension(ctx, databaseentity.UnitDimensionMass)
+	assert.Nil(t, err)
+	var names []string
+	for _, unit := range units {
+		names = append(names, unit.Name)
+	}
+	assert.Equal(t, []string{"g", "ons", "kg"}, names)
+}
+
+func Test_GetIngredientConversions(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := unitrepository.New(db)
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		ingredient := testdatabase.Ingredient(t, db, &databaseentity.Ingredient{})
+		gram, err := repo.GetUnitByName(ctx, "g")
+		assert.Nil(t, err)
+		testdatabase.IngredientConvertionUnit(t, db, &databaseentity.IngredientConvertionUnit{Serial: "CNV02", IngredientID: ingredient.ID, UnitID: gram.ID, Value: 920})
+		testdatabase.IngredientConvertionUnit(t, db, &databaseentity.IngredientConvertionUnit{Serial: "CNV01", IngredientID: ingredient.ID})
+		// other ingredient
+		testdatabase.IngredientConvertionUnit(t, db, &databaseentity.IngredientConvertionUnit{})
+
+		result, conversions, err := repo.GetIngredientConversions(ctx, ingredient.Serial)
+		assert.Nil(t, err)
+		assert.Equal(t, ingredient.ID, result.ID)
+		if assert.NotNil(t, result.Unit) {
+			assert.Equal(t, ingredient.UnitID, result.Unit.ID)
+		}
+		if assert.Len(t, conversions, 2) {
+			assert.Equal(t, "CNV01", conversions[0].Serial)
+			assert.Equal(t, "g", conversions[1].Unit.Name)
+			assert.Equal(t, float32(920), conversions[1].Value)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, _, err := repo.GetIngredientConversions(ctx, "ING404")
+		testdatabase.AssertHTTPError(t, err, http.StatusNotFound, entity.IngredientNotFound)
+	})
+}
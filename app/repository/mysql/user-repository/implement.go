@@ -2,28 +2,29 @@ package userrepository
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"net/http"
 	"rap-c/app/entity"
 	databaseentity "rap-c/app/entity/database-entity"
 	payloadentity "rap-c/app/entity/payload-entity"
+	"rap-c/app/helper"
 	"rap-c/app/repository/contract"
 	"strings"
 	"time"
 
-	"github.com/go-sql-driver/mysql"
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 )
 
 const (
-	minQueryLimit          int           = 10
-	maxQueryLimit          int           = 100
-	mysqlDuplicateErrorNum uint16        = 1062
-	emailUniqueKeyName     string        = "users.uni_users_email"
-	usernameUniqueKeyName  string        = "users.uni_users_username"
-	resetTokenExpiration   time.Duration = time.Hour
+	minQueryLimit         int    = 10
+	maxQueryLimit         int    = 100
+	emailUniqueKeyName    string = "uni_users_email"
+	usernameUniqueKeyName string = "uni_users_username"
+	// sqlite names column instead of constraint in duplicate error
+	emailUniqueColumn    string        = "users.email"
+	usernameUniqueColumn string        = "users.username"
+	resetTokenExpiration time.Duration = time.Hour
 )
 
 type repo struct {
@@ -34,25 +35,69 @@ func New(db *gorm.DB) contract.UserRepository {
 	return &repo{db}
 }
 
-func (r *repo) Create(ctx context.Context, user *databaseentity.User) error {
-	err := r.db.Save(user).Error
+func (r *repo) Create(ctx context.Context, user *databaseentity.User, invitation *databaseentity.UserInvitation, mails ...*databaseentity.MailOutbox) (err error) {
+	tx := r.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			err = &echo.HTTPError{
+				Code:     http.StatusInternalServerError,
+				Message:  http.StatusText(http.StatusInternalServerError),
+				Internal: entity.NewInternalError(entity.UserRepoCreateError, fmt.Sprint(r)),
+			}
+			tx.Rollback()
+		}
+	}()
+	if err = tx.Error; err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.UserRepoCreateError, err.Error()),
+		}
+	}
+
+	// save user
+	err = tx.Create(user).Error
 	if err != nil {
-		if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr != nil && mysqlErr.Number == mysqlDuplicateErrorNum {
-			if strings.Contains(err.Error(), emailUniqueKeyName) {
-				return &echo.HTTPError{
-					Code:     http.StatusBadRequest,
-					Message:  fmt.Sprintf(entity.CreateUserEmailDuplicateMessage, user.Email),
-					Internal: entity.NewInternalError(entity.CreateUserEmailDuplicate, err.Error()),
-				}
-
-			} else if strings.Contains(err.Error(), usernameUniqueKeyName) {
-				return &echo.HTTPError{
-					Code:     http.StatusBadRequest,
-					Message:  fmt.Sprintf(entity.CreateUserUsernameDuplicateMessage, user.Username),
-					Internal: entity.NewInternalError(entity.CreateUserUsernameDuplicate, err.Error()),
-				}
+		tx.Rollback()
+		if dupErr := r.duplicateError(err, user); dupErr != nil {
+			return dupErr
+		}
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.UserRepoCreateError, err.Error()),
+		}
+	}
+
+	// save invitation
+	if invitation != nil {
+		invitation.UserID = user.ID
+		err = tx.Create(invitation).Error
+		if err != nil {
+			tx.Rollback()
+			return &echo.HTTPError{
+				Code:     http.StatusInternalServerError,
+				Message:  http.StatusText(http.StatusInternalServerError),
+				Internal: entity.NewInternalError(entity.UserRepoCreateError, err.Error()),
 			}
 		}
+	}
+
+	// queue invitation mails
+	if len(mails) > 0 {
+		err = tx.Create(mails).Error
+		if err != nil {
+			tx.Rollback()
+			return &echo.HTTPError{
+				Code:     http.StatusInternalServerError,
+				Message:  http.StatusText(http.StatusInternalServerError),
+				Internal: entity.NewInternalError(entity.UserRepoCreateError, err.Error()),
+			}
+		}
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
 		return &echo.HTTPError{
 			Code:     http.StatusInternalServerError,
 			Message:  http.StatusText(http.StatusInternalServerError),
@@ -62,7 +107,103 @@ func (r *repo) Create(ctx context.Context, user *databaseentity.User) error {
 	return nil
 }
 
-func (r *repo) Update(ctx context.Context, user *databaseentity.User) error {
+func (r *repo) CreateOwner(ctx context.Context, user *databaseentity.User) (err error) {
+	tx := r.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			err = &echo.HTTPError{
+				Code:     http.StatusInternalServerError,
+				Message:  http.StatusText(http.StatusInternalServerError),
+				Internal: entity.NewInternalError(entity.UserRepoCreateOwnerError, fmt.Sprint(r)),
+			}
+			tx.Rollback()
+		}
+	}()
+	if err = tx.Error; err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.UserRepoCreateOwnerError, err.Error()),
+		}
+	}
+
+	// take the setup lock row first, concurrent setup waits on its primary key then fails as duplicate.
+	err = tx.Create(&databaseentity.SetupLock{ID: databaseentity.SetupLockID, CreatedAt: time.Now()}).Error
+	if err != nil {
+		tx.Rollback()
+		if helper.IsDuplicateKeyError(r.db, err) {
+			return &echo.HTTPError{
+				Code:     http.StatusForbidden,
+				Message:  entity.SetupAlreadyDoneForbiddenMessage,
+				Internal: entity.NewInternalError(entity.SetupAlreadyDoneForbidden, err.Error()),
+			}
+		}
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.UserRepoCreateOwnerError, err.Error()),
+		}
+	}
+
+	// non guest user created before setup wizard, e.g. from cli, is the owner already
+	var total int64
+	err = tx.Model(databaseentity.User{}).Where("is_guest = ?", false).Count(&total).Error
+	if err != nil {
+		tx.Rollback()
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.UserRepoCreateOwnerError, err.Error()),
+		}
+	}
+	if total > 0 {
+		tx.Rollback()
+		return &echo.HTTPError{
+			Code:     http.StatusForbidden,
+			Message:  entity.SetupAlreadyDoneForbiddenMessage,
+			Internal: entity.NewInternalError(entity.SetupAlreadyDoneForbidden, entity.SetupAlreadyDoneForbiddenMessage),
+		}
+	}
+
+	// save owner
+	err = tx.Create(user).Error
+	if err != nil {
+		tx.Rollback()
+		if dupErr := r.duplicateError(err, user); dupErr != nil {
+			return dupErr
+		}
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.UserRepoCreateOwnerError, err.Error()),
+		}
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.UserRepoCreateOwnerError, err.Error()),
+		}
+	}
+	return nil
+}
+
+func (r *repo) GetTotalNonGuestUsers(ctx context.Context) (int64, error) {
+	var result int64
+	err := r.db.Model(databaseentity.User{}).Where("is_guest = ?", false).Count(&result).Error
+	if err != nil {
+		return 0, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.UserRepoGetTotalNonGuestUsersError, err.Error()),
+		}
+	}
+	return result, nil
+}
+
+func (r *repo) Update(ctx context.Context, user *databaseentity.User, mails ...*databaseentity.MailOutbox) (err error) {
 	if user.ID == 0 {
 		return &echo.HTTPError{
 			Code:     http.StatusInternalServerError,
@@ -70,7 +211,55 @@ func (r *repo) Update(ctx context.Context, user *databaseentity.User) error {
 			Internal: entity.NewInternalError(entity.UserRepoUpdateError, "data not found, empty primary key"),
 		}
 	}
-	err := r.db.Save(user).Error
+
+	tx := r.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			err = &echo.HTTPError{
+				Code:     http.StatusInternalServerError,
+				Message:  http.StatusText(http.StatusInternalServerError),
+				Internal: entity.NewInternalError(entity.UserRepoUpdateError, fmt.Sprint(r)),
+			}
+			tx.Rollback()
+		}
+	}()
+	if err = tx.Error; err != nil {
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.UserRepoUpdateError, err.Error()),
+		}
+	}
+
+	// save user
+	err = tx.Save(user).Error
+	if err != nil {
+		tx.Rollback()
+		// email or username may collide, e.g. when pending email is applied
+		if dupErr := r.duplicateError(err, user); dupErr != nil {
+			return dupErr
+		}
+		return &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.UserRepoUpdateError, err.Error()),
+		}
+	}
+
+	// queue notification mails
+	if len(mails) > 0 {
+		err = tx.Create(mails).Error
+		if err != nil {
+			tx.Rollback()
+			return &echo.HTTPError{
+				Code:     http.StatusInternalServerError,
+				Message:  http.StatusText(http.StatusInternalServerError),
+				Internal: entity.NewInternalError(entity.UserRepoUpdateError, err.Error()),
+			}
+		}
+	}
+
+	err = tx.Commit().Error
 	if err != nil {
 		return &echo.HTTPError{
 			Code:     http.StatusInternalServerError,
@@ -98,7 +287,7 @@ func (r *repo) GetUserByField(ctx context.Context, fieldName string, fieldValue
 	var result databaseentity.User
 	err := r.db.Where(fmt.Sprintf("%s = ?", fieldName), fieldValue).First(&result).Error
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		if helper.IsNotFoundError(err) {
 			return nil, &echo.HTTPError{
 				Code:     notFoundStatus,
 				Message:  fmt.Sprintf(entity.SearchSingleUserNotFOundMessage, fieldName, fieldValue),
@@ -136,8 +325,7 @@ func (r *repo) GetUsersByRequest(ctx context.Context, req *payloadentity.GetUser
 		"username":  "username",
 		"fullName":  "full_name",
 		"email":     "email",
-		"role":      "role",
-		"createdAt": "creted_at",
+		"createdAt": "created_at",
 		"updatedAt": "updated_at",
 	}
 	sort := validSort[req.SortField]
@@ -179,7 +367,11 @@ func (r *repo) MapUserUsername(ctx context.Context, payload interface{}) (map[in
 		}
 	case []*databaseentity.Unit:
 		for _, itm := range users {
-			userIDs = append(userIDs, itm.ID)
+			userIDs = append(userIDs, itm.CreatedBy)
+		}
+	case []*databaseentity.AuditLog:
+		for _, itm := range users {
+			userIDs = append(userIDs, itm.ActorID)
 		}
 	case *databaseentity.User:
 		userIDs = append(userIDs, users.ID)
@@ -215,10 +407,10 @@ func (r *repo) renderUsersQuery(req *payloadentity.GetUserListRequest) *gorm.DB
 		qry = qry.Where("username = ?", req.Username)
 	}
 	if req.Email != "" {
-		qry = qry.Where("email like ?", fmt.Sprintf("%%%s%%", req.Email))
+		qry = qry.Where("lower(email) like lower(?)", fmt.Sprintf("%%%s%%", req.Email))
 	}
 	if req.FullName != "" {
-		qry = qry.Where("name like ?", fmt.Sprintf("%%%s%%", req.FullName))
+		qry = qry.Where("lower(full_name) like lower(?)", fmt.Sprintf("%%%s%%", req.FullName))
 	}
 	if req.Show != "" {
 		if req.Show == databaseentity.RequestShowActive {
@@ -232,3 +424,32 @@ func (r *repo) renderUsersQuery(req *payloadentity.GetUserListRequest) *gorm.DB
 	}
 	return qry
 }
+
+// convert duplicate entry error into bad request, return nil for other errors.
+// constraint name is checked before column name, so duplicated value cannot be mistaken as column name
+func (r *repo) duplicateError(err error, user *databaseentity.User) error {
+	if !helper.IsDuplicateKeyError(r.db, err) {
+		return nil
+	}
+	message := err.Error()
+	isEmail := strings.Contains(message, emailUniqueKeyName)
+	isUsername := strings.Contains(message, usernameUniqueKeyName)
+	if !isEmail && !isUsername {
+		isEmail = strings.Contains(message, emailUniqueColumn)
+		isUsername = strings.Contains(message, usernameUniqueColumn)
+	}
+	if isEmail {
+		return &echo.HTTPError{
+			Code:     http.StatusBadRequest,
+			Message:  fmt.Sprintf(entity.CreateUserEmailDuplicateMessage, user.Email),
+			Internal: entity.NewInternalError(entity.CreateUserEmailDuplicate, err.Error()),
+		}
+	} else if isUsername {
+		return &echo.HTTPError{
+			Code:     http.StatusBadRequest,
+			Message:  fmt.Sprintf(entity.CreateUserUsernameDuplicateMessage, user.Username),
+			Internal: entity.NewInternalError(entity.CreateUserUsernameDuplicate, err.Error()),
+		}
+	}
+	return nil
+}
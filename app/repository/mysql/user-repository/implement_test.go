@@ -0,0 +1,394 @@
+package userrepository_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"rap-c/app/entity"
+	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	"rap-c/app/helper"
+	userrepository "rap-c/app/repository/mysql/user-repository"
+	testdatabase "rap-c/app/repository/test-database"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Create(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := userrepository.New(db)
+	ctx := context.Background()
+	testdatabase.User(t, db, &databaseentity.User{Username: "existing", Email: "existing@example.com"})
+
+	tests := []struct {
+		name         string
+		user         *databaseentity.User
+		code         int
+		internalCode int
+	}{
+		{
+			name: "success",
+			user: &databaseentity.User{Username: "new", FullName: "New User", Email: "new@example.com"},
+		},
+		{
+			name:         "duplicate email",
+			user:         &databaseentity.User{Username: "other", FullName: "Other", Email: "existing@example.com"},
+			code:         http.StatusBadRequest,
+			internalCode: entity.CreateUserEmailDuplicate,
+		},
+		{
+			name:         "duplicate username",
+			user:         &databaseentity.User{Username: "existing", FullName: "Other", Email: "other@example.com"},
+			code:         http.StatusBadRequest,
+			internalCode: entity.CreateUserUsernameDuplicate,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invitation := &databaseentity.UserInvitation{TokenHash: helper.HashToken(tt.name), ExpiredAt: time.Now().Add(time.Hour)}
+			mail := &databaseentity.MailOutbox{Recipient: tt.user.Email, Subject: tt.name}
+			err := repo.Create(ctx, tt.user, invitation, mail)
+
+			var invitations, mails int64
+			assert.Nil(t, db.Model(databaseentity.UserInvitation{}).Where("token_hash = ?", invitation.TokenHash).Count(&invitations).Error)
+			assert.Nil(t, db.Model(databaseentity.MailOutbox{}).Where("subject = ?", tt.name).Count(&mails).Error)
+			if tt.code == 0 {
+				assert.Nil(t, err)
+				assert.NotZero(t, tt.user.ID)
+				assert.Equal(t, tt.user.ID, invitation.UserID)
+				assert.Equal(t, int64(1), invitations)
+				assert.Equal(t, int64(1), mails)
+				return
+			}
+			testdatabase.AssertHTTPError(t, err, tt.code, tt.internalCode)
+			assert.Zero(t, invitations)
+			assert.Zero(t, mails)
+		})
+	}
+
+	t.Run("failed mail rolls back user & invitation", func(t *testing.T) {
+		queued := &databaseentity.MailOutbox{Recipient: "queued@example.com", Subject: "queued"}
+		assert.Nil(t, db.Create(queued).Error)
+
+		user := &databaseentity.User{Username: "rollback", FullName: "Rollback", Email: "rollback@example.com"}
+		invitation := &databaseentity.UserInvitation{TokenHash: helper.HashToken("rollback"), ExpiredAt: time.Now().Add(time.Hour)}
+		// same primary key as queued mail
+		err := repo.Create(ctx, user, invitation, &databaseentity.MailOutbox{ID: queued.ID, Recipient: user.Email, Subject: "rollback"})
+		testdatabase.AssertHTTPError(t, err, http.StatusInternalServerError, entity.UserRepoCreateError)
+
+		_, err = repo.GetUserByField(ctx, "username", "rollback", http.StatusNotFound)
+		testdatabase.AssertHTTPError(t, err, http.StatusNotFound, entity.SearchSingleUserNotFOund)
+		var invitations int64
+		assert.Nil(t, db.Model(databaseentity.UserInvitation{}).Where("token_hash = ?", invitation.TokenHash).Count(&invitations).Error)
+		assert.Zero(t, invitations)
+
+		// retry after failure is not refused as duplicate
+		user.ID = 0
+		invitation.ID = 0
+		err = repo.Create(ctx, user, invitation, &databaseentity.MailOutbox{Recipient: user.Email, Subject: "rollback"})
+		assert.Nil(t, err)
+	})
+}
+
+func Test_CreateOwner(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := userrepository.New(db)
+	ctx := context.Background()
+	testdatabase.User(t, db, &databaseentity.User{IsGuest: true})
+
+	t.Run("success with guest only", func(t *testing.T) {
+		err := repo.CreateOwner(ctx, &databaseentity.User{Username: "owner", FullName: "Owner", Email: "owner@example.com"})
+		assert.Nil(t, err)
+	})
+
+	t.Run("setup already done", func(t *testing.T) {
+		err := repo.CreateOwner(ctx, &databaseentity.User{Username: "second", FullName: "Second", Email: "second@example.com"})
+		testdatabase.AssertHTTPError(t, err, http.StatusForbidden, entity.SetupAlreadyDoneForbidden)
+
+		total, err := repo.GetTotalNonGuestUsers(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), total)
+	})
+
+	t.Run("non guest user created before setup", func(t *testing.T) {
+		db := testdatabase.Open(t)
+		repo := userrepository.New(db)
+		testdatabase.User(t, db, &databaseentity.User{})
+
+		err := repo.CreateOwner(ctx, &databaseentity.User{Username: "owner", FullName: "Owner", Email: "owner@example.com"})
+		testdatabase.AssertHTTPError(t, err, http.StatusForbidden, entity.SetupAlreadyDoneForbidden)
+		var locks int64
+		assert.Nil(t, db.Model(databaseentity.SetupLock{}).Count(&locks).Error)
+		assert.Zero(t, locks, "setup lock must be rolled back")
+	})
+}
+
+func Test_CreateOwnerConcurrent(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := userrepository.New(db)
+	ctx := context.Background()
+
+	const total = 5
+	var wg sync.WaitGroup
+	errs := make([]error, total)
+	for i := 0; i < total; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = repo.CreateOwner(ctx, &databaseentity.User{
+				Username: fmt.Sprintf("owner%d", i),
+				FullName: "Owner",
+				Email:    fmt.Sprintf("owner%d@example.com", i),
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	var created int
+	for _, err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		testdatabase.AssertHTTPError(t, err, http.StatusForbidden, entity.SetupAlreadyDoneForbidden)
+	}
+	assert.Equal(t, 1, created)
+	owners, err := repo.GetTotalNonGuestUsers(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), owners)
+}
+
+func Test_Update(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := userrepository.New(db)
+	ctx := context.Background()
+	testdatabase.User(t, db, &databaseentity.User{Username: "taken", Email: "taken@example.com"})
+
+	t.Run("empty primary key", func(t *testing.T) {
+		err := repo.Update(ctx, &databaseentity.User{Username: "nobody"})
+		testdatabase.AssertHTTPError(t, err, http.StatusInternalServerError, entity.UserRepoUpdateError)
+	})
+
+	t.Run("success with queued mails", func(t *testing.T) {
+		user := testdatabase.User(t, db, &databaseentity.User{})
+		user.FullName = "Updated Name"
+		err := repo.Update(ctx, user, &databaseentity.MailOutbox{Recipient: user.Email, Subject: "updated"})
+		assert.Nil(t, err)
+
+		saved, err := repo.GetUserByField(ctx, "id", user.ID, http.StatusNotFound)
+		assert.Nil(t, err)
+		assert.Equal(t, "Updated Name", saved.FullName)
+		var total int64
+		assert.Nil(t, db.Model(databaseentity.MailOutbox{}).Where("recipient = ?", user.Email).Count(&total).Error)
+		assert.Equal(t, int64(1), total)
+	})
+
+	t.Run("duplicate email rolls back queued mails", func(t *testing.T) {
+		user := testdatabase.User(t, db, &databaseentity.User{})
+		user.Email = "taken@example.com"
+		err := repo.Update(ctx, user, &databaseentity.MailOutbox{Recipient: "rollback@example.com", Subject: "updated"})
+		testdatabase.AssertHTTPError(t, err, http.StatusBadRequest, entity.CreateUserEmailDuplicate)
+
+		var total int64
+		assert.Nil(t, db.Model(databaseentity.MailOutbox{}).Where("recipient = ?", "rollback@example.com").Count(&total).Error)
+		assert.Equal(t, int64(0), total)
+	})
+}
+
+func Test_GetUserByField(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := userrepository.New(db)
+	ctx := context.Background()
+	user := testdatabase.User(t, db, &databaseentity.User{Username: "jane", Email: "jane@example.com"})
+
+	tests := []struct {
+		name           string
+		fieldName      string
+		fieldValue     interface{}
+		notFoundStatus int
+		code           int
+		internalCode   int
+	}{
+		{name: "by id", fieldName: "id", fieldValue: user.ID},
+		{name: "by username", fieldName: "username", fieldValue: "jane"},
+		{name: "by email", fieldName: "email", fieldValue: "jane@example.com"},
+		{
+			name:           "not found with given status",
+			fieldName:      "username",
+			fieldValue:     "john",
+			notFoundStatus: http.StatusBadRequest,
+			code:           http.StatusBadRequest,
+			internalCode:   entity.SearchSingleUserNotFOund,
+		},
+		{
+			name:         "invalid field",
+			fieldName:    "password",
+			fieldValue:   "secret",
+			code:         http.StatusInternalServerError,
+			internalCode: entity.UserRepoGetUserByFieldError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := repo.GetUserByField(ctx, tt.fieldName, tt.fieldValue, tt.notFoundStatus)
+			if tt.code == 0 {
+				assert.Nil(t, err)
+				assert.Equal(t, user.ID, result.ID)
+				return
+			}
+			testdatabase.AssertHTTPError(t, err, tt.code, tt.internalCode)
+		})
+	}
+}
+
+func Test_GetUsersByRequest(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := userrepository.New(db)
+	ctx := context.Background()
+	// distinct timestamps, so sorting by time is deterministic
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fixtures := []*databaseentity.User{
+		{Username: "alice", FullName: "Alice Wonder", Email: "alice@mail.com"},
+		{Username: "bob", FullName: "Bob Builder", Email: "bob@example.com"},
+		{Username: "carol", FullName: "Carol Wonder", Email: "carol@example.com", Disabled: true},
+		{Username: "guest", FullName: "Guest", Email: "guest@example.com", IsGuest: true},
+	}
+	for i, usr := range fixtures {
+		usr.CreatedAt = day.AddDate(0, 0, i)
+		usr.UpdatedAt = day.AddDate(0, 0, i)
+		testdatabase.User(t, db, usr)
+	}
+
+	tests := []struct {
+		name      string
+		req       *payloadentity.GetUserListRequest
+		usernames []string
+	}{
+		{
+			name:      "default sort by created at",
+			req:       &payloadentity.GetUserListRequest{},
+			usernames: []string{"alice", "bob", "carol", "guest"},
+		},
+		{
+			name:      "filter username",
+			req:       &payloadentity.GetUserListRequest{Username: "bob"},
+			usernames: []string{"bob"},
+		},
+		{
+			name:      "filter email case insensitive",
+			req:       &payloadentity.GetUserListRequest{Email: "EXAMPLE"},
+			usernames: []string{"bob", "carol", "guest"},
+		},
+		{
+			name:      "filter full name",
+			req:       &payloadentity.GetUserListRequest{FullName: "wonder", SortField: "fullName"},
+			usernames: []string{"alice", "carol"},
+		},
+		{
+			name:      "show active",
+			req:       &payloadentity.GetUserListRequest{Show: databaseentity.RequestShowActive},
+			usernames: []string{"alice", "bob", "guest"},
+		},
+		{
+			name:      "show not active",
+			req:       &payloadentity.GetUserListRequest{Show: databaseentity.RequestShowNotActive},
+			usernames: []string{"carol"},
+		},
+		{
+			name:      "guest only",
+			req:       &payloadentity.GetUserListRequest{GuestOnly: true},
+			usernames: []string{"guest"},
+		},
+		{
+			name:      "sort username descending",
+			req:       &payloadentity.GetUserListRequest{SortField: "username", DescendingOrder: true},
+			usernames: []string{"guest", "carol", "bob", "alice"},
+		},
+		{
+			name:      "sort email",
+			req:       &payloadentity.GetUserListRequest{SortField: "email"},
+			usernames: []string{"alice", "bob", "carol", "guest"},
+		},
+		{
+			name:      "sort created at descending",
+			req:       &payloadentity.GetUserListRequest{SortField: "createdAt", DescendingOrder: true},
+			usernames: []string{"guest", "carol", "bob", "alice"},
+		},
+		{
+			name:      "sort updated at",
+			req:       &payloadentity.GetUserListRequest{SortField: "updatedAt"},
+			usernames: []string{"alice", "bob", "carol", "guest"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total, err := repo.GetTotalUsersByRequest(ctx, tt.req)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(len(tt.usernames)), total)
+
+			users, err := repo.GetUsersByRequest(ctx, tt.req)
+			assert.Nil(t, err)
+			var usernames []string
+			for _, usr := range users {
+				usernames = append(usernames, usr.Username)
+			}
+			assert.Equal(t, tt.usernames, usernames)
+		})
+	}
+}
+
+func Test_MapUserUsername(t *testing.T) {
+	db := testdatabase.Open(t)
+	repo := userrepository.New(db)
+	ctx := context.Background()
+	alice := testdatabase.User(t, db, &databaseentity.User{Username: "alice"})
+	bob := testdatabase.User(t, db, &databaseentity.User{Username: "bob"})
+	unit := testdatabase.Unit(t, db, &databaseentity.Unit{CreatedBy: bob.ID})
+
+	tests := []struct {
+		name    string
+		payload interface{}
+		result  map[int]string
+	}{
+		{
+			name:    "users",
+			payload: []*databaseentity.User{alice, bob},
+			result:  map[int]string{alice.ID: "alice", bob.ID: "bob"},
+		},
+		{
+			name:    "single user",
+			payload: alice,
+			result:  map[int]string{alice.ID: "alice"},
+		},
+		{
+			name:    "units by creator",
+			payload: []*databaseentity.Unit{unit},
+			result:  map[int]string{bob.ID: "bob"},
+		},
+		{
+			name:    "audit logs by actor",
+			payload: []*databaseentity.AuditLog{{ActorID: alice.ID}},
+			result:  map[int]string{alice.ID: "alice"},
+		},
+		{
+			name:    "ids",
+			payload: []int{bob.ID},
+			result:  map[int]string{bob.ID: "bob"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := repo.MapUserUsername(ctx, tt.payload)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.result, result)
+		})
+	}
+
+	t.Run("invalid payload", func(t *testing.T) {
+		_, err := repo.MapUserUsername(ctx, "alice")
+		testdatabase.AssertHTTPError(t, err, http.StatusInternalServerError, entity.UserRepoMapUserUsernameError)
+	})
+}
@@ -0,0 +1,23 @@
+package testdatabase
+
+import (
+	"rap-c/app/entity"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// assert repository error is http error with given status & internal error code
+func AssertHTTPError(t testing.TB, err error, code int, internalCode int) {
+	t.Helper()
+	herr, ok := err.(*echo.HTTPError)
+	if !assert.True(t, ok, "expected http error, got %v", err) {
+		return
+	}
+	assert.Equal(t, code, herr.Code)
+	ierr, ok := herr.Internal.(*entity.InternalError)
+	if assert.True(t, ok, "expected internal error, got %v", herr.Internal) {
+		assert.Equal(t, internalCode, ierr.Code)
+	}
+}
@@ -0,0 +1,121 @@
+// integration test harness running repositories against a disposable database.
+//
+// database driver is chosen by TEST_DB_DRIVER env (mysql, postgres or sqlite, default sqlite).
+// sqlite database is a file inside test temp dir, mysql & postgres connect to the server of usual
+// MYSQL_* / POSTGRES_* env then create a randomly named database dropped when the test ends.
+package testdatabase
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"rap-c/app"
+	"rap-c/app/helper"
+	auditrepository "rap-c/app/repository/mysql/audit-repository"
+	"rap-c/config"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	DriverEnvName  string = "TEST_DB_DRIVER"
+	dbNamePrefix   string = "rap_c_test_"
+	dbNameTokenLen int    = 16
+	dbNameHashLen  int    = 16
+)
+
+// open database migrated by base schema & every default module with audit hooks registered,
+// database is removed on test cleanup
+func Open(t testing.TB) *gorm.DB {
+	t.Helper()
+	db := OpenEmpty(t)
+	migrator, err := app.New(nil, db, nil).Register(app.DefaultModules()...).Migrator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(); err != nil {
+		t.Fatal(err)
+	}
+	if err := auditrepository.RegisterHooks(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// open database without any table nor hook, database is removed on test cleanup
+func OpenEmpty(t testing.TB) *gorm.DB {
+	t.Helper()
+	driver := os.Getenv(DriverEnvName)
+	if driver == "" {
+		driver = config.DBDriverSQLite
+	}
+	cfg := config.InitTestConfig(map[string]string{"DB_DRIVER": driver})
+
+	var db *gorm.DB
+	switch driver {
+	case config.DBDriverSQLite:
+		db = openSQLite(t, cfg)
+	case config.DBDriverMySQL, config.DBDriverPostgres:
+		db = openServer(t, cfg)
+	default:
+		t.Fatalf("unknown %s `%s`, use mysql, postgres or sqlite", DriverEnvName, driver)
+	}
+	// keep test output clean, failed query is reported by the test itself
+	db.Logger = logger.Default.LogMode(logger.Silent)
+	return db
+}
+
+func openSQLite(t testing.TB, cfg *config.Config) *gorm.DB {
+	db, err := cfg.WithDBName(filepath.Join(t.TempDir(), "test.db")).OpenDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { closeDB(t, db) })
+	return db
+}
+
+func openServer(t testing.TB, cfg *config.Config) *gorm.DB {
+	server, err := cfg.OpenDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := helper.GenerateToken(dbNameTokenLen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// hex only, so the name is a valid identifier without quoting
+	name := dbNamePrefix + helper.HashToken(token)[:dbNameHashLen]
+	if err := server.Exec(fmt.Sprintf("CREATE DATABASE %s", name)).Error; err != nil {
+		closeDB(t, server)
+		t.Fatal(err)
+	}
+
+	db, err := cfg.WithDBName(name).OpenDB()
+	if err != nil {
+		server.Exec(fmt.Sprintf("DROP DATABASE %s", name))
+		closeDB(t, server)
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		// connection must be closed first, postgres refuses to drop database in use
+		closeDB(t, db)
+		if err := server.Exec(fmt.Sprintf("DROP DATABASE %s", name)).Error; err != nil {
+			t.Error(err)
+		}
+		closeDB(t, server)
+	})
+	return db
+}
+
+func closeDB(t testing.TB, db *gorm.DB) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err := sqlDB.Close(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,129 @@
+package testdatabase
+
+import (
+	databaseentity "rap-c/app/entity/database-entity"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// fixtures insert rows directly without repository, empty required fields are filled with unique defaults
+
+var sequence atomic.Int64
+
+func User(t testing.TB, db *gorm.DB, user *databaseentity.User) *databaseentity.User {
+	t.Helper()
+	if user.Username == "" {
+		user.Username = uniqueName("user")
+	}
+	if user.FullName == "" {
+		user.FullName = user.Username
+	}
+	if user.Email == "" {
+		user.Email = user.Username + "@example.com"
+	}
+	create(t, db, user)
+	return user
+}
+
+func Unit(t testing.TB, db *gorm.DB, unit *databaseentity.Unit) *databaseentity.Unit {
+	t.Helper()
+	if unit.Name == "" {
+		unit.Name = uniqueName("unit")
+	}
+	create(t, db, unit)
+	return unit
+}
+
+func Ingredient(t testing.TB, db *gorm.DB, ingredient *databaseentity.Ingredient) *databaseentity.Ingredient {
+	t.Helper()
+	if ingredient.UnitID == 0 {
+		ingredient.UnitID = Unit(t, db, &databaseentity.Unit{CreatedBy: ingredient.CreatedBy}).ID
+	}
+	if ingredient.Serial == "" {
+		ingredient.Serial = uniqueName("ING")
+	}
+	if ingredient.Name == "" {
+		ingredient.Name = ingredient.Serial
+	}
+	create(t, db, ingredient)
+	return ingredient
+}
+
+func IngredientConvertionUnit(t testing.TB, db *gorm.DB, conversion *databaseentity.IngredientConvertionUnit) *databaseentity.IngredientConvertionUnit {
+	t.Helper()
+	if conversion.IngredientID == 0 {
+		conversion.IngredientID = Ingredient(t, db, &databaseentity.Ingredient{}).ID
+	}
+	if conversion.UnitID == 0 {
+		conversion.UnitID = Unit(t, db, &databaseentity.Unit{}).ID
+	}
+	if conversion.Serial == "" {
+		conversion.Serial = uniqueName("CNV")
+	}
+	create(t, db, conversion)
+	return conversion
+}
+
+func Recipe(t testing.TB, db *gorm.DB, recipe *databaseentity.Recipe) *databaseentity.Recipe {
+	t.Helper()
+	if recipe.Serial == "" {
+		recipe.Serial = uniqueName("RCP")
+	}
+	if recipe.Name == "" {
+		recipe.Name = recipe.Serial
+	}
+	create(t, db, recipe)
+	return recipe
+}
+
+func RecipeIngredient(t testing.TB, db *gorm.DB, line *databaseentity.RecipeIngredient) *databaseentity.RecipeIngredient {
+	t.Helper()
+	if line.RecipeID == 0 {
+		line.RecipeID = Recipe(t, db, &databaseentity.Recipe{}).ID
+	}
+	if line.IngredientID == nil && line.SubRecipeID == nil {
+		line.IngredientID = &Ingredient(t, db, &databaseentity.Ingredient{}).ID
+	}
+	if line.UnitID == 0 {
+		line.UnitID = Unit(t, db, &databaseentity.Unit{}).ID
+	}
+	if line.Serial == "" {
+		line.Serial = uniqueName("RCI")
+	}
+	create(t, db, line)
+	return line
+}
+
+func MailOutbox(t testing.TB, db *gorm.DB, mail *databaseentity.MailOutbox) *databaseentity.MailOutbox {
+	t.Helper()
+	if mail.Recipient == "" {
+		mail.Recipient = "recipient@example.com"
+	}
+	if mail.Subject == "" {
+		mail.Subject = "subject"
+	}
+	if mail.Status == "" {
+		mail.Status = databaseentity.MailStatusPending
+	}
+	if mail.NextAttemptAt.IsZero() {
+		mail.NextAttemptAt = time.Now()
+	}
+	create(t, db, mail)
+	return mail
+}
+
+func create(t testing.TB, db *gorm.DB, value interface{}) {
+	t.Helper()
+	if err := db.Create(value).Error; err != nil {
+		t.Fatal(err)
+	}
+}
+
+// prefix followed by sequence number, short enough for every name column
+func uniqueName(prefix string) string {
+	return prefix + strconv.FormatInt(sequence.Add(1), 10)
+}
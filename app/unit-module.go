@@ -0,0 +1,41 @@
+package app
+
+import (
+	"embed"
+	"io/fs"
+	"rap-c/app/handler/api"
+	unitrepository "rap-c/app/repository/mysql/unit-repository"
+	unitusecase "rap-c/app/usecase/unit-usecase"
+	"rap-c/route"
+)
+
+//go:embed migrations/unit
+var unitMigrations embed.FS
+
+// measurement unit, needs user module
+func NewUnitModule() Module {
+	return &unitModule{}
+}
+
+type unitModule struct{}
+
+func (m *unitModule) Name() string {
+	return "unit"
+}
+
+func (m *unitModule) Migrations() fs.FS {
+	return moduleMigrations(unitMigrations, m.Name())
+}
+
+func (m *unitModule) Load(c *Container) error {
+	if c.FormatterUsecase == nil {
+		return errMissing(m, "user")
+	}
+	c.UnitRepository = unitrepository.New(c.DB)
+	c.UnitUsecase = unitusecase.NewUsecase(c.Config, c.UnitRepository)
+	return nil
+}
+
+func (m *unitModule) Routes(g *route.Group, c *Container) {
+	route.SetUnitAPI(g, api.NewUnitHandler(c.Config, c.Route, c.UnitUsecase, c.FormatterUsecase))
+}
@@ -0,0 +1,43 @@
+package auditusecase
+
+import (
+	"context"
+	"rap-c/app/entity"
+	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	"rap-c/app/repository/contract"
+	usecasecontract "rap-c/app/usecase/contract"
+	"rap-c/config"
+)
+
+func NewUsecase(cfg *config.Config, auditRepo contract.AuditRepository) usecasecontract.AuditUsecase {
+	return &usecase{cfg, auditRepo}
+}
+
+type usecase struct {
+	cfg       *config.Config
+	auditRepo contract.AuditRepository
+}
+
+func (uc *usecase) GetAuditLogList(ctx context.Context, req *payloadentity.GetAuditLogListRequest) ([]*databaseentity.AuditLog, error) {
+	// validate filter
+	err := entity.InitValidator().Validate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	return uc.auditRepo.GetAuditLogsByRequest(ctx, req)
+}
+
+func (uc *usecase) GetTotalAuditLogList(ctx context.Context, req *payloadentity.GetAuditLogListRequest) (int64, error) {
+	// validate filter
+	err := entity.InitValidator().Validate(req)
+	if err != nil {
+		return 0, err
+	}
+
+	return uc.auditRepo.GetTotalAuditLogsByRequest(ctx, req)
+}
@@ -0,0 +1,86 @@
+package auditusecase_test
+
+import (
+	"context"
+	"net/http"
+	"rap-c/app/entity"
+	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	"rap-c/app/repository/contract/mocks"
+	auditusecase "rap-c/app/usecase/audit-usecase"
+	"rap-c/app/usecase/contract"
+	"rap-c/config"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func initUsecase(ctrl *gomock.Controller, cfg *config.Config) (contract.AuditUsecase, *mocks.MockAuditRepository) {
+	auditRepo := mocks.NewMockAuditRepository(ctrl)
+	usecase := auditusecase.NewUsecase(cfg, auditRepo)
+	return usecase, auditRepo
+}
+
+func Test_GetAuditLogList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	usecase, auditRepo := initUsecase(ctrl, nil)
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		req := &payloadentity.GetAuditLogListRequest{
+			EntityType: "user",
+			Action:     databaseentity.AuditActionUpdate,
+			DateFrom:   "2024-01-01",
+			DateTo:     "2024-01-31",
+		}
+		logs := []*databaseentity.AuditLog{{ID: 1, EntityType: "user", Action: databaseentity.AuditActionUpdate}}
+		auditRepo.EXPECT().GetAuditLogsByRequest(ctx, req).Return(logs, nil).Times(1)
+
+		resp, err := usecase.GetAuditLogList(ctx, req)
+		assert.Nil(t, err)
+		assert.Equal(t, logs, resp)
+		assert.Equal(t, entity.Pagination(1), req.Page)
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		_, err := usecase.GetAuditLogList(ctx, &payloadentity.GetAuditLogListRequest{
+			EntityType: "account",
+			Action:     "read",
+			DateFrom:   "01-01-2024",
+		})
+		assert.NotNil(t, err)
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, herr.Code)
+		assert.Equal(t, map[string][]*entity.ValidatorMessage{
+			"entityType": {{Tag: "oneof", Param: "user unit ingredient ingredient_conversion recipe recipe_ingredient"}},
+			"action":     {{Tag: "oneof", Param: "create update delete"}},
+			"dateFrom":   {{Tag: "datetime", Param: "2006-01-02"}},
+		}, herr.Message)
+	})
+}
+
+func Test_GetTotalAuditLogList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	usecase, auditRepo := initUsecase(ctrl, nil)
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		req := &payloadentity.GetAuditLogListRequest{Actor: "gendutski"}
+		auditRepo.EXPECT().GetTotalAuditLogsByRequest(ctx, req).Return(int64(5), nil).Times(1)
+
+		total, err := usecase.GetTotalAuditLogList(ctx, req)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(5), total)
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		_, err := usecase.GetTotalAuditLogList(ctx, &payloadentity.GetAuditLogListRequest{DateTo: "tomorrow"})
+		assert.NotNil(t, err)
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, herr.Code)
+	})
+}
@@ -0,0 +1,60 @@
+package authusecase
+
+import (
+	"context"
+	"net/http"
+	"rap-c/app/entity"
+	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	"rap-c/app/helper"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// get valid invitation and its invited user, unmatched email is treated as not found
+func (uc *usecase) getInvitation(ctx context.Context, email, token string) (*databaseentity.User, *databaseentity.UserInvitation, error) {
+	invitation, err := uc.invitationRepo.GetValidInvitation(ctx, token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := uc.authRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		if herr, ok := err.(*echo.HTTPError); ok && herr.Code == http.StatusNotFound {
+			return nil, nil, &echo.HTTPError{
+				Code:     http.StatusNotFound,
+				Message:  entity.InvitationNotFoundMessage,
+				Internal: entity.NewInternalError(entity.InvitationNotFound, err.Error()),
+			}
+		}
+		return nil, nil, err
+	}
+	if user.ID != invitation.UserID || !user.IsInvitationPending() {
+		return nil, nil, &echo.HTTPError{
+			Code:     http.StatusNotFound,
+			Message:  entity.InvitationNotFoundMessage,
+			Internal: entity.NewInternalError(entity.InvitationNotFound, "invitation does not belong to user"),
+		}
+	}
+	return user, invitation, nil
+}
+
+// generate new reset password token from request
+func (uc *usecase) newResetToken(payload *payloadentity.RequestResetPayload) (*databaseentity.PasswordResetToken, error) {
+	token, err := helper.GenerateToken(64)
+	if err != nil {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.HelperGenerateTokenError, err.Error()),
+		}
+	}
+	return &databaseentity.PasswordResetToken{
+		Email:     payload.Email,
+		Token:     token,
+		TokenHash: helper.HashToken(token),
+		IPAddress: payload.IPAddress,
+		ExpiredAt: time.Now().Add(time.Minute * time.Duration(uc.cfg.ResetTokenInMinutes())),
+	}, nil
+}
@@ -2,6 +2,7 @@ package authusecase
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"rap-c/app/entity"
 	databaseentity "rap-c/app/entity/database-entity"
@@ -23,13 +24,15 @@ const (
 	tokenStrSecret string = "secret"
 )
 
-func NewUsecase(cfg *config.Config, authRepo contract.AuthRepository) usecasecontract.AuthUsecase {
-	return &usecase{cfg, authRepo}
+func NewUsecase(cfg *config.Config, authRepo contract.AuthRepository, invitationRepo contract.InvitationRepository, mailUsecase usecasecontract.MailUsecase) usecasecontract.AuthUsecase {
+	return &usecase{cfg, authRepo, invitationRepo, mailUsecase}
 }
 
 type usecase struct {
-	cfg      *config.Config
-	authRepo contract.AuthRepository
+	cfg            *config.Config
+	authRepo       contract.AuthRepository
+	invitationRepo contract.InvitationRepository
+	mailUsecase    usecasecontract.MailUsecase
 }
 
 func (uc *usecase) AttemptLogin(ctx context.Context, payload *payloadentity.AttemptLoginPayload) (*databaseentity.User, error) {
@@ -55,10 +58,7 @@ func (uc *usecase) AttemptGuestLogin(ctx context.Context) (*databaseentity.User,
 			Internal: entity.NewInternalError(entity.AttemptGuestLoginForbidden, entity.AttemptGuestLoginForbiddenMessage),
 		}
 	}
-	user, err := uc.authRepo.DoUserLogin(ctx, &payloadentity.AttemptLoginPayload{
-		Email:    config.GuestEmail,
-		Password: config.GuestPassword,
-	})
+	user, err := uc.authRepo.GetGuestUser(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -200,26 +200,70 @@ func (uc *usecase) RenewPassword(ctx context.Context, user *databaseentity.User,
 	return uc.authRepo.DoRenewPassword(ctx, user, payload)
 }
 
-func (uc *usecase) RequestResetPassword(ctx context.Context, payload *payloadentity.RequestResetPayload) (*databaseentity.User, *databaseentity.PasswordResetToken, error) {
+func (uc *usecase) RequestResetPassword(ctx context.Context, payload *payloadentity.RequestResetPayload) error {
 	// validate payload
 	err := entity.InitValidator().Validate(payload)
 	if err != nil {
-		return nil, nil, err
+		return err
+	}
+
+	// rate limit per email & per ip address
+	since := time.Now().Add(-time.Minute * time.Duration(uc.cfg.ResetLimitInMinutes()))
+	limits := []struct {
+		fieldName string
+		value     string
+		max       int
+	}{
+		{"email", payload.Email, uc.cfg.ResetLimitPerEmail()},
+		{"ip_address", payload.IPAddress, uc.cfg.ResetLimitPerIP()},
+	}
+	for _, limit := range limits {
+		if limit.value == "" || limit.max < 1 {
+			continue
+		}
+		total, err := uc.authRepo.GetTotalResetRequestsByField(ctx, limit.fieldName, limit.value, since)
+		if err != nil {
+			return err
+		}
+		if total >= int64(limit.max) {
+			return &echo.HTTPError{
+				Code:     http.StatusTooManyRequests,
+				Message:  entity.ResetPasswordRequestTooManyMessage,
+				Internal: entity.NewInternalError(entity.ResetPasswordRequestTooMany, fmt.Sprintf("limit reached for %s", limit.fieldName)),
+			}
+		}
 	}
 
-	// check email
+	// check email, unknown email get the same treatment to prevent account enumeration
 	user, err := uc.authRepo.GetUserByEmail(ctx, payload.Email)
 	if err != nil {
-		return nil, nil, err
+		herr, ok := err.(*echo.HTTPError)
+		if !ok || herr.Code != http.StatusNotFound {
+			return err
+		}
+		user = nil
+	}
+	// unverified email cannot receive reset password link
+	if user != nil && user.EmailVerifiedAt == nil {
+		user = nil
 	}
 
-	// generate reset password token
-	token, err := uc.authRepo.GenerateUserResetPassword(ctx, payload)
+	// generate reset password token, also recorded for unknown email so rate limit applies equally
+	reset, err := uc.newResetToken(payload)
 	if err != nil {
-		return nil, nil, err
+		return err
 	}
 
-	return user, token, nil
+	// only registered user get reset password mail
+	var mails []*databaseentity.MailOutbox
+	if user != nil {
+		mail, err := uc.mailUsecase.ResetPassword(user, reset)
+		if err != nil {
+			return err
+		}
+		mails = append(mails, mail)
+	}
+	return uc.authRepo.GenerateUserResetPassword(ctx, reset, mails...)
 }
 
 func (uc *usecase) ValidateResetToken(ctx context.Context, payload *payloadentity.ValidateResetTokenPayload) error {
@@ -271,8 +315,9 @@ func (uc *usecase) SubmitResetPassword(ctx context.Context, payload *payloadenti
 	user.Password = encryptPass
 	user.UpdatedBy = user.ID
 
-	// update reset token
-	reset.Token = ""
+	// mark reset token as used
+	now := time.Now()
+	reset.UsedAt = &now
 
 	// save
 	err = uc.authRepo.DoResetPassword(ctx, user, reset)
@@ -281,3 +326,61 @@ func (uc *usecase) SubmitResetPassword(ctx context.Context, payload *payloadenti
 	}
 	return user, nil
 }
+
+func (uc *usecase) ValidateInvitation(ctx context.Context, payload *payloadentity.ValidateInvitationPayload) (*databaseentity.User, error) {
+	// validate payload
+	err := entity.InitValidator().Validate(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	user, _, err := uc.getInvitation(ctx, payload.Email, payload.Token)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (uc *usecase) AcceptInvitation(ctx context.Context, payload *payloadentity.AcceptInvitationPayload) (*databaseentity.User, error) {
+	// validate payload
+	err := entity.InitValidator().Validate(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	// get invitation & invited user
+	user, invitation, err := uc.getInvitation(ctx, payload.Email, payload.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	// encrypt password
+	encryptPass, err := helper.EncryptPassword(payload.Password)
+	if err != nil {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.HelperEncryptPasswordError, err.Error()),
+		}
+	}
+
+	// update user, invitation was received by email so the email is verified
+	now := time.Now()
+	user.Password = encryptPass
+	user.PasswordMustChange = false
+	if user.EmailVerifiedAt == nil {
+		user.EmailVerifiedAt = &now
+	}
+	user.UpdatedBy = user.ID
+
+	// mark invitation as accepted
+	invitation.AcceptedAt = &now
+	invitation.UpdatedBy = user.ID
+
+	// save
+	err = uc.invitationRepo.Accept(ctx, user, invitation)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
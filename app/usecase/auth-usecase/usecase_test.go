@@ -11,6 +11,7 @@ import (
 	repomocks "rap-c/app/repository/contract/mocks"
 	authusecase "rap-c/app/usecase/auth-usecase"
 	"rap-c/app/usecase/contract"
+	usecasemocks "rap-c/app/usecase/contract/mocks"
 	"rap-c/config"
 	"testing"
 	"time"
@@ -21,15 +22,17 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func initUsecase(ctrl *gomock.Controller, cfg *config.Config) (contract.AuthUsecase, *repomocks.MockAuthRepository) {
+func initUsecase(ctrl *gomock.Controller, cfg *config.Config) (contract.AuthUsecase, *repomocks.MockAuthRepository, *repomocks.MockInvitationRepository, *usecasemocks.MockMailUsecase) {
 	authRepo := repomocks.NewMockAuthRepository(ctrl)
-	uc := authusecase.NewUsecase(cfg, authRepo)
-	return uc, authRepo
+	invitationRepo := repomocks.NewMockInvitationRepository(ctrl)
+	mailUsecase := usecasemocks.NewMockMailUsecase(ctrl)
+	uc := authusecase.NewUsecase(cfg, authRepo, invitationRepo, mailUsecase)
+	return uc, authRepo, invitationRepo, mailUsecase
 }
 
 func Test_AttemptLogin(t *testing.T) {
 	ctrl := gomock.NewController(t)
-	uc, authRepo := initUsecase(ctrl, &config.Config{})
+	uc, authRepo, _, _ := initUsecase(ctrl, &config.Config{})
 	ctx := context.Background()
 	password := "password"
 
@@ -89,17 +92,14 @@ func Test_AttemptGuestLogin(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("success", func(t *testing.T) {
-		uc, authRepo := initUsecase(ctrl, config.InitTestConfig(map[string]string{"ENABLE_GUEST_LOGIN": "true"}))
+		uc, authRepo, _, _ := initUsecase(ctrl, config.InitTestConfig(map[string]string{"ENABLE_GUEST_LOGIN": "true"}))
 
 		validUser := &databaseentity.User{
 			ID:      2,
 			Email:   config.GuestEmail,
 			IsGuest: true,
 		}
-		authRepo.EXPECT().DoUserLogin(ctx, &payloadentity.AttemptLoginPayload{
-			Email:    config.GuestEmail,
-			Password: config.GuestPassword,
-		}).Return(validUser, nil).Times(1)
+		authRepo.EXPECT().GetGuestUser(ctx).Return(validUser, nil).Times(1)
 
 		res, err := uc.AttemptGuestLogin(ctx)
 		assert.Nil(t, err)
@@ -107,16 +107,13 @@ func Test_AttemptGuestLogin(t *testing.T) {
 	})
 
 	t.Run("not guest users", func(t *testing.T) {
-		uc, authRepo := initUsecase(ctrl, config.InitTestConfig(map[string]string{"ENABLE_GUEST_LOGIN": "true"}))
+		uc, authRepo, _, _ := initUsecase(ctrl, config.InitTestConfig(map[string]string{"ENABLE_GUEST_LOGIN": "true"}))
 
 		validUser := &databaseentity.User{
 			ID:    2,
 			Email: config.GuestEmail,
 		}
-		authRepo.EXPECT().DoUserLogin(ctx, &payloadentity.AttemptLoginPayload{
-			Email:    config.GuestEmail,
-			Password: config.GuestPassword,
-		}).Return(validUser, nil).Times(1)
+		authRepo.EXPECT().GetGuestUser(ctx).Return(validUser, nil).Times(1)
 
 		res, err := uc.AttemptGuestLogin(ctx)
 		assert.Nil(t, res)
@@ -127,8 +124,26 @@ func Test_AttemptGuestLogin(t *testing.T) {
 		assert.Equal(t, entity.NonGuestAttemptGuestLogin, herr.Internal.(*entity.InternalError).Code)
 	})
 
+	t.Run("guest user not found", func(t *testing.T) {
+		uc, authRepo, _, _ := initUsecase(ctrl, config.InitTestConfig(map[string]string{"ENABLE_GUEST_LOGIN": "true"}))
+
+		authRepo.EXPECT().GetGuestUser(ctx).Return(nil, &echo.HTTPError{
+			Code:     http.StatusUnauthorized,
+			Message:  entity.AttemptLoginFailedMessage,
+			Internal: entity.NewInternalError(entity.AttemptLoginFailed, "guest user not found"),
+		}).Times(1)
+
+		res, err := uc.AttemptGuestLogin(ctx)
+		assert.Nil(t, res)
+		assert.NotNil(t, err)
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusUnauthorized, herr.Code)
+		assert.Equal(t, entity.AttemptLoginFailed, herr.Internal.(*entity.InternalError).Code)
+	})
+
 	t.Run("disable guest", func(t *testing.T) {
-		uc, _ := initUsecase(ctrl, config.InitTestConfig(map[string]string{"ENABLE_GUEST_LOGIN": "false"}))
+		uc, _, _, _ := initUsecase(ctrl, config.InitTestConfig(map[string]string{"ENABLE_GUEST_LOGIN": "false"}))
 
 		res, err := uc.AttemptGuestLogin(ctx)
 		assert.Nil(t, res)
@@ -148,7 +163,7 @@ func Test_GenerateJwtToken(t *testing.T) {
 		"JWT_EXPIRATION_IN_MINUTES": "2",
 		"JWT_REMEMBER_IN_DAYS":      "2",
 	})
-	uc, _ := initUsecase(ctrl, cfg)
+	uc, _, _, _ := initUsecase(ctrl, cfg)
 	ctx := context.Background()
 	t.Run("success, short time token session", func(t *testing.T) {
 		exp := time.Now().Add(time.Minute * time.Duration(cfg.JwtExpirationInMinutes())).Unix()
@@ -196,7 +211,7 @@ func Test_GenerateJwtToken(t *testing.T) {
 
 func Test_ValidateJwtToken(t *testing.T) {
 	ctrl := gomock.NewController(t)
-	uc, authRepo := initUsecase(ctrl, &config.Config{})
+	uc, authRepo, _, _ := initUsecase(ctrl, &config.Config{})
 	ctx := context.Background()
 
 	validUser := &databaseentity.User{
@@ -334,7 +349,7 @@ func Test_ValidateJwtToken(t *testing.T) {
 
 func Test_RenewPassword(t *testing.T) {
 	ctrl := gomock.NewController(t)
-	uc, authRepo := initUsecase(ctrl, &config.Config{})
+	uc, authRepo, _, _ := initUsecase(ctrl, &config.Config{})
 	ctx := context.Background()
 
 	oldPassword := "0LdF4s#ionP455W0rd"
@@ -402,53 +417,98 @@ func Test_RenewPassword(t *testing.T) {
 
 func Test_RequestResetPassword(t *testing.T) {
 	ctrl := gomock.NewController(t)
-	uc, authRepo := initUsecase(ctrl, &config.Config{})
+	uc, authRepo, _, mailUsecase := initUsecase(ctrl, config.InitTestConfig(map[string]string{
+		"RESET_TOKEN_EXPIRATION_IN_MINUTES":     "60",
+		"RESET_REQUEST_LIMIT_PER_EMAIL":         "3",
+		"RESET_REQUEST_LIMIT_PER_IP":            "10",
+		"RESET_REQUEST_LIMIT_WINDOW_IN_MINUTES": "60",
+	}))
 	ctx := context.Background()
 
+	verifiedAt := time.Now()
 	validUser := &databaseentity.User{
 		Email:              "gendutski@gmail.com",
+		EmailVerifiedAt:    &verifiedAt,
 		PasswordMustChange: true,
 	}
-	validToken := &databaseentity.PasswordResetToken{
-		Token: "token",
-	}
+	validMail := &databaseentity.MailOutbox{Recipient: "gendutski@gmail.com"}
 
 	t.Run("success", func(t *testing.T) {
-		payload := &payloadentity.RequestResetPayload{Email: "gendutski@gmail.com"}
+		payload := &payloadentity.RequestResetPayload{Email: "gendutski@gmail.com", IPAddress: "127.0.0.1"}
+		authRepo.EXPECT().GetTotalResetRequestsByField(ctx, "email", "gendutski@gmail.com", gomock.Any()).Return(int64(0), nil).Times(1)
+		authRepo.EXPECT().GetTotalResetRequestsByField(ctx, "ip_address", "127.0.0.1", gomock.Any()).Return(int64(0), nil).Times(1)
 		authRepo.EXPECT().GetUserByEmail(ctx, "gendutski@gmail.com").Return(validUser, nil).Times(1)
-		authRepo.EXPECT().GenerateUserResetPassword(ctx, payload).Return(validToken, nil).Times(1)
+		mailUsecase.EXPECT().ResetPassword(validUser, gomock.Any()).Return(validMail, nil).Times(1)
+		authRepo.EXPECT().GenerateUserResetPassword(ctx, gomock.Any(), validMail).
+			DoAndReturn(func(ctx context.Context, reset *databaseentity.PasswordResetToken, mails ...*databaseentity.MailOutbox) error {
+				assert.Equal(t, "gendutski@gmail.com", reset.Email)
+				assert.Equal(t, "127.0.0.1", reset.IPAddress)
+				assert.NotEmpty(t, reset.Token)
+				assert.Equal(t, helper.HashToken(reset.Token), reset.TokenHash)
+				assert.WithinDuration(t, time.Now().Add(time.Hour), reset.ExpiredAt, time.Minute)
+				return nil
+			}).Times(1)
+
+		err := uc.RequestResetPassword(ctx, payload)
+		assert.Nil(t, err)
+	})
 
-		user, token, err := uc.RequestResetPassword(ctx, payload)
+	t.Run("unknown email", func(t *testing.T) {
+		payload := &payloadentity.RequestResetPayload{Email: "gendutski@gmail.com"}
+		authRepo.EXPECT().GetTotalResetRequestsByField(ctx, "email", "gendutski@gmail.com", gomock.Any()).Return(int64(0), nil).Times(1)
+		authRepo.EXPECT().GetUserByEmail(ctx, "gendutski@gmail.com").Return(nil, &echo.HTTPError{Code: http.StatusNotFound}).Times(1)
+		authRepo.EXPECT().GenerateUserResetPassword(ctx, gomock.Any()).Return(nil).Times(1)
+
+		err := uc.RequestResetPassword(ctx, payload)
 		assert.Nil(t, err)
-		assert.Equal(t, validToken, token)
-		assert.Equal(t, validUser, user)
+	})
+
+	t.Run("unverified email", func(t *testing.T) {
+		payload := &payloadentity.RequestResetPayload{Email: "gendutski@gmail.com"}
+		authRepo.EXPECT().GetTotalResetRequestsByField(ctx, "email", "gendutski@gmail.com", gomock.Any()).Return(int64(0), nil).Times(1)
+		authRepo.EXPECT().GetUserByEmail(ctx, "gendutski@gmail.com").Return(&databaseentity.User{Email: "gendutski@gmail.com"}, nil).Times(1)
+		authRepo.EXPECT().GenerateUserResetPassword(ctx, gomock.Any()).Return(nil).Times(1)
+
+		err := uc.RequestResetPassword(ctx, payload)
+		assert.Nil(t, err)
+	})
+
+	t.Run("too many requests", func(t *testing.T) {
+		payload := &payloadentity.RequestResetPayload{Email: "gendutski@gmail.com", IPAddress: "127.0.0.1"}
+		authRepo.EXPECT().GetTotalResetRequestsByField(ctx, "email", "gendutski@gmail.com", gomock.Any()).Return(int64(3), nil).Times(1)
+
+		err := uc.RequestResetPassword(ctx, payload)
+		assert.NotNil(t, err)
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusTooManyRequests, herr.Code)
+		assert.Equal(t, entity.ResetPasswordRequestTooMany, herr.Internal.(*entity.InternalError).Code)
 	})
 
 	t.Run("token failed", func(t *testing.T) {
 		payload := &payloadentity.RequestResetPayload{Email: "gendutski@gmail.com"}
+		authRepo.EXPECT().GetTotalResetRequestsByField(ctx, "email", "gendutski@gmail.com", gomock.Any()).Return(int64(0), nil).Times(1)
 		authRepo.EXPECT().GetUserByEmail(ctx, "gendutski@gmail.com").Return(validUser, nil).Times(1)
-		authRepo.EXPECT().GenerateUserResetPassword(ctx, payload).Return(nil, errors.New("accident happen")).Times(1)
+		mailUsecase.EXPECT().ResetPassword(validUser, gomock.Any()).Return(validMail, nil).Times(1)
+		authRepo.EXPECT().GenerateUserResetPassword(ctx, gomock.Any(), validMail).Return(errors.New("accident happen")).Times(1)
 
-		user, token, err := uc.RequestResetPassword(ctx, payload)
+		err := uc.RequestResetPassword(ctx, payload)
 		assert.NotNil(t, err)
-		assert.Nil(t, token)
-		assert.Nil(t, user)
 	})
 
 	t.Run("user failed", func(t *testing.T) {
 		payload := &payloadentity.RequestResetPayload{Email: "gendutski@gmail.com"}
-		authRepo.EXPECT().GetUserByEmail(ctx, "gendutski@gmail.com").Return(nil, errors.New("user not found")).Times(1)
+		authRepo.EXPECT().GetTotalResetRequestsByField(ctx, "email", "gendutski@gmail.com", gomock.Any()).Return(int64(0), nil).Times(1)
+		authRepo.EXPECT().GetUserByEmail(ctx, "gendutski@gmail.com").Return(nil, errors.New("accident happen")).Times(1)
 
-		user, token, err := uc.RequestResetPassword(ctx, payload)
+		err := uc.RequestResetPassword(ctx, payload)
 		assert.NotNil(t, err)
-		assert.Nil(t, token)
-		assert.Nil(t, user)
 	})
 
 	t.Run("paylod not valid", func(t *testing.T) {
 		payload := &payloadentity.RequestResetPayload{Email: "gendutski.com"}
 
-		_, _, err := uc.RequestResetPassword(ctx, payload)
+		err := uc.RequestResetPassword(ctx, payload)
 		assert.NotNil(t, err)
 		herr, ok := err.(*echo.HTTPError)
 		assert.True(t, ok)
@@ -459,7 +519,7 @@ func Test_RequestResetPassword(t *testing.T) {
 
 func Test_ValidateResetToken(t *testing.T) {
 	ctrl := gomock.NewController(t)
-	uc, authRepo := initUsecase(ctrl, &config.Config{})
+	uc, authRepo, _, _ := initUsecase(ctrl, &config.Config{})
 	ctx := context.Background()
 
 	t.Run("success", func(t *testing.T) {
@@ -490,7 +550,7 @@ func Test_ValidateResetToken(t *testing.T) {
 
 func Test_SubmitResetPassword(t *testing.T) {
 	ctrl := gomock.NewController(t)
-	uc, authRepo := initUsecase(ctrl, &config.Config{})
+	uc, authRepo, _, _ := initUsecase(ctrl, &config.Config{})
 	ctx := context.Background()
 
 	t.Run("success", func(t *testing.T) {
@@ -513,11 +573,11 @@ func Test_SubmitResetPassword(t *testing.T) {
 
 		authRepo.EXPECT().ValidateResetToken(ctx, payloadToken).Return(token, nil).Times(1)
 		authRepo.EXPECT().GetUserByEmail(ctx, "gendutski@gmail.com").Return(validUser, nil).Times(1)
-		authRepo.EXPECT().DoResetPassword(ctx, gomock.Any(), &databaseentity.PasswordResetToken{}).Return(nil).Times(1)
+		authRepo.EXPECT().DoResetPassword(ctx, gomock.Any(), token).Return(nil).Times(1)
 
 		_, err := uc.SubmitResetPassword(ctx, payload)
 		assert.Nil(t, err)
-		assert.Empty(t, token.Email)
+		assert.NotNil(t, token.UsedAt)
 	})
 
 	t.Run("validator empty struct", func(t *testing.T) {
@@ -564,3 +624,77 @@ func Test_SubmitResetPassword(t *testing.T) {
 		}, herr.Message)
 	})
 }
+
+func Test_AcceptInvitation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	uc, authRepo, invitationRepo, _ := initUsecase(ctrl, &config.Config{})
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		payload := &payloadentity.AcceptInvitationPayload{
+			Email:           "gendutski@gmail.com",
+			Token:           "token",
+			Password:        "new password",
+			ConfirmPassword: "new password",
+		}
+		invitation := &databaseentity.UserInvitation{UserID: 1}
+		invitedUser := &databaseentity.User{ID: 1, Email: "gendutski@gmail.com"}
+
+		invitationRepo.EXPECT().GetValidInvitation(ctx, "token").Return(invitation, nil).Times(1)
+		authRepo.EXPECT().GetUserByEmail(ctx, "gendutski@gmail.com").Return(invitedUser, nil).Times(1)
+		invitationRepo.EXPECT().Accept(ctx, invitedUser, invitation).Return(nil).Times(1)
+
+		user, err := uc.AcceptInvitation(ctx, payload)
+		assert.Nil(t, err)
+		assert.True(t, helper.ValidateEncryptedPassword(user.Password, "new password"))
+		assert.NotNil(t, user.EmailVerifiedAt)
+		assert.NotNil(t, invitation.AcceptedAt)
+	})
+
+	t.Run("invitation belongs to other user", func(t *testing.T) {
+		payload := &payloadentity.AcceptInvitationPayload{
+			Email:           "gendutski@gmail.com",
+			Token:           "token",
+			Password:        "new password",
+			ConfirmPassword: "new password",
+		}
+		invitationRepo.EXPECT().GetValidInvitation(ctx, "token").Return(&databaseentity.UserInvitation{UserID: 2}, nil).Times(1)
+		authRepo.EXPECT().GetUserByEmail(ctx, "gendutski@gmail.com").Return(&databaseentity.User{ID: 1}, nil).Times(1)
+
+		_, err := uc.AcceptInvitation(ctx, payload)
+		assert.NotNil(t, err)
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusNotFound, herr.Code)
+		assert.Equal(t, entity.InvitationNotFound, herr.Internal.(*entity.InternalError).Code)
+	})
+
+	t.Run("user already has password", func(t *testing.T) {
+		payload := &payloadentity.ValidateInvitationPayload{
+			Email: "gendutski@gmail.com",
+			Token: "token",
+		}
+		invitationRepo.EXPECT().GetValidInvitation(ctx, "token").Return(&databaseentity.UserInvitation{UserID: 1}, nil).Times(1)
+		authRepo.EXPECT().GetUserByEmail(ctx, "gendutski@gmail.com").Return(&databaseentity.User{ID: 1, Password: "password"}, nil).Times(1)
+
+		_, err := uc.ValidateInvitation(ctx, payload)
+		assert.NotNil(t, err)
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusNotFound, herr.Code)
+	})
+
+	t.Run("validator failed", func(t *testing.T) {
+		_, err := uc.AcceptInvitation(ctx, &payloadentity.AcceptInvitationPayload{
+			Email:           "gendutski@gmail.com",
+			Token:           "token",
+			Password:        "short",
+			ConfirmPassword: "short",
+		})
+		assert.NotNil(t, err)
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, herr.Code)
+		assert.Equal(t, entity.ValidatorBadRequest, herr.Internal.(*entity.InternalError).Code)
+	})
+}
@@ -0,0 +1,14 @@
+package contract
+
+import (
+	"context"
+	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+)
+
+type AuditUsecase interface {
+	// get audit log list
+	GetAuditLogList(ctx context.Context, req *payloadentity.GetAuditLogListRequest) ([]*databaseentity.AuditLog, error)
+	// get total audit log list
+	GetTotalAuditLogList(ctx context.Context, req *payloadentity.GetAuditLogListRequest) (int64, error)
+}
@@ -19,10 +19,14 @@ type AuthUsecase interface {
 	ValidateJwtToken(ctx context.Context, token *jwt.Token, guestAccepted bool) (*databaseentity.User, error)
 	// update or modify user password with new password
 	RenewPassword(ctx context.Context, user *databaseentity.User, payload *payloadentity.RenewPasswordPayload) error
-	// submit reset password request
-	RequestResetPassword(ctx context.Context, payload *payloadentity.RequestResetPayload) (*databaseentity.User, *databaseentity.PasswordResetToken, error)
+	// submit reset password request, reset password mail is only queued for registered email
+	RequestResetPassword(ctx context.Context, payload *payloadentity.RequestResetPayload) error
 	// validate reset password from email
 	ValidateResetToken(ctx context.Context, payload *payloadentity.ValidateResetTokenPayload) error
 	// submit reset password
 	SubmitResetPassword(ctx context.Context, payload *payloadentity.ResetPasswordPayload) (*databaseentity.User, error)
+	// validate invitation from email, return invited user
+	ValidateInvitation(ctx context.Context, payload *payloadentity.ValidateInvitationPayload) (*databaseentity.User, error)
+	// accept invitation and set user password
+	AcceptInvitation(ctx context.Context, payload *payloadentity.AcceptInvitationPayload) (*databaseentity.User, error)
 }
@@ -12,4 +12,14 @@ type FormatterUsecase interface {
 	FormatUsers(ctx context.Context, users []*databaseentity.User) ([]*responseentity.UserResponse, error)
 	FormatUnit(ctx context.Context, unit *databaseentity.Unit, mapUsers map[int]string) (*responseentity.UnitResponse, error)
 	FormatUnits(ctx context.Context, units []*databaseentity.Unit) ([]*responseentity.UnitResponse, error)
+	FormatUnitDetail(ctx context.Context, unit *databaseentity.Unit, usage *databaseentity.UnitUsage) (*responseentity.UnitDetailResponse, error)
+	FormatUnitConversion(ctx context.Context, conversion *databaseentity.UnitConversion) (*responseentity.ConvertUnitResponse, error)
+	FormatIngredient(ctx context.Context, ingredient *databaseentity.Ingredient, mapUsers map[int]string) (*responseentity.IngredientResponse, error)
+	FormatRecipeCosting(ctx context.Context, costing *databaseentity.RecipeCosting) (*responseentity.RecipeCostingResponse, error)
+	FormatRecipeScale(ctx context.Context, scale *databaseentity.RecipeScale) (*responseentity.RecipeScaleResponse, error)
+	FormatRecipeProduction(ctx context.Context, production *databaseentity.RecipeProduction) (*responseentity.RecipeProductionResponse, error)
+	FormatRecipeIngredient(ctx context.Context, line *databaseentity.RecipeIngredient, mapUsers map[int]string) (*responseentity.RecipeIngredientResponse, error)
+	FormatMail(ctx context.Context, mail *databaseentity.MailOutbox) (*responseentity.MailResponse, error)
+	FormatMails(ctx context.Context, mails []*databaseentity.MailOutbox) ([]*responseentity.MailResponse, error)
+	FormatAuditLogs(ctx context.Context, logs []*databaseentity.AuditLog) ([]*responseentity.AuditLogResponse, error)
 }
@@ -0,0 +1,18 @@
+package contract
+
+import (
+	"context"
+	responseentity "rap-c/app/entity/response-entity"
+)
+
+type HealthUsecase interface {
+	// process is alive, no dependency is checked
+	Liveness(ctx context.Context) *responseentity.HealthResponse
+	// check database, migrations, templates & mail transport, status is fail when any required check fails
+	Readiness(ctx context.Context) *responseentity.HealthResponse
+}
+
+type TemplateChecker interface {
+	// check templates are loaded & parseable
+	Check() error
+}
@@ -0,0 +1,12 @@
+package contract
+
+import (
+	"context"
+	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+)
+
+type IngredientUsecase interface {
+	// set density & piece weight bridging volume & count units into mass
+	SetWeight(ctx context.Context, payload *payloadentity.SetIngredientWeightPayload, author *databaseentity.User) (*databaseentity.Ingredient, error)
+}
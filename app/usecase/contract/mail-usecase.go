@@ -1,12 +1,40 @@
 package contract
 
 import (
+	"context"
 	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
 )
 
+// render emails into outbox mails, the mails are sent by mail worker
 type MailUsecase interface {
-	Welcome(user *databaseentity.User, password string) error
-	ResetPassword(user *databaseentity.User, token *databaseentity.PasswordResetToken) error
-	UpdateUser(user *databaseentity.User) error
-	UpdateActiveStatusUser(user *databaseentity.User) error
+	// welcome email with invitation link
+	Welcome(user *databaseentity.User, invitation *databaseentity.UserInvitation) (*databaseentity.MailOutbox, error)
+	// reset password link email
+	ResetPassword(user *databaseentity.User, token *databaseentity.PasswordResetToken) (*databaseentity.MailOutbox, error)
+	// user data changed email
+	UpdateUser(user *databaseentity.User) (*databaseentity.MailOutbox, error)
+	// user activated or deactivated email
+	UpdateActiveStatusUser(user *databaseentity.User) (*databaseentity.MailOutbox, error)
+	// verification link to unverified or pending email
+	VerifyEmail(user *databaseentity.User, token string) (*databaseentity.MailOutbox, error)
+	// notify current email that email change has been requested
+	EmailChangeRequested(user *databaseentity.User) (*databaseentity.MailOutbox, error)
+	// render template with sample data, broken custom template is reported instead of falling back to built-in template
+	PreviewTemplate(ctx context.Context, payload *payloadentity.PreviewMailTemplatePayload) (*databaseentity.MailOutbox, error)
+	// queue mails that are not part of other business change, e.g. resent verification link.
+	// mails announcing a change must be passed to the repository saving that change instead
+	Queue(ctx context.Context, mails ...*databaseentity.MailOutbox) error
+	// send pending mails due to be sent, failed mails are retried with exponential backoff, return total sent mails
+	SendPendingMails(ctx context.Context) (int, error)
+	// get dead letter mail list
+	GetDeadLetterList(ctx context.Context, req *payloadentity.GetDeadLetterListRequest) ([]*databaseentity.MailOutbox, error)
+	// get total dead letter mail list
+	GetTotalDeadLetterList(ctx context.Context, req *payloadentity.GetDeadLetterListRequest) (int64, error)
+	// put dead letter mail back to queue
+	ResendDeadLetter(ctx context.Context, payload *payloadentity.ResendMailPayload) (*databaseentity.MailOutbox, error)
+	// get mails captured by file mail transport, for development
+	GetCapturedMails(ctx context.Context) ([]*databaseentity.CapturedMail, error)
+	// get captured mail by file name
+	GetCapturedMail(ctx context.Context, name string) (*databaseentity.CapturedMail, error)
 }
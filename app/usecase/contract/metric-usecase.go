@@ -0,0 +1,8 @@
+package contract
+
+import "github.com/prometheus/client_golang/prometheus"
+
+type MetricUsecase interface {
+	// business gauges as prometheus collector, counted from database on every scrape
+	prometheus.Collector
+}
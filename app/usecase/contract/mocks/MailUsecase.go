@@ -0,0 +1,251 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: mail-usecase.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockMailUsecase is a mock of MailUsecase interface.
+type MockMailUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockMailUsecaseMockRecorder
+}
+
+// MockMailUsecaseMockRecorder is the mock recorder for MockMailUsecase.
+type MockMailUsecaseMockRecorder struct {
+	mock *MockMailUsecase
+}
+
+// NewMockMailUsecase creates a new mock instance.
+func NewMockMailUsecase(ctrl *gomock.Controller) *MockMailUsecase {
+	mock := &MockMailUsecase{ctrl: ctrl}
+	mock.recorder = &MockMailUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMailUsecase) EXPECT() *MockMailUsecaseMockRecorder {
+	return m.recorder
+}
+
+// EmailChangeRequested mocks base method.
+func (m *MockMailUsecase) EmailChangeRequested(user *databaseentity.User) (*databaseentity.MailOutbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EmailChangeRequested", user)
+	ret0, _ := ret[0].(*databaseentity.MailOutbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EmailChangeRequested indicates an expected call of EmailChangeRequested.
+func (mr *MockMailUsecaseMockRecorder) EmailChangeRequested(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmailChangeRequested", reflect.TypeOf((*MockMailUsecase)(nil).EmailChangeRequested), user)
+}
+
+// GetCapturedMail mocks base method.
+func (m *MockMailUsecase) GetCapturedMail(ctx context.Context, name string) (*databaseentity.CapturedMail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCapturedMail", ctx, name)
+	ret0, _ := ret[0].(*databaseentity.CapturedMail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCapturedMail indicates an expected call of GetCapturedMail.
+func (mr *MockMailUsecaseMockRecorder) GetCapturedMail(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCapturedMail", reflect.TypeOf((*MockMailUsecase)(nil).GetCapturedMail), ctx, name)
+}
+
+// GetCapturedMails mocks base method.
+func (m *MockMailUsecase) GetCapturedMails(ctx context.Context) ([]*databaseentity.CapturedMail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCapturedMails", ctx)
+	ret0, _ := ret[0].([]*databaseentity.CapturedMail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCapturedMails indicates an expected call of GetCapturedMails.
+func (mr *MockMailUsecaseMockRecorder) GetCapturedMails(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCapturedMails", reflect.TypeOf((*MockMailUsecase)(nil).GetCapturedMails), ctx)
+}
+
+// GetDeadLetterList mocks base method.
+func (m *MockMailUsecase) GetDeadLetterList(ctx context.Context, req *payloadentity.GetDeadLetterListRequest) ([]*databaseentity.MailOutbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeadLetterList", ctx, req)
+	ret0, _ := ret[0].([]*databaseentity.MailOutbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeadLetterList indicates an expected call of GetDeadLetterList.
+func (mr *MockMailUsecaseMockRecorder) GetDeadLetterList(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeadLetterList", reflect.TypeOf((*MockMailUsecase)(nil).GetDeadLetterList), ctx, req)
+}
+
+// GetTotalDeadLetterList mocks base method.
+func (m *MockMailUsecase) GetTotalDeadLetterList(ctx context.Context, req *payloadentity.GetDeadLetterListRequest) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTotalDeadLetterList", ctx, req)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTotalDeadLetterList indicates an expected call of GetTotalDeadLetterList.
+func (mr *MockMailUsecaseMockRecorder) GetTotalDeadLetterList(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotalDeadLetterList", reflect.TypeOf((*MockMailUsecase)(nil).GetTotalDeadLetterList), ctx, req)
+}
+
+// PreviewTemplate mocks base method.
+func (m *MockMailUsecase) PreviewTemplate(ctx context.Context, payload *payloadentity.PreviewMailTemplatePayload) (*databaseentity.MailOutbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviewTemplate", ctx, payload)
+	ret0, _ := ret[0].(*databaseentity.MailOutbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviewTemplate indicates an expected call of PreviewTemplate.
+func (mr *MockMailUsecaseMockRecorder) PreviewTemplate(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewTemplate", reflect.TypeOf((*MockMailUsecase)(nil).PreviewTemplate), ctx, payload)
+}
+
+// Queue mocks base method.
+func (m *MockMailUsecase) Queue(ctx context.Context, mails ...*databaseentity.MailOutbox) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range mails {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Queue", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Queue indicates an expected call of Queue.
+func (mr *MockMailUsecaseMockRecorder) Queue(ctx interface{}, mails ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, mails...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Queue", reflect.TypeOf((*MockMailUsecase)(nil).Queue), varargs...)
+}
+
+// ResendDeadLetter mocks base method.
+func (m *MockMailUsecase) ResendDeadLetter(ctx context.Context, payload *payloadentity.ResendMailPayload) (*databaseentity.MailOutbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResendDeadLetter", ctx, payload)
+	ret0, _ := ret[0].(*databaseentity.MailOutbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResendDeadLetter indicates an expected call of ResendDeadLetter.
+func (mr *MockMailUsecaseMockRecorder) ResendDeadLetter(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendDeadLetter", reflect.TypeOf((*MockMailUsecase)(nil).ResendDeadLetter), ctx, payload)
+}
+
+// ResetPassword mocks base method.
+func (m *MockMailUsecase) ResetPassword(user *databaseentity.User, token *databaseentity.PasswordResetToken) (*databaseentity.MailOutbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", user, token)
+	ret0, _ := ret[0].(*databaseentity.MailOutbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockMailUsecaseMockRecorder) ResetPassword(user, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockMailUsecase)(nil).ResetPassword), user, token)
+}
+
+// SendPendingMails mocks base method.
+func (m *MockMailUsecase) SendPendingMails(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendPendingMails", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendPendingMails indicates an expected call of SendPendingMails.
+func (mr *MockMailUsecaseMockRecorder) SendPendingMails(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendPendingMails", reflect.TypeOf((*MockMailUsecase)(nil).SendPendingMails), ctx)
+}
+
+// UpdateActiveStatusUser mocks base method.
+func (m *MockMailUsecase) UpdateActiveStatusUser(user *databaseentity.User) (*databaseentity.MailOutbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateActiveStatusUser", user)
+	ret0, _ := ret[0].(*databaseentity.MailOutbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateActiveStatusUser indicates an expected call of UpdateActiveStatusUser.
+func (mr *MockMailUsecaseMockRecorder) UpdateActiveStatusUser(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateActiveStatusUser", reflect.TypeOf((*MockMailUsecase)(nil).UpdateActiveStatusUser), user)
+}
+
+// UpdateUser mocks base method.
+func (m *MockMailUsecase) UpdateUser(user *databaseentity.User) (*databaseentity.MailOutbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUser", user)
+	ret0, _ := ret[0].(*databaseentity.MailOutbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUser indicates an expected call of UpdateUser.
+func (mr *MockMailUsecaseMockRecorder) UpdateUser(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockMailUsecase)(nil).UpdateUser), user)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockMailUsecase) VerifyEmail(user *databaseentity.User, token string) (*databaseentity.MailOutbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", user, token)
+	ret0, _ := ret[0].(*databaseentity.MailOutbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockMailUsecaseMockRecorder) VerifyEmail(user, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockMailUsecase)(nil).VerifyEmail), user, token)
+}
+
+// Welcome mocks base method.
+func (m *MockMailUsecase) Welcome(user *databaseentity.User, invitation *databaseentity.UserInvitation) (*databaseentity.MailOutbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Welcome", user, invitation)
+	ret0, _ := ret[0].(*databaseentity.MailOutbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Welcome indicates an expected call of Welcome.
+func (mr *MockMailUsecaseMockRecorder) Welcome(user, invitation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Welcome", reflect.TypeOf((*MockMailUsecase)(nil).Welcome), user, invitation)
+}
@@ -0,0 +1,155 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: recipe-usecase.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRecipeUsecase is a mock of RecipeUsecase interface.
+type MockRecipeUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecipeUsecaseMockRecorder
+}
+
+// MockRecipeUsecaseMockRecorder is the mock recorder for MockRecipeUsecase.
+type MockRecipeUsecaseMockRecorder struct {
+	mock *MockRecipeUsecase
+}
+
+// NewMockRecipeUsecase creates a new mock instance.
+func NewMockRecipeUsecase(ctrl *gomock.Controller) *MockRecipeUsecase {
+	mock := &MockRecipeUsecase{ctrl: ctrl}
+	mock.recorder = &MockRecipeUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecipeUsecase) EXPECT() *MockRecipeUsecaseMockRecorder {
+	return m.recorder
+}
+
+// CreateLine mocks base method.
+func (m *MockRecipeUsecase) CreateLine(ctx context.Context, payload *payloadentity.CreateRecipeIngredientPayload, author *databaseentity.User) (*databaseentity.RecipeIngredient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLine", ctx, payload, author)
+	ret0, _ := ret[0].(*databaseentity.RecipeIngredient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLine indicates an expected call of CreateLine.
+func (mr *MockRecipeUsecaseMockRecorder) CreateLine(ctx, payload, author interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLine", reflect.TypeOf((*MockRecipeUsecase)(nil).CreateLine), ctx, payload, author)
+}
+
+// GetCosting mocks base method.
+func (m *MockRecipeUsecase) GetCosting(ctx context.Context, serial string) (*databaseentity.RecipeCosting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCosting", ctx, serial)
+	ret0, _ := ret[0].(*databaseentity.RecipeCosting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCosting indicates an expected call of GetCosting.
+func (mr *MockRecipeUsecaseMockRecorder) GetCosting(ctx, serial interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCosting", reflect.TypeOf((*MockRecipeUsecase)(nil).GetCosting), ctx, serial)
+}
+
+// Produce mocks base method.
+func (m *MockRecipeUsecase) Produce(ctx context.Context, payload *payloadentity.ProduceRecipePayload, author *databaseentity.User) (*databaseentity.RecipeProduction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Produce", ctx, payload, author)
+	ret0, _ := ret[0].(*databaseentity.RecipeProduction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Produce indicates an expected call of Produce.
+func (mr *MockRecipeUsecaseMockRecorder) Produce(ctx, payload, author interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Produce", reflect.TypeOf((*MockRecipeUsecase)(nil).Produce), ctx, payload, author)
+}
+
+// RefreshCost mocks base method.
+func (m *MockRecipeUsecase) RefreshCost(ctx context.Context, payload *payloadentity.RefreshRecipeCostPayload, author *databaseentity.User) ([]*databaseentity.RecipeCosting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshCost", ctx, payload, author)
+	ret0, _ := ret[0].([]*databaseentity.RecipeCosting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshCost indicates an expected call of RefreshCost.
+func (mr *MockRecipeUsecaseMockRecorder) RefreshCost(ctx, payload, author interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshCost", reflect.TypeOf((*MockRecipeUsecase)(nil).RefreshCost), ctx, payload, author)
+}
+
+// RefreshCostByIngredient mocks base method.
+func (m *MockRecipeUsecase) RefreshCostByIngredient(ctx context.Context, ingredient *databaseentity.Ingredient, author *databaseentity.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshCostByIngredient", ctx, ingredient, author)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshCostByIngredient indicates an expected call of RefreshCostByIngredient.
+func (mr *MockRecipeUsecaseMockRecorder) RefreshCostByIngredient(ctx, ingredient, author interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshCostByIngredient", reflect.TypeOf((*MockRecipeUsecase)(nil).RefreshCostByIngredient), ctx, ingredient, author)
+}
+
+// RefreshCostByUnit mocks base method.
+func (m *MockRecipeUsecase) RefreshCostByUnit(ctx context.Context, unit *databaseentity.Unit, author *databaseentity.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshCostByUnit", ctx, unit, author)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshCostByUnit indicates an expected call of RefreshCostByUnit.
+func (mr *MockRecipeUsecaseMockRecorder) RefreshCostByUnit(ctx, unit, author interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshCostByUnit", reflect.TypeOf((*MockRecipeUsecase)(nil).RefreshCostByUnit), ctx, unit, author)
+}
+
+// Scale mocks base method.
+func (m *MockRecipeUsecase) Scale(ctx context.Context, req *payloadentity.ScaleRecipeRequest) (*databaseentity.RecipeScale, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Scale", ctx, req)
+	ret0, _ := ret[0].(*databaseentity.RecipeScale)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Scale indicates an expected call of Scale.
+func (mr *MockRecipeUsecaseMockRecorder) Scale(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scale", reflect.TypeOf((*MockRecipeUsecase)(nil).Scale), ctx, req)
+}
+
+// UpdateLine mocks base method.
+func (m *MockRecipeUsecase) UpdateLine(ctx context.Context, payload *payloadentity.UpdateRecipeIngredientPayload, author *databaseentity.User) (*databaseentity.RecipeIngredient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLine", ctx, payload, author)
+	ret0, _ := ret[0].(*databaseentity.RecipeIngredient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLine indicates an expected call of UpdateLine.
+func (mr *MockRecipeUsecaseMockRecorder) UpdateLine(ctx, payload, author interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLine", reflect.TypeOf((*MockRecipeUsecase)(nil).UpdateLine), ctx, payload, author)
+}
@@ -0,0 +1,26 @@
+package contract
+
+import (
+	"context"
+	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+)
+
+type RecipeUsecase interface {
+	// cost of every recipe line from current ingredient price
+	GetCosting(ctx context.Context, serial string) (*databaseentity.RecipeCosting, error)
+	// ingredient quantities, cost & stock warnings for requested portions
+	Scale(ctx context.Context, req *payloadentity.ScaleRecipeRequest) (*databaseentity.RecipeScale, error)
+	// take stock of ingredients & sub recipes, then add produced quantity to recipe stock
+	Produce(ctx context.Context, payload *payloadentity.ProduceRecipePayload, author *databaseentity.User) (*databaseentity.RecipeProduction, error)
+	// add ingredient or sub recipe line to recipe, sub recipe using recipe directly or through other sub recipes is rejected
+	CreateLine(ctx context.Context, payload *payloadentity.CreateRecipeIngredientPayload, author *databaseentity.User) (*databaseentity.RecipeIngredient, error)
+	// change ingredient or sub recipe, unit & quantity of recipe line, sub recipe using recipe directly or through other sub recipes is rejected
+	UpdateLine(ctx context.Context, payload *payloadentity.UpdateRecipeIngredientPayload, author *databaseentity.User) (*databaseentity.RecipeIngredient, error)
+	// save raw material costs of recipe & every recipe using it as sub recipe in one transaction
+	RefreshCost(ctx context.Context, payload *payloadentity.RefreshRecipeCostPayload, author *databaseentity.User) ([]*databaseentity.RecipeCosting, error)
+	// save raw material costs of recipes using ingredient & their parent recipes, recipe which cannot be costed is skipped
+	RefreshCostByIngredient(ctx context.Context, ingredient *databaseentity.Ingredient, author *databaseentity.User) error
+	// save raw material costs of recipes depending on unit & their parent recipes, recipe which cannot be costed is skipped
+	RefreshCostByUnit(ctx context.Context, unit *databaseentity.Unit, author *databaseentity.User) error
+}
@@ -15,4 +15,16 @@ type UnitUsecase interface {
 	GetTotalUnitList(ctx context.Context, req *payloadentity.GetUnitListRequest) (int64, error)
 	// delete unit
 	Delete(ctx context.Context, payload *payloadentity.DeleteUnitPayload) error
+	// get unit & rows using it
+	GetUnitDetail(ctx context.Context, name string) (*databaseentity.Unit, *databaseentity.UnitUsage, error)
+	// rename unit
+	Rename(ctx context.Context, payload *payloadentity.RenameUnitPayload) (*databaseentity.Unit, error)
+	// move every reference of unit to target unit then delete unit, return target unit
+	Merge(ctx context.Context, payload *payloadentity.MergeUnitPayload, author *databaseentity.User) (*databaseentity.Unit, error)
+	// convert quantity between units, ingredient conversions, density & piece weight are used when units have different dimension.
+	// ingredient may be nil, its unit must be preloaded like unit of every conversion
+	ConvertQuantity(ctx context.Context, quantity float64, from *databaseentity.Unit, to *databaseentity.Unit,
+		ingredient *databaseentity.Ingredient, conversions []*databaseentity.IngredientConvertionUnit) (*databaseentity.UnitConversion, error)
+	// convert quantity by unit names & optional ingredient serial
+	Convert(ctx context.Context, req *payloadentity.ConvertUnitRequest) (*databaseentity.UnitConversion, error)
 }
@@ -7,16 +7,31 @@ import (
 )
 
 type UserUsecase interface {
-	// create user
-	Create(ctx context.Context, payload *payloadentity.CreateUserPayload, author *databaseentity.User) (*databaseentity.User, string, error)
+	// create user and issue invitation, password is set by user when accepting invitation
+	Create(ctx context.Context, payload *payloadentity.CreateUserPayload, author *databaseentity.User) (*databaseentity.User, error)
+	// check first run setup, required while no non guest user exists
+	IsSetupRequired(ctx context.Context) (bool, error)
+	// create first non guest user from setup wizard, email is verified & password is chosen by owner
+	CreateOwner(ctx context.Context, payload *payloadentity.CreateOwnerPayload) (*databaseentity.User, error)
 	// get user list
 	GetUserList(ctx context.Context, req *payloadentity.GetUserListRequest) ([]*databaseentity.User, error)
 	// get total user list
 	GetTotalUserList(ctx context.Context, req *payloadentity.GetUserListRequest) (int64, error)
 	// get user by username
 	GetUserByUsername(ctx context.Context, req *payloadentity.GetUserDetailRequest) (*databaseentity.User, error)
-	// update current user data
+	// update current user data, changed email is stored as pending email until verified
+	// notification & verification mails are queued with the change
 	Update(ctx context.Context, payload *payloadentity.UpdateUserPayload, author *databaseentity.User) error
 	// update other user active status
 	UpdateActiveStatus(ctx context.Context, payload *payloadentity.ActiveStatusPayload, author *databaseentity.User) (*databaseentity.User, error)
+	// generate signed email verification token for new or pending email
+	GenerateEmailVerificationToken(ctx context.Context, user *databaseentity.User) (string, error)
+	// queue verification link mail for unverified or pending email
+	ResendEmailVerification(ctx context.Context, user *databaseentity.User) error
+	// verify email from signed verification token, pending email will replace current email
+	VerifyEmail(ctx context.Context, payload *payloadentity.VerifyEmailPayload) (*databaseentity.User, error)
+	// issue new invitation for user that has not accepted invitation, previous invitation is revoked
+	ResendInvitation(ctx context.Context, payload *payloadentity.UserInvitationPayload, author *databaseentity.User) (*databaseentity.User, error)
+	// revoke pending invitation of user that has not accepted invitation
+	RevokeInvitation(ctx context.Context, payload *payloadentity.UserInvitationPayload, author *databaseentity.User) (*databaseentity.User, error)
 }
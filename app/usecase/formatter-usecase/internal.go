@@ -1,6 +1,7 @@
 package formatterusecase
 
 import (
+	"encoding/json"
 	databaseentity "rap-c/app/entity/database-entity"
 	responseentity "rap-c/app/entity/response-entity"
 )
@@ -25,7 +26,10 @@ func (uc *usecase) formatUser(user *databaseentity.User, mapUsers map[int]string
 		Username:           user.Username,
 		FullName:           user.FullName,
 		Email:              user.Email,
+		PendingEmail:       user.PendingEmail,
+		EmailVerified:      user.EmailVerifiedAt != nil,
 		PasswordMustChange: user.PasswordMustChange,
+		InvitationPending:  user.IsInvitationPending(),
 		Disabled:           user.Disabled,
 		IsGuest:            user.IsGuest,
 		CreatedAt:          user.CreatedAt,
@@ -37,13 +41,331 @@ func (uc *usecase) formatUser(user *databaseentity.User, mapUsers map[int]string
 
 func (uc *usecase) formatUnit(unit *databaseentity.Unit, mapUsers map[int]string) *responseentity.UnitResponse {
 	createdBy, ok := mapUsers[unit.CreatedBy]
-	if !ok {
+	if unit.CreatedBy == 0 {
+		// standard unit created by migration
+		createdBy = defaultUserUsername
+	} else if !ok {
 		createdBy = errorUserUsername
 	}
 
 	return &responseentity.UnitResponse{
 		Name:      unit.Name,
+		Dimension: unit.Dimension,
+		Factor:    unit.Factor,
 		CreatedAt: unit.CreatedAt,
 		CreatedBy: createdBy,
 	}
 }
+
+func (uc *usecase) formatUnitConversion(conversion *databaseentity.UnitConversion) *responseentity.ConvertUnitResponse {
+	return &responseentity.ConvertUnitResponse{
+		Quantity: conversion.Quantity,
+		From:     conversion.From.Name,
+		Result:   conversion.Result,
+		To:       conversion.To.Name,
+		Bridge:   conversion.Bridge,
+	}
+}
+
+func (uc *usecase) formatIngredient(ingredient *databaseentity.Ingredient, mapUsers map[int]string) *responseentity.IngredientResponse {
+	updatedBy, ok := mapUsers[ingredient.UpdatedBy]
+	if !ok {
+		updatedBy = defaultUserUsername
+	}
+	result := &responseentity.IngredientResponse{
+		Serial:       ingredient.Serial,
+		Name:         ingredient.Name,
+		PricePerUnit: ingredient.PricePerUnit,
+		Stock:        ingredient.Stock,
+		Density:      ingredient.Density,
+		PieceWeight:  ingredient.PieceWeight,
+		UpdatedAt:    ingredient.UpdatedAt,
+		UpdatedBy:    updatedBy,
+	}
+	if ingredient.Unit != nil {
+		result.Unit = ingredient.Unit.Name
+	}
+	return result
+}
+
+func (uc *usecase) formatRecipeCosting(costing *databaseentity.RecipeCosting) *responseentity.RecipeCostingResponse {
+	recipe := costing.Recipe
+	result := &responseentity.RecipeCostingResponse{
+		Serial:           recipe.Serial,
+		Name:             recipe.Name,
+		Portions:         recipe.Quantity,
+		Lines:            []*responseentity.RecipeCostingLineResponse{},
+		RawMaterialCosts: costing.RawMaterialCosts,
+		LaborCosts:       float64(recipe.LaborCosts),
+		OverheadCosts:    float64(recipe.OverheadCosts),
+		HPP:              costing.HPP(),
+		HPPPerPortion:    costing.HPPPerPortion(),
+	}
+	for _, itm := range costing.Lines {
+		line := itm.RecipeIngredient
+		resp := &responseentity.RecipeCostingLineResponse{
+			Serial:        line.Serial,
+			Quantity:      float64(line.Quantity),
+			CostType:      itm.CostType(),
+			Cost:          itm.Cost,
+			SkipCalculate: itm.SkipCalculate,
+		}
+		resp.RawMaterialCost, resp.LaborCost, resp.OverheadCost = itm.CostShares()
+		if line.Ingredient != nil {
+			resp.IngredientSerial = line.Ingredient.Serial
+			resp.IngredientName = line.Ingredient.Name
+			resp.PricePerUnit = float64(line.Ingredient.PricePerUnit)
+		}
+		if line.SubRecipe != nil {
+			resp.SubRecipeSerial = line.SubRecipe.Serial
+			resp.SubRecipeName = line.SubRecipe.Name
+		}
+		if itm.SubRecipe != nil {
+			resp.PricePerUnit = itm.SubRecipe.HPPPerPortion()
+		}
+		if line.Unit != nil {
+			resp.Unit = line.Unit.Name
+		}
+		if itm.Conversion != nil {
+			resp.IngredientQuantity = itm.Conversion.Result
+			resp.IngredientUnit = itm.Conversion.To.Name
+			resp.Bridge = itm.Conversion.Bridge
+		}
+		result.Lines = append(result.Lines, resp)
+	}
+	return result
+}
+
+func (uc *usecase) formatRecipeScale(scale *databaseentity.RecipeScale) *responseentity.RecipeScaleResponse {
+	recipe := scale.Costing.Recipe
+	result := &responseentity.RecipeScaleResponse{
+		Serial:           recipe.Serial,
+		Name:             recipe.Name,
+		RecipePortions:   recipe.Quantity,
+		Portions:         scale.Portions,
+		Factor:           scale.Factor,
+		Lines:            []*responseentity.RecipeScaleLineResponse{},
+		RawMaterialCosts: scale.RawMaterialCosts(),
+		LaborCosts:       scale.LaborCosts(),
+		OverheadCosts:    scale.OverheadCosts(),
+		HPP:              scale.HPP(),
+		HPPPerPortion:    scale.Costing.HPPPerPortion(),
+		Warnings:         []*responseentity.RecipeStockWarningResponse{},
+	}
+	for _, itm := range scale.Lines {
+		line := itm.CostingLine.RecipeIngredient
+		resp := &responseentity.RecipeScaleLineResponse{
+			Serial:             line.Serial,
+			Quantity:           itm.Quantity,
+			IngredientQuantity: itm.IngredientQuantity,
+			Cost:               itm.Cost,
+			SkipCalculate:      itm.CostingLine.SkipCalculate,
+		}
+		if line.Ingredient != nil {
+			resp.IngredientSerial = line.Ingredient.Serial
+			resp.IngredientName = line.Ingredient.Name
+		}
+		if line.SubRecipe != nil {
+			resp.SubRecipeSerial = line.SubRecipe.Serial
+			resp.SubRecipeName = line.SubRecipe.Name
+		}
+		if line.Unit != nil {
+			resp.Unit = line.Unit.Name
+		}
+		if itm.CostingLine.Conversion != nil {
+			resp.IngredientUnit = itm.CostingLine.Conversion.To.Name
+		}
+		if itm.Purchase != nil {
+			resp.PurchaseQuantity = itm.Purchase.Result
+			resp.PurchaseUnit = itm.Purchase.To.Name
+		}
+		result.Lines = append(result.Lines, resp)
+	}
+	for _, itm := range scale.Warnings {
+		resp := &responseentity.RecipeStockWarningResponse{
+			Required: itm.Required,
+			Stock:    itm.Stock(),
+			Shortage: itm.Shortage(),
+		}
+		if itm.SubRecipe != nil {
+			resp.SubRecipeSerial = itm.SubRecipe.Serial
+			resp.SubRecipeName = itm.SubRecipe.Name
+			if itm.SubRecipe.Unit != nil {
+				resp.Unit = itm.SubRecipe.Unit.Name
+			}
+		} else {
+			resp.IngredientSerial = itm.Ingredient.Serial
+			resp.IngredientName = itm.Ingredient.Name
+			if itm.Ingredient.Unit != nil {
+				resp.Unit = itm.Ingredient.Unit.Name
+			}
+		}
+		result.Warnings = append(result.Warnings, resp)
+	}
+	return result
+}
+
+func (uc *usecase) formatRecipeIngredient(line *databaseentity.RecipeIngredient, mapUsers map[int]string) *responseentity.RecipeIngredientResponse {
+	updatedBy, ok := mapUsers[line.UpdatedBy]
+	if !ok {
+		updatedBy = defaultUserUsername
+	}
+	result := &responseentity.RecipeIngredientResponse{
+		Serial:       line.Serial,
+		RecipeSerial: line.Recipe.Serial,
+		Quantity:     float64(line.Quantity),
+		UpdatedAt:    line.UpdatedAt,
+		UpdatedBy:    updatedBy,
+	}
+	if line.Ingredient != nil {
+		result.IngredientSerial = line.Ingredient.Serial
+		result.IngredientName = line.Ingredient.Name
+	}
+	if line.SubRecipe != nil {
+		result.SubRecipeSerial = line.SubRecipe.Serial
+		result.SubRecipeName = line.SubRecipe.Name
+	}
+	if line.Unit != nil {
+		result.Unit = line.Unit.Name
+	}
+	return result
+}
+
+func (uc *usecase) formatRecipeProduction(production *databaseentity.RecipeProduction) *responseentity.RecipeProductionResponse {
+	recipe := production.Recipe
+	result := &responseentity.RecipeProductionResponse{
+		Serial:      recipe.Serial,
+		Name:        recipe.Name,
+		Quantity:    production.Quantity,
+		Stock:       float64(recipe.Stock),
+		Ingredients: []*responseentity.RecipeConsumptionResponse{},
+		SubRecipes:  []*responseentity.RecipeConsumptionResponse{},
+	}
+	if recipe.Unit != nil {
+		result.Unit = recipe.Unit.Name
+	}
+	for _, itm := range production.Ingredients {
+		resp := &responseentity.RecipeConsumptionResponse{
+			Serial:   itm.Ingredient.Serial,
+			Name:     itm.Ingredient.Name,
+			Quantity: itm.Quantity,
+			Stock:    float64(itm.Ingredient.Stock),
+		}
+		if itm.Ingredient.Unit != nil {
+			resp.Unit = itm.Ingredient.Unit.Name
+		}
+		result.Ingredients = append(result.Ingredients, resp)
+	}
+	for _, itm := range production.SubRecipes {
+		resp := &responseentity.RecipeConsumptionResponse{
+			Serial:   itm.Recipe.Serial,
+			Name:     itm.Recipe.Name,
+			Quantity: itm.Quantity,
+			Stock:    float64(itm.Recipe.Stock),
+		}
+		if itm.Recipe.Unit != nil {
+			resp.Unit = itm.Recipe.Unit.Name
+		}
+		result.SubRecipes = append(result.SubRecipes, resp)
+	}
+	return result
+}
+
+func (uc *usecase) formatUnitDetail(unit *responseentity.UnitResponse, usage *databaseentity.UnitUsage) *responseentity.UnitDetailResponse {
+	result := &responseentity.UnitDetailResponse{
+		UnitResponse:      *unit,
+		Ingredients:       []*responseentity.UnitIngredientResponse{},
+		Conversions:       []*responseentity.UnitConversionResponse{},
+		RecipeIngredients: []*responseentity.UnitRecipeIngredientResponse{},
+		Recipes:           []*responseentity.UnitRecipeResponse{},
+	}
+	for _, itm := range usage.Ingredients {
+		result.Ingredients = append(result.Ingredients, &responseentity.UnitIngredientResponse{
+			Serial: itm.Serial,
+			Name:   itm.Name,
+		})
+	}
+	for _, itm := range usage.Conversions {
+		conversion := &responseentity.UnitConversionResponse{
+			Serial: itm.Serial,
+			Value:  itm.Value,
+		}
+		if itm.Ingredient != nil {
+			conversion.IngredientName = itm.Ingredient.Name
+		}
+		result.Conversions = append(result.Conversions, conversion)
+	}
+	for _, itm := range usage.RecipeIngredients {
+		line := &responseentity.UnitRecipeIngredientResponse{
+			Serial:   itm.Serial,
+			Quantity: itm.Quantity,
+		}
+		if itm.Recipe != nil {
+			line.RecipeSerial = itm.Recipe.Serial
+			line.RecipeName = itm.Recipe.Name
+		}
+		if itm.Ingredient != nil {
+			line.IngredientName = itm.Ingredient.Name
+		}
+		if itm.SubRecipe != nil {
+			line.SubRecipeName = itm.SubRecipe.Name
+		}
+		result.RecipeIngredients = append(result.RecipeIngredients, line)
+	}
+	for _, itm := range usage.Recipes {
+		result.Recipes = append(result.Recipes, &responseentity.UnitRecipeResponse{
+			Serial: itm.Serial,
+			Name:   itm.Name,
+		})
+	}
+	return result
+}
+
+func (uc *usecase) formatMail(mail *databaseentity.MailOutbox) *responseentity.MailResponse {
+	return &responseentity.MailResponse{
+		ID:            mail.ID,
+		Recipient:     mail.Recipient,
+		Subject:       mail.Subject,
+		Status:        mail.Status,
+		Attempts:      mail.Attempts,
+		LastError:     mail.LastError,
+		NextAttemptAt: mail.NextAttemptAt,
+		SentAt:        mail.SentAt,
+		CreatedAt:     mail.CreatedAt,
+		UpdatedAt:     mail.UpdatedAt,
+	}
+}
+
+func (uc *usecase) formatAuditLog(log *databaseentity.AuditLog, mapUsers map[int]string) *responseentity.AuditLogResponse {
+	// actor 0 is change without login user, e.g. seeder
+	actor := defaultUserUsername
+	if log.ActorID > 0 {
+		var ok bool
+		actor, ok = mapUsers[log.ActorID]
+		if !ok {
+			actor = errorUserUsername
+		}
+	}
+
+	return &responseentity.AuditLogResponse{
+		ID:         log.ID,
+		Actor:      actor,
+		Action:     log.Action,
+		EntityType: log.EntityType,
+		EntityID:   log.EntityID,
+		Serial:     log.Serial,
+		Before:     auditData(log.BeforeData),
+		After:      auditData(log.AfterData),
+		IPAddress:  log.IPAddress,
+		RequestID:  log.RequestID,
+		CreatedAt:  log.CreatedAt,
+	}
+}
+
+// stored json diff, null when empty
+func auditData(data string) json.RawMessage {
+	if data == "" {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(data)
+}
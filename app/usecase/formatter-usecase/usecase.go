@@ -87,3 +87,128 @@ func (uc *usecase) FormatUnits(ctx context.Context, units []*databaseentity.Unit
 	}
 	return result, nil
 }
+
+func (uc *usecase) FormatUnitDetail(ctx context.Context, unit *databaseentity.Unit, usage *databaseentity.UnitUsage) (*responseentity.UnitDetailResponse, error) {
+	resp, err := uc.FormatUnit(ctx, unit, nil)
+	if err != nil {
+		return nil, err
+	}
+	if usage == nil {
+		usage = &databaseentity.UnitUsage{}
+	}
+	return uc.formatUnitDetail(resp, usage), nil
+}
+
+func (uc *usecase) FormatUnitConversion(ctx context.Context, conversion *databaseentity.UnitConversion) (*responseentity.ConvertUnitResponse, error) {
+	if conversion == nil || conversion.From == nil || conversion.To == nil {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.FormatterUsecaseFormatUnitConversionError, "empty unit conversion"),
+		}
+	}
+	return uc.formatUnitConversion(conversion), nil
+}
+
+func (uc *usecase) FormatIngredient(ctx context.Context, ingredient *databaseentity.Ingredient, mapUsers map[int]string) (*responseentity.IngredientResponse, error) {
+	var err error
+	if ingredient == nil {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.FormatterUsecaseFormatIngredientError, "empty ingredient"),
+		}
+	}
+	if mapUsers == nil {
+		mapUsers, err = uc.userRepo.MapUserUsername(ctx, []int{ingredient.UpdatedBy})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return uc.formatIngredient(ingredient, mapUsers), nil
+}
+
+func (uc *usecase) FormatRecipeCosting(ctx context.Context, costing *databaseentity.RecipeCosting) (*responseentity.RecipeCostingResponse, error) {
+	if costing == nil || costing.Recipe == nil {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.FormatterUsecaseFormatRecipeCostingError, "empty recipe costing"),
+		}
+	}
+	return uc.formatRecipeCosting(costing), nil
+}
+
+func (uc *usecase) FormatRecipeScale(ctx context.Context, scale *databaseentity.RecipeScale) (*responseentity.RecipeScaleResponse, error) {
+	if scale == nil || scale.Costing == nil || scale.Costing.Recipe == nil {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.FormatterUsecaseFormatRecipeScaleError, "empty recipe scale"),
+		}
+	}
+	return uc.formatRecipeScale(scale), nil
+}
+
+func (uc *usecase) FormatRecipeProduction(ctx context.Context, production *databaseentity.RecipeProduction) (*responseentity.RecipeProductionResponse, error) {
+	if production == nil || production.Recipe == nil {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.FormatterUsecaseFormatProductionError, "empty recipe production"),
+		}
+	}
+	return uc.formatRecipeProduction(production), nil
+}
+
+func (uc *usecase) FormatRecipeIngredient(ctx context.Context, line *databaseentity.RecipeIngredient, mapUsers map[int]string) (*responseentity.RecipeIngredientResponse, error) {
+	var err error
+	if line == nil || line.Recipe == nil {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.FormatterUsecaseFormatRecipeLineError, "empty recipe line"),
+		}
+	}
+	if mapUsers == nil {
+		mapUsers, err = uc.userRepo.MapUserUsername(ctx, []int{line.UpdatedBy})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return uc.formatRecipeIngredient(line, mapUsers), nil
+}
+
+func (uc *usecase) FormatMail(ctx context.Context, mail *databaseentity.MailOutbox) (*responseentity.MailResponse, error) {
+	if mail == nil {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.FormatterUsecaseFormatMailError, "empty mail"),
+		}
+	}
+	return uc.formatMail(mail), nil
+}
+
+func (uc *usecase) FormatMails(ctx context.Context, mails []*databaseentity.MailOutbox) ([]*responseentity.MailResponse, error) {
+	result := []*responseentity.MailResponse{}
+	for _, mail := range mails {
+		result = append(result, uc.formatMail(mail))
+	}
+	return result, nil
+}
+
+func (uc *usecase) FormatAuditLogs(ctx context.Context, logs []*databaseentity.AuditLog) ([]*responseentity.AuditLogResponse, error) {
+	if len(logs) == 0 {
+		return []*responseentity.AuditLogResponse{}, nil
+	}
+	mapUsers, err := uc.userRepo.MapUserUsername(ctx, logs)
+	if err != nil {
+		return nil, err
+	}
+	var result []*responseentity.AuditLogResponse
+	for _, log := range logs {
+		result = append(result, uc.formatAuditLog(log, mapUsers))
+	}
+	return result, nil
+}
@@ -2,6 +2,7 @@ package formatterusecase_test
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"rap-c/app/entity"
 	databaseentity "rap-c/app/entity/database-entity"
@@ -37,9 +38,10 @@ func Test_FormatUser(t *testing.T) {
 		resp, err := uc.FormatUser(ctx, user, nil)
 		assert.Nil(t, err)
 		assert.Equal(t, &responseentity.UserResponse{
-			Username:  "user-2",
-			CreatedBy: "user-1",
-			UpdatedBy: "user-1",
+			Username:          "user-2",
+			InvitationPending: true,
+			CreatedBy:         "user-1",
+			UpdatedBy:         "user-1",
 		}, resp)
 	})
 
@@ -49,9 +51,10 @@ func Test_FormatUser(t *testing.T) {
 		resp, err := uc.FormatUser(ctx, user, map[int]string{1: "gendutski"})
 		assert.Nil(t, err)
 		assert.Equal(t, &responseentity.UserResponse{
-			Username:  "user-2",
-			CreatedBy: "gendutski",
-			UpdatedBy: "gendutski",
+			Username:          "user-2",
+			InvitationPending: true,
+			CreatedBy:         "gendutski",
+			UpdatedBy:         "gendutski",
 		}, resp)
 	})
 
@@ -83,8 +86,8 @@ func Test_FormatUsers(t *testing.T) {
 		resp, err := uc.FormatUsers(ctx, users)
 		assert.Nil(t, err)
 		assert.Equal(t, []*responseentity.UserResponse{
-			{Username: "user-1", CreatedBy: "SYSTEM", UpdatedBy: "SYSTEM"},
-			{Username: "user-2", CreatedBy: "user-1", UpdatedBy: "user-1"},
+			{Username: "user-1", InvitationPending: true, CreatedBy: "SYSTEM", UpdatedBy: "SYSTEM"},
+			{Username: "user-2", InvitationPending: true, CreatedBy: "user-1", UpdatedBy: "user-1"},
 		}, resp)
 	})
 
@@ -167,3 +170,40 @@ func Test_FormatUnits(t *testing.T) {
 		assert.Empty(t, resp)
 	})
 }
+
+func Test_FormatAuditLogs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	uc, userRepo := initUsecase(ctrl, &config.Config{})
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		payload := []*databaseentity.AuditLog{
+			{ID: 1, ActorID: 0, Action: databaseentity.AuditActionCreate, EntityType: "user", EntityID: 1, Serial: "guest",
+				AfterData: `{"username":"guest"}`},
+			{ID: 2, ActorID: 1, Action: databaseentity.AuditActionUpdate, EntityType: "unit", EntityID: 2, Serial: "Gram",
+				BeforeData: `{"name":"gr"}`, AfterData: `{"name":"Gram"}`},
+			{ID: 3, ActorID: 2, Action: databaseentity.AuditActionDelete, EntityType: "unit", EntityID: 3, Serial: "Liter",
+				BeforeData: `{"name":"Liter"}`},
+		}
+		userRepo.EXPECT().MapUserUsername(ctx, payload).Return(map[int]string{
+			1: "user-1",
+		}, nil).Times(1)
+
+		resp, err := uc.FormatAuditLogs(ctx, payload)
+		assert.Nil(t, err)
+		assert.Equal(t, []*responseentity.AuditLogResponse{
+			{ID: 1, Actor: "SYSTEM", Action: "create", EntityType: "user", EntityID: 1, Serial: "guest",
+				Before: json.RawMessage("null"), After: json.RawMessage(`{"username":"guest"}`)},
+			{ID: 2, Actor: "user-1", Action: "update", EntityType: "unit", EntityID: 2, Serial: "Gram",
+				Before: json.RawMessage(`{"name":"gr"}`), After: json.RawMessage(`{"name":"Gram"}`)},
+			{ID: 3, Actor: "DELETED USER", Action: "delete", EntityType: "unit", EntityID: 3, Serial: "Liter",
+				Before: json.RawMessage(`{"name":"Liter"}`), After: json.RawMessage("null")},
+		}, resp)
+	})
+
+	t.Run("empty logs", func(t *testing.T) {
+		resp, err := uc.FormatAuditLogs(ctx, []*databaseentity.AuditLog{})
+		assert.Nil(t, err)
+		assert.Empty(t, resp)
+	})
+}
@@ -0,0 +1,104 @@
+package healthusecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"rap-c/app/entity"
+	responseentity "rap-c/app/entity/response-entity"
+	"rap-c/app/repository/contract"
+	usecasecontract "rap-c/app/usecase/contract"
+	"rap-c/config"
+	"time"
+)
+
+// maximum time of each readiness check
+const checkTimeout time.Duration = 3 * time.Second
+
+// readiness check names
+const (
+	CheckDatabase   string = "database"
+	CheckMigrations string = "migrations"
+	CheckTemplates  string = "templates"
+	CheckMail       string = "mail"
+)
+
+func NewUsecase(cfg *config.Config, healthRepo contract.HealthRepository, mailSender contract.MailSender,
+	templateChecker usecasecontract.TemplateChecker) usecasecontract.HealthUsecase {
+	return &usecase{cfg, healthRepo, mailSender, templateChecker}
+}
+
+type usecase struct {
+	cfg             *config.Config
+	healthRepo      contract.HealthRepository
+	mailSender      contract.MailSender
+	templateChecker usecasecontract.TemplateChecker
+}
+
+func (uc *usecase) Liveness(ctx context.Context) *responseentity.HealthResponse {
+	return &responseentity.HealthResponse{Status: responseentity.HealthStatusOK}
+}
+
+func (uc *usecase) Readiness(ctx context.Context) *responseentity.HealthResponse {
+	start := time.Now()
+	result := &responseentity.HealthResponse{
+		Status: responseentity.HealthStatusOK,
+		Checks: make(map[string]*responseentity.HealthCheckResponse),
+	}
+
+	result.Checks[CheckDatabase] = uc.check(ctx, false, uc.healthRepo.Ping)
+	result.Checks[CheckMigrations] = uc.check(ctx, false, func(ctx context.Context) error {
+		pending, err := uc.healthRepo.GetTotalPendingMigrations(ctx)
+		if err != nil {
+			return err
+		}
+		if pending > 0 {
+			return fmt.Errorf("database schema is behind by %d migration(s)", pending)
+		}
+		return nil
+	})
+	result.Checks[CheckTemplates] = uc.check(ctx, false, func(ctx context.Context) error {
+		return uc.templateChecker.Check()
+	})
+	// unreachable mail server only delays outbox, so it does not fail readiness
+	result.Checks[CheckMail] = uc.check(ctx, true, uc.mailSender.Ping)
+
+	for _, check := range result.Checks {
+		if check.Status != responseentity.HealthStatusOK && !check.Optional {
+			result.Status = responseentity.HealthStatusFail
+		}
+	}
+	result.DurationMs = durationMs(time.Since(start))
+	return result
+}
+
+func (uc *usecase) check(ctx context.Context, optional bool, fn func(ctx context.Context) error) *responseentity.HealthCheckResponse {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := fn(ctx)
+	result := &responseentity.HealthCheckResponse{
+		Status:     responseentity.HealthStatusOK,
+		Optional:   optional,
+		DurationMs: durationMs(time.Since(start)),
+	}
+	if err != nil {
+		result.Status = responseentity.HealthStatusFail
+		result.Error = errorMessage(err)
+	}
+	return result
+}
+
+// internal message of repository error, without http status
+func errorMessage(err error) string {
+	var internalErr *entity.InternalError
+	if errors.As(err, &internalErr) {
+		return internalErr.Message
+	}
+	return err.Error()
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
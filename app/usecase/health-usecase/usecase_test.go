@@ -0,0 +1,114 @@
+package healthusecase_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"rap-c/app/entity"
+	responseentity "rap-c/app/entity/response-entity"
+	"rap-c/app/repository/contract/mocks"
+	"rap-c/app/usecase/contract"
+	healthusecase "rap-c/app/usecase/health-usecase"
+	"rap-c/config"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type templateChecker struct {
+	err error
+}
+
+func (c *templateChecker) Check() error {
+	return c.err
+}
+
+func initUsecase(ctrl *gomock.Controller, cfg *config.Config, checker contract.TemplateChecker) (contract.HealthUsecase, *mocks.MockHealthRepository, *mocks.MockMailSender) {
+	healthRepo := mocks.NewMockHealthRepository(ctrl)
+	mailSender := mocks.NewMockMailSender(ctrl)
+	usecase := healthusecase.NewUsecase(cfg, healthRepo, mailSender, checker)
+	return usecase, healthRepo, mailSender
+}
+
+func Test_Liveness(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	usecase, _, _ := initUsecase(ctrl, nil, &templateChecker{})
+
+	resp := usecase.Liveness(context.Background())
+	assert.Equal(t, responseentity.HealthStatusOK, resp.Status)
+	assert.Empty(t, resp.Checks)
+}
+
+func Test_Readiness(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	checker := &templateChecker{}
+	usecase, healthRepo, mailSender := initUsecase(ctrl, nil, checker)
+	ctx := context.Background()
+
+	t.Run("all ready", func(t *testing.T) {
+		checker.err = nil
+		healthRepo.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+		healthRepo.EXPECT().GetTotalPendingMigrations(gomock.Any()).Return(0, nil).Times(1)
+		mailSender.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+
+		resp := usecase.Readiness(ctx)
+		assert.Equal(t, responseentity.HealthStatusOK, resp.Status)
+		assert.Len(t, resp.Checks, 4)
+		for _, check := range resp.Checks {
+			assert.Equal(t, responseentity.HealthStatusOK, check.Status)
+			assert.Empty(t, check.Error)
+		}
+	})
+
+	t.Run("database down", func(t *testing.T) {
+		checker.err = nil
+		healthRepo.EXPECT().Ping(gomock.Any()).Return(&echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.HealthRepoPingError, "connection refused"),
+		}).Times(1)
+		healthRepo.EXPECT().GetTotalPendingMigrations(gomock.Any()).Return(0, nil).Times(1)
+		mailSender.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+
+		resp := usecase.Readiness(ctx)
+		assert.Equal(t, responseentity.HealthStatusFail, resp.Status)
+		assert.Equal(t, responseentity.HealthStatusFail, resp.Checks[healthusecase.CheckDatabase].Status)
+		assert.Equal(t, "connection refused", resp.Checks[healthusecase.CheckDatabase].Error)
+	})
+
+	t.Run("pending migrations", func(t *testing.T) {
+		checker.err = nil
+		healthRepo.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+		healthRepo.EXPECT().GetTotalPendingMigrations(gomock.Any()).Return(2, nil).Times(1)
+		mailSender.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+
+		resp := usecase.Readiness(ctx)
+		assert.Equal(t, responseentity.HealthStatusFail, resp.Status)
+		assert.Equal(t, responseentity.HealthStatusFail, resp.Checks[healthusecase.CheckMigrations].Status)
+	})
+
+	t.Run("template not loaded", func(t *testing.T) {
+		checker.err = errors.New("template dashboard not loaded")
+		healthRepo.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+		healthRepo.EXPECT().GetTotalPendingMigrations(gomock.Any()).Return(0, nil).Times(1)
+		mailSender.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+
+		resp := usecase.Readiness(ctx)
+		assert.Equal(t, responseentity.HealthStatusFail, resp.Status)
+		assert.Equal(t, "template dashboard not loaded", resp.Checks[healthusecase.CheckTemplates].Error)
+	})
+
+	t.Run("mail unreachable is optional", func(t *testing.T) {
+		checker.err = nil
+		healthRepo.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+		healthRepo.EXPECT().GetTotalPendingMigrations(gomock.Any()).Return(0, nil).Times(1)
+		mailSender.EXPECT().Ping(gomock.Any()).Return(errors.New("dial tcp: i/o timeout")).Times(1)
+
+		resp := usecase.Readiness(ctx)
+		assert.Equal(t, responseentity.HealthStatusOK, resp.Status)
+		assert.Equal(t, responseentity.HealthStatusFail, resp.Checks[healthusecase.CheckMail].Status)
+		assert.True(t, resp.Checks[healthusecase.CheckMail].Optional)
+	})
+}
@@ -0,0 +1,54 @@
+package ingredientusecase
+
+import (
+	"context"
+	"net/http"
+	"rap-c/app/entity"
+	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	"rap-c/app/repository/contract"
+	usecasecontract "rap-c/app/usecase/contract"
+	"rap-c/config"
+
+	"github.com/labstack/echo/v4"
+)
+
+func NewUsecase(cfg *config.Config, ingredientRepo contract.IngredientRepository) usecasecontract.IngredientUsecase {
+	return &usecase{cfg, ingredientRepo}
+}
+
+type usecase struct {
+	cfg            *config.Config
+	ingredientRepo contract.IngredientRepository
+}
+
+func (uc *usecase) SetWeight(ctx context.Context, payload *payloadentity.SetIngredientWeightPayload, author *databaseentity.User) (*databaseentity.Ingredient, error) {
+	// validate payload
+	err := entity.InitValidator().Validate(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	// get ingredient
+	ingredient, err := uc.ingredientRepo.GetIngredientBySerial(ctx, payload.Serial)
+	if err != nil {
+		return nil, err
+	}
+	if ingredient.Density == payload.Density && ingredient.PieceWeight == payload.PieceWeight {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusConflict,
+			Message:  entity.UpdateIngredientNoChangeMessage,
+			Internal: entity.NewInternalError(entity.UpdateIngredientNoChange, entity.UpdateIngredientNoChangeMessage),
+		}
+	}
+
+	// update ingredient
+	ingredient.Density = payload.Density
+	ingredient.PieceWeight = payload.PieceWeight
+	ingredient.UpdatedBy = author.ID
+	err = uc.ingredientRepo.Update(ctx, ingredient)
+	if err != nil {
+		return nil, err
+	}
+	return ingredient, nil
+}
@@ -0,0 +1,65 @@
+package ingredientusecase_test
+
+import (
+	"context"
+	"net/http"
+	"rap-c/app/entity"
+	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	"rap-c/app/repository/contract/mocks"
+	"rap-c/app/usecase/contract"
+	ingredientusecase "rap-c/app/usecase/ingredient-usecase"
+	"rap-c/config"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func initUsecase(ctrl *gomock.Controller, cfg *config.Config) (contract.IngredientUsecase, *mocks.MockIngredientRepository) {
+	ingredientRepo := mocks.NewMockIngredientRepository(ctrl)
+	usecase := ingredientusecase.NewUsecase(cfg, ingredientRepo)
+	return usecase, ingredientRepo
+}
+
+func Test_SetWeight(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	usecase, ingredientRepo := initUsecase(ctrl, nil)
+	ctx := context.Background()
+	author := &databaseentity.User{ID: 3}
+
+	t.Run("success", func(t *testing.T) {
+		egg := &databaseentity.Ingredient{ID: 1, Serial: "EGG"}
+		ingredientRepo.EXPECT().GetIngredientBySerial(ctx, "EGG").Return(egg, nil).Times(1)
+		ingredientRepo.EXPECT().Update(ctx, egg).Return(nil).Times(1)
+
+		ingredient, err := usecase.SetWeight(ctx, &payloadentity.SetIngredientWeightPayload{Serial: "EGG", Density: 1.03, PieceWeight: 60}, author)
+		assert.Nil(t, err)
+		assert.Equal(t, 1.03, ingredient.Density)
+		assert.Equal(t, float64(60), ingredient.PieceWeight)
+		assert.Equal(t, author.ID, ingredient.UpdatedBy)
+	})
+
+	t.Run("no change", func(t *testing.T) {
+		ingredientRepo.EXPECT().GetIngredientBySerial(ctx, "EGG").Return(&databaseentity.Ingredient{ID: 1, Serial: "EGG", PieceWeight: 60}, nil).Times(1)
+
+		_, err := usecase.SetWeight(ctx, &payloadentity.SetIngredientWeightPayload{Serial: "EGG", PieceWeight: 60}, author)
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusConflict, herr.Code)
+		assert.Equal(t, entity.NewInternalError(entity.UpdateIngredientNoChange, entity.UpdateIngredientNoChangeMessage), herr.Internal)
+	})
+
+	t.Run("invalid payload", func(t *testing.T) {
+		_, err := usecase.SetWeight(ctx, &payloadentity.SetIngredientWeightPayload{Density: -1, PieceWeight: -1}, author)
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, herr.Code)
+		assert.Equal(t, map[string][]*entity.ValidatorMessage{
+			"serial":      {{Tag: "required"}},
+			"density":     {{Tag: "gte", Param: "0"}},
+			"pieceWeight": {{Tag: "gte", Param: "0"}},
+		}, herr.Message)
+	})
+}
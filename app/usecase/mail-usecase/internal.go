@@ -1,19 +1,68 @@
 package mailusecase
 
 import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"net/mail"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"rap-c/app/entity"
+	databaseentity "rap-c/app/entity/database-entity"
 	"rap-c/config"
+	"text/template"
 	"time"
 
-	"github.com/go-gomail/gomail"
+	"github.com/labstack/echo/v4"
 	"github.com/matcornic/hermes/v2"
 )
 
 const (
-	defaultEmailLogo string = "https://github.com/gendutski/rap-c/blob/main/storage/public-asset/images/logo-s.png?raw=true"
+	defaultEmailLogo string        = "https://github.com/gendutski/rap-c/blob/main/storage/public-asset/images/logo-s.png?raw=true"
+	claimLease       time.Duration = time.Minute * 5
+	defaultLocale    string        = "id"
+	templateExt      string        = ".json"
+	previewRecipient string        = "preview@example.com"
+
+	welcomeTemplate       string = "welcome"
+	resetPasswordTemplate string = "reset-password"
+	updateUserTemplate    string = "update-user"
+	activeStatusTemplate  string = "active-status"
+	verifyEmailTemplate   string = "verify-email"
+	changeEmailTemplate   string = "email-change-requested"
 )
 
+// built-in templates, used when custom template not exists or failed to render
+//
+//go:embed templates
+var builtinTemplates embed.FS
+
+// mail template file, every string is text/template executed with mail data
+type mailTemplate struct {
+	Subject    string   `json:"subject"`
+	Greeting   string   `json:"greeting"`
+	Name       string   `json:"name"`
+	Intros     []string `json:"intros"`
+	Dictionary []struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	} `json:"dictionary"`
+	Actions []struct {
+		Instructions string `json:"instructions"`
+		Button       struct {
+			Color string `json:"color"`
+			Text  string `json:"text"`
+			Link  string `json:"link"`
+		} `json:"button"`
+	} `json:"actions"`
+	Outros    []string `json:"outros"`
+	Signature string   `json:"signature"`
+}
+
 func (uc *usecase) initHermes() *hermes.Hermes {
 	h := hermes.Hermes{
 		Product: hermes.Product{
@@ -27,20 +76,157 @@ func (uc *usecase) initHermes() *hermes.Hermes {
 	return &h
 }
 
-func (uc *usecase) send(to, subject, txtBody, htmlBody string) error {
-	from := mail.Address{
-		Name:    uc.cfg.MailSenderName(),
-		Address: uc.cfg.MailSenderAddress(),
+// compose rendered email into outbox mail, sent later by mail worker
+func (uc *usecase) compose(to, subject, txtBody, htmlBody string) *databaseentity.MailOutbox {
+	return &databaseentity.MailOutbox{
+		Recipient:     to,
+		Subject:       subject,
+		TextBody:      txtBody,
+		HTMLBody:      htmlBody,
+		Status:        databaseentity.MailStatusPending,
+		NextAttemptAt: time.Now(),
 	}
+}
 
-	m := gomail.NewMessage()
-	m.SetHeader("From", from.String())
-	m.SetHeader("To", to)
-	m.SetHeader("Subject", subject)
+func (uc *usecase) locale() string {
+	if uc.cfg.MailLocale() == "" {
+		return defaultLocale
+	}
+	return uc.cfg.MailLocale()
+}
 
-	m.SetBody("text/plain", txtBody)
-	m.AddAlternative("text/html", htmlBody)
+// render template in configured locale into outbox mail
+func (uc *usecase) renderMail(to, name string, data map[string]interface{}) (*databaseentity.MailOutbox, error) {
+	subject, txt, html, err := uc.renderTemplate(uc.locale(), name, data, true)
+	if err != nil {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.MailUsecaseRenderTemplateError, err.Error()),
+		}
+	}
+	return uc.compose(to, subject, txt, html), nil
+}
+
+// render custom template, when fallback is true broken custom template is logged and built-in template is used
+func (uc *usecase) renderTemplate(locale, name string, data map[string]interface{}, fallback bool) (subject, txt, html string, err error) {
+	// custom template
+	raw, err := os.ReadFile(filepath.Join(uc.cfg.MailTemplateDir(), locale, name+templateExt))
+	if err == nil {
+		subject, txt, html, err = uc.generate(raw, data)
+		if err == nil {
+			return subject, txt, html, nil
+		}
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		err = fmt.Errorf("custom mail template %s/%s: %v", locale, name, err)
+		if !fallback {
+			return "", "", "", err
+		}
+		entity.InitLog(context.Background(), "mail-template", "", "custom mail template failed, use built-in template", http.StatusInternalServerError, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  err.Error(),
+			Internal: entity.NewInternalError(entity.MailUsecaseRenderTemplateError, err.Error()),
+		}).Log()
+	}
+
+	// built-in template, unknown locale use default locale
+	raw, err = builtinTemplates.ReadFile(path.Join("templates", locale, name+templateExt))
+	if err != nil {
+		raw, err = builtinTemplates.ReadFile(path.Join("templates", defaultLocale, name+templateExt))
+		if err != nil {
+			return "", "", "", fmt.Errorf("mail template %s not found", name)
+		}
+	}
+	return uc.generate(raw, data)
+}
+
+// execute template strings then generate hermes html & plain text email
+func (uc *usecase) generate(raw []byte, data map[string]interface{}) (subject, txt, html string, err error) {
+	var tmpl mailTemplate
+	err = json.Unmarshal(raw, &tmpl)
+	if err != nil {
+		return "", "", "", err
+	}
 
-	d := gomail.NewDialer(uc.cfg.MailHost(), uc.cfg.MailPort(), uc.cfg.MailUser(), uc.cfg.MailPassword())
-	return d.DialAndSend(m)
+	// execute every template string, stop on first error
+	exec := func(text string) string {
+		if err != nil || text == "" {
+			return text
+		}
+		var t *template.Template
+		t, err = template.New("mail").Option("missingkey=error").Parse(text)
+		if err != nil {
+			return ""
+		}
+		var buf bytes.Buffer
+		err = t.Execute(&buf, data)
+		return buf.String()
+	}
+
+	email := hermes.Email{
+		Body: hermes.Body{
+			Greeting:  exec(tmpl.Greeting),
+			Name:      exec(tmpl.Name),
+			Signature: exec(tmpl.Signature),
+		},
+	}
+	for _, itm := range tmpl.Intros {
+		email.Body.Intros = append(email.Body.Intros, exec(itm))
+	}
+	for _, itm := range tmpl.Dictionary {
+		email.Body.Dictionary = append(email.Body.Dictionary, hermes.Entry{Key: exec(itm.Key), Value: exec(itm.Value)})
+	}
+	for _, itm := range tmpl.Actions {
+		email.Body.Actions = append(email.Body.Actions, hermes.Action{
+			Instructions: exec(itm.Instructions),
+			Button: hermes.Button{
+				Color: itm.Button.Color,
+				Text:  exec(itm.Button.Text),
+				Link:  exec(itm.Button.Link),
+			},
+		})
+	}
+	for _, itm := range tmpl.Outros {
+		email.Body.Outros = append(email.Body.Outros, exec(itm))
+	}
+	subject = exec(tmpl.Subject)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	// generate html & text email
+	h := uc.initHermes()
+	html, err = h.GenerateHTML(email)
+	if err != nil {
+		return "", "", "", err
+	}
+	txt, err = h.GeneratePlainText(email)
+	if err != nil {
+		return "", "", "", err
+	}
+	return subject, txt, html, nil
+}
+
+// sample data to preview template
+func previewData(cfg *config.Config, router *config.Route, name string) map[string]interface{} {
+	result := map[string]interface{}{
+		"FullName": "Firman Darmawan",
+		"Username": "gendutski",
+		"Email":    "gendutski@example.com",
+		"NewEmail": "new.gendutski@example.com",
+		"Disabled": true,
+	}
+	switch name {
+	case welcomeTemplate:
+		result["Link"] = cfg.URL(router.AcceptInvitationWebPage.Path()) + "?token=preview"
+		result["ExpiredIn"] = cfg.InviteTokenInHours()
+	case resetPasswordTemplate:
+		result["Link"] = cfg.URL(router.ResetPasswordWebPage.Path()) + "?token=preview"
+		result["ExpiredIn"] = cfg.ResetTokenInMinutes()
+	case verifyEmailTemplate:
+		result["Link"] = cfg.URL(router.VerifyEmailWebPage.Path()) + "?token=preview"
+		result["ExpiredIn"] = cfg.VerifyTokenInHours()
+	}
+	return result
 }
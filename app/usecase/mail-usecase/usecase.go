@@ -1,233 +1,204 @@
 package mailusecase
 
 import (
-	"fmt"
+	"context"
 	"net/http"
 	"net/url"
 	"rap-c/app/entity"
 	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	repocontract "rap-c/app/repository/contract"
 	"rap-c/app/usecase/contract"
 	"rap-c/config"
+	"time"
 
 	"github.com/labstack/echo/v4"
-	"github.com/matcornic/hermes/v2"
 )
 
-const (
-	welcomeSubject      string = "Selamat datang di Rap-C"
-	resetSubject        string = "Permintaan reset pasword di Rap-C"
-	updateSubject       string = "Perubahan data user di Rap-C"
-	activeStatusSubject string = "Perubahan status aktif user di Rap-C"
-)
-
-func NewUsecase(cfg *config.Config, router *config.Route) contract.MailUsecase {
-	return &usecase{cfg, router}
+func NewUsecase(
+	cfg *config.Config,
+	router *config.Route,
+	outboxRepo repocontract.OutboxRepository,
+	mailSender repocontract.MailSender,
+	capturedMailRepo repocontract.CapturedMailRepository,
+) contract.MailUsecase {
+	return &usecase{cfg, router, outboxRepo, mailSender, capturedMailRepo}
 }
 
 type usecase struct {
-	cfg    *config.Config
-	router *config.Route
+	cfg              *config.Config
+	router           *config.Route
+	outboxRepo       repocontract.OutboxRepository
+	mailSender       repocontract.MailSender
+	capturedMailRepo repocontract.CapturedMailRepository
 }
 
-func (uc *usecase) Welcome(user *databaseentity.User, password string) error {
-	// init hermes
-	h := uc.initHermes()
+func (uc *usecase) Welcome(user *databaseentity.User, invitation *databaseentity.UserInvitation) (*databaseentity.MailOutbox, error) {
+	// encode email & invitation token
+	params := url.Values{}
+	params.Add("email", user.Email)
+	params.Add("token", invitation.Token)
+
+	return uc.renderMail(user.Email, welcomeTemplate, map[string]interface{}{
+		"FullName":  user.FullName,
+		"Username":  user.Username,
+		"Email":     user.Email,
+		"Link":      uc.cfg.URL(uc.router.AcceptInvitationWebPage.Path()) + "?" + params.Encode(),
+		"ExpiredIn": uc.cfg.InviteTokenInHours(),
+	})
+}
 
+func (uc *usecase) ResetPassword(user *databaseentity.User, token *databaseentity.PasswordResetToken) (*databaseentity.MailOutbox, error) {
 	// encode email & password
 	params := url.Values{}
 	params.Add("email", user.Email)
-	params.Add("password", password)
-
-	// init hermes email
-	email := hermes.Email{
-		Body: hermes.Body{
-			Greeting: "Hai",
-			Name:     user.FullName,
-			Intros: []string{
-				"Selamat datang di `rap-c`, aplikasi yang dirancang untuk memudahkan pengelolaan resep, menghitung harga pokok penjualan, mengelola stok bahan baku, dan menyimpan catatan transaksi dalam general ledger sederhana.",
-				"Anda telah diajukan menjadi user di aplikasi ini, dengan detail sebagai berikut:",
-			},
-			Dictionary: []hermes.Entry{
-				{Key: "Nama Lengkap", Value: user.FullName},
-				{Key: "Username", Value: user.Username},
-				{Key: "Password", Value: password},
-			},
-			Actions: []hermes.Action{
-				{
-					Instructions: "Silahkan klik tombol dibawah untuk login:",
-					Button: hermes.Button{
-						Text: "Login menggunakan akun anda",
-						Link: uc.cfg.URL(uc.router.LoginWebPage.Path()) + "?" + params.Encode(),
-					},
-				},
-			},
-			Signature: "Hormat Kami",
-		},
-	}
-
-	// generate html email
-	resHtml, err := h.GenerateHTML(email)
-	if err != nil {
-		return &echo.HTTPError{
-			Code:     http.StatusInternalServerError,
-			Message:  http.StatusText(http.StatusInternalServerError),
-			Internal: entity.NewInternalError(entity.MailUsecaseGenerateHTMLError, err.Error()),
-		}
-	}
+	params.Add("token", token.Token)
 
-	// generate text html
-	resText, err := h.GeneratePlainText(email)
-	if err != nil {
-		return &echo.HTTPError{
-			Code:     http.StatusInternalServerError,
-			Message:  http.StatusText(http.StatusInternalServerError),
-			Internal: entity.NewInternalError(entity.MailUsecaseGeneratePlainTextError, err.Error()),
-		}
-	}
+	return uc.renderMail(user.Email, resetPasswordTemplate, map[string]interface{}{
+		"FullName":  user.FullName,
+		"Username":  user.Username,
+		"Email":     user.Email,
+		"Link":      uc.cfg.URL(uc.router.ResetPasswordWebPage.Path()) + "?" + params.Encode(),
+		"ExpiredIn": uc.cfg.ResetTokenInMinutes(),
+	})
+}
 
-	return uc.send(user.Email, welcomeSubject, resText, resHtml)
+func (uc *usecase) UpdateUser(user *databaseentity.User) (*databaseentity.MailOutbox, error) {
+	return uc.renderMail(user.Email, updateUserTemplate, map[string]interface{}{
+		"FullName": user.FullName,
+		"Username": user.Username,
+		"Email":    user.Email,
+	})
 }
 
-func (uc *usecase) ResetPassword(user *databaseentity.User, token *databaseentity.PasswordResetToken) error {
-	// init hermes
-	h := uc.initHermes()
+func (uc *usecase) UpdateActiveStatusUser(user *databaseentity.User) (*databaseentity.MailOutbox, error) {
+	return uc.renderMail(user.Email, activeStatusTemplate, map[string]interface{}{
+		"FullName": user.FullName,
+		"Username": user.Username,
+		"Email":    user.Email,
+		"Disabled": user.Disabled,
+	})
+}
 
-	// encode email & password
+func (uc *usecase) VerifyEmail(user *databaseentity.User, token string) (*databaseentity.MailOutbox, error) {
+	// encode token
 	params := url.Values{}
-	params.Add("email", user.Email)
-	params.Add("token", token.Token)
+	params.Add("token", token)
+
+	return uc.renderMail(user.EmailToVerify(), verifyEmailTemplate, map[string]interface{}{
+		"FullName":  user.FullName,
+		"Username":  user.Username,
+		"Email":     user.EmailToVerify(),
+		"Link":      uc.cfg.URL(uc.router.VerifyEmailWebPage.Path()) + "?" + params.Encode(),
+		"ExpiredIn": uc.cfg.VerifyTokenInHours(),
+	})
+}
 
-	// init hermes email
-	email := hermes.Email{
-		Body: hermes.Body{
-			Greeting: "Hai",
-			Name:     user.FullName,
-			Intros: []string{
-				"Anda menerima email ini karena permintaan reset password untuk akun anda telah diterima.",
-			},
-			Actions: []hermes.Action{
-				{
-					Instructions: "Silahkan klik tombol dibawah untuk reset password Anda:",
-					Button: hermes.Button{
-						Color: "#DC4D2F",
-						Text:  "Reset your password",
-						Link:  uc.cfg.URL(uc.router.ResetPasswordWebPage.Path()) + "?" + params.Encode(),
-					},
-				},
-			},
-			Outros: []string{
-				"Jika Anda tidak meminta reset password, Anda tidak perlu melakukan tindakan lebih lanjut.",
-			},
-			Signature: "Hormat Kami",
-		},
-	}
+func (uc *usecase) EmailChangeRequested(user *databaseentity.User) (*databaseentity.MailOutbox, error) {
+	return uc.renderMail(user.Email, changeEmailTemplate, map[string]interface{}{
+		"FullName": user.FullName,
+		"Username": user.Username,
+		"Email":    user.Email,
+		"NewEmail": user.PendingEmail,
+	})
+}
 
-	// generate html email
-	resHtml, err := h.GenerateHTML(email)
+func (uc *usecase) PreviewTemplate(ctx context.Context, payload *payloadentity.PreviewMailTemplatePayload) (*databaseentity.MailOutbox, error) {
+	// validate payload
+	err := entity.InitValidator().Validate(payload)
 	if err != nil {
-		return &echo.HTTPError{
-			Code:     http.StatusInternalServerError,
-			Message:  http.StatusText(http.StatusInternalServerError),
-			Internal: entity.NewInternalError(entity.MailUsecaseGenerateHTMLError, err.Error()),
-		}
+		return nil, err
+	}
+	if payload.Locale == "" {
+		payload.Locale = uc.locale()
 	}
 
-	// generate text html
-	resText, err := h.GeneratePlainText(email)
+	// render without fallback, so broken custom template is reported
+	subject, txt, html, err := uc.renderTemplate(payload.Locale, payload.Name, previewData(uc.cfg, uc.router, payload.Name), false)
 	if err != nil {
-		return &echo.HTTPError{
-			Code:     http.StatusInternalServerError,
-			Message:  http.StatusText(http.StatusInternalServerError),
-			Internal: entity.NewInternalError(entity.MailUsecaseGeneratePlainTextError, err.Error()),
+		return nil, &echo.HTTPError{
+			Code:     http.StatusBadRequest,
+			Message:  err.Error(),
+			Internal: entity.NewInternalError(entity.MailTemplateRenderFailed, err.Error()),
 		}
 	}
+	return uc.compose(previewRecipient, subject, txt, html), nil
+}
 
-	return uc.send(user.Email, resetSubject, resText, resHtml)
-}
-
-func (uc *usecase) UpdateUser(user *databaseentity.User) error {
-	// init hermes
-	h := uc.initHermes()
-
-	// init hermes email
-	email := hermes.Email{
-		Body: hermes.Body{
-			Greeting: "Hai",
-			Name:     user.FullName,
-			Intros: []string{
-				"Anda menerima email ini karena anda melakukan perubahan pada data user anda.",
-			},
-			Dictionary: []hermes.Entry{
-				{Key: "Nama Lengkap", Value: user.FullName},
-				{Key: "Username", Value: user.Username},
-				{Key: "Email", Value: user.Email},
-			},
-			Signature: "Hormat Kami",
-		},
-	}
+func (uc *usecase) Queue(ctx context.Context, mails ...*databaseentity.MailOutbox) error {
+	return uc.outboxRepo.Create(ctx, mails...)
+}
 
-	// generate html email
-	resHtml, err := h.GenerateHTML(email)
+func (uc *usecase) SendPendingMails(ctx context.Context) (int, error) {
+	// claim due mails
+	mails, err := uc.outboxRepo.ClaimPendingMails(ctx, time.Now(), uc.cfg.OutboxBatchSize(), claimLease)
 	if err != nil {
-		return &echo.HTTPError{
-			Code:     http.StatusInternalServerError,
-			Message:  http.StatusText(http.StatusInternalServerError),
-			Internal: entity.NewInternalError(entity.MailUsecaseGenerateHTMLError, err.Error()),
-		}
+		return 0, err
 	}
 
-	// generate text html
-	resText, err := h.GeneratePlainText(email)
-	if err != nil {
-		return &echo.HTTPError{
-			Code:     http.StatusInternalServerError,
-			Message:  http.StatusText(http.StatusInternalServerError),
-			Internal: entity.NewInternalError(entity.MailUsecaseGeneratePlainTextError, err.Error()),
+	// send & record result of each mail
+	var total int
+	backoff := time.Second * time.Duration(uc.cfg.OutboxBackoff())
+	for _, mail := range mails {
+		sendErr := uc.mailSender.Send(ctx, mail)
+		if sendErr != nil {
+			mail.Failed(sendErr, time.Now(), uc.cfg.OutboxMaxAttempts(), backoff)
+		} else {
+			mail.Sent(time.Now())
+			total++
+		}
+		err = uc.outboxRepo.Update(ctx, mail)
+		if err != nil {
+			return total, err
 		}
 	}
-
-	return uc.send(user.Email, updateSubject, resText, resHtml)
+	return total, nil
 }
 
-func (uc *usecase) UpdateActiveStatusUser(user *databaseentity.User) error {
-	// init hermes
-	h := uc.initHermes()
-	status := "di non aktifkan"
-	if !user.Disabled {
-		status = "diaktifkan"
+func (uc *usecase) GetDeadLetterList(ctx context.Context, req *payloadentity.GetDeadLetterListRequest) ([]*databaseentity.MailOutbox, error) {
+	if req.Page < 1 {
+		req.Page = 1
 	}
+	return uc.outboxRepo.GetDeadLettersByRequest(ctx, req)
+}
 
-	// init hermes email
-	email := hermes.Email{
-		Body: hermes.Body{
-			Greeting: "Hai",
-			Name:     user.FullName,
-			Intros: []string{
-				fmt.Sprintf("Anda menerima email ini karena user anda %s", status),
-			},
-			Signature: "Hormat Kami",
-		},
+func (uc *usecase) GetTotalDeadLetterList(ctx context.Context, req *payloadentity.GetDeadLetterListRequest) (int64, error) {
+	return uc.outboxRepo.GetTotalDeadLettersByRequest(ctx, req)
+}
+
+func (uc *usecase) ResendDeadLetter(ctx context.Context, payload *payloadentity.ResendMailPayload) (*databaseentity.MailOutbox, error) {
+	// validate payload
+	err := entity.InitValidator().Validate(payload)
+	if err != nil {
+		return nil, err
 	}
 
-	// generate html email
-	resHtml, err := h.GenerateHTML(email)
+	// get mail
+	mail, err := uc.outboxRepo.GetMailByID(ctx, payload.ID)
 	if err != nil {
-		return &echo.HTTPError{
-			Code:     http.StatusInternalServerError,
-			Message:  http.StatusText(http.StatusInternalServerError),
-			Internal: entity.NewInternalError(entity.MailUsecaseGenerateHTMLError, err.Error()),
+		return nil, err
+	}
+	if mail.Status != databaseentity.MailStatusDead {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusBadRequest,
+			Message:  entity.ResendMailNotDeadLetterMessage,
+			Internal: entity.NewInternalError(entity.ResendMailNotDeadLetter, entity.ResendMailNotDeadLetterMessage),
 		}
 	}
 
-	// generate text html
-	resText, err := h.GeneratePlainText(email)
+	// put back to queue
+	mail.Requeue(time.Now())
+	err = uc.outboxRepo.Update(ctx, mail)
 	if err != nil {
-		return &echo.HTTPError{
-			Code:     http.StatusInternalServerError,
-			Message:  http.StatusText(http.StatusInternalServerError),
-			Internal: entity.NewInternalError(entity.MailUsecaseGeneratePlainTextError, err.Error()),
-		}
+		return nil, err
 	}
+	return mail, nil
+}
+
+func (uc *usecase) GetCapturedMails(ctx context.Context) ([]*databaseentity.CapturedMail, error) {
+	return uc.capturedMailRepo.GetCapturedMails(ctx)
+}
 
-	return uc.send(user.Email, activeStatusSubject, resText, resHtml)
+func (uc *usecase) GetCapturedMail(ctx context.Context, name string) (*databaseentity.CapturedMail, error) {
+	return uc.capturedMailRepo.GetCapturedMail(ctx, name)
 }
@@ -0,0 +1,246 @@
+package mailusecase_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"rap-c/app/entity"
+	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	"rap-c/app/repository/contract/mocks"
+	"rap-c/app/usecase/contract"
+	mailusecase "rap-c/app/usecase/mail-usecase"
+	"rap-c/config"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func initUsecase(ctrl *gomock.Controller, cfg *config.Config) (contract.MailUsecase, *mocks.MockOutboxRepository, *mocks.MockMailSender) {
+	outboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	mailSender := mocks.NewMockMailSender(ctrl)
+	capturedMailRepo := mocks.NewMockCapturedMailRepository(ctrl)
+	uc := mailusecase.NewUsecase(cfg, &config.Route{}, outboxRepo, mailSender, capturedMailRepo)
+	return uc, outboxRepo, mailSender
+}
+
+func Test_SendPendingMails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	uc, outboxRepo, mailSender := initUsecase(ctrl, config.InitTestConfig(map[string]string{
+		"MAIL_OUTBOX_BATCH_SIZE":         "20",
+		"MAIL_OUTBOX_MAX_ATTEMPTS":       "3",
+		"MAIL_OUTBOX_BACKOFF_IN_SECONDS": "30",
+	}))
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		mail := &databaseentity.MailOutbox{ID: 1, Recipient: "gendutski@gmail.com", Status: databaseentity.MailStatusPending}
+		outboxRepo.EXPECT().ClaimPendingMails(ctx, gomock.Any(), 20, gomock.Any()).Return([]*databaseentity.MailOutbox{mail}, nil).Times(1)
+		mailSender.EXPECT().Send(ctx, mail).Return(nil).Times(1)
+		outboxRepo.EXPECT().Update(ctx, mail).Return(nil).Times(1)
+
+		total, err := uc.SendPendingMails(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, total)
+		assert.Equal(t, databaseentity.MailStatusSent, mail.Status)
+		assert.NotNil(t, mail.SentAt)
+	})
+
+	t.Run("failed send is retried later", func(t *testing.T) {
+		mail := &databaseentity.MailOutbox{ID: 1, Recipient: "gendutski@gmail.com", Status: databaseentity.MailStatusPending}
+		outboxRepo.EXPECT().ClaimPendingMails(ctx, gomock.Any(), 20, gomock.Any()).Return([]*databaseentity.MailOutbox{mail}, nil).Times(1)
+		mailSender.EXPECT().Send(ctx, mail).Return(errors.New("connection refused")).Times(1)
+		outboxRepo.EXPECT().Update(ctx, mail).Return(nil).Times(1)
+
+		total, err := uc.SendPendingMails(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, total)
+		assert.Equal(t, databaseentity.MailStatusPending, mail.Status)
+		assert.Equal(t, 1, mail.Attempts)
+		assert.Equal(t, "connection refused", mail.LastError)
+		assert.WithinDuration(t, time.Now().Add(30*time.Second), mail.NextAttemptAt, 5*time.Second)
+	})
+
+	t.Run("failed send reach max attempts", func(t *testing.T) {
+		mail := &databaseentity.MailOutbox{ID: 1, Recipient: "gendutski@gmail.com", Status: databaseentity.MailStatusPending, Attempts: 2}
+		outboxRepo.EXPECT().ClaimPendingMails(ctx, gomock.Any(), 20, gomock.Any()).Return([]*databaseentity.MailOutbox{mail}, nil).Times(1)
+		mailSender.EXPECT().Send(ctx, mail).Return(errors.New("connection refused")).Times(1)
+		outboxRepo.EXPECT().Update(ctx, mail).Return(nil).Times(1)
+
+		total, err := uc.SendPendingMails(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, total)
+		assert.Equal(t, databaseentity.MailStatusDead, mail.Status)
+		assert.Equal(t, 3, mail.Attempts)
+	})
+
+	t.Run("claim error", func(t *testing.T) {
+		outboxRepo.EXPECT().ClaimPendingMails(ctx, gomock.Any(), 20, gomock.Any()).Return(nil, errors.New("connection lost")).Times(1)
+
+		_, err := uc.SendPendingMails(ctx)
+		assert.NotNil(t, err)
+	})
+}
+
+func Test_ResendDeadLetter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	uc, outboxRepo, _ := initUsecase(ctrl, &config.Config{})
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		mail := &databaseentity.MailOutbox{ID: 1, Status: databaseentity.MailStatusDead, Attempts: 5, LastError: "timeout"}
+		outboxRepo.EXPECT().GetMailByID(ctx, 1).Return(mail, nil).Times(1)
+		outboxRepo.EXPECT().Update(ctx, mail).Return(nil).Times(1)
+
+		res, err := uc.ResendDeadLetter(ctx, &payloadentity.ResendMailPayload{ID: 1})
+		assert.Nil(t, err)
+		assert.Equal(t, databaseentity.MailStatusPending, res.Status)
+		assert.Equal(t, 0, res.Attempts)
+	})
+
+	t.Run("not dead letter", func(t *testing.T) {
+		outboxRepo.EXPECT().GetMailByID(ctx, 2).Return(&databaseentity.MailOutbox{ID: 2, Status: databaseentity.MailStatusSent}, nil).Times(1)
+
+		_, err := uc.ResendDeadLetter(ctx, &payloadentity.ResendMailPayload{ID: 2})
+		assert.NotNil(t, err)
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, herr.Code)
+		assert.Equal(t, entity.ResendMailNotDeadLetter, herr.Internal.(*entity.InternalError).Code)
+	})
+
+	t.Run("empty payload", func(t *testing.T) {
+		_, err := uc.ResendDeadLetter(ctx, &payloadentity.ResendMailPayload{})
+		assert.NotNil(t, err)
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, herr.Code)
+		assert.Equal(t, map[string][]*entity.ValidatorMessage{
+			"id": {{Tag: "required"}},
+		}, herr.Message)
+	})
+}
+
+func Test_Welcome(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	user := &databaseentity.User{FullName: "Firman Darmawan", Username: "gendutski", Email: "gendutski@gmail.com"}
+	invitation := &databaseentity.UserInvitation{Token: "invitation-token"}
+
+	t.Run("built-in template", func(t *testing.T) {
+		uc, _, _ := initUsecase(ctrl, config.InitTestConfig(map[string]string{
+			"MAIL_LOCALE":                    "id",
+			"MAIL_TEMPLATE_DIR":              t.TempDir(),
+			"INVITATION_EXPIRATION_IN_HOURS": "72",
+		}))
+
+		mail, err := uc.Welcome(user, invitation)
+		assert.Nil(t, err)
+		assert.Equal(t, "gendutski@gmail.com", mail.Recipient)
+		assert.Equal(t, "Selamat datang di Rap-C", mail.Subject)
+		assert.Equal(t, databaseentity.MailStatusPending, mail.Status)
+		assert.Contains(t, mail.TextBody, "berlaku selama 72 jam")
+		assert.Contains(t, mail.HTMLBody, "invitation-token")
+	})
+
+	t.Run("built-in english template", func(t *testing.T) {
+		uc, _, _ := initUsecase(ctrl, config.InitTestConfig(map[string]string{
+			"MAIL_LOCALE":       "en",
+			"MAIL_TEMPLATE_DIR": t.TempDir(),
+		}))
+
+		mail, err := uc.Welcome(user, invitation)
+		assert.Nil(t, err)
+		assert.Equal(t, "Welcome to Rap-C", mail.Subject)
+	})
+
+	t.Run("unknown locale use default locale", func(t *testing.T) {
+		uc, _, _ := initUsecase(ctrl, config.InitTestConfig(map[string]string{
+			"MAIL_LOCALE":       "fr",
+			"MAIL_TEMPLATE_DIR": t.TempDir(),
+		}))
+
+		mail, err := uc.Welcome(user, invitation)
+		assert.Nil(t, err)
+		assert.Equal(t, "Selamat datang di Rap-C", mail.Subject)
+	})
+
+	t.Run("custom template", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTemplate(t, dir, "en", "welcome", `{"subject": "Hello {{.Username}}", "intros": ["Join us at {{.Link}}"]}`)
+		uc, _, _ := initUsecase(ctrl, config.InitTestConfig(map[string]string{
+			"MAIL_LOCALE":       "en",
+			"MAIL_TEMPLATE_DIR": dir,
+		}))
+
+		mail, err := uc.Welcome(user, invitation)
+		assert.Nil(t, err)
+		assert.Equal(t, "Hello gendutski", mail.Subject)
+		assert.Contains(t, mail.TextBody, "invitation-token")
+	})
+
+	t.Run("broken custom template fallback to built-in", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTemplate(t, dir, "en", "welcome", `{"subject": "Hello {{.Unknown}}"}`)
+		uc, _, _ := initUsecase(ctrl, config.InitTestConfig(map[string]string{
+			"MAIL_LOCALE":       "en",
+			"MAIL_TEMPLATE_DIR": dir,
+		}))
+
+		mail, err := uc.Welcome(user, invitation)
+		assert.Nil(t, err)
+		assert.Equal(t, "Welcome to Rap-C", mail.Subject)
+	})
+}
+
+func Test_PreviewTemplate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dir := t.TempDir()
+	writeTemplate(t, dir, "en", "update-user", `{"subject": "Hello {{.Link}}"}`)
+	uc, _, _ := initUsecase(ctrl, config.InitTestConfig(map[string]string{
+		"MAIL_LOCALE":       "id",
+		"MAIL_TEMPLATE_DIR": dir,
+	}))
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		payload := &payloadentity.PreviewMailTemplatePayload{Name: "active-status"}
+		mail, err := uc.PreviewTemplate(ctx, payload)
+		assert.Nil(t, err)
+		assert.Equal(t, "id", payload.Locale)
+		assert.Equal(t, "Perubahan status aktif user di Rap-C", mail.Subject)
+		assert.Contains(t, mail.TextBody, "di non aktifkan")
+	})
+
+	t.Run("broken custom template", func(t *testing.T) {
+		_, err := uc.PreviewTemplate(ctx, &payloadentity.PreviewMailTemplatePayload{Name: "update-user", Locale: "en"})
+		assert.NotNil(t, err)
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, herr.Code)
+		assert.Equal(t, entity.MailTemplateRenderFailed, herr.Internal.(*entity.InternalError).Code)
+	})
+
+	t.Run("unknown template", func(t *testing.T) {
+		_, err := uc.PreviewTemplate(ctx, &payloadentity.PreviewMailTemplatePayload{Name: "unknown", Locale: "de"})
+		assert.NotNil(t, err)
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, herr.Code)
+		assert.Equal(t, map[string][]*entity.ValidatorMessage{
+			"name":   {{Tag: "oneof", Param: "welcome reset-password update-user active-status verify-email email-change-requested"}},
+			"locale": {{Tag: "oneof", Param: "id en"}},
+		}, herr.Message)
+	})
+}
+
+func writeTemplate(t *testing.T, dir, locale, name, content string) {
+	err := os.MkdirAll(filepath.Join(dir, locale), 0755)
+	assert.Nil(t, err)
+	err = os.WriteFile(filepath.Join(dir, locale, name+".json"), []byte(content), 0644)
+	assert.Nil(t, err)
+}
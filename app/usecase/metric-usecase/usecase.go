@@ -0,0 +1,67 @@
+package metricusecase
+
+import (
+	"context"
+	"net/http"
+	"rap-c/app/entity"
+	"rap-c/app/repository/contract"
+	usecasecontract "rap-c/app/usecase/contract"
+	"rap-c/config"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// maximum time to count gauges on each scrape
+const collectTimeout time.Duration = 5 * time.Second
+
+func NewUsecase(cfg *config.Config, metricRepo contract.MetricRepository) usecasecontract.MetricUsecase {
+	return &usecase{
+		cfg:        cfg,
+		metricRepo: metricRepo,
+		activeUsers: prometheus.NewDesc("rapc_active_users",
+			"Total active non guest users.", nil, nil),
+		lowStockIngredients: prometheus.NewDesc("rapc_low_stock_ingredients",
+			"Total ingredients with stock at or below low stock threshold.", nil, nil),
+		pendingMails: prometheus.NewDesc("rapc_mail_outbox_pending",
+			"Total mails waiting in outbox.", nil, nil),
+	}
+}
+
+type usecase struct {
+	cfg                 *config.Config
+	metricRepo          contract.MetricRepository
+	activeUsers         *prometheus.Desc
+	lowStockIngredients *prometheus.Desc
+	pendingMails        *prometheus.Desc
+}
+
+func (uc *usecase) Describe(ch chan<- *prometheus.Desc) {
+	ch <- uc.activeUsers
+	ch <- uc.lowStockIngredients
+	ch <- uc.pendingMails
+}
+
+func (uc *usecase) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	total, err := uc.metricRepo.GetTotalActiveUsers(ctx)
+	uc.collectGauge(ctx, ch, uc.activeUsers, total, err)
+
+	total, err = uc.metricRepo.GetTotalLowStockIngredients(ctx, uc.cfg.LowStockThreshold())
+	uc.collectGauge(ctx, ch, uc.lowStockIngredients, total, err)
+
+	total, err = uc.metricRepo.GetTotalPendingMails(ctx)
+	uc.collectGauge(ctx, ch, uc.pendingMails, total, err)
+}
+
+// failed gauge is logged and reported as invalid metric, other gauges are still collected
+func (uc *usecase) collectGauge(ctx context.Context, ch chan<- prometheus.Metric, desc *prometheus.Desc, total int64, err error) {
+	if err != nil {
+		entity.InitLog(ctx, "metrics", "", "collect business gauge failed", http.StatusInternalServerError, err).Log()
+		ch <- prometheus.NewInvalidMetric(desc, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(total))
+}
@@ -0,0 +1,56 @@
+package metricusecase_test
+
+import (
+	"errors"
+	"rap-c/app/repository/contract/mocks"
+	"rap-c/app/usecase/contract"
+	metricusecase "rap-c/app/usecase/metric-usecase"
+	"rap-c/config"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func initUsecase(ctrl *gomock.Controller, cfg *config.Config) (contract.MetricUsecase, *mocks.MockMetricRepository) {
+	metricRepo := mocks.NewMockMetricRepository(ctrl)
+	usecase := metricusecase.NewUsecase(cfg, metricRepo)
+	return usecase, metricRepo
+}
+
+func Test_Collect(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cfg := config.InitTestConfig(map[string]string{"METRICS_LOW_STOCK_THRESHOLD": "5"})
+	usecase, metricRepo := initUsecase(ctrl, cfg)
+
+	t.Run("success", func(t *testing.T) {
+		metricRepo.EXPECT().GetTotalActiveUsers(gomock.Any()).Return(int64(3), nil).Times(1)
+		metricRepo.EXPECT().GetTotalLowStockIngredients(gomock.Any(), float64(5)).Return(int64(2), nil).Times(1)
+		metricRepo.EXPECT().GetTotalPendingMails(gomock.Any()).Return(int64(7), nil).Times(1)
+
+		err := testutil.CollectAndCompare(usecase, strings.NewReader(`
+# HELP rapc_active_users Total active non guest users.
+# TYPE rapc_active_users gauge
+rapc_active_users 3
+# HELP rapc_low_stock_ingredients Total ingredients with stock at or below low stock threshold.
+# TYPE rapc_low_stock_ingredients gauge
+rapc_low_stock_ingredients 2
+# HELP rapc_mail_outbox_pending Total mails waiting in outbox.
+# TYPE rapc_mail_outbox_pending gauge
+rapc_mail_outbox_pending 7
+`))
+		assert.Nil(t, err)
+	})
+
+	t.Run("failed gauge", func(t *testing.T) {
+		metricRepo.EXPECT().GetTotalActiveUsers(gomock.Any()).Return(int64(0), errors.New("db down")).Times(1)
+		metricRepo.EXPECT().GetTotalLowStockIngredients(gomock.Any(), float64(5)).Return(int64(2), nil).Times(1)
+		metricRepo.EXPECT().GetTotalPendingMails(gomock.Any()).Return(int64(7), nil).Times(1)
+
+		err := testutil.CollectAndCompare(usecase, strings.NewReader(""))
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "db down")
+	})
+}
@@ -0,0 +1,356 @@
+package recipeusecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"rap-c/app/entity"
+	databaseentity "rap-c/app/entity/database-entity"
+
+	"github.com/labstack/echo/v4"
+)
+
+// every recipe is costed once, recipes being costed are kept to find sub recipe cycle
+type costingState struct {
+	costings       map[int]*databaseentity.RecipeCosting
+	visiting       map[int]bool
+	conversions    map[int][]*databaseentity.IngredientConvertionUnit
+	dimensionUnits map[string][]*databaseentity.Unit
+}
+
+func newCostingState() *costingState {
+	return &costingState{
+		costings:       make(map[int]*databaseentity.RecipeCosting),
+		visiting:       make(map[int]bool),
+		conversions:    make(map[int][]*databaseentity.IngredientConvertionUnit),
+		dimensionUnits: make(map[string][]*databaseentity.Unit),
+	}
+}
+
+func (uc *usecase) costRecipe(ctx context.Context, recipe *databaseentity.Recipe, state *costingState) (*databaseentity.RecipeCosting, error) {
+	if result, ok := state.costings[recipe.ID]; ok {
+		return result, nil
+	}
+	if state.visiting[recipe.ID] {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusConflict,
+			Message:  fmt.Sprintf(entity.SubRecipeCycleMessage, recipe.Serial),
+			Internal: entity.NewInternalError(entity.SubRecipeCycle, entity.SubRecipeCycleMessage),
+		}
+	}
+	state.visiting[recipe.ID] = true
+	defer delete(state.visiting, recipe.ID)
+
+	lines, err := uc.recipeRepo.GetRecipeIngredients(ctx, recipe)
+	if err != nil {
+		return nil, err
+	}
+
+	// get conversion units of ingredients not fetched yet
+	var ingredientIDs []int
+	for _, line := range lines {
+		if line.Ingredient == nil {
+			continue
+		}
+		if _, ok := state.conversions[line.Ingredient.ID]; !ok {
+			state.conversions[line.Ingredient.ID] = nil
+			ingredientIDs = append(ingredientIDs, line.Ingredient.ID)
+		}
+	}
+	if len(ingredientIDs) > 0 {
+		conversions, err := uc.recipeRepo.GetIngredientConversions(ctx, ingredientIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, conversion := range conversions {
+			state.conversions[conversion.IngredientID] = append(state.conversions[conversion.IngredientID], conversion)
+		}
+	}
+
+	// convert every line into ingredient unit or yield unit of sub recipe
+	result := &databaseentity.RecipeCosting{Recipe: recipe}
+	for _, line := range lines {
+		var costingLine *databaseentity.RecipeCostingLine
+		if line.SubRecipe != nil {
+			subCosting, err := uc.costRecipe(ctx, line.SubRecipe, state)
+			if err != nil {
+				return nil, err
+			}
+			costingLine, err = uc.costSubRecipeLine(ctx, line, subCosting)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			costingLine, err = uc.costLine(ctx, line, state.conversions[line.Ingredient.ID])
+			if err != nil {
+				return nil, err
+			}
+		}
+		result.Lines = append(result.Lines, costingLine)
+		result.RawMaterialCosts += costingLine.Cost
+	}
+	state.costings[recipe.ID] = result
+	return result, nil
+}
+
+func (uc *usecase) costLine(ctx context.Context, line *databaseentity.RecipeIngredient,
+	conversions []*databaseentity.IngredientConvertionUnit) (*databaseentity.RecipeCostingLine, error) {
+	ingredient := line.Ingredient
+	conversion, err := uc.unitUsecase.ConvertQuantity(ctx, float64(line.Quantity), line.Unit, ingredient.Unit, ingredient, conversions)
+	if err != nil {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusBadRequest,
+			Message:  fmt.Sprintf(entity.RecipeIngredientNotConvertibleMessage, line.Unit.Name, ingredient.Name, ingredient.Unit.Name),
+			Internal: entity.NewInternalError(entity.RecipeIngredientNotConvertible, err.Error()),
+		}
+	}
+
+	result := &databaseentity.RecipeCostingLine{
+		RecipeIngredient: line,
+		Conversion:       conversion,
+	}
+	for _, itm := range conversions {
+		if itm.UnitID == line.UnitID && itm.SkipCalculate {
+			result.SkipCalculate = true
+			return result, nil
+		}
+	}
+	result.Cost = conversion.Result * float64(ingredient.PricePerUnit)
+	return result, nil
+}
+
+// sub recipe line converts into yield unit of sub recipe through standard units only
+func (uc *usecase) costSubRecipeLine(ctx context.Context, line *databaseentity.RecipeIngredient,
+	subCosting *databaseentity.RecipeCosting) (*databaseentity.RecipeCostingLine, error) {
+	conversion, err := uc.convertSubRecipeLine(ctx, line)
+	if err != nil {
+		return nil, err
+	}
+	return &databaseentity.RecipeCostingLine{
+		RecipeIngredient: line,
+		Conversion:       conversion,
+		SubRecipe:        subCosting,
+		Cost:             conversion.Result * subCosting.HPPPerPortion(),
+	}, nil
+}
+
+func (uc *usecase) convertSubRecipeLine(ctx context.Context, line *databaseentity.RecipeIngredient) (*databaseentity.UnitConversion, error) {
+	subRecipe := line.SubRecipe
+	if subRecipe.Unit == nil || subRecipe.Quantity <= 0 {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusBadRequest,
+			Message:  fmt.Sprintf(entity.SubRecipeWithoutYieldMessage, subRecipe.Serial),
+			Internal: entity.NewInternalError(entity.SubRecipeWithoutYield, entity.SubRecipeWithoutYieldMessage),
+		}
+	}
+	conversion, err := uc.unitUsecase.ConvertQuantity(ctx, float64(line.Quantity), line.Unit, subRecipe.Unit, nil, nil)
+	if err != nil {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusBadRequest,
+			Message:  fmt.Sprintf(entity.RecipeIngredientNotConvertibleMessage, line.Unit.Name, subRecipe.Name, subRecipe.Unit.Name),
+			Internal: entity.NewInternalError(entity.RecipeIngredientNotConvertible, err.Error()),
+		}
+	}
+	return conversion, nil
+}
+
+func (uc *usecase) scaleLine(ctx context.Context, costingLine *databaseentity.RecipeCostingLine, factor float64,
+	state *costingState) (*databaseentity.RecipeScaleLine, error) {
+	line := costingLine.RecipeIngredient
+	result := &databaseentity.RecipeScaleLine{
+		CostingLine:        costingLine,
+		Quantity:           float64(line.Quantity) * factor,
+		IngredientQuantity: costingLine.Conversion.Result * factor,
+		Cost:               costingLine.Cost * factor,
+	}
+
+	// sub recipe is not bought, it stays in its yield unit
+	var err error
+	if line.SubRecipe != nil {
+		result.Purchase, err = uc.unitUsecase.ConvertQuantity(ctx, result.IngredientQuantity, line.SubRecipe.Unit, line.SubRecipe.Unit, nil, nil)
+	} else {
+		result.Purchase, err = uc.purchase(ctx, result.IngredientQuantity, line.Ingredient, state)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ingredient quantity in ingredient unit, conversion unit or standard unit of ingredient unit dimension,
+// the largest unit whose quantity is at least 1 is chosen. conversion unit flagged skip calculate is not a purchase unit
+func (uc *usecase) purchase(ctx context.Context, quantity float64, ingredient *databaseentity.Ingredient,
+	state *costingState) (*databaseentity.UnitConversion, error) {
+	conversions := state.conversions[ingredient.ID]
+	result, err := uc.unitUsecase.ConvertQuantity(ctx, quantity, ingredient.Unit, ingredient.Unit, ingredient, conversions)
+	if err != nil {
+		return nil, err
+	}
+	var units []*databaseentity.Unit
+	for _, conversion := range conversions {
+		if conversion.SkipCalculate || conversion.Unit == nil {
+			continue
+		}
+		units = append(units, conversion.Unit)
+	}
+	if ingredient.Unit.IsStandard() {
+		dimensionUnits, ok := state.dimensionUnits[ingredient.Unit.Dimension]
+		if !ok {
+			dimensionUnits, err = uc.unitRepo.GetUnitsByDimension(ctx, ingredient.Unit.Dimension)
+			if err != nil {
+				return nil, err
+			}
+			state.dimensionUnits[ingredient.Unit.Dimension] = dimensionUnits
+		}
+		units = append(units, dimensionUnits...)
+	}
+	for _, unit := range units {
+		if unit.ID == ingredient.Unit.ID {
+			continue
+		}
+		candidate, err := uc.unitUsecase.ConvertQuantity(ctx, quantity, ingredient.Unit, unit, ingredient, conversions)
+		if err != nil {
+			continue
+		}
+		if candidate.Result >= minPurchaseQuantity && (result.Result < minPurchaseQuantity || candidate.Result < result.Result) {
+			result = candidate
+		}
+	}
+	return result, nil
+}
+
+// add stock taken by quantity of costed recipe in its yield unit into production,
+// sub recipe out of stock is produced from its own lines
+func (uc *usecase) consume(costing *databaseentity.RecipeCosting, quantity float64, production *databaseentity.RecipeProduction,
+	mapIngredients map[int]*databaseentity.IngredientConsumption, mapSubRecipes map[int]*databaseentity.SubRecipeConsumption) {
+	factor := quantity / float64(costing.Recipe.Quantity)
+	for _, line := range costing.Lines {
+		required := line.Conversion.Result * factor
+		if line.SubRecipe == nil {
+			ingredient := line.RecipeIngredient.Ingredient
+			consumption := mapIngredients[ingredient.ID]
+			if consumption == nil {
+				consumption = &databaseentity.IngredientConsumption{Ingredient: ingredient}
+				mapIngredients[ingredient.ID] = consumption
+				production.Ingredients = append(production.Ingredients, consumption)
+			}
+			consumption.Quantity += required
+			continue
+		}
+
+		subRecipe := line.RecipeIngredient.SubRecipe
+		consumption := mapSubRecipes[subRecipe.ID]
+		if consumption == nil {
+			consumption = &databaseentity.SubRecipeConsumption{Recipe: subRecipe}
+		}
+		taken := math.Min(required, math.Max(float64(subRecipe.Stock)-consumption.Quantity, 0))
+		if taken > 0 {
+			if mapSubRecipes[subRecipe.ID] == nil {
+				mapSubRecipes[subRecipe.ID] = consumption
+				production.SubRecipes = append(production.SubRecipes, consumption)
+			}
+			consumption.Quantity += taken
+		}
+		if required > taken {
+			uc.consume(line.SubRecipe, required-taken, production, mapIngredients, mapSubRecipes)
+		}
+	}
+}
+
+// cost recipes, then every recipe using them directly or through other sub recipes, changed costs are saved in one transaction.
+// when skipping, recipe which cannot be costed keeps its saved cost & recipes using it are not costed either
+func (uc *usecase) refreshCosts(ctx context.Context, recipes []*databaseentity.Recipe, author *databaseentity.User,
+	skipNotCostable bool) ([]*databaseentity.RecipeCosting, error) {
+	var result []*databaseentity.RecipeCosting
+	var changed []*databaseentity.Recipe
+	state := newCostingState()
+	queue := append([]*databaseentity.Recipe{}, recipes...)
+	queued := make(map[int]bool)
+	for _, recipe := range recipes {
+		queued[recipe.ID] = true
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		costing, err := uc.costRecipe(ctx, current, state)
+		if err != nil {
+			if herr, ok := err.(*echo.HTTPError); ok && skipNotCostable && herr.Code < http.StatusInternalServerError {
+				continue
+			}
+			return nil, err
+		}
+		if current.RawMaterialCosts != float32(costing.RawMaterialCosts) {
+			current.RawMaterialCosts = float32(costing.RawMaterialCosts)
+			current.UpdatedBy = author.ID
+			changed = append(changed, current)
+		}
+		result = append(result, costing)
+		parents, err := uc.recipeRepo.GetParentRecipes(ctx, current)
+		if err != nil {
+			return nil, err
+		}
+		for _, parent := range parents {
+			if !queued[parent.ID] {
+				queued[parent.ID] = true
+				queue = append(queue, parent)
+			}
+		}
+	}
+	if len(changed) > 0 {
+		err := uc.recipeRepo.UpdateRawMaterialCosts(ctx, changed)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// set ingredient or sub recipe, unit & quantity of line. line must convert into ingredient unit or yield unit of sub recipe
+// so it can be costed, sub recipe cycle is rejected by repository when the line is saved
+func (uc *usecase) setLine(ctx context.Context, line *databaseentity.RecipeIngredient, ingredientSerial, subRecipeSerial, unitName string,
+	quantity float32) error {
+	unit, err := uc.unitRepo.GetUnitByName(ctx, unitName)
+	if err != nil {
+		return err
+	}
+	line.UnitID = unit.ID
+	line.Unit = unit
+	line.Quantity = quantity
+
+	if subRecipeSerial != "" {
+		subRecipe, err := uc.recipeRepo.GetRecipeBySerial(ctx, subRecipeSerial)
+		if err != nil {
+			return err
+		}
+		line.IngredientID, line.Ingredient = nil, nil
+		line.SubRecipeID, line.SubRecipe = &subRecipe.ID, subRecipe
+		_, err = uc.convertSubRecipeLine(ctx, line)
+		return err
+	}
+
+	ingredient, err := uc.ingredientRepo.GetIngredientBySerial(ctx, ingredientSerial)
+	if err != nil {
+		return err
+	}
+	line.SubRecipeID, line.SubRecipe = nil, nil
+	line.IngredientID, line.Ingredient = &ingredient.ID, ingredient
+	conversions, err := uc.recipeRepo.GetIngredientConversions(ctx, []int{ingredient.ID})
+	if err != nil {
+		return err
+	}
+	_, err = uc.costLine(ctx, line, conversions)
+	return err
+}
+
+// saved line is kept when costs cannot be refreshed, failure is logged and costs are saved again by refresh cost
+func (uc *usecase) refreshLineCosts(ctx context.Context, line *databaseentity.RecipeIngredient, author *databaseentity.User) {
+	_, err := uc.refreshCosts(ctx, []*databaseentity.Recipe{line.Recipe}, author, true)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if herr, ok := err.(*echo.HTTPError); ok {
+			status = herr.Code
+		}
+		entity.InitLog(ctx, "recipe-line", "", "refresh recipe costs failed, line is saved", status, err).Log()
+	}
+}
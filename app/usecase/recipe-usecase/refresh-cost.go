@@ -0,0 +1,53 @@
+package recipeusecase
+
+import (
+	"context"
+	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	usecasecontract "rap-c/app/usecase/contract"
+)
+
+// unit usecase saving raw material costs of recipes depending on merged units, unit module is loaded before recipe module
+// so recipe module wraps unit usecase instead of unit usecase calling recipe usecase
+func NewCostRefreshingUnitUsecase(unitUsecase usecasecontract.UnitUsecase, recipeUsecase usecasecontract.RecipeUsecase) usecasecontract.UnitUsecase {
+	return &costRefreshingUnitUsecase{unitUsecase, recipeUsecase}
+}
+
+type costRefreshingUnitUsecase struct {
+	usecasecontract.UnitUsecase
+	recipeUsecase usecasecontract.RecipeUsecase
+}
+
+func (uc *costRefreshingUnitUsecase) Merge(ctx context.Context, payload *payloadentity.MergeUnitPayload, author *databaseentity.User) (*databaseentity.Unit, error) {
+	target, err := uc.UnitUsecase.Merge(ctx, payload, author)
+	if err != nil {
+		return nil, err
+	}
+	err = uc.recipeUsecase.RefreshCostByUnit(ctx, target, author)
+	if err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// ingredient usecase saving raw material costs of recipes using ingredient whose weight changed
+func NewCostRefreshingIngredientUsecase(ingredientUsecase usecasecontract.IngredientUsecase, recipeUsecase usecasecontract.RecipeUsecase) usecasecontract.IngredientUsecase {
+	return &costRefreshingIngredientUsecase{ingredientUsecase, recipeUsecase}
+}
+
+type costRefreshingIngredientUsecase struct {
+	usecasecontract.IngredientUsecase
+	recipeUsecase usecasecontract.RecipeUsecase
+}
+
+func (uc *costRefreshingIngredientUsecase) SetWeight(ctx context.Context, payload *payloadentity.SetIngredientWeightPayload, author *databaseentity.User) (*databaseentity.Ingredient, error) {
+	ingredient, err := uc.IngredientUsecase.SetWeight(ctx, payload, author)
+	if err != nil {
+		return nil, err
+	}
+	err = uc.recipeUsecase.RefreshCostByIngredient(ctx, ingredient, author)
+	if err != nil {
+		return nil, err
+	}
+	return ingredient, nil
+}
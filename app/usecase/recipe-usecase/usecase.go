@@ -0,0 +1,239 @@
+package recipeusecase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"rap-c/app/entity"
+	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	"rap-c/app/helper"
+	"rap-c/app/repository/contract"
+	usecasecontract "rap-c/app/usecase/contract"
+	"rap-c/config"
+
+	"github.com/labstack/echo/v4"
+)
+
+// least purchase quantity, below 1 since float32 conversion value may turn exactly 1 into 0.99999...
+const minPurchaseQuantity = 0.999999
+
+func NewUsecase(cfg *config.Config, recipeRepo contract.RecipeRepository, ingredientRepo contract.IngredientRepository,
+	unitRepo contract.UnitRepository, unitUsecase usecasecontract.UnitUsecase) usecasecontract.RecipeUsecase {
+	return &usecase{cfg, recipeRepo, ingredientRepo, unitRepo, unitUsecase}
+}
+
+type usecase struct {
+	cfg            *config.Config
+	recipeRepo     contract.RecipeRepository
+	ingredientRepo contract.IngredientRepository
+	unitRepo       contract.UnitRepository
+	unitUsecase    usecasecontract.UnitUsecase
+}
+
+func (uc *usecase) GetCosting(ctx context.Context, serial string) (*databaseentity.RecipeCosting, error) {
+	recipe, err := uc.recipeRepo.GetRecipeBySerial(ctx, serial)
+	if err != nil {
+		return nil, err
+	}
+	return uc.costRecipe(ctx, recipe, newCostingState())
+}
+
+func (uc *usecase) Scale(ctx context.Context, req *payloadentity.ScaleRecipeRequest) (*databaseentity.RecipeScale, error) {
+	// validate request
+	err := entity.InitValidator().Validate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	recipe, err := uc.recipeRepo.GetRecipeBySerial(ctx, req.Serial)
+	if err != nil {
+		return nil, err
+	}
+	state := newCostingState()
+	costing, err := uc.costRecipe(ctx, recipe, state)
+	if err != nil {
+		return nil, err
+	}
+	if recipe.Quantity <= 0 {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusBadRequest,
+			Message:  fmt.Sprintf(entity.ScaleRecipeWithoutPortionMessage, recipe.Serial),
+			Internal: entity.NewInternalError(entity.ScaleRecipeWithoutPortion, entity.ScaleRecipeWithoutPortionMessage),
+		}
+	}
+
+	// scale every line & sum required quantity of every ingredient & sub recipe
+	result := &databaseentity.RecipeScale{
+		Costing:  costing,
+		Portions: req.Portions,
+		Factor:   float64(req.Portions) / float64(recipe.Quantity),
+	}
+	var warnings []*databaseentity.RecipeStockWarning
+	mapIngredientWarnings := make(map[int]*databaseentity.RecipeStockWarning)
+	mapSubRecipeWarnings := make(map[int]*databaseentity.RecipeStockWarning)
+	for _, costingLine := range costing.Lines {
+		line, err := uc.scaleLine(ctx, costingLine, result.Factor, state)
+		if err != nil {
+			return nil, err
+		}
+		result.Lines = append(result.Lines, line)
+
+		var warning *databaseentity.RecipeStockWarning
+		if subRecipe := costingLine.RecipeIngredient.SubRecipe; subRecipe != nil {
+			warning = mapSubRecipeWarnings[subRecipe.ID]
+			if warning == nil {
+				warning = &databaseentity.RecipeStockWarning{SubRecipe: subRecipe}
+				mapSubRecipeWarnings[subRecipe.ID] = warning
+				warnings = append(warnings, warning)
+			}
+		} else {
+			ingredient := costingLine.RecipeIngredient.Ingredient
+			warning = mapIngredientWarnings[ingredient.ID]
+			if warning == nil {
+				warning = &databaseentity.RecipeStockWarning{Ingredient: ingredient}
+				mapIngredientWarnings[ingredient.ID] = warning
+				warnings = append(warnings, warning)
+			}
+		}
+		warning.Required += line.IngredientQuantity
+	}
+	for _, warning := range warnings {
+		if warning.Shortage() > 0 {
+			result.Warnings = append(result.Warnings, warning)
+		}
+	}
+	return result, nil
+}
+
+func (uc *usecase) Produce(ctx context.Context, payload *payloadentity.ProduceRecipePayload, author *databaseentity.User) (*databaseentity.RecipeProduction, error) {
+	// validate payload
+	err := entity.InitValidator().Validate(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	recipe, err := uc.recipeRepo.GetRecipeBySerial(ctx, payload.Serial)
+	if err != nil {
+		return nil, err
+	}
+	costing, err := uc.costRecipe(ctx, recipe, newCostingState())
+	if err != nil {
+		return nil, err
+	}
+	if recipe.Quantity <= 0 {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusBadRequest,
+			Message:  fmt.Sprintf(entity.ScaleRecipeWithoutPortionMessage, recipe.Serial),
+			Internal: entity.NewInternalError(entity.ScaleRecipeWithoutPortion, entity.ScaleRecipeWithoutPortionMessage),
+		}
+	}
+
+	// stock is checked while it is taken, so concurrent production cannot take the same stock
+	result := &databaseentity.RecipeProduction{Recipe: recipe, Quantity: payload.Quantity}
+	uc.consume(costing, payload.Quantity, result, make(map[int]*databaseentity.IngredientConsumption), make(map[int]*databaseentity.SubRecipeConsumption))
+	err = uc.recipeRepo.Produce(ctx, result, author.ID)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (uc *usecase) CreateLine(ctx context.Context, payload *payloadentity.CreateRecipeIngredientPayload, author *databaseentity.User) (*databaseentity.RecipeIngredient, error) {
+	// validate payload
+	err := entity.InitValidator().Validate(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	recipe, err := uc.recipeRepo.GetRecipeBySerial(ctx, payload.Serial)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := helper.GenerateSerial("RCI", 11)
+	if err != nil {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.HelperGenerateSerialError, err.Error()),
+		}
+	}
+	line := &databaseentity.RecipeIngredient{
+		Serial:    serial,
+		RecipeID:  recipe.ID,
+		Recipe:    recipe,
+		CreatedBy: author.ID,
+		UpdatedBy: author.ID,
+	}
+	err = uc.setLine(ctx, line, payload.IngredientSerial, payload.SubRecipeSerial, payload.Unit, payload.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	err = uc.recipeRepo.CreateRecipeIngredient(ctx, line)
+	if err != nil {
+		return nil, err
+	}
+
+	// saved costs follow the new line
+	uc.refreshLineCosts(ctx, line, author)
+	return line, nil
+}
+
+func (uc *usecase) UpdateLine(ctx context.Context, payload *payloadentity.UpdateRecipeIngredientPayload, author *databaseentity.User) (*databaseentity.RecipeIngredient, error) {
+	// validate payload
+	err := entity.InitValidator().Validate(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	line, err := uc.recipeRepo.GetRecipeIngredientBySerial(ctx, payload.Serial)
+	if err != nil {
+		return nil, err
+	}
+	err = uc.setLine(ctx, line, payload.IngredientSerial, payload.SubRecipeSerial, payload.Unit, payload.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	line.UpdatedBy = author.ID
+	err = uc.recipeRepo.UpdateRecipeIngredient(ctx, line)
+	if err != nil {
+		return nil, err
+	}
+
+	// saved costs follow the changed line
+	uc.refreshLineCosts(ctx, line, author)
+	return line, nil
+}
+
+func (uc *usecase) RefreshCost(ctx context.Context, payload *payloadentity.RefreshRecipeCostPayload, author *databaseentity.User) ([]*databaseentity.RecipeCosting, error) {
+	// validate payload
+	err := entity.InitValidator().Validate(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	recipe, err := uc.recipeRepo.GetRecipeBySerial(ctx, payload.Serial)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.refreshCosts(ctx, []*databaseentity.Recipe{recipe}, author, false)
+}
+
+func (uc *usecase) RefreshCostByIngredient(ctx context.Context, ingredient *databaseentity.Ingredient, author *databaseentity.User) error {
+	recipes, err := uc.recipeRepo.GetRecipesByIngredient(ctx, ingredient)
+	if err != nil {
+		return err
+	}
+	_, err = uc.refreshCosts(ctx, recipes, author, true)
+	return err
+}
+
+func (uc *usecase) RefreshCostByUnit(ctx context.Context, unit *databaseentity.Unit, author *databaseentity.User) error {
+	recipes, err := uc.recipeRepo.GetRecipesByUnit(ctx, unit)
+	if err != nil {
+		return err
+	}
+	_, err = uc.refreshCosts(ctx, recipes, author, true)
+	return err
+}
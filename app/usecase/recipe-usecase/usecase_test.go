@@ -0,0 +1,775 @@
+package recipeusecase_test
+
+import (
+	"context"
+	"net/http"
+	"rap-c/app/entity"
+	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	"rap-c/app/repository/contract/mocks"
+	"rap-c/app/usecase/contract"
+	usecasemocks "rap-c/app/usecase/contract/mocks"
+	ingredientusecase "rap-c/app/usecase/ingredient-usecase"
+	recipeusecase "rap-c/app/usecase/recipe-usecase"
+	unitusecase "rap-c/app/usecase/unit-usecase"
+	"rap-c/config"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// unit usecase only converts quantity, so it is not mocked
+func initUsecase(ctrl *gomock.Controller, cfg *config.Config) (contract.RecipeUsecase, *mocks.MockRecipeRepository) {
+	usecase, recipeRepo, _, _ := initLineUsecase(ctrl, cfg)
+	return usecase, recipeRepo
+}
+
+// ingredient & unit repositories are used to set recipe lines, unit repository also gives purchase units
+func initLineUsecase(ctrl *gomock.Controller, cfg *config.Config) (contract.RecipeUsecase, *mocks.MockRecipeRepository,
+	*mocks.MockIngredientRepository, *mocks.MockUnitRepository) {
+	recipeRepo := mocks.NewMockRecipeRepository(ctrl)
+	ingredientRepo := mocks.NewMockIngredientRepository(ctrl)
+	unitRepo := mocks.NewMockUnitRepository(ctrl)
+	usecase := recipeusecase.NewUsecase(cfg, recipeRepo, ingredientRepo, unitRepo, unitusecase.NewUsecase(cfg, unitRepo))
+	return usecase, recipeRepo, ingredientRepo, unitRepo
+}
+
+func Test_GetCosting(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	usecase, recipeRepo := initUsecase(ctrl, nil)
+	ctx := context.Background()
+	kg := &databaseentity.Unit{ID: 1, Name: "kg", Dimension: databaseentity.UnitDimensionMass, Factor: 1000}
+	g := &databaseentity.Unit{ID: 2, Name: "g", Dimension: databaseentity.UnitDimensionMass, Factor: 1}
+	cup := &databaseentity.Unit{ID: 3, Name: "cup", Dimension: databaseentity.UnitDimensionVolume, Factor: 250}
+	butir := &databaseentity.Unit{ID: 4, Name: "butir", Dimension: databaseentity.UnitDimensionCount, Factor: 1}
+	pinch := &databaseentity.Unit{ID: 5, Name: "pinch"}
+	flour := &databaseentity.Ingredient{ID: 1, Serial: "FLOUR", Name: "Flour", Unit: kg, PricePerUnit: 12000, Density: 0.5}
+	egg := &databaseentity.Ingredient{ID: 2, Serial: "EGG", Name: "Egg", Unit: kg, PricePerUnit: 30000, PieceWeight: 50}
+	salt := &databaseentity.Ingredient{ID: 3, Serial: "SALT", Name: "Salt", Unit: g, PricePerUnit: 10}
+	recipe := &databaseentity.Recipe{ID: 1, Serial: "CAKE", Quantity: 8, LaborCosts: 5000, OverheadCosts: 1000}
+
+	t.Run("success", func(t *testing.T) {
+		lines := []*databaseentity.RecipeIngredient{
+			{Serial: "RCI01", IngredientID: &flour.ID, Ingredient: flour, UnitID: cup.ID, Unit: cup, Quantity: 2},
+			{Serial: "RCI02", IngredientID: &egg.ID, Ingredient: egg, UnitID: butir.ID, Unit: butir, Quantity: 4},
+			{Serial: "RCI03", IngredientID: &salt.ID, Ingredient: salt, UnitID: pinch.ID, Unit: pinch, Quantity: 1},
+		}
+		conversions := []*databaseentity.IngredientConvertionUnit{{IngredientID: salt.ID, UnitID: pinch.ID, Unit: pinch, Value: 2, SkipCalculate: true}}
+		recipeRepo.EXPECT().GetRecipeBySerial(ctx, "CAKE").Return(recipe, nil).Times(1)
+		recipeRepo.EXPECT().GetRecipeIngredients(ctx, recipe).Return(lines, nil).Times(1)
+		recipeRepo.EXPECT().GetIngredientConversions(ctx, []int{flour.ID, egg.ID, salt.ID}).Return(conversions, nil).Times(1)
+
+		costing, err := usecase.GetCosting(ctx, "CAKE")
+		assert.Nil(t, err)
+		if assert.Len(t, costing.Lines, 3) {
+			// 2 cup = 500 ml = 250 g of flour
+			assert.InDelta(t, 0.25, costing.Lines[0].Conversion.Result, 0.0001)
+			assert.Equal(t, databaseentity.UnitBridgeDensity, costing.Lines[0].Conversion.Bridge)
+			assert.InDelta(t, 3000, costing.Lines[0].Cost, 0.0001)
+			// 4 eggs = 200 g
+			assert.InDelta(t, 0.2, costing.Lines[1].Conversion.Result, 0.0001)
+			assert.Equal(t, databaseentity.UnitBridgePieceWeight, costing.Lines[1].Conversion.Bridge)
+			assert.InDelta(t, 6000, costing.Lines[1].Cost, 0.0001)
+			// pinch of salt is not calculated
+			assert.Equal(t, databaseentity.UnitBridgeIngredient, costing.Lines[2].Conversion.Bridge)
+			assert.True(t, costing.Lines[2].SkipCalculate)
+			assert.Zero(t, costing.Lines[2].Cost)
+		}
+		assert.InDelta(t, 9000, costing.RawMaterialCosts, 0.0001)
+		assert.InDelta(t, 15000, costing.HPP(), 0.0001)
+		assert.InDelta(t, 1875, costing.HPPPerPortion(), 0.0001)
+	})
+
+	t.Run("not convertible", func(t *testing.T) {
+		lines := []*databaseentity.RecipeIngredient{
+			{Serial: "RCI01", IngredientID: &salt.ID, Ingredient: salt, UnitID: cup.ID, Unit: cup, Quantity: 1},
+		}
+		recipeRepo.EXPECT().GetRecipeBySerial(ctx, "CAKE").Return(recipe, nil).Times(1)
+		recipeRepo.EXPECT().GetRecipeIngredients(ctx, recipe).Return(lines, nil).Times(1)
+		recipeRepo.EXPECT().GetIngredientConversions(ctx, []int{salt.ID}).Return(nil, nil).Times(1)
+
+		_, err := usecase.GetCosting(ctx, "CAKE")
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, herr.Code)
+		assert.Equal(t, "cannot convert `cup` of ingredient `Salt` into `g`", herr.Message)
+	})
+
+	t.Run("recipe not found", func(t *testing.T) {
+		recipeRepo.EXPECT().GetRecipeBySerial(ctx, "PIE").Return(nil, &echo.HTTPError{
+			Code:     http.StatusNotFound,
+			Internal: entity.NewInternalError(entity.RecipeNotFound),
+		}).Times(1)
+
+		_, err := usecase.GetCosting(ctx, "PIE")
+		assert.NotNil(t, err)
+	})
+}
+
+func Test_Scale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	usecase, recipeRepo, _, unitRepo := initLineUsecase(ctrl, nil)
+	ctx := context.Background()
+	kg := &databaseentity.Unit{ID: 1, Name: "kg", Dimension: databaseentity.UnitDimensionMass, Factor: 1000}
+	g := &databaseentity.Unit{ID: 2, Name: "g", Dimension: databaseentity.UnitDimensionMass, Factor: 1}
+	butir := &databaseentity.Unit{ID: 4, Name: "butir", Dimension: databaseentity.UnitDimensionCount, Factor: 1}
+	pinch := &databaseentity.Unit{ID: 5, Name: "pinch"}
+	sak := &databaseentity.Unit{ID: 6, Name: "sak"}
+	flour := &databaseentity.Ingredient{ID: 1, Serial: "FLOUR", Name: "Flour", Unit: kg, PricePerUnit: 12000, Stock: 20}
+	egg := &databaseentity.Ingredient{ID: 2, Serial: "EGG", Name: "Egg", Unit: kg, PricePerUnit: 30000, Stock: 3, PieceWeight: 50}
+	salt := &databaseentity.Ingredient{ID: 3, Serial: "SALT", Name: "Salt", Unit: g, PricePerUnit: 10, Stock: 1000}
+	recipe := &databaseentity.Recipe{ID: 1, Serial: "CAKE", Quantity: 8, LaborCosts: 5000, OverheadCosts: 1000}
+	lines := []*databaseentity.RecipeIngredient{
+		{Serial: "RCI01", IngredientID: &flour.ID, Ingredient: flour, UnitID: kg.ID, Unit: kg, Quantity: 2},
+		{Serial: "RCI02", IngredientID: &egg.ID, Ingredient: egg, UnitID: butir.ID, Unit: butir, Quantity: 4},
+		{Serial: "RCI03", IngredientID: &salt.ID, Ingredient: salt, UnitID: pinch.ID, Unit: pinch, Quantity: 1},
+		{Serial: "RCI04", IngredientID: &egg.ID, Ingredient: egg, UnitID: butir.ID, Unit: butir, Quantity: 2},
+	}
+	// 1 kg of flour = 0.04 sak, 1 g of salt = 2 pinch
+	conversions := []*databaseentity.IngredientConvertionUnit{
+		{IngredientID: flour.ID, UnitID: sak.ID, Unit: sak, Value: 0.04},
+		{IngredientID: salt.ID, UnitID: pinch.ID, Unit: pinch, Value: 2, SkipCalculate: true},
+	}
+	expectCosting := func(recipe *databaseentity.Recipe) {
+		recipeRepo.EXPECT().GetRecipeBySerial(ctx, recipe.Serial).Return(recipe, nil).Times(1)
+		recipeRepo.EXPECT().GetRecipeIngredients(ctx, recipe).Return(lines, nil).Times(1)
+		recipeRepo.EXPECT().GetIngredientConversions(ctx, []int{flour.ID, egg.ID, salt.ID}).Return(conversions, nil).Times(1)
+	}
+	// standard mass units are fetched once per scale
+	expectMassUnits := func() {
+		unitRepo.EXPECT().GetUnitsByDimension(ctx, databaseentity.UnitDimensionMass).Return([]*databaseentity.Unit{g, kg}, nil).Times(1)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		expectCosting(recipe)
+		expectMassUnits()
+
+		scale, err := usecase.Scale(ctx, &payloadentity.ScaleRecipeRequest{Serial: "CAKE", Portions: 100})
+		assert.Nil(t, err)
+		assert.InDelta(t, 12.5, scale.Factor, 0.0001)
+		if assert.Len(t, scale.Lines, 4) {
+			// 25 kg of flour is bought as 1 sak
+			assert.InDelta(t, 25, scale.Lines[0].Quantity, 0.0001)
+			assert.InDelta(t, 25, scale.Lines[0].IngredientQuantity, 0.0001)
+			assert.InDelta(t, 1, scale.Lines[0].Purchase.Result, 0.0001)
+			assert.Equal(t, "sak", scale.Lines[0].Purchase.To.Name)
+			assert.InDelta(t, 300000, scale.Lines[0].Cost, 0.0001)
+			// 50 eggs = 2.5 kg
+			assert.InDelta(t, 50, scale.Lines[1].Quantity, 0.0001)
+			assert.InDelta(t, 2.5, scale.Lines[1].Purchase.Result, 0.0001)
+			assert.Equal(t, "kg", scale.Lines[1].Purchase.To.Name)
+			// pinch is not a purchase unit
+			assert.InDelta(t, 6.25, scale.Lines[2].IngredientQuantity, 0.0001)
+			assert.Equal(t, "g", scale.Lines[2].Purchase.To.Name)
+			assert.Zero(t, scale.Lines[2].Cost)
+		}
+		assert.InDelta(t, 412500, scale.RawMaterialCosts(), 0.0001)
+		assert.InDelta(t, 487500, scale.HPP(), 0.0001)
+		// flour & egg of both lines exceed stock
+		if assert.Len(t, scale.Warnings, 2) {
+			assert.Equal(t, "FLOUR", scale.Warnings[0].Ingredient.Serial)
+			assert.InDelta(t, 5, scale.Warnings[0].Shortage(), 0.0001)
+			assert.Equal(t, "EGG", scale.Warnings[1].Ingredient.Serial)
+			assert.InDelta(t, 3.75, scale.Warnings[1].Required, 0.0001)
+			assert.InDelta(t, 0.75, scale.Warnings[1].Shortage(), 0.0001)
+		}
+	})
+
+	t.Run("small batch", func(t *testing.T) {
+		expectCosting(recipe)
+		expectMassUnits()
+
+		scale, err := usecase.Scale(ctx, &payloadentity.ScaleRecipeRequest{Serial: "CAKE", Portions: 4})
+		assert.Nil(t, err)
+		// 1 kg of flour is less than 1 sak
+		assert.InDelta(t, 1, scale.Lines[0].Purchase.Result, 0.0001)
+		assert.Equal(t, "kg", scale.Lines[0].Purchase.To.Name)
+		assert.Empty(t, scale.Warnings)
+	})
+
+	t.Run("recipe without portion", func(t *testing.T) {
+		expectCosting(&databaseentity.Recipe{ID: 2, Serial: "SAUCE"})
+
+		_, err := usecase.Scale(ctx, &payloadentity.ScaleRecipeRequest{Serial: "SAUCE", Portions: 4})
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, herr.Code)
+		assert.Equal(t, "recipe `SAUCE` has no portion to scale from", herr.Message)
+	})
+
+	t.Run("sub recipe", func(t *testing.T) {
+		f := newSubRecipeFixture()
+		recipeRepo.EXPECT().GetRecipeBySerial(ctx, "PASTA").Return(f.pasta, nil).Times(1)
+		f.expectCosting(ctx, recipeRepo)
+		expectMassUnits()
+
+		// 8 portions need 1 l of sauce, only 0.2 l in stock
+		scale, err := usecase.Scale(ctx, &payloadentity.ScaleRecipeRequest{Serial: "PASTA", Portions: 8})
+		assert.Nil(t, err)
+		if assert.Len(t, scale.Lines, 2) {
+			assert.InDelta(t, 1000, scale.Lines[1].Quantity, 0.0001)
+			assert.InDelta(t, 1, scale.Lines[1].Purchase.Result, 0.0001)
+			assert.Equal(t, "l", scale.Lines[1].Purchase.To.Name)
+		}
+		if assert.Len(t, scale.Warnings, 1) {
+			assert.Equal(t, f.sauce, scale.Warnings[0].SubRecipe)
+			assert.InDelta(t, 0.8, scale.Warnings[0].Shortage(), 0.0001)
+		}
+	})
+
+	t.Run("standard unit of ingredient unit dimension", func(t *testing.T) {
+		sugar := &databaseentity.Ingredient{ID: 7, Serial: "SUGAR", Name: "Sugar", Unit: g, PricePerUnit: 15, Stock: 5000}
+		syrup := &databaseentity.Recipe{ID: 3, Serial: "SYRUP", Quantity: 1}
+		recipeRepo.EXPECT().GetRecipeBySerial(ctx, "SYRUP").Return(syrup, nil).Times(1)
+		recipeRepo.EXPECT().GetRecipeIngredients(ctx, syrup).Return([]*databaseentity.RecipeIngredient{
+			{Serial: "RCI05", IngredientID: &sugar.ID, Ingredient: sugar, UnitID: g.ID, Unit: g, Quantity: 250},
+		}, nil).Times(1)
+		recipeRepo.EXPECT().GetIngredientConversions(ctx, []int{sugar.ID}).Return(nil, nil).Times(1)
+		expectMassUnits()
+
+		// 2500 g of sugar is bought as 2.5 kg without kg conversion unit
+		scale, err := usecase.Scale(ctx, &payloadentity.ScaleRecipeRequest{Serial: "SYRUP", Portions: 10})
+		assert.Nil(t, err)
+		if assert.Len(t, scale.Lines, 1) {
+			assert.InDelta(t, 2500, scale.Lines[0].IngredientQuantity, 0.0001)
+			assert.InDelta(t, 2.5, scale.Lines[0].Purchase.Result, 0.0001)
+			assert.Equal(t, "kg", scale.Lines[0].Purchase.To.Name)
+			assert.Equal(t, databaseentity.UnitBridgeDimension, scale.Lines[0].Purchase.Bridge)
+		}
+	})
+
+	t.Run("invalid portions", func(t *testing.T) {
+		_, err := usecase.Scale(ctx, &payloadentity.ScaleRecipeRequest{Serial: "CAKE"})
+		assert.NotNil(t, err)
+	})
+}
+
+// pasta uses 500 ml of sauce yielding 2 l, sauce costs 12000 per l
+type subRecipeFixture struct {
+	flour, tomato          *databaseentity.Ingredient
+	sauce, pasta           *databaseentity.Recipe
+	sauceLines, pastaLines []*databaseentity.RecipeIngredient
+}
+
+func newSubRecipeFixture() *subRecipeFixture {
+	kg := &databaseentity.Unit{ID: 1, Name: "kg", Dimension: databaseentity.UnitDimensionMass, Factor: 1000}
+	ml := &databaseentity.Unit{ID: 2, Name: "ml", Dimension: databaseentity.UnitDimensionVolume, Factor: 1}
+	l := &databaseentity.Unit{ID: 3, Name: "l", Dimension: databaseentity.UnitDimensionVolume, Factor: 1000}
+	result := &subRecipeFixture{
+		flour:  &databaseentity.Ingredient{ID: 1, Serial: "FLOUR", Name: "Flour", Unit: kg, PricePerUnit: 12000, Stock: 10},
+		tomato: &databaseentity.Ingredient{ID: 2, Serial: "TOMATO", Name: "Tomato", Unit: kg, PricePerUnit: 20000, Stock: 5},
+		sauce:  &databaseentity.Recipe{ID: 10, Serial: "SAUCE", Name: "Sauce", Quantity: 2, UnitID: &l.ID, Unit: l, LaborCosts: 4000, Stock: 0.2},
+		pasta:  &databaseentity.Recipe{ID: 11, Serial: "PASTA", Name: "Pasta", Quantity: 4},
+	}
+	result.sauceLines = []*databaseentity.RecipeIngredient{
+		{Serial: "RCI01", IngredientID: &result.tomato.ID, Ingredient: result.tomato, UnitID: kg.ID, Unit: kg, Quantity: 1},
+	}
+	result.pastaLines = []*databaseentity.RecipeIngredient{
+		{Serial: "RCI02", IngredientID: &result.flour.ID, Ingredient: result.flour, UnitID: kg.ID, Unit: kg, Quantity: 1},
+		{Serial: "RCI03", SubRecipeID: &result.sauce.ID, SubRecipe: result.sauce, UnitID: ml.ID, Unit: ml, Quantity: 500},
+	}
+	return result
+}
+
+func (f *subRecipeFixture) expectCosting(ctx context.Context, recipeRepo *mocks.MockRecipeRepository) {
+	recipeRepo.EXPECT().GetRecipeIngredients(ctx, f.pasta).Return(f.pastaLines, nil).Times(1)
+	recipeRepo.EXPECT().GetIngredientConversions(ctx, []int{f.flour.ID}).Return(nil, nil).Times(1)
+	recipeRepo.EXPECT().GetRecipeIngredients(ctx, f.sauce).Return(f.sauceLines, nil).Times(1)
+	recipeRepo.EXPECT().GetIngredientConversions(ctx, []int{f.tomato.ID}).Return(nil, nil).Times(1)
+}
+
+func Test_GetCostingSubRecipe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	usecase, recipeRepo := initUsecase(ctrl, nil)
+	ctx := context.Background()
+
+	t.Run("cost rolls up", func(t *testing.T) {
+		f := newSubRecipeFixture()
+		recipeRepo.EXPECT().GetRecipeBySerial(ctx, "PASTA").Return(f.pasta, nil).Times(1)
+		f.expectCosting(ctx, recipeRepo)
+
+		costing, err := usecase.GetCosting(ctx, "PASTA")
+		assert.Nil(t, err)
+		if assert.Len(t, costing.Lines, 2) {
+			line := costing.Lines[1]
+			assert.InDelta(t, 0.5, line.Conversion.Result, 0.0001)
+			assert.Equal(t, "l", line.Conversion.To.Name)
+			if assert.NotNil(t, line.SubRecipe) {
+				assert.InDelta(t, 12000, line.SubRecipe.HPPPerPortion(), 0.0001)
+			}
+			assert.InDelta(t, 6000, line.Cost, 0.0001)
+			assert.Equal(t, databaseentity.RecipeLineCostTypeIntermediateProduct, line.CostType())
+			rawMaterial, labor, overhead := line.CostShares()
+			assert.InDelta(t, 5000, rawMaterial, 0.0001)
+			assert.InDelta(t, 1000, labor, 0.0001)
+			assert.InDelta(t, 0, overhead, 0.0001)
+		}
+		assert.InDelta(t, 18000, costing.RawMaterialCosts, 0.0001)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		f := newSubRecipeFixture()
+		ml := f.pastaLines[1].Unit
+		f.sauceLines = append(f.sauceLines, &databaseentity.RecipeIngredient{Serial: "RCI04", SubRecipeID: &f.pasta.ID, SubRecipe: f.pasta, UnitID: ml.ID, Unit: ml, Quantity: 1})
+		recipeRepo.EXPECT().GetRecipeBySerial(ctx, "PASTA").Return(f.pasta, nil).Times(1)
+		f.expectCosting(ctx, recipeRepo)
+
+		_, err := usecase.GetCosting(ctx, "PASTA")
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusConflict, herr.Code)
+		assert.Equal(t, "recipe `PASTA` is used as its own sub recipe", herr.Message)
+	})
+
+	t.Run("sub recipe without yield unit", func(t *testing.T) {
+		f := newSubRecipeFixture()
+		f.sauce.UnitID = nil
+		f.sauce.Unit = nil
+		recipeRepo.EXPECT().GetRecipeBySerial(ctx, "PASTA").Return(f.pasta, nil).Times(1)
+		f.expectCosting(ctx, recipeRepo)
+
+		_, err := usecase.GetCosting(ctx, "PASTA")
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, herr.Code)
+		assert.Equal(t, "sub recipe `SAUCE` has no yield unit or quantity", herr.Message)
+	})
+}
+
+func Test_Produce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	usecase, recipeRepo := initUsecase(ctrl, nil)
+	ctx := context.Background()
+	author := &databaseentity.User{ID: 7}
+
+	t.Run("sub recipe stock consumed first", func(t *testing.T) {
+		f := newSubRecipeFixture()
+		recipeRepo.EXPECT().GetRecipeBySerial(ctx, "PASTA").Return(f.pasta, nil).Times(1)
+		f.expectCosting(ctx, recipeRepo)
+		recipeRepo.EXPECT().Produce(ctx, gomock.Any(), author.ID).Return(nil).Times(1)
+
+		// 8 portions need 1 l of sauce, 0.2 l in stock & 0.8 l is made from 0.4 kg of tomato
+		production, err := usecase.Produce(ctx, &payloadentity.ProduceRecipePayload{Serial: "PASTA", Quantity: 8}, author)
+		assert.Nil(t, err)
+		if assert.Len(t, production.Ingredients, 2) {
+			assert.Equal(t, f.flour, production.Ingredients[0].Ingredient)
+			assert.InDelta(t, 2, production.Ingredients[0].Quantity, 0.0001)
+			assert.Equal(t, f.tomato, production.Ingredients[1].Ingredient)
+			assert.InDelta(t, 0.4, production.Ingredients[1].Quantity, 0.0001)
+		}
+		if assert.Len(t, production.SubRecipes, 1) {
+			assert.Equal(t, f.sauce, production.SubRecipes[0].Recipe)
+			assert.InDelta(t, 0.2, production.SubRecipes[0].Quantity, 0.0001)
+		}
+	})
+
+	t.Run("sub recipe in stock", func(t *testing.T) {
+		f := newSubRecipeFixture()
+		f.sauce.Stock = 5
+		recipeRepo.EXPECT().GetRecipeBySerial(ctx, "PASTA").Return(f.pasta, nil).Times(1)
+		f.expectCosting(ctx, recipeRepo)
+		recipeRepo.EXPECT().Produce(ctx, gomock.Any(), author.ID).Return(nil).Times(1)
+
+		production, err := usecase.Produce(ctx, &payloadentity.ProduceRecipePayload{Serial: "PASTA", Quantity: 8}, author)
+		assert.Nil(t, err)
+		assert.Len(t, production.Ingredients, 1)
+		if assert.Len(t, production.SubRecipes, 1) {
+			assert.InDelta(t, 1, production.SubRecipes[0].Quantity, 0.0001)
+		}
+	})
+
+	t.Run("ingredient stock not enough", func(t *testing.T) {
+		f := newSubRecipeFixture()
+		f.flour.Stock = 1
+		recipeRepo.EXPECT().GetRecipeBySerial(ctx, "PASTA").Return(f.pasta, nil).Times(1)
+		f.expectCosting(ctx, recipeRepo)
+		recipeRepo.EXPECT().Produce(ctx, gomock.Any(), author.ID).Return(&echo.HTTPError{
+			Code:     http.StatusConflict,
+			Message:  "stock of ingredient `Flour` is not enough, 1 kg more is needed",
+			Internal: entity.NewInternalError(entity.ProduceStockNotEnough, "stock of ingredient `Flour` is not enough, 1 kg more is needed"),
+		}).Times(1)
+
+		_, err := usecase.Produce(ctx, &payloadentity.ProduceRecipePayload{Serial: "PASTA", Quantity: 8}, author)
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusConflict, herr.Code)
+		assert.Equal(t, "stock of ingredient `Flour` is not enough, 1 kg more is needed", herr.Message)
+	})
+
+	t.Run("invalid quantity", func(t *testing.T) {
+		_, err := usecase.Produce(ctx, &payloadentity.ProduceRecipePayload{Serial: "PASTA"}, author)
+		assert.NotNil(t, err)
+	})
+}
+
+func Test_RefreshCost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	usecase, recipeRepo := initUsecase(ctrl, nil)
+	ctx := context.Background()
+	author := &databaseentity.User{ID: 7}
+
+	t.Run("ripple up to parent recipes", func(t *testing.T) {
+		f := newSubRecipeFixture()
+		// pasta cost is already up to date, so it is not saved
+		f.pasta.RawMaterialCosts = 18000
+		recipeRepo.EXPECT().GetRecipeBySerial(ctx, "SAUCE").Return(f.sauce, nil).Times(1)
+		f.expectCosting(ctx, recipeRepo)
+		recipeRepo.EXPECT().UpdateRawMaterialCosts(ctx, []*databaseentity.Recipe{f.sauce}).Return(nil).Times(1)
+		recipeRepo.EXPECT().GetParentRecipes(ctx, f.sauce).Return([]*databaseentity.Recipe{f.pasta}, nil).Times(1)
+		recipeRepo.EXPECT().GetParentRecipes(ctx, f.pasta).Return(nil, nil).Times(1)
+
+		costings, err := usecase.RefreshCost(ctx, &payloadentity.RefreshRecipeCostPayload{Serial: "SAUCE"}, author)
+		assert.Nil(t, err)
+		if assert.Len(t, costings, 2) {
+			assert.Equal(t, f.sauce, costings[0].Recipe)
+			assert.Equal(t, f.pasta, costings[1].Recipe)
+		}
+		assert.Equal(t, float32(20000), f.sauce.RawMaterialCosts)
+		assert.Equal(t, author.ID, f.sauce.UpdatedBy)
+	})
+
+	t.Run("not convertible", func(t *testing.T) {
+		f := newSubRecipeFixture()
+		f.sauceLines[0].Unit = f.pastaLines[1].Unit
+		recipeRepo.EXPECT().GetRecipeBySerial(ctx, "SAUCE").Return(f.sauce, nil).Times(1)
+		recipeRepo.EXPECT().GetRecipeIngredients(ctx, f.sauce).Return(f.sauceLines, nil).Times(1)
+		recipeRepo.EXPECT().GetIngredientConversions(ctx, []int{f.tomato.ID}).Return(nil, nil).Times(1)
+
+		_, err := usecase.RefreshCost(ctx, &payloadentity.RefreshRecipeCostPayload{Serial: "SAUCE"}, author)
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, herr.Code)
+	})
+
+	t.Run("empty serial", func(t *testing.T) {
+		_, err := usecase.RefreshCost(ctx, &payloadentity.RefreshRecipeCostPayload{}, author)
+		assert.NotNil(t, err)
+	})
+}
+
+func Test_CreateLine(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	usecase, recipeRepo, ingredientRepo, unitRepo := initLineUsecase(ctrl, nil)
+	ctx := context.Background()
+	author := &databaseentity.User{ID: 7}
+
+	t.Run("ingredient line", func(t *testing.T) {
+		f := newSubRecipeFixture()
+		kg := f.pastaLines[0].Unit
+		recipeRepo.EXPECT().GetRecipeBySerial(ctx, "PASTA").Return(f.pasta, nil).Times(1)
+		unitRepo.EXPECT().GetUnitByName(ctx, "kg").Return(kg, nil).Times(1)
+		ingredientRepo.EXPECT().GetIngredientBySerial(ctx, "TOMATO").Return(f.tomato, nil).Times(1)
+		recipeRepo.EXPECT().GetIngredientConversions(ctx, []int{f.tomato.ID}).Return(nil, nil).Times(1)
+		recipeRepo.EXPECT().CreateRecipeIngredient(ctx, gomock.Any()).Return(nil).Times(1)
+		// saved line is not returned by mocked repository, so only existing lines are costed
+		f.expectCosting(ctx, recipeRepo)
+		recipeRepo.EXPECT().UpdateRawMaterialCosts(ctx, []*databaseentity.Recipe{f.pasta}).Return(nil).Times(1)
+		recipeRepo.EXPECT().GetParentRecipes(ctx, f.pasta).Return(nil, nil).Times(1)
+
+		line, err := usecase.CreateLine(ctx, &payloadentity.CreateRecipeIngredientPayload{
+			Serial:           "PASTA",
+			IngredientSerial: "TOMATO",
+			Unit:             "kg",
+			Quantity:         0.5,
+		}, author)
+		assert.Nil(t, err)
+		assert.Len(t, line.Serial, 11)
+		assert.Equal(t, f.pasta.ID, line.RecipeID)
+		assert.Equal(t, &f.tomato.ID, line.IngredientID)
+		assert.Nil(t, line.SubRecipeID)
+		assert.Equal(t, kg.ID, line.UnitID)
+		assert.Equal(t, float32(0.5), line.Quantity)
+		assert.Equal(t, author.ID, line.CreatedBy)
+		assert.Equal(t, float32(18000), f.pasta.RawMaterialCosts)
+	})
+
+	t.Run("self reference", func(t *testing.T) {
+		f := newSubRecipeFixture()
+		recipeRepo.EXPECT().GetRecipeBySerial(ctx, "SAUCE").Return(f.sauce, nil).Times(2)
+		unitRepo.EXPECT().GetUnitByName(ctx, "l").Return(f.sauce.Unit, nil).Times(1)
+		recipeRepo.EXPECT().CreateRecipeIngredient(ctx, gomock.Any()).Return(&echo.HTTPError{
+			Code:     http.StatusConflict,
+			Internal: entity.NewInternalError(entity.SubRecipeCycle, entity.SubRecipeCycleMessage),
+		}).Times(1)
+
+		_, err := usecase.CreateLine(ctx, &payloadentity.CreateRecipeIngredientPayload{
+			Serial:          "SAUCE",
+			SubRecipeSerial: "SAUCE",
+			Unit:            "l",
+			Quantity:        1,
+		}, author)
+		herr, ok := err.(*echo.HTTPError)
+		if assert.True(t, ok) {
+			assert.Equal(t, http.StatusConflict, herr.Code)
+			assert.Equal(t, entity.SubRecipeCycle, herr.Internal.(*entity.InternalError).Code)
+		}
+	})
+
+	t.Run("costs not refreshed", func(t *testing.T) {
+		f := newSubRecipeFixture()
+		kg := f.pastaLines[0].Unit
+		recipeRepo.EXPECT().GetRecipeBySerial(ctx, "PASTA").Return(f.pasta, nil).Times(1)
+		unitRepo.EXPECT().GetUnitByName(ctx, "kg").Return(kg, nil).Times(1)
+		ingredientRepo.EXPECT().GetIngredientBySerial(ctx, "TOMATO").Return(f.tomato, nil).Times(1)
+		recipeRepo.EXPECT().GetIngredientConversions(ctx, []int{f.tomato.ID}).Return(nil, nil).Times(1)
+		recipeRepo.EXPECT().CreateRecipeIngredient(ctx, gomock.Any()).Return(nil).Times(1)
+		recipeRepo.EXPECT().GetRecipeIngredients(ctx, f.pasta).Return(nil, &echo.HTTPError{Code: http.StatusInternalServerError}).Times(1)
+
+		// saved line is returned, costs are saved again by refresh cost
+		line, err := usecase.CreateLine(ctx, &payloadentity.CreateRecipeIngredientPayload{
+			Serial:           "PASTA",
+			IngredientSerial: "TOMATO",
+			Unit:             "kg",
+			Quantity:         0.5,
+		}, author)
+		assert.Nil(t, err)
+		assert.Equal(t, f.pasta.ID, line.RecipeID)
+		assert.Equal(t, float32(0), f.pasta.RawMaterialCosts)
+	})
+
+	t.Run("sub recipe without yield", func(t *testing.T) {
+		f := newSubRecipeFixture()
+		lasagna := &databaseentity.Recipe{ID: 12, Serial: "LASAGNA", Quantity: 6}
+		recipeRepo.EXPECT().GetRecipeBySerial(ctx, "LASAGNA").Return(lasagna, nil).Times(1)
+		recipeRepo.EXPECT().GetRecipeBySerial(ctx, "PASTA").Return(f.pasta, nil).Times(1)
+		unitRepo.EXPECT().GetUnitByName(ctx, "kg").Return(f.pastaLines[0].Unit, nil).Times(1)
+
+		_, err := usecase.CreateLine(ctx, &payloadentity.CreateRecipeIngredientPayload{
+			Serial:          "LASAGNA",
+			SubRecipeSerial: "PASTA",
+			Unit:            "kg",
+			Quantity:        1,
+		}, author)
+		herr, ok := err.(*echo.HTTPError)
+		if assert.True(t, ok) {
+			assert.Equal(t, http.StatusBadRequest, herr.Code)
+			assert.Equal(t, entity.SubRecipeWithoutYield, herr.Internal.(*entity.InternalError).Code)
+		}
+	})
+
+	t.Run("ingredient & sub recipe", func(t *testing.T) {
+		_, err := usecase.CreateLine(ctx, &payloadentity.CreateRecipeIngredientPayload{
+			Serial:           "PASTA",
+			IngredientSerial: "TOMATO",
+			SubRecipeSerial:  "SAUCE",
+			Unit:             "kg",
+			Quantity:         1,
+		}, author)
+		herr, ok := err.(*echo.HTTPError)
+		if assert.True(t, ok) {
+			assert.Equal(t, http.StatusBadRequest, herr.Code)
+		}
+	})
+}
+
+func Test_UpdateLine(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	usecase, recipeRepo, _, unitRepo := initLineUsecase(ctrl, nil)
+	ctx := context.Background()
+	author := &databaseentity.User{ID: 7}
+
+	t.Run("switch to sub recipe", func(t *testing.T) {
+		f := newSubRecipeFixture()
+		kg := f.pastaLines[0].Unit
+		ml := f.pastaLines[1].Unit
+		line := &databaseentity.RecipeIngredient{ID: 9, Serial: "RCI09", RecipeID: f.pasta.ID, Recipe: f.pasta,
+			IngredientID: &f.flour.ID, Ingredient: f.flour, UnitID: kg.ID, Unit: kg, Quantity: 1}
+		recipeRepo.EXPECT().GetRecipeIngredientBySerial(ctx, "RCI09").Return(line, nil).Times(1)
+		unitRepo.EXPECT().GetUnitByName(ctx, "ml").Return(ml, nil).Times(1)
+		recipeRepo.EXPECT().GetRecipeBySerial(ctx, "SAUCE").Return(f.sauce, nil).Times(1)
+		recipeRepo.EXPECT().UpdateRecipeIngredient(ctx, line).Return(nil).Times(1)
+		f.expectCosting(ctx, recipeRepo)
+		recipeRepo.EXPECT().UpdateRawMaterialCosts(ctx, []*databaseentity.Recipe{f.pasta}).Return(nil).Times(1)
+		recipeRepo.EXPECT().GetParentRecipes(ctx, f.pasta).Return(nil, nil).Times(1)
+
+		result, err := usecase.UpdateLine(ctx, &payloadentity.UpdateRecipeIngredientPayload{
+			Serial:          "RCI09",
+			SubRecipeSerial: "SAUCE",
+			Unit:            "ml",
+			Quantity:        250,
+		}, author)
+		assert.Nil(t, err)
+		assert.Nil(t, result.IngredientID)
+		assert.Nil(t, result.Ingredient)
+		assert.Equal(t, &f.sauce.ID, result.SubRecipeID)
+		assert.Equal(t, ml.ID, result.UnitID)
+		assert.Equal(t, float32(250), result.Quantity)
+		assert.Equal(t, author.ID, result.UpdatedBy)
+	})
+
+	t.Run("self reference", func(t *testing.T) {
+		f := newSubRecipeFixture()
+		line := f.sauceLines[0]
+		line.Recipe = f.sauce
+		recipeRepo.EXPECT().GetRecipeIngredientBySerial(ctx, line.Serial).Return(line, nil).Times(1)
+		unitRepo.EXPECT().GetUnitByName(ctx, "l").Return(f.sauce.Unit, nil).Times(1)
+		recipeRepo.EXPECT().GetRecipeBySerial(ctx, "SAUCE").Return(f.sauce, nil).Times(1)
+		recipeRepo.EXPECT().UpdateRecipeIngredient(ctx, line).Return(&echo.HTTPError{
+			Code:     http.StatusConflict,
+			Internal: entity.NewInternalError(entity.SubRecipeCycle, entity.SubRecipeCycleMessage),
+		}).Times(1)
+
+		_, err := usecase.UpdateLine(ctx, &payloadentity.UpdateRecipeIngredientPayload{
+			Serial:          line.Serial,
+			SubRecipeSerial: "SAUCE",
+			Unit:            "l",
+			Quantity:        1,
+		}, author)
+		herr, ok := err.(*echo.HTTPError)
+		if assert.True(t, ok) {
+			assert.Equal(t, http.StatusConflict, herr.Code)
+			assert.Equal(t, entity.SubRecipeCycle, herr.Internal.(*entity.InternalError).Code)
+		}
+	})
+
+	t.Run("line not found", func(t *testing.T) {
+		recipeRepo.EXPECT().GetRecipeIngredientBySerial(ctx, "RCI99").Return(nil, &echo.HTTPError{Code: http.StatusNotFound}).Times(1)
+
+		_, err := usecase.UpdateLine(ctx, &payloadentity.UpdateRecipeIngredientPayload{
+			Serial:           "RCI99",
+			IngredientSerial: "TOMATO",
+			Unit:             "kg",
+			Quantity:         1,
+		}, author)
+		herr, ok := err.(*echo.HTTPError)
+		if assert.True(t, ok) {
+			assert.Equal(t, http.StatusNotFound, herr.Code)
+		}
+	})
+}
+
+func Test_RefreshCostByIngredient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	usecase, recipeRepo := initUsecase(ctrl, nil)
+	ctx := context.Background()
+	author := &databaseentity.User{ID: 7}
+
+	t.Run("every changed cost saved at once", func(t *testing.T) {
+		f := newSubRecipeFixture()
+		recipeRepo.EXPECT().GetRecipesByIngredient(ctx, f.tomato).Return([]*databaseentity.Recipe{f.sauce}, nil).Times(1)
+		f.expectCosting(ctx, recipeRepo)
+		recipeRepo.EXPECT().GetParentRecipes(ctx, f.sauce).Return([]*databaseentity.Recipe{f.pasta}, nil).Times(1)
+		recipeRepo.EXPECT().GetParentRecipes(ctx, f.pasta).Return(nil, nil).Times(1)
+		recipeRepo.EXPECT().UpdateRawMaterialCosts(ctx, []*databaseentity.Recipe{f.sauce, f.pasta}).Return(nil).Times(1)
+
+		assert.Nil(t, usecase.RefreshCostByIngredient(ctx, f.tomato, author))
+		assert.Equal(t, float32(20000), f.sauce.RawMaterialCosts)
+		assert.Equal(t, float32(18000), f.pasta.RawMaterialCosts)
+		assert.Equal(t, author.ID, f.pasta.UpdatedBy)
+	})
+
+	t.Run("recipe not convertible is skipped", func(t *testing.T) {
+		f := newSubRecipeFixture()
+		f.sauceLines[0].Unit = f.pastaLines[1].Unit
+		recipeRepo.EXPECT().GetRecipesByIngredient(ctx, f.tomato).Return([]*databaseentity.Recipe{f.sauce}, nil).Times(1)
+		recipeRepo.EXPECT().GetRecipeIngredients(ctx, f.sauce).Return(f.sauceLines, nil).Times(1)
+		recipeRepo.EXPECT().GetIngredientConversions(ctx, []int{f.tomato.ID}).Return(nil, nil).Times(1)
+
+		assert.Nil(t, usecase.RefreshCostByIngredient(ctx, f.tomato, author))
+		assert.Zero(t, f.sauce.RawMaterialCosts)
+	})
+
+	t.Run("failed save", func(t *testing.T) {
+		f := newSubRecipeFixture()
+		f.pasta.RawMaterialCosts = 18000
+		recipeRepo.EXPECT().GetRecipesByIngredient(ctx, f.tomato).Return([]*databaseentity.Recipe{f.sauce}, nil).Times(1)
+		f.expectCosting(ctx, recipeRepo)
+		recipeRepo.EXPECT().GetParentRecipes(ctx, f.sauce).Return([]*databaseentity.Recipe{f.pasta}, nil).Times(1)
+		recipeRepo.EXPECT().GetParentRecipes(ctx, f.pasta).Return(nil, nil).Times(1)
+		recipeRepo.EXPECT().UpdateRawMaterialCosts(ctx, []*databaseentity.Recipe{f.sauce}).Return(&echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Internal: entity.NewInternalError(entity.RecipeRepoUpdateRawMaterialCostsError),
+		}).Times(1)
+
+		err := usecase.RefreshCostByIngredient(ctx, f.tomato, author)
+		assert.NotNil(t, err)
+	})
+}
+
+func Test_RefreshCostByUnit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	usecase, recipeRepo := initUsecase(ctrl, nil)
+	ctx := context.Background()
+	author := &databaseentity.User{ID: 7}
+	f := newSubRecipeFixture()
+	f.pasta.RawMaterialCosts = 18000
+	recipeRepo.EXPECT().GetRecipesByUnit(ctx, f.sauce.Unit).Return([]*databaseentity.Recipe{f.sauce, f.pasta}, nil).Times(1)
+	f.expectCosting(ctx, recipeRepo)
+	recipeRepo.EXPECT().GetParentRecipes(ctx, f.sauce).Return([]*databaseentity.Recipe{f.pasta}, nil).Times(1)
+	recipeRepo.EXPECT().GetParentRecipes(ctx, f.pasta).Return(nil, nil).Times(1)
+	recipeRepo.EXPECT().UpdateRawMaterialCosts(ctx, []*databaseentity.Recipe{f.sauce}).Return(nil).Times(1)
+
+	assert.Nil(t, usecase.RefreshCostByUnit(ctx, f.sauce.Unit, author))
+}
+
+func Test_CostRefreshingUnitUsecase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	unitRepo := mocks.NewMockUnitRepository(ctrl)
+	recipeUsecase := usecasemocks.NewMockRecipeUsecase(ctrl)
+	usecase := recipeusecase.NewCostRefreshingUnitUsecase(unitusecase.NewUsecase(nil, unitRepo), recipeUsecase)
+	ctx := context.Background()
+	author := &databaseentity.User{ID: 7}
+	kg := &databaseentity.Unit{ID: 1, Name: "Kg"}
+	kilogram := &databaseentity.Unit{ID: 2, Name: "Kilogram"}
+
+	t.Run("merged", func(t *testing.T) {
+		unitRepo.EXPECT().GetUnitByName(ctx, "Kg").Return(kg, nil).Times(1)
+		unitRepo.EXPECT().GetUnitByName(ctx, "Kilogram").Return(kilogram, nil).Times(1)
+		unitRepo.EXPECT().Merge(ctx, kg, kilogram, author.ID).Return(nil).Times(1)
+		recipeUsecase.EXPECT().RefreshCostByUnit(ctx, kilogram, author).Return(nil).Times(1)
+
+		unit, err := usecase.Merge(ctx, &payloadentity.MergeUnitPayload{Name: "Kg", TargetName: "Kilogram"}, author)
+		assert.Nil(t, err)
+		assert.Equal(t, kilogram, unit)
+	})
+
+	t.Run("not merged", func(t *testing.T) {
+		unitRepo.EXPECT().GetUnitByName(ctx, "Kg").Return(kg, nil).Times(1)
+		unitRepo.EXPECT().GetUnitByName(ctx, "Kilogram").Return(kilogram, nil).Times(1)
+		unitRepo.EXPECT().Merge(ctx, kg, kilogram, author.ID).Return(&echo.HTTPError{Code: http.StatusConflict}).Times(1)
+
+		_, err := usecase.Merge(ctx, &payloadentity.MergeUnitPayload{Name: "Kg", TargetName: "Kilogram"}, author)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("other methods untouched", func(t *testing.T) {
+		unitRepo.EXPECT().GetUnitByName(ctx, "Kg").Return(kg, nil).Times(1)
+		unitRepo.EXPECT().GetUnitUsage(ctx, kg).Return(&databaseentity.UnitUsage{}, nil).Times(1)
+
+		unit, _, err := usecase.GetUnitDetail(ctx, "Kg")
+		assert.Nil(t, err)
+		assert.Equal(t, kg, unit)
+	})
+}
+
+func Test_CostRefreshingIngredientUsecase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ingredientRepo := mocks.NewMockIngredientRepository(ctrl)
+	recipeUsecase := usecasemocks.NewMockRecipeUsecase(ctrl)
+	usecase := recipeusecase.NewCostRefreshingIngredientUsecase(ingredientusecase.NewUsecase(nil, ingredientRepo), recipeUsecase)
+	ctx := context.Background()
+	author := &databaseentity.User{ID: 7}
+
+	t.Run("weight changed", func(t *testing.T) {
+		egg := &databaseentity.Ingredient{ID: 1, Serial: "EGG"}
+		ingredientRepo.EXPECT().GetIngredientBySerial(ctx, "EGG").Return(egg, nil).Times(1)
+		ingredientRepo.EXPECT().Update(ctx, egg).Return(nil).Times(1)
+		recipeUsecase.EXPECT().RefreshCostByIngredient(ctx, egg, author).Return(nil).Times(1)
+
+		result, err := usecase.SetWeight(ctx, &payloadentity.SetIngredientWeightPayload{Serial: "EGG", PieceWeight: 60}, author)
+		assert.Nil(t, err)
+		assert.Equal(t, float64(60), result.PieceWeight)
+	})
+
+	t.Run("failed refresh", func(t *testing.T) {
+		egg := &databaseentity.Ingredient{ID: 1, Serial: "EGG"}
+		ingredientRepo.EXPECT().GetIngredientBySerial(ctx, "EGG").Return(egg, nil).Times(1)
+		ingredientRepo.EXPECT().Update(ctx, egg).Return(nil).Times(1)
+		recipeUsecase.EXPECT().RefreshCostByIngredient(ctx, egg, author).Return(&echo.HTTPError{Code: http.StatusInternalServerError}).Times(1)
+
+		_, err := usecase.SetWeight(ctx, &payloadentity.SetIngredientWeightPayload{Serial: "EGG", PieceWeight: 60}, author)
+		assert.NotNil(t, err)
+	})
+}
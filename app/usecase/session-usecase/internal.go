@@ -28,10 +28,10 @@ type sessionError struct {
 func (uc *usecase) initSession(r *http.Request) *sessions.Session {
 	sess, err := uc.store.Get(r, sessionID)
 	if err != nil {
-		entity.InitLog(r.RequestURI, r.Method, "get session", http.StatusUnauthorized, &echo.HTTPError{
+		entity.InitLog(r.Context(), r.RequestURI, r.Method, "get session", http.StatusUnauthorized, &echo.HTTPError{
 			Code:    http.StatusBadRequest,
 			Message: err,
-		}, uc.cfg.LogMode(), uc.cfg.EnableWarnFileLog()).Log()
+		}).Log()
 		sess, _ = uc.store.New(r, sessionID)
 	}
 	return sess
@@ -91,13 +91,12 @@ func (uc *usecase) SetError(e echo.Context, theError error) error {
 
 	// set error log
 	entity.InitLog(
+		e.Request().Context(),
 		e.Request().RequestURI,
 		e.Request().Method,
 		"session",
 		herr.Code,
 		herr,
-		uc.cfg.LogMode(),
-		uc.cfg.EnableWarnFileLog(),
 	).Log()
 
 	// get or set session
@@ -244,12 +243,12 @@ func (uc *usecase) GetPrevRoute(e echo.Context) (method string, path string) {
 	// check session assertion
 	strJSON, ok := prev.([]byte)
 	if !ok {
-		entity.InitLog(r.RequestURI, r.Method, "get session", http.StatusInternalServerError, &echo.HTTPError{
+		entity.InitLog(r.Context(), r.RequestURI, r.Method, "get session", http.StatusInternalServerError, &echo.HTTPError{
 			Code:    http.StatusInternalServerError,
 			Message: http.StatusText(http.StatusInternalServerError),
 			Internal: entity.NewInternalError(entity.SessionUsecaseGetInfoError,
 				fmt.Sprintf("session error conversion is %T, not []byte", prev)),
-		}, uc.cfg.LogMode(), uc.cfg.EnableWarnFileLog()).Log()
+		}).Log()
 		return
 	}
 
@@ -257,11 +256,11 @@ func (uc *usecase) GetPrevRoute(e echo.Context) (method string, path string) {
 	delete(sess.Values, prevRouteKey)
 	err := sess.Save(r, e.Response())
 	if err != nil {
-		entity.InitLog(r.RequestURI, r.Method, "get session", http.StatusInternalServerError, &echo.HTTPError{
+		entity.InitLog(r.Context(), r.RequestURI, r.Method, "get session", http.StatusInternalServerError, &echo.HTTPError{
 			Code:     http.StatusInternalServerError,
 			Message:  http.StatusText(http.StatusInternalServerError),
 			Internal: entity.NewInternalError(entity.SessionUsecaseGetInfoError, err.Error()),
-		}, uc.cfg.LogMode(), uc.cfg.EnableWarnFileLog()).Log()
+		}).Log()
 		return
 	}
 
@@ -269,11 +268,11 @@ func (uc *usecase) GetPrevRoute(e echo.Context) (method string, path string) {
 	var result map[string]string
 	err = json.Unmarshal(strJSON, &result)
 	if err != nil {
-		entity.InitLog(r.RequestURI, r.Method, "get session", http.StatusInternalServerError, &echo.HTTPError{
+		entity.InitLog(r.Context(), r.RequestURI, r.Method, "get session", http.StatusInternalServerError, &echo.HTTPError{
 			Code:     http.StatusInternalServerError,
 			Message:  http.StatusText(http.StatusInternalServerError),
 			Internal: entity.NewInternalError(entity.SessionUsecaseGetInfoError, err.Error()),
-		}, uc.cfg.LogMode(), uc.cfg.EnableWarnFileLog()).Log()
+		}).Log()
 		return
 	}
 	method = result[prevRouteMapMethod]
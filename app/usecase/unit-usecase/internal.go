@@ -0,0 +1,97 @@
+package unitusecase
+
+import (
+	databaseentity "rap-c/app/entity/database-entity"
+	"strings"
+)
+
+// quantity of other unit in one unit, when both are the same unit or standard units of the same dimension
+func rate(unit *databaseentity.Unit, other *databaseentity.Unit) (float64, string, bool) {
+	if unit.ID == other.ID {
+		return 1, databaseentity.UnitBridgeSame, true
+	}
+	if unit.SameDimension(other) {
+		return unit.Factor / other.Factor, databaseentity.UnitBridgeDimension, true
+	}
+	return 0, "", false
+}
+
+// quantity of ingredient unit in one unit & ingredient bridges used.
+// one ingredient unit equals conversion value of conversion unit, conversions are preferred over ingredient weight
+func ingredientRate(unit *databaseentity.Unit, ingredient *databaseentity.Ingredient, conversions []*databaseentity.IngredientConvertionUnit) (float64, []string, bool) {
+	if result, _, ok := rate(unit, ingredient.Unit); ok {
+		return result, nil, true
+	}
+	for _, conversion := range conversions {
+		if conversion.Unit == nil || conversion.Value <= 0 {
+			continue
+		}
+		if result, _, ok := rate(unit, conversion.Unit); ok {
+			return result / float64(conversion.Value), []string{databaseentity.UnitBridgeIngredient}, true
+		}
+	}
+
+	// both in gram
+	unitGram, unitBridges, ok := gramRate(unit, ingredient)
+	if !ok {
+		return 0, nil, false
+	}
+	ingredientGram, ingredientBridges, ok := ingredientGramRate(ingredient, conversions)
+	if !ok {
+		return 0, nil, false
+	}
+	return unitGram / ingredientGram, append(unitBridges, ingredientBridges...), true
+}
+
+// gram in one standard unit, volume needs ingredient density & count needs ingredient piece weight
+func gramRate(unit *databaseentity.Unit, ingredient *databaseentity.Ingredient) (float64, []string, bool) {
+	if !unit.IsStandard() {
+		return 0, nil, false
+	}
+	switch unit.Dimension {
+	case databaseentity.UnitDimensionMass:
+		return unit.Factor, nil, true
+	case databaseentity.UnitDimensionVolume:
+		if ingredient.Density > 0 {
+			return unit.Factor * ingredient.Density, []string{databaseentity.UnitBridgeDensity}, true
+		}
+	case databaseentity.UnitDimensionCount:
+		if ingredient.PieceWeight > 0 {
+			return unit.Factor * ingredient.PieceWeight, []string{databaseentity.UnitBridgePieceWeight}, true
+		}
+	}
+	return 0, nil, false
+}
+
+// gram in one ingredient unit, through conversion when ingredient unit is custom unit
+func ingredientGramRate(ingredient *databaseentity.Ingredient, conversions []*databaseentity.IngredientConvertionUnit) (float64, []string, bool) {
+	if result, bridges, ok := gramRate(ingredient.Unit, ingredient); ok {
+		return result, bridges, true
+	}
+	for _, conversion := range conversions {
+		if conversion.Unit == nil || conversion.Value <= 0 {
+			continue
+		}
+		if result, bridges, ok := gramRate(conversion.Unit, ingredient); ok {
+			return result * float64(conversion.Value), append([]string{databaseentity.UnitBridgeIngredient}, bridges...), true
+		}
+	}
+	return 0, nil, false
+}
+
+// every bridge once, in the same order
+func joinBridges(bridges []string) string {
+	var result []string
+	for _, bridge := range []string{databaseentity.UnitBridgeIngredient, databaseentity.UnitBridgeDensity, databaseentity.UnitBridgePieceWeight} {
+		for _, used := range bridges {
+			if used == bridge {
+				result = append(result, bridge)
+				break
+			}
+		}
+	}
+	if len(result) == 0 {
+		return databaseentity.UnitBridgeIngredient
+	}
+	return strings.Join(result, "+")
+}
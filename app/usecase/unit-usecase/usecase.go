@@ -2,12 +2,16 @@ package unitusecase
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"rap-c/app/entity"
 	databaseentity "rap-c/app/entity/database-entity"
 	payloadentity "rap-c/app/entity/payload-entity"
 	"rap-c/app/repository/contract"
 	usecasecontract "rap-c/app/usecase/contract"
 	"rap-c/config"
+
+	"github.com/labstack/echo/v4"
 )
 
 func NewUsecase(cfg *config.Config, unitRepo contract.UnitRepository) usecasecontract.UnitUsecase {
@@ -29,6 +33,8 @@ func (uc *usecase) Create(ctx context.Context, payload *payloadentity.CreateUnit
 	// create unit
 	unit := databaseentity.Unit{
 		Name:      payload.Name,
+		Dimension: payload.Dimension,
+		Factor:    payload.Factor,
 		CreatedBy: author.ID,
 	}
 	err = uc.unitRepo.Create(ctx, &unit)
@@ -65,3 +71,144 @@ func (uc *usecase) Delete(ctx context.Context, payload *payloadentity.DeleteUnit
 	// delete unit
 	return uc.unitRepo.Delete(ctx, unit)
 }
+
+func (uc *usecase) GetUnitDetail(ctx context.Context, name string) (*databaseentity.Unit, *databaseentity.UnitUsage, error) {
+	unit, err := uc.unitRepo.GetUnitByName(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	usage, err := uc.unitRepo.GetUnitUsage(ctx, unit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return unit, usage, nil
+}
+
+func (uc *usecase) Rename(ctx context.Context, payload *payloadentity.RenameUnitPayload) (*databaseentity.Unit, error) {
+	// validate payload
+	err := entity.InitValidator().Validate(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	// get unit
+	unit, err := uc.unitRepo.GetUnitByName(ctx, payload.Name)
+	if err != nil {
+		return nil, err
+	}
+	if unit.Name == payload.NewName {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusConflict,
+			Message:  entity.UpdateUnitNoChangeMessage,
+			Internal: entity.NewInternalError(entity.UpdateUnitNoChange, entity.UpdateUnitNoChangeMessage),
+		}
+	}
+
+	// rename unit, references use unit id so they follow the new name
+	unit.Name = payload.NewName
+	err = uc.unitRepo.Update(ctx, unit)
+	if err != nil {
+		return nil, err
+	}
+	return unit, nil
+}
+
+func (uc *usecase) Merge(ctx context.Context, payload *payloadentity.MergeUnitPayload, author *databaseentity.User) (*databaseentity.Unit, error) {
+	// validate payload
+	err := entity.InitValidator().Validate(payload)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Name == payload.TargetName {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusBadRequest,
+			Message:  entity.MergeUnitIntoItselfMessage,
+			Internal: entity.NewInternalError(entity.MergeUnitIntoItself, entity.MergeUnitIntoItselfMessage),
+		}
+	}
+
+	// get both units
+	unit, err := uc.unitRepo.GetUnitByName(ctx, payload.Name)
+	if err != nil {
+		return nil, err
+	}
+	target, err := uc.unitRepo.GetUnitByName(ctx, payload.TargetName)
+	if err != nil {
+		return nil, err
+	}
+	// quantities are moved as is, so standard units must have the same size
+	if unit.IsStandard() && target.IsStandard() && (unit.Dimension != target.Dimension || unit.Factor != target.Factor) {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusConflict,
+			Message:  fmt.Sprintf(entity.MergeUnitSizeConflictMessage, unit.Name, target.Name),
+			Internal: entity.NewInternalError(entity.MergeUnitSizeConflict, "different standard unit size"),
+		}
+	}
+
+	// merge unit
+	err = uc.unitRepo.Merge(ctx, unit, target, author.ID)
+	if err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+func (uc *usecase) ConvertQuantity(ctx context.Context, quantity float64, from *databaseentity.Unit, to *databaseentity.Unit,
+	ingredient *databaseentity.Ingredient, conversions []*databaseentity.IngredientConvertionUnit) (*databaseentity.UnitConversion, error) {
+	result := &databaseentity.UnitConversion{
+		Quantity: quantity,
+		From:     from,
+		To:       to,
+	}
+	if rate, bridge, ok := rate(from, to); ok {
+		result.Result = quantity * rate
+		result.Bridge = bridge
+		return result, nil
+	}
+
+	// both units into ingredient unit, then ingredient unit into target unit
+	if ingredient != nil && ingredient.Unit != nil {
+		fromRate, fromBridges, fromOk := ingredientRate(from, ingredient, conversions)
+		toRate, toBridges, toOk := ingredientRate(to, ingredient, conversions)
+		if fromOk && toOk {
+			result.Result = quantity * fromRate / toRate
+			result.Bridge = joinBridges(append(fromBridges, toBridges...))
+			return result, nil
+		}
+	}
+	message := fmt.Sprintf(entity.UnitNotConvertibleMessage, from.Name, to.Name)
+	return nil, &echo.HTTPError{
+		Code:     http.StatusBadRequest,
+		Message:  message,
+		Internal: entity.NewInternalError(entity.UnitNotConvertible, message),
+	}
+}
+
+func (uc *usecase) Convert(ctx context.Context, req *payloadentity.ConvertUnitRequest) (*databaseentity.UnitConversion, error) {
+	// validate request
+	err := entity.InitValidator().Validate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// get both units
+	from, err := uc.unitRepo.GetUnitByName(ctx, req.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := uc.unitRepo.GetUnitByName(ctx, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	// get ingredient conversions
+	var ingredient *databaseentity.Ingredient
+	var conversions []*databaseentity.IngredientConvertionUnit
+	if req.Ingredient != "" {
+		ingredient, conversions, err = uc.unitRepo.GetIngredientConversions(ctx, req.Ingredient)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return uc.ConvertQuantity(ctx, req.Quantity, from, to, ingredient, conversions)
+}
@@ -66,6 +66,55 @@ func Test_Create(t *testing.T) {
 			"name": {{Tag: "max", Param: "30"}},
 		}, herr.Message)
 	})
+
+	t.Run("standard unit", func(t *testing.T) {
+		validUnit := &databaseentity.Unit{
+			Name:      "Cup",
+			Dimension: databaseentity.UnitDimensionVolume,
+			Factor:    240,
+			CreatedBy: 1,
+		}
+		unitRepo.EXPECT().Create(ctx, validUnit).Return(nil).Times(1)
+
+		resp, err := usecase.Create(ctx, &payloadentity.CreateUnitPayload{
+			Name:      "Cup",
+			Dimension: databaseentity.UnitDimensionVolume,
+			Factor:    240,
+		}, &databaseentity.User{ID: 1})
+		assert.Nil(t, err)
+		assert.Equal(t, validUnit, resp)
+	})
+
+	t.Run("invalid dimension & factor", func(t *testing.T) {
+		tests := []struct {
+			payload *payloadentity.CreateUnitPayload
+			message map[string][]*entity.ValidatorMessage
+		}{
+			{
+				payload: &payloadentity.CreateUnitPayload{Name: "Cup", Dimension: "length", Factor: 1},
+				message: map[string][]*entity.ValidatorMessage{"dimension": {{Tag: "oneof", Param: "mass volume count"}}},
+			},
+			{
+				payload: &payloadentity.CreateUnitPayload{Name: "Cup", Dimension: databaseentity.UnitDimensionVolume},
+				message: map[string][]*entity.ValidatorMessage{"factor": {{Tag: "required_with", Param: "Dimension"}}},
+			},
+			{
+				payload: &payloadentity.CreateUnitPayload{Name: "Cup", Factor: 240},
+				message: map[string][]*entity.ValidatorMessage{"factor": {{Tag: "excluded_without", Param: "Dimension"}}},
+			},
+			{
+				payload: &payloadentity.CreateUnitPayload{Name: "Cup", Dimension: databaseentity.UnitDimensionVolume, Factor: -1},
+				message: map[string][]*entity.ValidatorMessage{"factor": {{Tag: "gte", Param: "0"}}},
+			},
+		}
+		for _, tt := range tests {
+			_, err := usecase.Create(ctx, tt.payload, &databaseentity.User{ID: 1})
+			herr, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, herr.Code)
+			assert.Equal(t, tt.message, herr.Message)
+		}
+	})
 }
 
 func Test_DeleteCreate(t *testing.T) {
@@ -107,3 +156,216 @@ func Test_DeleteCreate(t *testing.T) {
 		}, herr.Message)
 	})
 }
+
+func Test_GetUnitDetail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	usecase, unitRepo := initUsecase(ctrl, nil)
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		validUnit := &databaseentity.Unit{ID: 1, Name: "Kilogram"}
+		usage := &databaseentity.UnitUsage{Ingredients: []*databaseentity.Ingredient{{Serial: "ING1", UnitID: 1}}}
+		unitRepo.EXPECT().GetUnitByName(ctx, "Kilogram").Return(validUnit, nil).Times(1)
+		unitRepo.EXPECT().GetUnitUsage(ctx, validUnit).Return(usage, nil).Times(1)
+
+		unit, resp, err := usecase.GetUnitDetail(ctx, "Kilogram")
+		assert.Nil(t, err)
+		assert.Equal(t, validUnit, unit)
+		assert.Equal(t, usage, resp)
+	})
+
+	t.Run("unit not exists", func(t *testing.T) {
+		unitRepo.EXPECT().GetUnitByName(ctx, "Gram").Return(nil, &echo.HTTPError{Code: http.StatusNotFound}).Times(1)
+
+		_, _, err := usecase.GetUnitDetail(ctx, "Gram")
+		assert.NotNil(t, err)
+	})
+}
+
+func Test_Rename(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	usecase, unitRepo := initUsecase(ctrl, nil)
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		unitRepo.EXPECT().GetUnitByName(ctx, "Kilogrm").Return(&databaseentity.Unit{ID: 1, Name: "Kilogrm"}, nil).Times(1)
+		unitRepo.EXPECT().Update(ctx, &databaseentity.Unit{ID: 1, Name: "Kilogram"}).Return(nil).Times(1)
+
+		unit, err := usecase.Rename(ctx, &payloadentity.RenameUnitPayload{Name: "Kilogrm", NewName: "Kilogram"})
+		assert.Nil(t, err)
+		assert.Equal(t, "Kilogram", unit.Name)
+	})
+
+	t.Run("same name", func(t *testing.T) {
+		unitRepo.EXPECT().GetUnitByName(ctx, "Kilogram").Return(&databaseentity.Unit{ID: 1, Name: "Kilogram"}, nil).Times(1)
+
+		_, err := usecase.Rename(ctx, &payloadentity.RenameUnitPayload{Name: "Kilogram", NewName: "Kilogram"})
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusConflict, herr.Code)
+		assert.Equal(t, entity.NewInternalError(entity.UpdateUnitNoChange, entity.UpdateUnitNoChangeMessage), herr.Internal)
+	})
+
+	t.Run("empty payload", func(t *testing.T) {
+		_, err := usecase.Rename(ctx, &payloadentity.RenameUnitPayload{})
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, herr.Code)
+		assert.Equal(t, map[string][]*entity.ValidatorMessage{
+			"name":    {{Tag: "required"}},
+			"newName": {{Tag: "required"}},
+		}, herr.Message)
+	})
+}
+
+func Test_Merge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	usecase, unitRepo := initUsecase(ctrl, nil)
+	ctx := context.Background()
+	author := &databaseentity.User{ID: 7}
+
+	t.Run("success", func(t *testing.T) {
+		kg := &databaseentity.Unit{ID: 1, Name: "Kg"}
+		kilogram := &databaseentity.Unit{ID: 2, Name: "Kilogram"}
+		unitRepo.EXPECT().GetUnitByName(ctx, "Kg").Return(kg, nil).Times(1)
+		unitRepo.EXPECT().GetUnitByName(ctx, "Kilogram").Return(kilogram, nil).Times(1)
+		unitRepo.EXPECT().Merge(ctx, kg, kilogram, author.ID).Return(nil).Times(1)
+
+		unit, err := usecase.Merge(ctx, &payloadentity.MergeUnitPayload{Name: "Kg", TargetName: "Kilogram"}, author)
+		assert.Nil(t, err)
+		assert.Equal(t, kilogram, unit)
+	})
+
+	t.Run("into itself", func(t *testing.T) {
+		_, err := usecase.Merge(ctx, &payloadentity.MergeUnitPayload{Name: "Kg", TargetName: "Kg"}, author)
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, herr.Code)
+		assert.Equal(t, entity.NewInternalError(entity.MergeUnitIntoItself, entity.MergeUnitIntoItselfMessage), herr.Internal)
+	})
+
+	t.Run("target not exists", func(t *testing.T) {
+		unitRepo.EXPECT().GetUnitByName(ctx, "Kg").Return(&databaseentity.Unit{ID: 1, Name: "Kg"}, nil).Times(1)
+		unitRepo.EXPECT().GetUnitByName(ctx, "Kilo").Return(nil, &echo.HTTPError{Code: http.StatusNotFound}).Times(1)
+
+		_, err := usecase.Merge(ctx, &payloadentity.MergeUnitPayload{Name: "Kg", TargetName: "Kilo"}, author)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("standard units of different size", func(t *testing.T) {
+		kg := &databaseentity.Unit{ID: 1, Name: "kg", Dimension: databaseentity.UnitDimensionMass, Factor: 1000}
+		g := &databaseentity.Unit{ID: 2, Name: "g", Dimension: databaseentity.UnitDimensionMass, Factor: 1}
+		unitRepo.EXPECT().GetUnitByName(ctx, "kg").Return(kg, nil).Times(1)
+		unitRepo.EXPECT().GetUnitByName(ctx, "g").Return(g, nil).Times(1)
+
+		_, err := usecase.Merge(ctx, &payloadentity.MergeUnitPayload{Name: "kg", TargetName: "g"}, author)
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusConflict, herr.Code)
+		assert.Equal(t, "cannot merge `kg` into `g`, standard units have different size", herr.Message)
+	})
+}
+
+func Test_ConvertQuantity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	usecase, _ := initUsecase(ctrl, nil)
+	ctx := context.Background()
+	kg := &databaseentity.Unit{ID: 1, Name: "kg", Dimension: databaseentity.UnitDimensionMass, Factor: 1000}
+	g := &databaseentity.Unit{ID: 2, Name: "g", Dimension: databaseentity.UnitDimensionMass, Factor: 1}
+	l := &databaseentity.Unit{ID: 3, Name: "l", Dimension: databaseentity.UnitDimensionVolume, Factor: 1000}
+	sdm := &databaseentity.Unit{ID: 4, Name: "sdm", Dimension: databaseentity.UnitDimensionVolume, Factor: 15}
+	butir := &databaseentity.Unit{ID: 5, Name: "butir", Dimension: databaseentity.UnitDimensionCount, Factor: 1}
+	pack := &databaseentity.Unit{ID: 6, Name: "pack"}
+	// one liter of oil weighs 920 gram, conversion is preferred over density
+	oil := &databaseentity.Ingredient{Name: "Oil", Unit: l, Density: 0.5}
+	oilConversions := []*databaseentity.IngredientConvertionUnit{{Unit: g, Value: 920}}
+	// one pack of flour weighs 1 kg
+	flour := &databaseentity.Ingredient{Name: "Flour", Unit: pack}
+	flourConversions := []*databaseentity.IngredientConvertionUnit{{Unit: kg, Value: 1}}
+	// one milliliter of sugar weighs 0.8 gram, one egg weighs 60 gram
+	ml := &databaseentity.Unit{ID: 7, Name: "ml", Dimension: databaseentity.UnitDimensionVolume, Factor: 1}
+	sugar := &databaseentity.Ingredient{Name: "Sugar", Unit: kg, Density: 0.8}
+	egg := &databaseentity.Ingredient{Name: "Egg", Unit: kg, Density: 1.2, PieceWeight: 60}
+	sugarPack := &databaseentity.Ingredient{Name: "Sugar Pack", Unit: pack, Density: 0.8}
+
+	tests := []struct {
+		name        string
+		quantity    float64
+		from        *databaseentity.Unit
+		to          *databaseentity.Unit
+		ingredient  *databaseentity.Ingredient
+		conversions []*databaseentity.IngredientConvertionUnit
+		result      float64
+		bridge      string
+	}{
+		{name: "same unit", quantity: 3, from: pack, to: pack, result: 3, bridge: databaseentity.UnitBridgeSame},
+		{name: "same dimension", quantity: 1.5, from: kg, to: g, result: 1500, bridge: databaseentity.UnitBridgeDimension},
+		{name: "kitchen unit", quantity: 2, from: l, to: sdm, result: 2000.0 / 15, bridge: databaseentity.UnitBridgeDimension},
+		{name: "volume into mass", quantity: 2, from: sdm, to: g, ingredient: oil, conversions: oilConversions, result: 27.6, bridge: databaseentity.UnitBridgeIngredient},
+		{name: "mass into volume", quantity: 460, from: g, to: l, ingredient: oil, conversions: oilConversions, result: 0.5, bridge: databaseentity.UnitBridgeIngredient},
+		{name: "custom unit", quantity: 500, from: g, to: pack, ingredient: flour, conversions: flourConversions, result: 0.5, bridge: databaseentity.UnitBridgeIngredient},
+		{name: "density", quantity: 2, from: sdm, to: g, ingredient: sugar, result: 24, bridge: databaseentity.UnitBridgeDensity},
+		{name: "piece weight", quantity: 3, from: butir, to: g, ingredient: egg, result: 180, bridge: databaseentity.UnitBridgePieceWeight},
+		{name: "volume into count", quantity: 100, from: ml, to: butir, ingredient: egg, result: 2, bridge: "density+pieceWeight"},
+		{name: "density into custom unit", quantity: 500, from: ml, to: pack, ingredient: sugarPack, conversions: flourConversions, result: 0.4, bridge: "ingredient+density"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conversion, err := usecase.ConvertQuantity(ctx, tt.quantity, tt.from, tt.to, tt.ingredient, tt.conversions)
+			assert.Nil(t, err)
+			assert.InDelta(t, tt.result, conversion.Result, 0.0001)
+			assert.Equal(t, tt.bridge, conversion.Bridge)
+		})
+	}
+
+	t.Run("not convertible", func(t *testing.T) {
+		for _, ingredient := range []*databaseentity.Ingredient{nil, flour} {
+			_, err := usecase.ConvertQuantity(ctx, 1, butir, g, ingredient, flourConversions)
+			herr, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, herr.Code)
+			assert.Equal(t, "cannot convert `butir` into `g`", herr.Message)
+		}
+	})
+}
+
+func Test_Convert(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	usecase, unitRepo := initUsecase(ctrl, nil)
+	ctx := context.Background()
+	ml := &databaseentity.Unit{ID: 1, Name: "ml", Dimension: databaseentity.UnitDimensionVolume, Factor: 1}
+	g := &databaseentity.Unit{ID: 2, Name: "g", Dimension: databaseentity.UnitDimensionMass, Factor: 1}
+
+	t.Run("with ingredient", func(t *testing.T) {
+		oil := &databaseentity.Ingredient{Serial: "ING01", Unit: ml}
+		unitRepo.EXPECT().GetUnitByName(ctx, "ml").Return(ml, nil).Times(1)
+		unitRepo.EXPECT().GetUnitByName(ctx, "g").Return(g, nil).Times(1)
+		unitRepo.EXPECT().GetIngredientConversions(ctx, "ING01").Return(oil, []*databaseentity.IngredientConvertionUnit{{Unit: g, Value: 0.92}}, nil).Times(1)
+
+		conversion, err := usecase.Convert(ctx, &payloadentity.ConvertUnitRequest{Quantity: 100, From: "ml", To: "g", Ingredient: "ING01"})
+		assert.Nil(t, err)
+		assert.InDelta(t, 92, conversion.Result, 0.0001)
+		assert.Equal(t, databaseentity.UnitBridgeIngredient, conversion.Bridge)
+	})
+
+	t.Run("without ingredient", func(t *testing.T) {
+		unitRepo.EXPECT().GetUnitByName(ctx, "ml").Return(ml, nil).Times(1)
+		unitRepo.EXPECT().GetUnitByName(ctx, "g").Return(g, nil).Times(1)
+
+		_, err := usecase.Convert(ctx, &payloadentity.ConvertUnitRequest{Quantity: 100, From: "ml", To: "g"})
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, herr.Code)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		_, err := usecase.Convert(ctx, &payloadentity.ConvertUnitRequest{})
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, map[string][]*entity.ValidatorMessage{
+			"quantity": {{Tag: "gt", Param: "0"}},
+			"from":     {{Tag: "required"}},
+			"to":       {{Tag: "required"}},
+		}, herr.Message)
+	})
+}
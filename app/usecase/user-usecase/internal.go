@@ -0,0 +1,97 @@
+package userusecase
+
+import (
+	"context"
+	"net/http"
+	"rap-c/app/entity"
+	databaseentity "rap-c/app/entity/database-entity"
+	payloadentity "rap-c/app/entity/payload-entity"
+	"rap-c/app/helper"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func verificationNotFound(message string) error {
+	return &echo.HTTPError{
+		Code:     http.StatusNotFound,
+		Message:  entity.EmailVerificationNotFoundMessage,
+		Internal: entity.NewInternalError(entity.EmailVerificationNotFound, message),
+	}
+}
+
+// get user by username that has not accepted invitation yet
+func (uc *usecase) getInvitedUser(ctx context.Context, payload *payloadentity.UserInvitationPayload) (*databaseentity.User, error) {
+	// validate payload
+	err := entity.InitValidator().Validate(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.GetUserByField(ctx, "username", payload.Username, http.StatusNotFound)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsInvitationPending() {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusBadRequest,
+			Message:  entity.InvitationAlreadyAcceptedMessage,
+			Internal: entity.NewInternalError(entity.InvitationAlreadyAccepted, entity.InvitationAlreadyAcceptedMessage),
+		}
+	}
+	return user, nil
+}
+
+// new invitation for user with welcome mail containing invitation link, both must be saved in one transaction
+func (uc *usecase) newInvitation(user *databaseentity.User, author *databaseentity.User) (*databaseentity.UserInvitation, *databaseentity.MailOutbox, error) {
+	token, err := helper.GenerateToken(64)
+	if err != nil {
+		return nil, nil, &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.HelperGenerateTokenError, err.Error()),
+		}
+	}
+	invitation := &databaseentity.UserInvitation{
+		UserID:    user.ID,
+		Token:     token,
+		TokenHash: helper.HashToken(token),
+		ExpiredAt: time.Now().Add(time.Hour * time.Duration(uc.cfg.InviteTokenInHours())),
+		CreatedBy: author.ID,
+		UpdatedBy: author.ID,
+	}
+
+	mail, err := uc.mailUsecase.Welcome(user, invitation)
+	if err != nil {
+		return nil, nil, err
+	}
+	return invitation, mail, nil
+}
+
+// render mails for updated user, changed pending email notify current email and get verification link
+func (uc *usecase) renderUpdateMails(ctx context.Context, user *databaseentity.User, prevPendingEmail string) ([]*databaseentity.MailOutbox, error) {
+	mail, err := uc.mailUsecase.UpdateUser(user)
+	if err != nil {
+		return nil, err
+	}
+	result := []*databaseentity.MailOutbox{mail}
+	if user.PendingEmail == "" || user.PendingEmail == prevPendingEmail {
+		return result, nil
+	}
+
+	mail, err = uc.mailUsecase.EmailChangeRequested(user)
+	if err != nil {
+		return nil, err
+	}
+	result = append(result, mail)
+
+	token, err := uc.GenerateEmailVerificationToken(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	mail, err = uc.mailUsecase.VerifyEmail(user, token)
+	if err != nil {
+		return nil, err
+	}
+	return append(result, mail), nil
+}
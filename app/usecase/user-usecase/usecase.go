@@ -2,6 +2,7 @@ package userusecase
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"rap-c/app/entity"
 	databaseentity "rap-c/app/entity/database-entity"
@@ -10,72 +11,129 @@ import (
 	"rap-c/app/repository/contract"
 	usecasecontract "rap-c/app/usecase/contract"
 	"rap-c/config"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 )
 
-func NewUsecase(cfg *config.Config, userRepo contract.UserRepository) usecasecontract.UserUsecase {
-	return &usecase{cfg, userRepo}
+const (
+	verifyStrID      string = "id"
+	verifyStrEmail   string = "email"
+	verifyStrPurpose string = "purpose"
+	verifyPurpose    string = "verify-email"
+)
+
+func NewUsecase(cfg *config.Config, userRepo contract.UserRepository, invitationRepo contract.InvitationRepository, mailUsecase usecasecontract.MailUsecase) usecasecontract.UserUsecase {
+	return &usecase{cfg, userRepo, invitationRepo, mailUsecase}
 }
 
 type usecase struct {
-	cfg      *config.Config
-	userRepo contract.UserRepository
+	cfg            *config.Config
+	userRepo       contract.UserRepository
+	invitationRepo contract.InvitationRepository
+	mailUsecase    usecasecontract.MailUsecase
 }
 
-func (uc *usecase) Create(ctx context.Context, payload *payloadentity.CreateUserPayload, author *databaseentity.User) (*databaseentity.User, string, error) {
+func (uc *usecase) Create(ctx context.Context, payload *payloadentity.CreateUserPayload, author *databaseentity.User) (*databaseentity.User, error) {
 	// validate payload
 	err := entity.InitValidator().Validate(payload)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
-	// generate strong password
-	password, err := helper.GenerateStrongPassword()
+	// set payload & result, password is set by user when accepting invitation
+	token, err := helper.GenerateToken(64)
 	if err != nil {
-		return nil, "", &echo.HTTPError{
+		return nil, &echo.HTTPError{
 			Code:     http.StatusInternalServerError,
 			Message:  http.StatusText(http.StatusInternalServerError),
-			Internal: entity.NewInternalError(entity.HelperGenerateStrongPasswordError, err.Error()),
+			Internal: entity.NewInternalError(entity.HelperGenerateTokenError, err.Error()),
+		}
+	}
+	user := databaseentity.User{
+		Username:  payload.Username,
+		FullName:  payload.FullName,
+		Email:     payload.Email,
+		IsGuest:   payload.IsGuest,
+		Token:     token,
+		CreatedBy: author.ID,
+		UpdatedBy: author.ID,
+	}
+
+	// save with invitation & welcome mail
+	invitation, mail, err := uc.newInvitation(&user, author)
+	if err != nil {
+		return nil, err
+	}
+	err = uc.userRepo.Create(ctx, &user, invitation, mail)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (uc *usecase) IsSetupRequired(ctx context.Context) (bool, error) {
+	total, err := uc.userRepo.GetTotalNonGuestUsers(ctx)
+	if err != nil {
+		return false, err
+	}
+	return total == 0, nil
+}
+
+func (uc *usecase) CreateOwner(ctx context.Context, payload *payloadentity.CreateOwnerPayload) (*databaseentity.User, error) {
+	// validate payload
+	err := entity.InitValidator().Validate(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	// refuse early, repository check again in transaction
+	required, err := uc.IsSetupRequired(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !required {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusForbidden,
+			Message:  entity.SetupAlreadyDoneForbiddenMessage,
+			Internal: entity.NewInternalError(entity.SetupAlreadyDoneForbidden, entity.SetupAlreadyDoneForbiddenMessage),
 		}
 	}
-	// encrypt password
-	encryptPassword, err := helper.EncryptPassword(password)
+
+	// set payload & result
+	encryptPass, err := helper.EncryptPassword(payload.Password)
 	if err != nil {
-		return nil, "", &echo.HTTPError{
+		return nil, &echo.HTTPError{
 			Code:     http.StatusInternalServerError,
 			Message:  http.StatusText(http.StatusInternalServerError),
 			Internal: entity.NewInternalError(entity.HelperEncryptPasswordError, err.Error()),
 		}
 	}
-
-	// set payload & result
 	token, err := helper.GenerateToken(64)
 	if err != nil {
-		return nil, "", &echo.HTTPError{
+		return nil, &echo.HTTPError{
 			Code:     http.StatusInternalServerError,
 			Message:  http.StatusText(http.StatusInternalServerError),
 			Internal: entity.NewInternalError(entity.HelperGenerateTokenError, err.Error()),
 		}
 	}
+	now := time.Now()
 	user := databaseentity.User{
-		Username:           payload.Username,
-		FullName:           payload.FullName,
-		Email:              payload.Email,
-		Password:           encryptPassword,
-		PasswordMustChange: true,
-		IsGuest:            payload.IsGuest,
-		Token:              token,
-		CreatedBy:          author.ID,
-		UpdatedBy:          author.ID,
+		Username:        payload.Username,
+		FullName:        payload.FullName,
+		Email:           payload.Email,
+		EmailVerifiedAt: &now,
+		Password:        encryptPass,
+		Token:           token,
 	}
 
 	// save
-	err = uc.userRepo.Create(ctx, &user)
+	err = uc.userRepo.CreateOwner(ctx, &user)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
-	return &user, password, nil
+	return &user, nil
 }
 
 func (uc *usecase) GetUserList(ctx context.Context, req *payloadentity.GetUserListRequest) ([]*databaseentity.User, error) {
@@ -112,6 +170,7 @@ func (uc *usecase) Update(ctx context.Context, payload *payloadentity.UpdateUser
 	}
 
 	var isModified bool
+	prevPendingEmail := author.PendingEmail
 	if payload.Username != "" {
 		author.Username = payload.Username
 		isModified = true
@@ -121,7 +180,27 @@ func (uc *usecase) Update(ctx context.Context, payload *payloadentity.UpdateUser
 		isModified = true
 	}
 	if payload.Email != "" {
-		author.Email = payload.Email
+		// new email must be unique
+		if payload.Email != author.Email && payload.Email != author.PendingEmail {
+			_, err := uc.userRepo.GetUserByField(ctx, "email", payload.Email, http.StatusNotFound)
+			if err == nil {
+				return &echo.HTTPError{
+					Code:     http.StatusBadRequest,
+					Message:  fmt.Sprintf(entity.CreateUserEmailDuplicateMessage, payload.Email),
+					Internal: entity.NewInternalError(entity.CreateUserEmailDuplicate, entity.CreateUserEmailDuplicateMessage),
+				}
+			}
+			if herr, ok := err.(*echo.HTTPError); !ok || herr.Code != http.StatusNotFound {
+				return err
+			}
+		}
+
+		// changed email stay pending until verified, same email cancel pending change
+		if payload.Email == author.Email {
+			author.PendingEmail = ""
+		} else {
+			author.PendingEmail = payload.Email
+		}
 		isModified = true
 	}
 	if payload.Password != "" {
@@ -140,7 +219,13 @@ func (uc *usecase) Update(ctx context.Context, payload *payloadentity.UpdateUser
 	}
 	if isModified {
 		author.UpdatedBy = author.ID
-		return uc.userRepo.Update(ctx, author)
+
+		// notify user, and verify requested email
+		mails, err := uc.renderUpdateMails(ctx, author, prevPendingEmail)
+		if err != nil {
+			return err
+		}
+		return uc.userRepo.Update(ctx, author, mails...)
 	}
 	return &echo.HTTPError{
 		Code:     http.StatusConflict,
@@ -182,10 +267,143 @@ func (uc *usecase) UpdateActiveStatus(ctx context.Context, payload *payloadentit
 	// update user
 	user.Disabled = payload.Disabled
 	user.UpdatedBy = author.ID
+	mail, err := uc.mailUsecase.UpdateActiveStatusUser(user)
+	if err != nil {
+		return nil, err
+	}
+	err = uc.userRepo.Update(ctx, user, mail)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (uc *usecase) GenerateEmailVerificationToken(ctx context.Context, user *databaseentity.User) (string, error) {
+	email := user.EmailToVerify()
+	if email == "" {
+		return "", &echo.HTTPError{
+			Code:     http.StatusConflict,
+			Message:  entity.EmailAlreadyVerifiedMessage,
+			Internal: entity.NewInternalError(entity.EmailAlreadyVerified, entity.EmailAlreadyVerifiedMessage),
+		}
+	}
+
+	claims := jwt.MapClaims{
+		verifyStrID:      user.ID,
+		verifyStrEmail:   email,
+		verifyStrPurpose: verifyPurpose,
+		"exp":            time.Now().Add(time.Hour * time.Duration(uc.cfg.VerifyTokenInHours())).Unix(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenStr, err := token.SignedString([]byte(uc.cfg.JwtSecret()))
+	if err != nil {
+		return "", &echo.HTTPError{
+			Code:     http.StatusInternalServerError,
+			Message:  http.StatusText(http.StatusInternalServerError),
+			Internal: entity.NewInternalError(entity.UserUsecaseGenerateVerifyTokenError, err.Error()),
+		}
+	}
+	return tokenStr, nil
+}
+
+func (uc *usecase) ResendEmailVerification(ctx context.Context, user *databaseentity.User) error {
+	token, err := uc.GenerateEmailVerificationToken(ctx, user)
+	if err != nil {
+		return err
+	}
+	mail, err := uc.mailUsecase.VerifyEmail(user, token)
+	if err != nil {
+		return err
+	}
+	return uc.mailUsecase.Queue(ctx, mail)
+}
+
+func (uc *usecase) VerifyEmail(ctx context.Context, payload *payloadentity.VerifyEmailPayload) (*databaseentity.User, error) {
+	// validate payload
+	err := entity.InitValidator().Validate(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	// parse signed token
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(payload.Token, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(uc.cfg.JwtSecret()), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return nil, verificationNotFound(err.Error())
+	}
+	purpose, _ := claims[verifyStrPurpose].(string)
+	email, _ := claims[verifyStrEmail].(string)
+	id, _ := claims[verifyStrID].(float64)
+	if purpose != verifyPurpose || email == "" || id < 1 {
+		return nil, verificationNotFound("invalid verification claims")
+	}
+
+	// get user
+	user, err := uc.userRepo.GetUserByField(ctx, "id", int(id), http.StatusNotFound)
+	if err != nil {
+		if herr, ok := err.(*echo.HTTPError); ok && herr.Code == http.StatusNotFound {
+			return nil, verificationNotFound(err.Error())
+		}
+		return nil, err
+	}
+
+	// token only valid for current pending email or unverified email
+	if user.PendingEmail != "" && email == user.PendingEmail {
+		user.Email = user.PendingEmail
+		user.PendingEmail = ""
+	} else if email != user.Email || user.EmailVerifiedAt != nil {
+		return nil, verificationNotFound("verification token is outdated")
+	}
+
+	// save
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	user.UpdatedBy = user.ID
 	err = uc.userRepo.Update(ctx, user)
 	if err != nil {
 		return nil, err
 	}
+	return user, nil
+}
 
+func (uc *usecase) ResendInvitation(ctx context.Context, payload *payloadentity.UserInvitationPayload, author *databaseentity.User) (*databaseentity.User, error) {
+	user, err := uc.getInvitedUser(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	// issue new invitation, previous invitation is revoked
+	invitation, mail, err := uc.newInvitation(user, author)
+	if err != nil {
+		return nil, err
+	}
+	err = uc.invitationRepo.Create(ctx, invitation, mail)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (uc *usecase) RevokeInvitation(ctx context.Context, payload *payloadentity.UserInvitationPayload, author *databaseentity.User) (*databaseentity.User, error) {
+	user, err := uc.getInvitedUser(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	// revoke pending invitation
+	total, err := uc.invitationRepo.Revoke(ctx, user, author)
+	if err != nil {
+		return nil, err
+	}
+	if total == 0 {
+		return nil, &echo.HTTPError{
+			Code:     http.StatusNotFound,
+			Message:  entity.InvitationNotFoundMessage,
+			Internal: entity.NewInternalError(entity.InvitationNotFound, "no pending invitation"),
+		}
+	}
 	return user, nil
 }
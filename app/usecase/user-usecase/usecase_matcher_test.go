@@ -19,7 +19,7 @@ func (r *createMatcher) Matches(x interface{}) bool {
 	}
 	r.expected.Password = req.Password
 	r.expected.Token = req.Token
-	// validate each field except Password
+	// validate each field except Password, password is empty for invited user and encrypted for updated user
 	if req.Username != r.expected.Username {
 		return false
 	}
@@ -29,6 +29,9 @@ func (r *createMatcher) Matches(x interface{}) bool {
 	if req.Email != r.expected.Email {
 		return false
 	}
+	if req.PendingEmail != r.expected.PendingEmail {
+		return false
+	}
 	if req.Disabled != r.expected.Disabled {
 		return false
 	}
@@ -44,11 +47,6 @@ func (r *createMatcher) Matches(x interface{}) bool {
 	if req.UpdatedBy != r.expected.UpdatedBy {
 		return false
 	}
-	// validate password, password is auto generated, so check if not empty only
-	if req.Password == "" {
-		r.want = "password not empty"
-		return false
-	}
 	// validate token, token is auto generated, so check if not empty only
 	if req.Token == "" {
 		r.want = "token not empty"
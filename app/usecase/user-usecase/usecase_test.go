@@ -9,70 +9,104 @@ import (
 	"rap-c/app/helper"
 	repomocks "rap-c/app/repository/contract/mocks"
 	"rap-c/app/usecase/contract"
+	usecasemocks "rap-c/app/usecase/contract/mocks"
 	userusecase "rap-c/app/usecase/user-usecase"
 	"rap-c/config"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 )
 
-func initUsecase(ctrl *gomock.Controller, cfg *config.Config) (contract.UserUsecase, *repomocks.MockUserRepository) {
+func initUsecase(ctrl *gomock.Controller, cfg *config.Config) (contract.UserUsecase, *repomocks.MockUserRepository, *repomocks.MockInvitationRepository, *usecasemocks.MockMailUsecase) {
 	userRepo := repomocks.NewMockUserRepository(ctrl)
-	uc := userusecase.NewUsecase(cfg, userRepo)
-	return uc, userRepo
+	invitationRepo := repomocks.NewMockInvitationRepository(ctrl)
+	mailUsecase := usecasemocks.NewMockMailUsecase(ctrl)
+	uc := userusecase.NewUsecase(cfg, userRepo, invitationRepo, mailUsecase)
+	return uc, userRepo, invitationRepo, mailUsecase
 }
 
 func Test_Create(t *testing.T) {
 	ctrl := gomock.NewController(t)
-	uc, userRepo := initUsecase(ctrl, &config.Config{})
+	uc, userRepo, _, mailUsecase := initUsecase(ctrl, config.InitTestConfig(map[string]string{
+		"INVITATION_EXPIRATION_IN_HOURS": "72",
+	}))
 	ctx := context.Background()
 
 	t.Run("success non guest", func(t *testing.T) {
+		author := &databaseentity.User{ID: 1}
+		validMail := &databaseentity.MailOutbox{Recipient: "mvp.firman.darmawan@gmail.com"}
 		userRepo.EXPECT().Create(ctx, CreateMatcher(&databaseentity.User{
-			Username:           "gendutski",
-			FullName:           "Firman Darmawan",
-			Email:              "mvp.firman.darmawan@gmail.com",
-			PasswordMustChange: true,
-			CreatedBy:          1,
-			UpdatedBy:          1,
-		})).Return(nil).Times(1)
-
-		res, pass, err := uc.Create(ctx, &payloadentity.CreateUserPayload{
+			Username:  "gendutski",
+			FullName:  "Firman Darmawan",
+			Email:     "mvp.firman.darmawan@gmail.com",
+			CreatedBy: 1,
+			UpdatedBy: 1,
+		}), gomock.Any(), validMail).
+			DoAndReturn(func(ctx context.Context, user *databaseentity.User, invitation *databaseentity.UserInvitation, mails ...*databaseentity.MailOutbox) error {
+				assert.NotEmpty(t, invitation.Token)
+				assert.Equal(t, helper.HashToken(invitation.Token), invitation.TokenHash)
+				assert.WithinDuration(t, time.Now().Add(72*time.Hour), invitation.ExpiredAt, time.Minute)
+				assert.Equal(t, 1, invitation.CreatedBy)
+				return nil
+			}).Times(1)
+		mailUsecase.EXPECT().Welcome(gomock.Any(), gomock.Any()).Return(validMail, nil).Times(1)
+
+		res, err := uc.Create(ctx, &payloadentity.CreateUserPayload{
 			Username: "gendutski",
 			FullName: "Firman Darmawan",
 			Email:    "mvp.firman.darmawan@gmail.com",
-		}, &databaseentity.User{ID: 1})
+		}, author)
 		assert.Nil(t, err)
 		assert.NotNil(t, res)
-		assert.True(t, helper.ValidateEncryptedPassword(res.Password, pass))
+		assert.Empty(t, res.Password)
+		assert.True(t, res.IsInvitationPending())
 	})
 
 	t.Run("success guest", func(t *testing.T) {
+		author := &databaseentity.User{ID: 1}
+		validMail := &databaseentity.MailOutbox{Recipient: "mvp.firman.darmawan@gmail.com"}
 		userRepo.EXPECT().Create(ctx, CreateMatcher(&databaseentity.User{
-			Username:           "gendutski",
-			FullName:           "Firman Darmawan",
-			Email:              "mvp.firman.darmawan@gmail.com",
-			PasswordMustChange: true,
-			IsGuest:            true,
-			CreatedBy:          1,
-			UpdatedBy:          1,
-		})).Return(nil).Times(1)
-
-		res, pass, err := uc.Create(ctx, &payloadentity.CreateUserPayload{
+			Username:  "gendutski",
+			FullName:  "Firman Darmawan",
+			Email:     "mvp.firman.darmawan@gmail.com",
+			IsGuest:   true,
+			CreatedBy: 1,
+			UpdatedBy: 1,
+		}), gomock.Any(), validMail).Return(nil).Times(1)
+		mailUsecase.EXPECT().Welcome(gomock.Any(), gomock.Any()).Return(validMail, nil).Times(1)
+
+		res, err := uc.Create(ctx, &payloadentity.CreateUserPayload{
 			Username: "gendutski",
 			FullName: "Firman Darmawan",
 			Email:    "mvp.firman.darmawan@gmail.com",
 			IsGuest:  true,
-		}, &databaseentity.User{ID: 1})
+		}, author)
 		assert.Nil(t, err)
 		assert.NotNil(t, res)
-		assert.True(t, helper.ValidateEncryptedPassword(res.Password, pass))
+	})
+
+	t.Run("failed save leaves no invitation nor mail behind", func(t *testing.T) {
+		author := &databaseentity.User{ID: 1}
+		validMail := &databaseentity.MailOutbox{Recipient: "mvp.firman.darmawan@gmail.com"}
+		saveErr := &echo.HTTPError{Code: http.StatusInternalServerError, Internal: entity.NewInternalError(entity.UserRepoCreateError, "mail insert failed")}
+		mailUsecase.EXPECT().Welcome(gomock.Any(), gomock.Any()).Return(validMail, nil).Times(1)
+		// invitation & mail are only passed to the user transaction, nothing is queued on its own
+		userRepo.EXPECT().Create(ctx, gomock.Any(), gomock.Any(), validMail).Return(saveErr).Times(1)
+
+		res, err := uc.Create(ctx, &payloadentity.CreateUserPayload{
+			Username: "gendutski",
+			FullName: "Firman Darmawan",
+			Email:    "mvp.firman.darmawan@gmail.com",
+		}, author)
+		assert.Nil(t, res)
+		assert.Equal(t, saveErr, err)
 	})
 
 	t.Run("not valid payload", func(t *testing.T) {
-		_, _, err := uc.Create(ctx, &payloadentity.CreateUserPayload{
+		_, err := uc.Create(ctx, &payloadentity.CreateUserPayload{
 			FullName: "Firman Darmawan",
 			Email:    "gendutski.gmail.com",
 		}, &databaseentity.User{Username: "gendutski"})
@@ -88,9 +122,93 @@ func Test_Create(t *testing.T) {
 	})
 }
 
+func Test_IsSetupRequired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	uc, userRepo, _, _ := initUsecase(ctrl, &config.Config{})
+	ctx := context.Background()
+
+	t.Run("no non guest user", func(t *testing.T) {
+		userRepo.EXPECT().GetTotalNonGuestUsers(ctx).Return(int64(0), nil).Times(1)
+
+		res, err := uc.IsSetupRequired(ctx)
+		assert.Nil(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("owner exists", func(t *testing.T) {
+		userRepo.EXPECT().GetTotalNonGuestUsers(ctx).Return(int64(1), nil).Times(1)
+
+		res, err := uc.IsSetupRequired(ctx)
+		assert.Nil(t, err)
+		assert.False(t, res)
+	})
+}
+
+func Test_CreateOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	uc, userRepo, _, _ := initUsecase(ctrl, &config.Config{})
+	ctx := context.Background()
+	payload := &payloadentity.CreateOwnerPayload{
+		Username:        "gendutski",
+		FullName:        "Firman Darmawan",
+		Email:           "mvp.firman.darmawan@gmail.com",
+		Password:        "password",
+		ConfirmPassword: "password",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		userRepo.EXPECT().GetTotalNonGuestUsers(ctx).Return(int64(0), nil).Times(1)
+		userRepo.EXPECT().CreateOwner(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, user *databaseentity.User) error {
+				assert.Equal(t, "gendutski", user.Username)
+				assert.False(t, user.IsGuest)
+				assert.False(t, user.PasswordMustChange)
+				assert.NotNil(t, user.EmailVerifiedAt)
+				assert.NotEmpty(t, user.Token)
+				assert.True(t, helper.ValidateEncryptedPassword(user.Password, "password"))
+				return nil
+			}).Times(1)
+
+		res, err := uc.CreateOwner(ctx, payload)
+		assert.Nil(t, err)
+		assert.NotNil(t, res)
+	})
+
+	t.Run("setup already done", func(t *testing.T) {
+		userRepo.EXPECT().GetTotalNonGuestUsers(ctx).Return(int64(1), nil).Times(1)
+
+		res, err := uc.CreateOwner(ctx, payload)
+		assert.Nil(t, res)
+		assert.NotNil(t, err)
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusForbidden, herr.Code)
+		assert.Equal(t, entity.SetupAlreadyDoneForbidden, herr.Internal.(*entity.InternalError).Code)
+	})
+
+	t.Run("not valid payload", func(t *testing.T) {
+		res, err := uc.CreateOwner(ctx, &payloadentity.CreateOwnerPayload{
+			Username:        "gendutski",
+			FullName:        "Firman Darmawan",
+			Email:           "mvp.firman.darmawan@gmail.com",
+			Password:        "pass",
+			ConfirmPassword: "password",
+		})
+		assert.Nil(t, res)
+		assert.NotNil(t, err)
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, herr.Code)
+		assert.Equal(t, map[string][]*entity.ValidatorMessage{
+			"password":        {{Tag: "min", Param: "8"}},
+			"confirmPassword": {{Tag: "eqfield", Param: "Password"}},
+		}, herr.Message)
+	})
+}
+
 func Test_GetUserByUsername(t *testing.T) {
 	ctrl := gomock.NewController(t)
-	uc, userRepo := initUsecase(ctrl, &config.Config{})
+	uc, userRepo, _, _ := initUsecase(ctrl, &config.Config{})
 	ctx := context.Background()
 
 	t.Run("success", func(t *testing.T) {
@@ -120,8 +238,14 @@ func Test_GetUserByUsername(t *testing.T) {
 
 func Test_Update(t *testing.T) {
 	ctrl := gomock.NewController(t)
-	uc, userRepo := initUsecase(ctrl, &config.Config{})
+	uc, userRepo, _, mailUsecase := initUsecase(ctrl, config.InitTestConfig(map[string]string{
+		"JWT_SECRET":                             "secret",
+		"EMAIL_VERIFICATION_EXPIRATION_IN_HOURS": "24",
+	}))
 	ctx := context.Background()
+	updateMail := &databaseentity.MailOutbox{Subject: "update"}
+	changeMail := &databaseentity.MailOutbox{Subject: "change"}
+	verifyMail := &databaseentity.MailOutbox{Subject: "verify"}
 
 	t.Run("success", func(t *testing.T) {
 		author := &databaseentity.User{
@@ -133,13 +257,19 @@ func Test_Update(t *testing.T) {
 			Token:    "token",
 		}
 
+		userRepo.EXPECT().GetUserByField(ctx, "email", "mvp.firman.darmawan@gmail.com", http.StatusNotFound).
+			Return(nil, &echo.HTTPError{Code: http.StatusNotFound}).Times(1)
+		mailUsecase.EXPECT().UpdateUser(author).Return(updateMail, nil).Times(1)
+		mailUsecase.EXPECT().EmailChangeRequested(author).Return(changeMail, nil).Times(1)
+		mailUsecase.EXPECT().VerifyEmail(author, gomock.Any()).Return(verifyMail, nil).Times(1)
 		userRepo.EXPECT().Update(ctx, CreateMatcher(&databaseentity.User{
 			Username:           "gendutski-1",
 			FullName:           "Lord Firman Darmawan",
-			Email:              "mvp.firman.darmawan@gmail.com",
+			Email:              "gendutski@gmail.com",
+			PendingEmail:       "mvp.firman.darmawan@gmail.com",
 			PasswordMustChange: false,
 			UpdatedBy:          1,
-		})).Return(nil).Times(1)
+		}), updateMail, changeMail, verifyMail).Return(nil).Times(1)
 
 		err := uc.Update(ctx, &payloadentity.UpdateUserPayload{
 			Username:        "gendutski-1",
@@ -152,11 +282,50 @@ func Test_Update(t *testing.T) {
 		assert.True(t, helper.ValidateEncryptedPassword(author.Password, "new awesome password"))
 		assert.False(t, author.PasswordMustChange)
 		assert.Equal(t,
-			[]string{"gendutski-1", "Lord Firman Darmawan", "mvp.firman.darmawan@gmail.com"},
-			[]string{author.Username, author.FullName, author.Email},
+			[]string{"gendutski-1", "Lord Firman Darmawan", "gendutski@gmail.com", "mvp.firman.darmawan@gmail.com"},
+			[]string{author.Username, author.FullName, author.Email, author.PendingEmail},
 		)
 	})
 
+	t.Run("email already used", func(t *testing.T) {
+		author := &databaseentity.User{
+			ID:       1,
+			Username: "gendutski",
+			Email:    "gendutski@gmail.com",
+		}
+		userRepo.EXPECT().GetUserByField(ctx, "email", "other@gmail.com", http.StatusNotFound).
+			Return(&databaseentity.User{ID: 2, Email: "other@gmail.com"}, nil).Times(1)
+
+		err := uc.Update(ctx, &payloadentity.UpdateUserPayload{Email: "other@gmail.com"}, author)
+		assert.NotNil(t, err)
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, herr.Code)
+		assert.Equal(t, entity.CreateUserEmailDuplicate, herr.Internal.(*entity.InternalError).Code)
+		assert.Empty(t, author.PendingEmail)
+	})
+
+	t.Run("cancel pending email", func(t *testing.T) {
+		author := &databaseentity.User{
+			ID:           1,
+			Username:     "gendutski",
+			Email:        "gendutski@gmail.com",
+			PendingEmail: "other@gmail.com",
+			Password:     "password",
+			Token:        "token",
+		}
+		mailUsecase.EXPECT().UpdateUser(author).Return(updateMail, nil).Times(1)
+		userRepo.EXPECT().Update(ctx, CreateMatcher(&databaseentity.User{
+			Username:  "gendutski",
+			Email:     "gendutski@gmail.com",
+			UpdatedBy: 1,
+		}), updateMail).Return(nil).Times(1)
+
+		err := uc.Update(ctx, &payloadentity.UpdateUserPayload{Email: "gendutski@gmail.com"}, author)
+		assert.Nil(t, err)
+		assert.Empty(t, author.PendingEmail)
+	})
+
 	t.Run("no change (empty payload)", func(t *testing.T) {
 		author := &databaseentity.User{
 			Username: "gendutski",
@@ -202,8 +371,9 @@ func Test_Update(t *testing.T) {
 
 func Test_UpdateActiveStatus(t *testing.T) {
 	ctrl := gomock.NewController(t)
-	uc, userRepo := initUsecase(ctrl, &config.Config{})
+	uc, userRepo, _, mailUsecase := initUsecase(ctrl, &config.Config{})
 	ctx := context.Background()
+	validMail := &databaseentity.MailOutbox{Recipient: "other-user@gmail.com"}
 
 	author := &databaseentity.User{
 		ID:       1,
@@ -217,12 +387,13 @@ func Test_UpdateActiveStatus(t *testing.T) {
 			Token:    "token",
 		}
 		userRepo.EXPECT().GetUserByField(ctx, "username", "other-user", 404).Return(currentUser, nil).Times(1)
+		mailUsecase.EXPECT().UpdateActiveStatusUser(currentUser).Return(validMail, nil).Times(1)
 		userRepo.EXPECT().Update(ctx, CreateMatcher(&databaseentity.User{
 			Username:  "other-user",
 			Password:  "password",
 			Disabled:  true,
 			UpdatedBy: author.ID,
-		})).Return(nil).Times(1)
+		}), validMail).Return(nil).Times(1)
 
 		res, err := uc.UpdateActiveStatus(ctx, &payloadentity.ActiveStatusPayload{
 			Username: "other-user",
@@ -240,12 +411,13 @@ func Test_UpdateActiveStatus(t *testing.T) {
 			Token:    "token",
 		}
 		userRepo.EXPECT().GetUserByField(ctx, "username", "other-user", 404).Return(currentUser, nil).Times(1)
+		mailUsecase.EXPECT().UpdateActiveStatusUser(currentUser).Return(validMail, nil).Times(1)
 		userRepo.EXPECT().Update(ctx, CreateMatcher(&databaseentity.User{
 			Username:  "other-user",
 			Password:  "password",
 			Disabled:  false,
 			UpdatedBy: author.ID,
-		})).Return(nil).Times(1)
+		}), validMail).Return(nil).Times(1)
 
 		res, err := uc.UpdateActiveStatus(ctx, &payloadentity.ActiveStatusPayload{
 			Username: "other-user",
@@ -295,3 +467,173 @@ func Test_UpdateActiveStatus(t *testing.T) {
 		assert.Equal(t, entity.DeactivatingInActiveUser, herr.Internal.(*entity.InternalError).Code)
 	})
 }
+
+func Test_VerifyEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	uc, userRepo, _, _ := initUsecase(ctrl, config.InitTestConfig(map[string]string{
+		"JWT_SECRET":                             "secret",
+		"EMAIL_VERIFICATION_EXPIRATION_IN_HOURS": "24",
+	}))
+	ctx := context.Background()
+
+	t.Run("success new user", func(t *testing.T) {
+		user := &databaseentity.User{ID: 1, Username: "gendutski", Email: "gendutski@gmail.com"}
+		token, err := uc.GenerateEmailVerificationToken(ctx, user)
+		assert.Nil(t, err)
+
+		userRepo.EXPECT().GetUserByField(ctx, "id", 1, http.StatusNotFound).Return(user, nil).Times(1)
+		userRepo.EXPECT().Update(ctx, user).Return(nil).Times(1)
+
+		res, err := uc.VerifyEmail(ctx, &payloadentity.VerifyEmailPayload{Token: token})
+		assert.Nil(t, err)
+		assert.Equal(t, "gendutski@gmail.com", res.Email)
+		assert.NotNil(t, res.EmailVerifiedAt)
+	})
+
+	t.Run("success pending email", func(t *testing.T) {
+		verifiedAt := time.Now()
+		user := &databaseentity.User{
+			ID:              1,
+			Username:        "gendutski",
+			Email:           "gendutski@gmail.com",
+			PendingEmail:    "mvp.firman.darmawan@gmail.com",
+			EmailVerifiedAt: &verifiedAt,
+		}
+		token, err := uc.GenerateEmailVerificationToken(ctx, user)
+		assert.Nil(t, err)
+
+		userRepo.EXPECT().GetUserByField(ctx, "id", 1, http.StatusNotFound).Return(user, nil).Times(1)
+		userRepo.EXPECT().Update(ctx, user).Return(nil).Times(1)
+
+		res, err := uc.VerifyEmail(ctx, &payloadentity.VerifyEmailPayload{Token: token})
+		assert.Nil(t, err)
+		assert.Equal(t, "mvp.firman.darmawan@gmail.com", res.Email)
+		assert.Empty(t, res.PendingEmail)
+	})
+
+	t.Run("outdated pending email", func(t *testing.T) {
+		verifiedAt := time.Now()
+		user := &databaseentity.User{
+			ID:              1,
+			Email:           "gendutski@gmail.com",
+			PendingEmail:    "first@gmail.com",
+			EmailVerifiedAt: &verifiedAt,
+		}
+		token, err := uc.GenerateEmailVerificationToken(ctx, user)
+		assert.Nil(t, err)
+
+		// pending email changed after token sent
+		userRepo.EXPECT().GetUserByField(ctx, "id", 1, http.StatusNotFound).Return(&databaseentity.User{
+			ID:              1,
+			Email:           "gendutski@gmail.com",
+			PendingEmail:    "second@gmail.com",
+			EmailVerifiedAt: &verifiedAt,
+		}, nil).Times(1)
+
+		_, err = uc.VerifyEmail(ctx, &payloadentity.VerifyEmailPayload{Token: token})
+		assert.NotNil(t, err)
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusNotFound, herr.Code)
+		assert.Equal(t, entity.EmailVerificationNotFound, herr.Internal.(*entity.InternalError).Code)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		_, err := uc.VerifyEmail(ctx, &payloadentity.VerifyEmailPayload{Token: "not a token"})
+		assert.NotNil(t, err)
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusNotFound, herr.Code)
+		assert.Equal(t, entity.EmailVerificationNotFound, herr.Internal.(*entity.InternalError).Code)
+	})
+
+	t.Run("already verified", func(t *testing.T) {
+		verifiedAt := time.Now()
+		_, err := uc.GenerateEmailVerificationToken(ctx, &databaseentity.User{
+			ID:              1,
+			Email:           "gendutski@gmail.com",
+			EmailVerifiedAt: &verifiedAt,
+		})
+		assert.NotNil(t, err)
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusConflict, herr.Code)
+		assert.Equal(t, entity.EmailAlreadyVerified, herr.Internal.(*entity.InternalError).Code)
+	})
+}
+
+func Test_ResendInvitation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	uc, userRepo, invitationRepo, mailUsecase := initUsecase(ctrl, config.InitTestConfig(map[string]string{
+		"INVITATION_EXPIRATION_IN_HOURS": "72",
+	}))
+	ctx := context.Background()
+	author := &databaseentity.User{ID: 1}
+
+	t.Run("success", func(t *testing.T) {
+		invitedUser := &databaseentity.User{ID: 2, Username: "invited"}
+		validMail := &databaseentity.MailOutbox{Recipient: "invited@gmail.com"}
+		userRepo.EXPECT().GetUserByField(ctx, "username", "invited", http.StatusNotFound).Return(invitedUser, nil).Times(1)
+		mailUsecase.EXPECT().Welcome(invitedUser, gomock.Any()).Return(validMail, nil).Times(1)
+		invitationRepo.EXPECT().Create(ctx, gomock.Any(), validMail).
+			DoAndReturn(func(ctx context.Context, invitation *databaseentity.UserInvitation, mails ...*databaseentity.MailOutbox) error {
+				assert.Equal(t, 2, invitation.UserID)
+				assert.Equal(t, 1, invitation.UpdatedBy)
+				return nil
+			}).Times(1)
+
+		user, err := uc.ResendInvitation(ctx, &payloadentity.UserInvitationPayload{Username: "invited"}, author)
+		assert.Nil(t, err)
+		assert.Equal(t, invitedUser, user)
+	})
+
+	t.Run("already accepted", func(t *testing.T) {
+		userRepo.EXPECT().GetUserByField(ctx, "username", "gendutski", http.StatusNotFound).
+			Return(&databaseentity.User{ID: 3, Username: "gendutski", Password: "password"}, nil).Times(1)
+
+		_, err := uc.ResendInvitation(ctx, &payloadentity.UserInvitationPayload{Username: "gendutski"}, author)
+		assert.NotNil(t, err)
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, herr.Code)
+		assert.Equal(t, entity.InvitationAlreadyAccepted, herr.Internal.(*entity.InternalError).Code)
+	})
+}
+
+func Test_RevokeInvitation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	uc, userRepo, invitationRepo, _ := initUsecase(ctrl, &config.Config{})
+	ctx := context.Background()
+	author := &databaseentity.User{ID: 1}
+
+	t.Run("success", func(t *testing.T) {
+		invitedUser := &databaseentity.User{ID: 2, Username: "invited"}
+		userRepo.EXPECT().GetUserByField(ctx, "username", "invited", http.StatusNotFound).Return(invitedUser, nil).Times(1)
+		invitationRepo.EXPECT().Revoke(ctx, invitedUser, author).Return(int64(1), nil).Times(1)
+
+		user, err := uc.RevokeInvitation(ctx, &payloadentity.UserInvitationPayload{Username: "invited"}, author)
+		assert.Nil(t, err)
+		assert.Equal(t, invitedUser, user)
+	})
+
+	t.Run("no pending invitation", func(t *testing.T) {
+		invitedUser := &databaseentity.User{ID: 2, Username: "invited"}
+		userRepo.EXPECT().GetUserByField(ctx, "username", "invited", http.StatusNotFound).Return(invitedUser, nil).Times(1)
+		invitationRepo.EXPECT().Revoke(ctx, invitedUser, author).Return(int64(0), nil).Times(1)
+
+		_, err := uc.RevokeInvitation(ctx, &payloadentity.UserInvitationPayload{Username: "invited"}, author)
+		assert.NotNil(t, err)
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusNotFound, herr.Code)
+		assert.Equal(t, entity.InvitationNotFound, herr.Internal.(*entity.InternalError).Code)
+	})
+
+	t.Run("validator failed", func(t *testing.T) {
+		_, err := uc.RevokeInvitation(ctx, &payloadentity.UserInvitationPayload{}, author)
+		assert.NotNil(t, err)
+		herr, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, herr.Code)
+	})
+}
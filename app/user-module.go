@@ -0,0 +1,62 @@
+package app
+
+import (
+	"embed"
+	"io/fs"
+	"rap-c/app/handler/api"
+	"rap-c/app/handler/web"
+	authrepository "rap-c/app/repository/mysql/auth-repository"
+	invitationrepository "rap-c/app/repository/mysql/invitation-repository"
+	userrepository "rap-c/app/repository/mysql/user-repository"
+	authusecase "rap-c/app/usecase/auth-usecase"
+	formatterusecase "rap-c/app/usecase/formatter-usecase"
+	sessionusecase "rap-c/app/usecase/session-usecase"
+	userusecase "rap-c/app/usecase/user-usecase"
+	"rap-c/route"
+)
+
+//go:embed migrations/user
+var userMigrations embed.FS
+
+// user, login, session & setup wizard, needs mail module
+func NewUserModule() Module {
+	return &userModule{}
+}
+
+type userModule struct{}
+
+func (m *userModule) Name() string {
+	return "user"
+}
+
+func (m *userModule) Migrations() fs.FS {
+	return moduleMigrations(userMigrations, m.Name())
+}
+
+func (m *userModule) Load(c *Container) error {
+	if c.MailUsecase == nil {
+		return errMissing(m, "mail")
+	}
+	c.UserRepository = userrepository.New(c.DB)
+	c.AuthRepository = authrepository.New(c.DB)
+	c.InvitationRepository = invitationrepository.New(c.DB)
+
+	c.FormatterUsecase = formatterusecase.NewUsecase(c.Config, c.UserRepository)
+	c.AuthUsecase = authusecase.NewUsecase(c.Config, c.AuthRepository, c.InvitationRepository, c.MailUsecase)
+	c.UserUsecase = userusecase.NewUsecase(c.Config, c.UserRepository, c.InvitationRepository, c.MailUsecase)
+	c.SessionUsecase = sessionusecase.NewUsecase(c.Config, c.Route, c.SessionStore, c.AuthUsecase)
+	return nil
+}
+
+func (m *userModule) Routes(g *route.Group, c *Container) {
+	// api
+	route.SetAuthAPI(g, api.NewAuthHandler(c.Config, c.Route, c.AuthUsecase),
+		api.NewSetupHandler(c.Config, c.Route, c.UserUsecase, c.AuthUsecase))
+	route.SetUserAPI(g, api.NewUserHandler(c.Config, c.Route, c.UserUsecase, c.FormatterUsecase))
+
+	// web page
+	route.SetAuthWebPage(g, web.NewAuthPage(c.Config, c.Route, c.AuthUsecase, c.SessionUsecase, c.MailUsecase),
+		web.NewSetupPage(c.Config, c.Route, c.UserUsecase), c.UserUsecase)
+	route.SetUserWebPage(g, web.NewUserPage(c.Config, c.Route, c.SessionUsecase, c.UserUsecase, c.MailUsecase),
+		web.NewDashboardPage(c.Config, c.Route, c.SessionUsecase))
+}
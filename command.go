@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"rap-c/app/entity"
+	"rap-c/app/handler/cli"
+	"rap-c/config"
+	"strings"
+	"time"
+)
+
+const usage string = `Usage: rap-c <command> [arguments]
+
+Commands:
+  serve                        run http server & mail worker (default)
+  install                      generate .env file, prompt unanswered config
+      -answers <file.yaml|file.json>, -set KEY=VALUE, -non-interactive, -output, -force
+  migrate <up|down|status>     apply, revert or show database schema migrations
+  seed                         create guest user when not exists
+  user create                  create user & send invitation link
+      -username, -full-name, -email, -guest
+  user reset-password          send reset password link
+      -email
+  user disable                 disable user
+      -username
+  config check                 validate config, database & smtp connection and schema
+  version                      print version
+`
+
+// dispatch subcommand from os args
+func run(args []string) {
+	if len(args) == 0 {
+		serve()
+		return
+	}
+
+	switch args[0] {
+	case "serve":
+		serve()
+	case "install":
+		install(args[1:])
+	case "migrate":
+		migrate(argAt(args, 1))
+	case "seed":
+		seed()
+	case "user":
+		user(argAt(args, 1), args[min(len(args), 2):])
+	case "config":
+		configCommand(argAt(args, 1))
+	case "version":
+		fmt.Printf("%s %s\n", config.AppName, config.Version)
+	case "help", "-h", "-help", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Printf("Unknown command `%s`\n\n", args[0])
+		fmt.Print(usage)
+		os.Exit(1)
+	}
+}
+
+func argAt(args []string, i int) string {
+	if i < len(args) {
+		return args[i]
+	}
+	return ""
+}
+
+// config values from repeated -set KEY=VALUE flag
+type setFlag map[string]string
+
+func (f setFlag) String() string {
+	return fmt.Sprint(map[string]string(f))
+}
+
+func (f setFlag) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expect KEY=VALUE, got `%s`", value)
+	}
+	f[key] = val
+	return nil
+}
+
+// generate .env from flags, answers file, environment or prompt
+func install(args []string) {
+	opt := &config.InstallOption{Values: make(map[string]string)}
+	fs := flag.NewFlagSet("install", flag.ExitOnError)
+	fs.StringVar(&opt.AnswersFile, "answers", "", "yaml or json file of answers, keyed by env variable name")
+	fs.Var(setFlag(opt.Values), "set", "answer as KEY=VALUE, can be repeated")
+	fs.BoolVar(&opt.NonInteractive, "non-interactive", false, "never prompt, unanswered config use default value")
+	fs.StringVar(&opt.Output, "output", ".env", "generated file location")
+	fs.BoolVar(&opt.Force, "force", false, "overwrite existing file")
+	fs.Parse(args)
+
+	config.GenerateDotEnv(opt)
+}
+
+// run migration command: up, down or status
+func migrate(command string) {
+	if command != "up" && command != "down" && command != "status" {
+		fmt.Println("Usage: rap-c migrate <up|down|status>")
+		os.Exit(1)
+	}
+
+	cfg := config.InitConfig()
+	db := cfg.ConnectDB()
+	migrator, err := newBuilder(cfg, db, config.InitRoute()).Migrator()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	switch command {
+	case "up":
+		migrations, err := migrator.Up()
+		for _, itm := range migrations {
+			fmt.Printf("Applied %d_%s\n", itm.Version, itm.Name)
+		}
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if len(migrations) == 0 {
+			fmt.Println("Nothing to migrate, schema is up to date")
+		}
+	case "down":
+		itm, err := migrator.Down()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if itm == nil {
+			fmt.Println("Nothing to revert")
+		} else {
+			fmt.Printf("Reverted %d_%s\n", itm.Version, itm.Name)
+		}
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		for _, itm := range statuses {
+			appliedAt := "pending"
+			if itm.AppliedAt != nil {
+				appliedAt = itm.AppliedAt.Format(time.DateTime)
+			}
+			fmt.Printf("%-6d %-40s %s\n", itm.Version, itm.Name, appliedAt)
+		}
+	}
+}
+
+// create guest user without running server
+func seed() {
+	cfg := config.InitConfig()
+	db := cfg.ConnectDB()
+	router := config.InitRoute()
+	checkSchema(newBuilder(cfg, db, router))
+	seedDB(cfg, db, router)
+	fmt.Println("Seed done")
+}
+
+// run user admin command: create, reset-password or disable
+func user(command string, args []string) {
+	if command != "create" && command != "reset-password" && command != "disable" {
+		fmt.Println("Usage: rap-c user <create|reset-password|disable> [flags]")
+		os.Exit(1)
+	}
+
+	cfg := config.InitConfig()
+	db := cfg.ConnectDB()
+	router := config.InitRoute()
+	builder := newBuilder(cfg, db, router)
+	checkSchema(builder)
+	err := entity.SetupLogger(cfg)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	c := build(builder).Container
+	userCLI := cli.NewUserCLI(cfg, os.Stdout, c.UserUsecase, c.AuthUsecase, c.MailUsecase)
+
+	// changes made from cli are recorded in audit log with cli as ip address
+	ctx := entity.ContextWithIPAddress(context.Background(), "cli")
+	switch command {
+	case "create":
+		err = userCLI.Create(ctx, args)
+	case "reset-password":
+		err = userCLI.ResetPassword(ctx, args)
+	case "disable":
+		err = userCLI.Disable(ctx, args)
+	}
+	if err != nil {
+		fmt.Println(cli.ErrorMessage(err))
+		os.Exit(1)
+	}
+}
+
+// run config command: check
+func configCommand(command string) {
+	if command != "check" {
+		fmt.Println("Usage: rap-c config check")
+		os.Exit(1)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Println("Config cannot be loaded:", err)
+		os.Exit(1)
+	}
+	results := cfg.Check()
+	results = append(results, checkSchemaResult(cfg, results))
+
+	if printCheckReport(results) > 0 {
+		os.Exit(1)
+	}
+}
+
+// schema check is skipped when database is unreachable
+func checkSchemaResult(cfg *config.Config, results []*config.CheckResult) *config.CheckResult {
+	result := &config.CheckResult{Section: "connection", Name: "schema", Status: config.CheckOK}
+	for _, itm := range results {
+		if itm.Name == config.CheckDatabaseName && itm.Status != config.CheckOK {
+			result.Status = config.CheckWarning
+			result.Message = "not checked, database unreachable"
+			return result
+		}
+	}
+
+	db, err := cfg.OpenDB()
+	if err != nil {
+		result.Status = config.CheckFailed
+		result.Message = err.Error()
+		return result
+	}
+	sqlDB, _ := db.DB()
+	defer sqlDB.Close()
+
+	migrator, err := newBuilder(cfg, db, config.InitRoute()).Migrator()
+	var pending int
+	if err == nil {
+		pending, err = migrator.Pending()
+	}
+	if err != nil {
+		result.Status = config.CheckFailed
+		result.Message = err.Error()
+	} else if pending > 0 {
+		result.Status = config.CheckFailed
+		result.Message = fmt.Sprintf("behind by %d migration(s), run `rap-c migrate up`", pending)
+	} else {
+		result.Message = "up to date"
+	}
+	return result
+}
+
+// print check results grouped by section, return total failed
+func printCheckReport(results []*config.CheckResult) int {
+	var section string
+	var total = make(map[config.CheckStatus]int)
+	for _, itm := range results {
+		if itm.Section != section {
+			if section != "" {
+				fmt.Println()
+			}
+			section = itm.Section
+			fmt.Println(section)
+		}
+		total[itm.Status]++
+		line := fmt.Sprintf("  [%-4s] %s", itm.Status, itm.Name)
+		if itm.Message != "" {
+			line = fmt.Sprintf("%-50s %s", line, itm.Message)
+		}
+		fmt.Println(line)
+	}
+	fmt.Printf("\n%d ok, %d warning, %d failed\n", total[config.CheckOK], total[config.CheckWarning], total[config.CheckFailed])
+	return total[config.CheckFailed]
+}
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-gomail/gomail"
+	"github.com/go-playground/validator/v10"
+)
+
+type CheckStatus string
+
+const (
+	CheckOK      CheckStatus = "OK"
+	CheckWarning CheckStatus = "WARN"
+	CheckFailed  CheckStatus = "FAIL"
+)
+
+// name of database connection check result
+const CheckDatabaseName string = "database"
+
+// connection check timeout
+const checkTimeout time.Duration = time.Second * 10
+
+// default & well known secrets refused by config check
+var defaultSecrets = []string{"secret", "session secret", "password", "changeme", "12345678"}
+
+// single line of config check report
+type CheckResult struct {
+	Section string
+	Name    string
+	Status  CheckStatus
+	Message string
+}
+
+// validate every config field then check database & smtp connection
+func (cfg *Config) Check() []*CheckResult {
+	result := cfg.checkFields()
+	result = append(result, cfg.checkDatabase(), cfg.checkMail(), cfg.checkTLS())
+	return result
+}
+
+// validate config field by validate tag, one result for each env variable
+func (cfg *Config) checkFields() []*CheckResult {
+	messages := make(map[string]string)
+	err := configValidator().Struct(cfg.config)
+	var fieldErrors validator.ValidationErrors
+	if errors.As(err, &fieldErrors) {
+		for _, fe := range fieldErrors {
+			// list item error, e.g. TrustedProxies[1], is reported on the list field
+			name := strings.SplitN(fe.StructField(), "[", 2)[0]
+			if _, ok := messages[name]; !ok {
+				messages[name] = checkMessage(fe)
+			}
+		}
+	}
+
+	var result []*CheckResult
+	var section string
+	elm := reflect.TypeOf(*cfg.config)
+	for i := 0; i < elm.NumField(); i++ {
+		field := elm.Field(i)
+		if title, ok := sectionTitles[field.Name]; ok {
+			section = title
+		}
+		env := field.Tag.Get("envconfig")
+		if env == "" || env == "-" {
+			continue
+		}
+
+		res := &CheckResult{Section: section, Name: env, Status: CheckOK}
+		if msg, ok := messages[field.Name]; ok {
+			res.Status = CheckFailed
+			res.Message = msg
+		}
+		result = append(result, res)
+	}
+	return result
+}
+
+func (cfg *Config) checkDatabase() *CheckResult {
+	result := &CheckResult{Section: "connection", Name: CheckDatabaseName, Status: CheckOK}
+	target := fmt.Sprintf("mysql %s:%d/%s", cfg.config.MysqlHost, cfg.config.MysqlPort, cfg.config.MysqlDBName)
+	switch cfg.config.DBDriver {
+	case DBDriverPostgres:
+		target = fmt.Sprintf("postgres %s:%d/%s", cfg.config.PostgresHost, cfg.config.PostgresPort, cfg.config.PostgresDBName)
+	case DBDriverSQLite:
+		target = fmt.Sprintf("sqlite %s", cfg.config.SQLitePath)
+	}
+
+	db, err := cfg.OpenDB()
+	if err == nil {
+		sqlDB, _ := db.DB()
+		defer sqlDB.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+		defer cancel()
+		err = sqlDB.PingContext(ctx)
+	}
+	if err != nil {
+		result.Status = CheckFailed
+		result.Message = fmt.Sprintf("cannot connect to %s: %v", target, err)
+		return result
+	}
+	result.Message = fmt.Sprintf("connected to %s", target)
+	return result
+}
+
+// smtp dial & auth, or folder check for file transport
+func (cfg *Config) checkMail() *CheckResult {
+	result := &CheckResult{Section: "connection", Name: "mail", Status: CheckOK}
+	// file & stdout transport expose reset password & invitation links to anyone reading them
+	if cfg.config.MailTransport != MailTransportSMTP && !cfg.IsDevelopment() {
+		result.Status = CheckFailed
+		result.Message = fmt.Sprintf("mail transport %s is for development only, use smtp or set APP_ENV=%s", cfg.config.MailTransport, AppEnvDevelopment)
+		return result
+	}
+	switch cfg.config.MailTransport {
+	case MailTransportSMTP:
+		target := fmt.Sprintf("%s:%d", cfg.config.MailHost, cfg.config.MailPort)
+		dialer := gomail.NewDialer(cfg.config.MailHost, cfg.config.MailPort, cfg.config.MailUser, cfg.config.MailPassword)
+		conn, err := dialer.Dial()
+		if err != nil {
+			result.Status = CheckFailed
+			result.Message = fmt.Sprintf("cannot connect to smtp %s: %v", target, err)
+			return result
+		}
+		conn.Close()
+		result.Message = fmt.Sprintf("connected to smtp %s", target)
+	case MailTransportFile:
+		info, err := os.Stat(cfg.config.MailFileDir)
+		if os.IsNotExist(err) {
+			result.Status = CheckWarning
+			result.Message = fmt.Sprintf("folder %s not exists, created on first mail", cfg.config.MailFileDir)
+		} else if err != nil {
+			result.Status = CheckFailed
+			result.Message = err.Error()
+		} else if !info.IsDir() {
+			result.Status = CheckFailed
+			result.Message = fmt.Sprintf("%s is not a folder", cfg.config.MailFileDir)
+		} else {
+			result.Message = fmt.Sprintf("mail written to %s", cfg.config.MailFileDir)
+		}
+	default:
+		result.Message = fmt.Sprintf("mail transport is %s, smtp not checked", cfg.config.MailTransport)
+	}
+	return result
+}
+
+// load tls certificate & key pair when https is enabled
+func (cfg *Config) checkTLS() *CheckResult {
+	result := &CheckResult{Section: "connection", Name: "tls", Status: CheckOK}
+	if !cfg.EnableTLS() {
+		result.Message = "tls certificate not set, serve plain http"
+		return result
+	}
+	_, err := tls.LoadX509KeyPair(cfg.config.TLSCertFile, cfg.config.TLSKeyFile)
+	if err != nil {
+		result.Status = CheckFailed
+		result.Message = fmt.Sprintf("cannot load tls certificate: %v", err)
+		return result
+	}
+	result.Message = fmt.Sprintf("serve https with %s", cfg.config.TLSCertFile)
+	return result
+}
+
+func configValidator() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterValidation("secret", func(fl validator.FieldLevel) bool {
+		value := strings.TrimSpace(fl.Field().String())
+		for _, secret := range defaultSecrets {
+			if strings.EqualFold(value, secret) {
+				return false
+			}
+		}
+		return true
+	})
+	return validate
+}
+
+// readable message of failed validate tag
+func checkMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "required_if", "required_with":
+		return "must not be empty"
+	case "file":
+		return "must be an existing file"
+	case "gtfield":
+		// compared field is shown as env variable name
+		if field, ok := reflect.TypeOf(config{}).FieldByName(fe.Param()); ok {
+			return fmt.Sprintf("must be greater than %s", field.Tag.Get("envconfig"))
+		}
+		return fmt.Sprintf("must be greater than %s", fe.Param())
+	case "min":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be at least %s characters", fe.Param())
+		}
+		return fmt.Sprintf("must be at least %s", fe.Param())
+	case "max":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be at most %s characters", fe.Param())
+		}
+		return fmt.Sprintf("must be at most %s", fe.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", strings.ReplaceAll(fe.Param(), " ", ", "))
+	case "email":
+		return "must be a valid email address"
+	case "http_url":
+		return "must be a valid http or https url"
+	case "cidr":
+		return fmt.Sprintf("`%v` must be an ip range, e.g. 10.0.0.0/8", fe.Value())
+	case "secret":
+		return "must not use default or well known secret"
+	}
+	return fmt.Sprintf("failed on `%s` rule", fe.Tag())
+}
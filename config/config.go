@@ -3,12 +3,18 @@ package config
 import (
 	"fmt"
 	"log"
+	"net"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/glebarez/sqlite"
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
@@ -16,12 +22,14 @@ import (
 const (
 	GuestUsername         string = "Guest"
 	GuestEmail            string = "guest@example.com"
-	GuestPassword         string = "$GuestP@ssw0rD!"
 	AppName               string = "Rap-C"
 	EchoJwtUserContextKey string = "user"
 	EchoTokenContextKey   string = "token"
 )
 
+// application version, set on build with -ldflags "-X rap-c/config.Version=v1.0.0"
+var Version string = "dev"
+
 type LogMode int
 
 const (
@@ -30,49 +38,127 @@ const (
 	LogModeAll
 )
 
+const (
+	LogFormatText string = "text"
+	LogFormatJSON string = "json"
+)
+
+const (
+	AppEnvProduction  string = "production"
+	AppEnvDevelopment string = "development"
+)
+
+const (
+	MailTransportSMTP   string = "smtp"
+	MailTransportFile   string = "file"
+	MailTransportStdout string = "stdout"
+)
+
+const (
+	DBDriverMySQL    string = "mysql"
+	DBDriverPostgres string = "postgres"
+	DBDriverSQLite   string = "sqlite"
+)
+
 // immutable config
 type config struct {
-	Port               int     `envconfig:"HTTP_PORT" default:"8080" prompt:"Enter port to serve http"`
+	Port               int     `envconfig:"HTTP_PORT" default:"8080" prompt:"Enter port to serve http" validate:"min=1,max=65535"`
 	EnableDebug        bool    `envconfig:"ENABLE_DEBUG" default:"false" prompt:"Enable debug to show error received"`
-	LogMode            LogMode `envconfig:"LOG_MODE" default:"1" prompt:"Enter log mode (1:error, 2:error & warn, 3:all)"`
+	AppEnv             string  `envconfig:"APP_ENV" default:"production" prompt:"Enter app environment (production or development), development pages are only served in development" validate:"oneof=production development"`
+	LogMode            LogMode `envconfig:"LOG_MODE" default:"1" prompt:"Enter log mode (1:error, 2:error & warn, 3:all)" validate:"min=1,max=3"`
 	EnableWarnFileLog  bool    `envconfig:"ENABLE_WARN_FILE_LOG" default:"false" prompt:"Enable log for warning type error (eg: http bad request error)"`
 	EnableGuestLogin   bool    `envconfig:"ENABLE_GUEST_LOGIN" default:"false" prompt:"Enable guest login"`
 	AutoReloadTemplate bool    `envconfig:"AUTO_RELOAD_TEMPLATE" default:"false" prompt:"Auto reload template (not recommended for production)"`
-	SessionKey         string  `envconfig:"SESSION_KEY" default:"session secret" prompt:"Enter http session key secret"`
-	AppURL             string  `envconfig:"APP_URL" default:"http://localhost:8080" prompt:"Enter website location"`
+	SessionKey         string  `envconfig:"SESSION_KEY" default:"session secret" prompt:"Enter http session key secret" validate:"secret,min=32"`
+	AppURL             string  `envconfig:"APP_URL" default:"http://localhost:8080" prompt:"Enter website location" validate:"required,http_url"`
+
+	// http server
+	ListenHost               string   `envconfig:"HTTP_HOST" prompt:"Enter host or ip address to listen, empty to listen on all interfaces"`
+	ReadTimeoutInSeconds     int      `envconfig:"HTTP_READ_TIMEOUT_IN_SECONDS" default:"30" prompt:"Enter maximum time to read request in seconds" validate:"min=1"`
+	WriteTimeoutInSeconds    int      `envconfig:"HTTP_WRITE_TIMEOUT_IN_SECONDS" default:"60" prompt:"Enter maximum time to write response in seconds, longer than request timeout" validate:"min=1,gtfield=RequestTimeoutInSeconds"`
+	IdleTimeoutInSeconds     int      `envconfig:"HTTP_IDLE_TIMEOUT_IN_SECONDS" default:"120" prompt:"Enter maximum time to keep idle connection in seconds" validate:"min=1"`
+	RequestTimeoutInSeconds  int      `envconfig:"HTTP_REQUEST_TIMEOUT_IN_SECONDS" default:"30" prompt:"Enter maximum time to handle request in seconds" validate:"min=1"`
+	ShutdownTimeoutInSeconds int      `envconfig:"HTTP_SHUTDOWN_TIMEOUT_IN_SECONDS" default:"30" prompt:"Enter maximum time to finish running requests & mails on shutdown in seconds" validate:"min=1"`
+	TLSCertFile              string   `envconfig:"TLS_CERT_FILE" prompt:"Enter tls certificate file, empty to serve plain http" validate:"required_with=TLSKeyFile,omitempty,file"`
+	TLSKeyFile               string   `envconfig:"TLS_KEY_FILE" prompt:"Enter tls private key file, empty to serve plain http" validate:"required_with=TLSCertFile,omitempty,file"`
+	TrustedProxies           []string `envconfig:"HTTP_TRUSTED_PROXIES" prompt:"Enter comma separated ip ranges of reverse proxies allowed to set X-Forwarded-For (e.g. 10.0.0.0/8), empty when clients connect directly" validate:"dive,cidr"`
+
+	// log
+	LogFormat       string `envconfig:"LOG_FORMAT" default:"text" prompt:"Enter log format (text or json)" validate:"oneof=text json"`
+	LogDir          string `envconfig:"LOG_DIR" default:"storage/log" prompt:"Enter folder of error & warning log files" validate:"required"`
+	LogMaxSizeInMB  int    `envconfig:"LOG_MAX_SIZE_IN_MB" default:"10" prompt:"Enter maximum log file size in megabytes before rotated" validate:"min=1"`
+	LogMaxAgeInDays int    `envconfig:"LOG_MAX_AGE_IN_DAYS" default:"30" prompt:"Enter maximum days to keep rotated log files" validate:"min=1"`
+	LogMaxBackups   int    `envconfig:"LOG_MAX_BACKUPS" default:"10" prompt:"Enter maximum rotated log files to keep, 0 to keep all"`
 
 	// jwt
-	JwtSecret              string `envconfig:"JWT_SECRET" default:"secret" prompt:"Enter secret to generate JWT token"`
-	JwtExpirationInMinutes int    `envconfig:"JWT_EXPIRATION_IN_MINUTES" default:"60" prompt:"Enter token expired in minute"`
-	JwtRememberInDays      int    `envconfig:"JWT_REMEMBER_IN_DAYS" default:"30" prompt:"Enter token remember(for remember login) in days"`
+	JwtSecret              string `envconfig:"JWT_SECRET" default:"secret" prompt:"Enter secret to generate JWT token" validate:"secret,min=32"`
+	JwtExpirationInMinutes int    `envconfig:"JWT_EXPIRATION_IN_MINUTES" default:"60" prompt:"Enter token expired in minute" validate:"min=1"`
+	JwtRememberInDays      int    `envconfig:"JWT_REMEMBER_IN_DAYS" default:"30" prompt:"Enter token remember(for remember login) in days" validate:"min=1"`
+	VerifyTokenInHours     int    `envconfig:"EMAIL_VERIFICATION_EXPIRATION_IN_HOURS" default:"24" prompt:"Enter email verification link expired in hours" validate:"min=1"`
+	InviteTokenInHours     int    `envconfig:"INVITATION_EXPIRATION_IN_HOURS" default:"72" prompt:"Enter user invitation link expired in hours" validate:"min=1"`
+
+	// reset password
+	ResetTokenInMinutes int `envconfig:"RESET_TOKEN_EXPIRATION_IN_MINUTES" default:"60" prompt:"Enter reset password token expired in minute" validate:"min=1"`
+	ResetLimitPerEmail  int `envconfig:"RESET_REQUEST_LIMIT_PER_EMAIL" default:"3" prompt:"Enter maximum reset password request per email in limit window" validate:"min=1"`
+	ResetLimitPerIP     int `envconfig:"RESET_REQUEST_LIMIT_PER_IP" default:"10" prompt:"Enter maximum reset password request per ip address in limit window" validate:"min=1"`
+	ResetLimitInMinutes int `envconfig:"RESET_REQUEST_LIMIT_WINDOW_IN_MINUTES" default:"60" prompt:"Enter reset password request limit window in minute" validate:"min=1"`
+
+	// mail transport
+	MailTransport string `envconfig:"MAIL_TRANSPORT" default:"smtp" prompt:"Enter mail transport (smtp, file or stdout)" validate:"oneof=smtp file stdout"`
+	MailFileDir   string `envconfig:"MAIL_FILE_DIR" default:"storage/mail" prompt:"Enter folder to save .eml files for file mail transport" validate:"required_if=MailTransport file"`
+
+	// mail template
+	MailLocale      string `envconfig:"MAIL_LOCALE" default:"id" prompt:"Enter email language (id or en)" validate:"oneof=id en"`
+	MailTemplateDir string `envconfig:"MAIL_TEMPLATE_DIR" default:"storage/templates/mail" prompt:"Enter folder of custom email templates" validate:"required"`
 
 	// smtp
-	MailHost          string `envconfig:"MAIL_HOST" default:"smtp.gmail.com" prompt:"Enter smtp server host"`
-	MailPort          int    `envconfig:"MAIL_PORT" default:"465" prompt:"Enter smtp server port"`
+	MailHost          string `envconfig:"MAIL_HOST" default:"smtp.gmail.com" prompt:"Enter smtp server host" validate:"required_if=MailTransport smtp"`
+	MailPort          int    `envconfig:"MAIL_PORT" default:"465" prompt:"Enter smtp server port" validate:"min=1,max=65535"`
 	MailUser          string `envconfig:"MAIL_USER" prompt:"Enter smtp server user"`
 	MailPassword      string `envconfig:"MAIL_PASSWORD" prompt:"Enter smtp server password" secret:"true"`
-	MailSenderName    string `envconfig:"MAIL_SENDER_NAME" default:"Rap-C" prompt:"Enter email sender name"`
-	MailSenderAddress string `envconfig:"MAIL_SENDER_ADDRESS" prompt:"Enter email sender address"`
+	MailSenderName    string `envconfig:"MAIL_SENDER_NAME" default:"Rap-C" prompt:"Enter email sender name" validate:"required"`
+	MailSenderAddress string `envconfig:"MAIL_SENDER_ADDRESS" prompt:"Enter email sender address" validate:"required,email"`
+
+	// mail outbox
+	OutboxInterval    int `envconfig:"MAIL_OUTBOX_INTERVAL_IN_SECONDS" default:"10" prompt:"Enter interval of mail worker checking outbox in seconds" validate:"min=1"`
+	OutboxBatchSize   int `envconfig:"MAIL_OUTBOX_BATCH_SIZE" default:"20" prompt:"Enter maximum mail sent by worker in each interval" validate:"min=1"`
+	OutboxMaxAttempts int `envconfig:"MAIL_OUTBOX_MAX_ATTEMPTS" default:"5" prompt:"Enter maximum send attempts before mail is moved to dead letter" validate:"min=1"`
+	OutboxBackoff     int `envconfig:"MAIL_OUTBOX_BACKOFF_IN_SECONDS" default:"30" prompt:"Enter first retry delay in seconds, doubled on each next retry" validate:"min=1"`
 
-	// first user installation
-	FirstUserUsername string `envconfig:"FIRST_USER_USERNAME" default:"gendutski" prompt:"Enter first user username"`
-	FirstUserFullName string `envconfig:"FIRST_USER_FULL_NAME" default:"Firman Darmawan" prompt:"Enter first user full name"`
-	FirstUserEmail    string `envconfig:"FIRST_USER_EMAIL" default:"mvp.firman.darmawan@gmail.com" prompt:"Enter first user email"`
-	FirstUserPassword string `envconfig:"FIRST_USER_PASSWORD" default:"password" prompt:"Enter first user password" secret:"true"`
+	// metrics
+	EnableMetrics     bool    `envconfig:"ENABLE_METRICS" default:"true" prompt:"Enable prometheus metrics endpoint"`
+	MetricsToken      string  `envconfig:"METRICS_TOKEN" prompt:"Enter bearer token required to read metrics, empty to allow anyone" secret:"true"`
+	LowStockThreshold float64 `envconfig:"METRICS_LOW_STOCK_THRESHOLD" default:"10" prompt:"Enter ingredient stock counted as low stock in metrics" validate:"min=0"`
+
+	// database
+	DBDriver   string `envconfig:"DB_DRIVER" default:"mysql" prompt:"Enter database driver (mysql, postgres or sqlite)" validate:"oneof=mysql postgres sqlite"`
+	SQLitePath string `envconfig:"SQLITE_PATH" default:"storage/rap-c.db" prompt:"Enter sqlite database file for sqlite driver" validate:"required_if=DBDriver sqlite"`
 
 	// mysql
-	MysqlHost                  string `envconfig:"MYSQL_HOST" default:"localhost" prompt:"Enter mysql host"`
-	MysqlPort                  int    `envconfig:"MYSQL_PORT" default:"3306" prompt:"Enter mysql port"`
-	MysqlDBName                string `envconfig:"MYSQL_DB_NAME" default:"rap_c" prompt:"Enter database name"`
-	MysqlUsername              string `envconfig:"MYSQL_USERNAME" default:"" prompt:"Enter mysql username"`
+	MysqlHost                  string `envconfig:"MYSQL_HOST" default:"localhost" prompt:"Enter mysql host" validate:"required_if=DBDriver mysql"`
+	MysqlPort                  int    `envconfig:"MYSQL_PORT" default:"3306" prompt:"Enter mysql port" validate:"min=1,max=65535"`
+	MysqlDBName                string `envconfig:"MYSQL_DB_NAME" default:"rap_c" prompt:"Enter database name" validate:"required_if=DBDriver mysql"`
+	MysqlUsername              string `envconfig:"MYSQL_USERNAME" default:"" prompt:"Enter mysql username" validate:"required_if=DBDriver mysql"`
 	MysqlPassword              string `envconfig:"MYSQL_PASSWORD" default:"" prompt:"Enter mysql password" secret:"true"`
-	MysqlLogMode               int    `envconfig:"MYSQL_LOG_MODE" default:"1" prompt:"Enter gorm log mode 1-4"`
+	MysqlLogMode               int    `envconfig:"MYSQL_LOG_MODE" default:"1" prompt:"Enter gorm log mode 1-4" validate:"min=1,max=4"`
 	MysqlParseTime             bool   `envconfig:"MYSQL_PARSE_TIME" default:"true" prompt:"Parse mysql time to local"`
-	MysqlCharset               string `envconfig:"MYSQL_CHARSET" default:"utf8mb4" prompt:"Enter mysql database charset"`
-	MysqlLoc                   string `envconfig:"MYSQL_LOC" default:"Local" prompt:"Enter mysql local time"`
-	MysqlMaxLifetimeConnection int    `envconfig:"MYSQL_MAX_LIFETIME_CONNECTION" default:"10" prompt:"Enter mysql maximum amount of time a connection may be reused, in minute"`
-	MysqlMaxOpenConnection     int    `envconfig:"MYSQL_MAX_OPEN_CONNECTION" default:"50" prompt:"Enter mysql maximum number of open connections to the database"`
+	MysqlCharset               string `envconfig:"MYSQL_CHARSET" default:"utf8mb4" prompt:"Enter mysql database charset" validate:"required_if=DBDriver mysql"`
+	MysqlLoc                   string `envconfig:"MYSQL_LOC" default:"Local" prompt:"Enter mysql local time" validate:"required_if=DBDriver mysql"`
+	MysqlMaxLifetimeConnection int    `envconfig:"MYSQL_MAX_LIFETIME_CONNECTION" default:"10" prompt:"Enter mysql maximum amount of time a connection may be reused, in minute" validate:"min=1"`
+	MysqlMaxOpenConnection     int    `envconfig:"MYSQL_MAX_OPEN_CONNECTION" default:"50" prompt:"Enter mysql maximum number of open connections to the database" validate:"min=1"`
 	MysqlMaxIdleConnection     int    `envconfig:"MYSQL_MAX_IDLE_CONNECTION" default:"10" prompt:"Enter mysql maximum number of connections in the idle connection pool"`
+
+	// postgres
+	PostgresHost                  string `envconfig:"POSTGRES_HOST" default:"localhost" prompt:"Enter postgres host" validate:"required_if=DBDriver postgres"`
+	PostgresPort                  int    `envconfig:"POSTGRES_PORT" default:"5432" prompt:"Enter postgres port" validate:"min=1,max=65535"`
+	PostgresDBName                string `envconfig:"POSTGRES_DB_NAME" default:"rap_c" prompt:"Enter postgres database name" validate:"required_if=DBDriver postgres"`
+	PostgresUsername              string `envconfig:"POSTGRES_USERNAME" default:"" prompt:"Enter postgres username" validate:"required_if=DBDriver postgres"`
+	PostgresPassword              string `envconfig:"POSTGRES_PASSWORD" default:"" prompt:"Enter postgres password" secret:"true"`
+	PostgresSSLMode               string `envconfig:"POSTGRES_SSL_MODE" default:"disable" prompt:"Enter postgres ssl mode (disable, require, verify-ca or verify-full)" validate:"oneof=disable require verify-ca verify-full"`
+	PostgresTimeZone              string `envconfig:"POSTGRES_TIMEZONE" default:"UTC" prompt:"Enter postgres session time zone" validate:"required_if=DBDriver postgres"`
+	PostgresMaxLifetimeConnection int    `envconfig:"POSTGRES_MAX_LIFETIME_CONNECTION" default:"10" prompt:"Enter postgres maximum amount of time a connection may be reused, in minute" validate:"min=1"`
+	PostgresMaxOpenConnection     int    `envconfig:"POSTGRES_MAX_OPEN_CONNECTION" default:"50" prompt:"Enter postgres maximum number of open connections to the database" validate:"min=1"`
+	PostgresMaxIdleConnection     int    `envconfig:"POSTGRES_MAX_IDLE_CONNECTION" default:"10" prompt:"Enter postgres maximum number of connections in the idle connection pool"`
 }
 
 type Config struct {
@@ -80,13 +166,26 @@ type Config struct {
 }
 
 func InitConfig() *Config {
+	cfg, err := LoadConfig()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	return cfg
+}
+
+// load config from .env & environment, return error instead of exit
+func LoadConfig() (*Config, error) {
 	var cfg config
 	err := godotenv.Overload()
 	if err != nil {
 		log.Println(err)
 	}
-	envconfig.MustProcess("", &cfg)
-	return &Config{config: &cfg}
+	err = envconfig.Process("", &cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Config{config: &cfg}, nil
 }
 
 // for testing purposes
@@ -120,6 +219,45 @@ func (cfg *Config) URL(path string) string {
 
 // connect to db
 func (cfg *Config) ConnectDB() *gorm.DB {
+	db, err := cfg.OpenDB()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	return db
+}
+
+// connect to db, return error instead of exit
+func (cfg *Config) OpenDB() (*gorm.DB, error) {
+	gormConfig := &gorm.Config{
+		Logger: logger.Default.LogMode(logger.LogLevel(cfg.config.MysqlLogMode)),
+	}
+	switch cfg.config.DBDriver {
+	case DBDriverMySQL:
+		return cfg.openMysql(gormConfig)
+	case DBDriverPostgres:
+		return cfg.openPostgres(gormConfig)
+	case DBDriverSQLite:
+		return cfg.openSQLite(gormConfig)
+	}
+	return nil, fmt.Errorf("unknown database driver `%s`, use mysql, postgres or sqlite", cfg.config.DBDriver)
+}
+
+// copy of config connecting to another database of the same server, sqlite uses name as file path
+func (cfg *Config) WithDBName(name string) *Config {
+	copied := *cfg.config
+	switch copied.DBDriver {
+	case DBDriverMySQL:
+		copied.MysqlDBName = name
+	case DBDriverPostgres:
+		copied.PostgresDBName = name
+	case DBDriverSQLite:
+		copied.SQLitePath = name
+	}
+	return &Config{config: &copied}
+}
+
+func (cfg *Config) openMysql(gormConfig *gorm.Config) (*gorm.DB, error) {
 	// construct connection string
 	dsn := fmt.Sprintf(
 		"%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=%+v&loc=%s",
@@ -133,12 +271,9 @@ func (cfg *Config) ConnectDB() *gorm.DB {
 		cfg.config.MysqlLoc)
 
 	// open mysql connection
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.LogLevel(cfg.config.MysqlLogMode)),
-	})
+	db, err := gorm.Open(mysql.Open(dsn), gormConfig)
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return nil, err
 	}
 
 	// set configuration pooling connection
@@ -147,12 +282,58 @@ func (cfg *Config) ConnectDB() *gorm.DB {
 	mysqlDb.SetConnMaxLifetime(time.Duration(cfg.config.MysqlMaxLifetimeConnection) * time.Minute)
 	mysqlDb.SetMaxIdleConns(cfg.config.MysqlMaxIdleConnection)
 
-	return db
+	return db, nil
+}
+
+func (cfg *Config) openPostgres(gormConfig *gorm.Config) (*gorm.DB, error) {
+	// construct connection string, values are quoted so password may contain space
+	dsn := fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s TimeZone=%s",
+		postgresValue(cfg.config.PostgresHost),
+		cfg.config.PostgresPort,
+		postgresValue(cfg.config.PostgresUsername),
+		postgresValue(cfg.config.PostgresPassword),
+		postgresValue(cfg.config.PostgresDBName),
+		cfg.config.PostgresSSLMode,
+		postgresValue(cfg.config.PostgresTimeZone))
+
+	// open postgres connection
+	db, err := gorm.Open(postgres.Open(dsn), gormConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// set configuration pooling connection
+	postgresDb, _ := db.DB()
+	postgresDb.SetMaxOpenConns(cfg.config.PostgresMaxOpenConnection)
+	postgresDb.SetConnMaxLifetime(time.Duration(cfg.config.PostgresMaxLifetimeConnection) * time.Minute)
+	postgresDb.SetMaxIdleConns(cfg.config.PostgresMaxIdleConnection)
+
+	return db, nil
+}
+
+// quote postgres connection string value
+func postgresValue(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
+}
+
+// single file database, foreign keys are enforced & writer waits for lock instead of failing
+func (cfg *Config) openSQLite(gormConfig *gorm.Config) (*gorm.DB, error) {
+	err := os.MkdirAll(filepath.Dir(cfg.config.SQLitePath), 0755)
+	if err != nil {
+		return nil, err
+	}
+	// transaction takes write lock when it begins, so transaction reading before it writes waits for busy timeout
+	// instead of failing as soon as another transaction writes
+	dsn := cfg.config.SQLitePath + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate"
+	return gorm.Open(sqlite.Open(dsn), gormConfig)
 }
 
 // ----------------------private to public field-----------------------------\\
 func (cfg *Config) Port() int                { return cfg.config.Port }
 func (cfg *Config) EnableDebug() bool        { return cfg.config.EnableDebug }
+func (cfg *Config) IsDevelopment() bool      { return cfg.config.AppEnv == AppEnvDevelopment }
 func (cfg *Config) LogMode() LogMode         { return cfg.config.LogMode }
 func (cfg *Config) EnableWarnFileLog() bool  { return cfg.config.EnableWarnFileLog }
 func (cfg *Config) EnableGuestLogin() bool   { return cfg.config.EnableGuestLogin }
@@ -160,10 +341,54 @@ func (cfg *Config) AutoReloadTemplate() bool { return cfg.config.AutoReloadTempl
 func (cfg *Config) SessionKey() string       { return cfg.config.SessionKey }
 func (cfg *Config) AppURL() string           { return cfg.config.AppURL }
 
+// http server
+func (cfg *Config) ListenHost() string            { return cfg.config.ListenHost }
+func (cfg *Config) TrustedProxies() []string      { return cfg.config.TrustedProxies }
+func (cfg *Config) ReadTimeoutInSeconds() int     { return cfg.config.ReadTimeoutInSeconds }
+func (cfg *Config) WriteTimeoutInSeconds() int    { return cfg.config.WriteTimeoutInSeconds }
+func (cfg *Config) IdleTimeoutInSeconds() int     { return cfg.config.IdleTimeoutInSeconds }
+func (cfg *Config) RequestTimeoutInSeconds() int  { return cfg.config.RequestTimeoutInSeconds }
+func (cfg *Config) ShutdownTimeoutInSeconds() int { return cfg.config.ShutdownTimeoutInSeconds }
+func (cfg *Config) TLSCertFile() string           { return cfg.config.TLSCertFile }
+func (cfg *Config) TLSKeyFile() string            { return cfg.config.TLSKeyFile }
+
+// listen address of http server
+func (cfg *Config) ListenAddress() string {
+	return net.JoinHostPort(cfg.config.ListenHost, strconv.Itoa(cfg.config.Port))
+}
+
+// serve https when both tls certificate & key are set
+func (cfg *Config) EnableTLS() bool {
+	return cfg.config.TLSCertFile != "" && cfg.config.TLSKeyFile != ""
+}
+
+// log
+func (cfg *Config) LogFormat() string    { return cfg.config.LogFormat }
+func (cfg *Config) LogDir() string       { return cfg.config.LogDir }
+func (cfg *Config) LogMaxSizeInMB() int  { return cfg.config.LogMaxSizeInMB }
+func (cfg *Config) LogMaxAgeInDays() int { return cfg.config.LogMaxAgeInDays }
+func (cfg *Config) LogMaxBackups() int   { return cfg.config.LogMaxBackups }
+
 // jwt
 func (cfg *Config) JwtSecret() string           { return cfg.config.JwtSecret }
 func (cfg *Config) JwtExpirationInMinutes() int { return cfg.config.JwtExpirationInMinutes }
 func (cfg *Config) JwtRememberInDays() int      { return cfg.config.JwtRememberInDays }
+func (cfg *Config) VerifyTokenInHours() int     { return cfg.config.VerifyTokenInHours }
+func (cfg *Config) InviteTokenInHours() int     { return cfg.config.InviteTokenInHours }
+
+// reset password
+func (cfg *Config) ResetTokenInMinutes() int { return cfg.config.ResetTokenInMinutes }
+func (cfg *Config) ResetLimitPerEmail() int  { return cfg.config.ResetLimitPerEmail }
+func (cfg *Config) ResetLimitPerIP() int     { return cfg.config.ResetLimitPerIP }
+func (cfg *Config) ResetLimitInMinutes() int { return cfg.config.ResetLimitInMinutes }
+
+// mail transport
+func (cfg *Config) MailTransport() string { return cfg.config.MailTransport }
+func (cfg *Config) MailFileDir() string   { return cfg.config.MailFileDir }
+
+// mail template
+func (cfg *Config) MailLocale() string      { return cfg.config.MailLocale }
+func (cfg *Config) MailTemplateDir() string { return cfg.config.MailTemplateDir }
 
 // smtp
 func (cfg *Config) MailHost() string          { return cfg.config.MailHost }
@@ -173,8 +398,17 @@ func (cfg *Config) MailPassword() string      { return cfg.config.MailPassword }
 func (cfg *Config) MailSenderName() string    { return cfg.config.MailSenderName }
 func (cfg *Config) MailSenderAddress() string { return cfg.config.MailSenderAddress }
 
-// first user installation
-func (cfg *Config) FirstUserUsername() string { return cfg.config.FirstUserUsername }
-func (cfg *Config) FirstUserFullName() string { return cfg.config.FirstUserFullName }
-func (cfg *Config) FirstUserEmail() string    { return cfg.config.FirstUserEmail }
-func (cfg *Config) FirstUserPassword() string { return cfg.config.FirstUserPassword }
+// mail outbox
+func (cfg *Config) OutboxInterval() int    { return cfg.config.OutboxInterval }
+func (cfg *Config) OutboxBatchSize() int   { return cfg.config.OutboxBatchSize }
+func (cfg *Config) OutboxMaxAttempts() int { return cfg.config.OutboxMaxAttempts }
+func (cfg *Config) OutboxBackoff() int     { return cfg.config.OutboxBackoff }
+
+// database
+func (cfg *Config) DBDriver() string   { return cfg.config.DBDriver }
+func (cfg *Config) SQLitePath() string { return cfg.config.SQLitePath }
+
+// metrics
+func (cfg *Config) EnableMetrics() bool        { return cfg.config.EnableMetrics }
+func (cfg *Config) MetricsToken() string       { return cfg.config.MetricsToken }
+func (cfg *Config) LowStockThreshold() float64 { return cfg.config.LowStockThreshold }
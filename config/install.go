@@ -2,29 +2,122 @@ package config
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
 	"syscall"
 
 	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
 )
 
+// .env section comment, keyed by first field name of each section
+var sectionTitles = map[string]string{
+	"Port":                "main config",
+	"ListenHost":          "http server config",
+	"LogFormat":           "log config",
+	"JwtSecret":           "jwt config",
+	"ResetTokenInMinutes": "reset password config",
+	"MailTransport":       "mail transport config",
+	"MailLocale":          "mail template config",
+	"MailHost":            "smtp mail config",
+	"OutboxInterval":      "mail outbox config",
+	"EnableMetrics":       "metrics config",
+	"DBDriver":            "database config",
+	"MysqlHost":           "mysql config",
+	"PostgresHost":        "postgres config",
+}
+
+// install answer sources, first found is used:
+// values, answers file, environment variable, prompt then default
+type InstallOption struct {
+	// answers from command flag, keyed by env variable name
+	Values map[string]string
+	// yaml or json file, keyed by env variable name
+	AnswersFile string
+	// never prompt, also applied when stdin is not a terminal
+	NonInteractive bool
+	// .env file location
+	Output string
+	// overwrite existing output file
+	Force bool
+}
+
 // generate .env file from struct envconfig tags
-func GenerateDotEnv() {
+func GenerateDotEnv(opt *InstallOption) {
+	if !opt.Force {
+		if _, err := os.Stat(opt.Output); err == nil {
+			fmt.Printf("%s already exists, use -force to overwrite\n", opt.Output)
+			os.Exit(1)
+		}
+	}
+
+	answers, err := readAnswers(opt)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	interactive := !opt.NonInteractive && term.IsTerminal(int(syscall.Stdin))
+
 	cfg := config{}
-	rows := readStruct(reflect.TypeOf(cfg))
+	rows := readStruct(reflect.TypeOf(cfg), answers, interactive)
 	text := strings.Join(rows, "\n")
-	err := os.WriteFile(".env", []byte(text), 0644)
+	err = os.WriteFile(opt.Output, []byte(text), 0644)
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
+	fmt.Printf("%s generated, run `rap-c config check` to validate it\n", opt.Output)
+}
+
+// merge answers file & flag values, validate env variable names
+func readAnswers(opt *InstallOption) (map[string]string, error) {
+	result := make(map[string]string)
+	if opt.AnswersFile != "" {
+		content, err := os.ReadFile(opt.AnswersFile)
+		if err != nil {
+			return nil, err
+		}
+		var values map[string]interface{}
+		if strings.ToLower(filepath.Ext(opt.AnswersFile)) == ".json" {
+			err = json.Unmarshal(content, &values)
+		} else {
+			err = yaml.Unmarshal(content, &values)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read answers file %s: %v", opt.AnswersFile, err)
+		}
+		for key, val := range values {
+			if val == nil {
+				result[key] = ""
+			} else {
+				result[key] = fmt.Sprint(val)
+			}
+		}
+	}
+	for key, val := range opt.Values {
+		result[key] = val
+	}
+
+	// refuse unknown key, typo must not silently fallback to default
+	known := make(map[string]bool)
+	elm := reflect.TypeOf(config{})
+	for i := 0; i < elm.NumField(); i++ {
+		known[elm.Field(i).Tag.Get("envconfig")] = true
+	}
+	for key := range result {
+		if !known[key] {
+			return nil, fmt.Errorf("unknown config `%s`", key)
+		}
+	}
+	return result, nil
 }
 
-func readStruct(elm reflect.Type) []string {
+func readStruct(elm reflect.Type, answers map[string]string, interactive bool) []string {
 	var result []string
 	reader := bufio.NewReader(os.Stdin)
 	numFields := elm.NumField()
@@ -35,16 +128,13 @@ func readStruct(elm reflect.Type) []string {
 		kind := field.Type.Kind()
 
 		// set .env comment
-		if i == 0 {
-			result = append(result, "# main config")
-		} else if field.Name == "JwtSecret" {
-			result = append(result, "\n# jwt config")
-		} else if field.Name == "MailHost" {
-			result = append(result, "\n# smtp mail config")
-		} else if field.Name == "FirstUserUsername" {
-			result = append(result, "\n# first user installation config")
-		} else if field.Name == "MysqlHost" {
-			result = append(result, "\n# mysql config")
+		if title, ok := sectionTitles[field.Name]; ok {
+			if i > 0 {
+				title = "\n# " + title
+			} else {
+				title = "# " + title
+			}
+			result = append(result, title)
 		}
 
 		// env variable
@@ -52,28 +142,35 @@ func readStruct(elm reflect.Type) []string {
 		if envconfig == "" || envconfig == "-" {
 			continue
 		}
-
-		// init prompt
-		prompt := tag.Get("prompt")
 		_default := tag.Get("default")
-		if _default != "" {
-			prompt += fmt.Sprintf(" (default:%s)", _default)
-		}
-		if kind.String() == "bool" {
-			prompt += " (true or false)"
+
+		// answered by flag, file or environment
+		scan, ok := answers[envconfig]
+		if !ok {
+			scan, ok = os.LookupEnv(envconfig)
 		}
-		prompt += ": "
 
-		// is secret prompt
-		secret, _ := strconv.ParseBool(tag.Get("secret"))
+		if !ok && interactive {
+			// init prompt
+			prompt := tag.Get("prompt")
+			if _default != "" {
+				prompt += fmt.Sprintf(" (default:%s)", _default)
+			}
+			if kind.String() == "bool" {
+				prompt += " (true or false)"
+			}
+			prompt += ": "
 
-		// scan
-		var scan string
-		if secret {
-			scan = promptPassword(prompt)
-			fmt.Println()
-		} else {
-			scan = promptString(prompt, reader)
+			// is secret prompt
+			secret, _ := strconv.ParseBool(tag.Get("secret"))
+
+			// scan
+			if secret {
+				scan = promptPassword(prompt)
+				fmt.Println()
+			} else {
+				scan = promptString(prompt, reader)
+			}
 		}
 		if scan == "" && _default != "" {
 			scan = _default
@@ -30,10 +30,33 @@ type Route struct {
 	CreateUserAPI           routeDetail `method:"POST" path:"/api/user/create"`
 	UpdateUserAPI           routeDetail `method:"PUT" path:"/api/user/update"`
 	SetStatusUserAPI        routeDetail `method:"PUT" path:"/api/user/active-status"`
+	ResendVerificationAPI   routeDetail `method:"POST" path:"/api/user/resend-verification"`
+	VerifyEmailAPI          routeDetail `method:"POST" path:"/api/verify-email"`
+	AcceptInvitationAPI     routeDetail `method:"POST" path:"/api/accept-invitation"`
+	ResendInvitationAPI     routeDetail `method:"POST" path:"/api/user/invitation/resend"`
+	RevokeInvitationAPI     routeDetail `method:"PUT" path:"/api/user/invitation/revoke"`
 	ListUnitAPI             routeDetail `method:"GET" path:"/api/unit/list"`
 	TotalUnitAPI            routeDetail `method:"GET" path:"/api/unit/total"`
 	CreateUnitAPI           routeDetail `method:"POST" path:"/api/unit/create"`
 	DeleteUnitAPI           routeDetail `method:"DELETE" path:"/api/unit/delete"`
+	DetailUnitAPI           routeDetail `method:"GET" path:"/api/unit/detail/:name"`
+	RenameUnitAPI           routeDetail `method:"PUT" path:"/api/unit/rename"`
+	MergeUnitAPI            routeDetail `method:"PUT" path:"/api/unit/merge"`
+	ConvertUnitAPI          routeDetail `method:"GET" path:"/api/unit/convert"`
+	SetWeightIngredientAPI  routeDetail `method:"PUT" path:"/api/ingredient/weight"`
+	CostingRecipeAPI        routeDetail `method:"GET" path:"/api/recipe/costing/:serial"`
+	ScaleRecipeAPI          routeDetail `method:"GET" path:"/api/recipe/scale/:serial"`
+	ProduceRecipeAPI        routeDetail `method:"POST" path:"/api/recipe/produce"`
+	RefreshCostRecipeAPI    routeDetail `method:"PUT" path:"/api/recipe/refresh-cost"`
+	CreateRecipeLineAPI     routeDetail `method:"POST" path:"/api/recipe/line/create"`
+	UpdateRecipeLineAPI     routeDetail `method:"PUT" path:"/api/recipe/line/update"`
+	ListDeadLetterAPI       routeDetail `method:"GET" path:"/api/mail/dead-letter/list"`
+	TotalDeadLetterAPI      routeDetail `method:"GET" path:"/api/mail/dead-letter/total"`
+	ResendDeadLetterAPI     routeDetail `method:"POST" path:"/api/mail/dead-letter/resend"`
+	PreviewMailTemplateAPI  routeDetail `method:"GET" path:"/api/mail/template/preview"`
+	SetupAPI                routeDetail `method:"POST" path:"/api/setup"`
+	ListAuditLogAPI         routeDetail `method:"GET" path:"/api/audit/list"`
+	TotalAuditLogAPI        routeDetail `method:"GET" path:"/api/audit/total"`
 
 	// web
 	LoginWebPage              routeDetail `method:"GET" path:"/login"`
@@ -42,8 +65,22 @@ type Route struct {
 	PasswordMustChangeWebPage routeDetail `method:"GET" path:"/renew-password"`
 	ForgotPasswordWebPage     routeDetail `method:"GET" path:"/forgot-password"`
 	ResetPasswordWebPage      routeDetail `method:"GET" path:"/reset-password"`
+	VerifyEmailWebPage        routeDetail `method:"GET" path:"/verify-email"`
+	AcceptInvitationWebPage   routeDetail `method:"GET" path:"/accept-invitation"`
 	DashboardWebPage          routeDetail `method:"GET" path:"/dashboard"`
 	ProfileWebPage            routeDetail `method:"GET" path:"/profile"`
+	SetupWebPage              routeDetail `method:"GET" path:"/setup"`
+	AuditWebPage              routeDetail `method:"GET" path:"/audit"`
+	CapturedMailWebPage       routeDetail `method:"GET" path:"/dev/mail"`
+	CapturedMailDetailWebPage routeDetail `method:"GET" path:"/dev/mail/:name"`
+
+	// monitoring
+	MetricsEndpoint   routeDetail `method:"GET" path:"/metrics"`
+	LivenessEndpoint  routeDetail `method:"GET" path:"/healthz"`
+	ReadinessEndpoint routeDetail `method:"GET" path:"/readyz"`
+
+	// route field name by method & path, used as metric label
+	names map[string]string
 }
 
 func (cfg *Route) DefaultAuthorizedWebPage(method, path string) routeDetail {
@@ -56,6 +93,11 @@ func (cfg *Route) DefaultAuthorizedWebPage(method, path string) routeDetail {
 	return cfg.DashboardWebPage
 }
 
+// route field name of registered method & path, empty when route is not in config
+func (cfg *Route) Name(method, path string) string {
+	return cfg.names[method+" "+path]
+}
+
 func InitRoute() *Route {
 	var route Route
 	setRouteDetails(&route)
@@ -65,6 +107,7 @@ func InitRoute() *Route {
 func setRouteDetails(route *Route) {
 	val := reflect.ValueOf(route).Elem()
 	typ := val.Type()
+	route.names = make(map[string]string)
 
 	for i := 0; i < val.NumField(); i++ {
 		field := val.Field(i)
@@ -79,6 +122,7 @@ func setRouteDetails(route *Route) {
 				path:   pathTag,
 			}
 			field.Set(reflect.ValueOf(detail))
+			route.names[methodTag+" "+pathTag] = structField.Name
 		}
 	}
 }
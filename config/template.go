@@ -80,6 +80,30 @@ func NewRenderer(autoReloadTemplate bool) (*Renderer, error) {
 			},
 			ExecuteTemplate: "index",
 		},
+		"accept-invitation.html": {
+			Files: []string{
+				filepath.Join(storagePath, templatePath, "accept-invitation.html"),
+			},
+			ExecuteTemplate: "index",
+		},
+		"verify-email.html": {
+			Files: []string{
+				filepath.Join(storagePath, templatePath, "verify-email.html"),
+			},
+			ExecuteTemplate: "index",
+		},
+		"setup.html": {
+			Files: []string{
+				filepath.Join(storagePath, templatePath, "setup.html"),
+			},
+			ExecuteTemplate: "index",
+		},
+		"captured-mail.html": {
+			Files: []string{
+				filepath.Join(storagePath, templatePath, "captured-mail.html"),
+			},
+			ExecuteTemplate: "index",
+		},
 		"profile": {
 			Files: []string{
 				filepath.Join(storagePath, templatePath, "layouts", "layout.html"),
@@ -96,6 +120,14 @@ func NewRenderer(autoReloadTemplate bool) (*Renderer, error) {
 			},
 			ExecuteTemplate: "layout",
 		},
+		"audit": {
+			Files: []string{
+				filepath.Join(storagePath, templatePath, "layouts", "layout.html"),
+				filepath.Join(storagePath, templatePath, "layouts", "sidebar-menu.html"),
+				filepath.Join(storagePath, templatePath, "audit", "index.html"),
+			},
+			ExecuteTemplate: "layout",
+		},
 	}
 
 	templates, err := loadTemplates(keys)
@@ -148,3 +180,20 @@ func (r *Renderer) Render(
 	}
 	return fmt.Errorf("template %s not found", name)
 }
+
+// all templates are loaded, reloaded templates must be parseable
+func (r *Renderer) Check() error {
+	if r == nil {
+		return fmt.Errorf("template renderer not loaded")
+	}
+	if r.autoReloadTemplate {
+		_, err := loadTemplates(r.keys)
+		return err
+	}
+	for key := range r.keys {
+		if _, ok := r.templates[key]; !ok {
+			return fmt.Errorf("template %s not loaded", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,794 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"rap-c/app/entity"
+	databaseentity "rap-c/app/entity/database-entity"
+	testdatabase "rap-c/app/repository/test-database"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_HTTP_SetupAPI(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	t.Run("validation error shape", func(t *testing.T) {
+		rec := app.api(app.router.SetupAPI.Method(), app.router.SetupAPI.Path(), map[string]interface{}{}, "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		body := decodeJSON(t, rec)
+		assert.Contains(t, body, "message")
+		assert.Contains(t, body, "requestId")
+		assert.NotContains(t, body, "error", "internal error is hidden without debug")
+		assert.Equal(t, rec.Header().Get("X-Request-Id"), body["requestId"])
+	})
+
+	t.Run("create owner then refuse second setup", func(t *testing.T) {
+		token := app.setupOwner()
+		assert.NotEmpty(t, token)
+
+		rec := app.api(app.router.SetupAPI.Method(), app.router.SetupAPI.Path(), map[string]interface{}{
+			"username":        "second",
+			"fullName":        "Second",
+			"email":           "second@example.com",
+			"password":        testPassword,
+			"confirmPassword": testPassword,
+		}, "")
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Equal(t, entity.SetupAlreadyDoneForbidden, errorCode(t, rec))
+	})
+}
+
+func Test_HTTP_AuthAPI(t *testing.T) {
+	app := newTestApp(t, nil)
+	ownerToken := app.setupOwner()
+
+	t.Run("login", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			email    string
+			password string
+			status   int
+			code     int
+		}{
+			{name: "success", email: testOwnerEmail, password: testPassword, status: http.StatusOK},
+			{name: "wrong password", email: testOwnerEmail, password: "wrong password", status: http.StatusUnauthorized, code: entity.AttemptLoginFailed},
+			{name: "unknown email", email: "unknown@example.com", password: testPassword, status: http.StatusUnauthorized, code: entity.AttemptLoginFailed},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				rec := app.api(app.router.LoginAPI.Method(), app.router.LoginAPI.Path(), map[string]interface{}{
+					"email":    tt.email,
+					"password": tt.password,
+				}, "")
+				assert.Equal(t, tt.status, rec.Code)
+				if tt.code != 0 {
+					assert.Equal(t, tt.code, errorCode(t, rec))
+				}
+			})
+		}
+	})
+
+	t.Run("guest login", func(t *testing.T) {
+		assert.NotEmpty(t, app.loginGuest())
+	})
+
+	t.Run("request & reset password", func(t *testing.T) {
+		rec := app.api(app.router.RequestResetPasswordAPI.Method(), app.router.RequestResetPasswordAPI.Path(), map[string]interface{}{
+			"email": testOwnerEmail,
+		}, "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		params := app.mailLink(testOwnerEmail, app.router.ResetPasswordWebPage.Path())
+
+		newPassword := "N3w#Secret!pass"
+		rec = app.api(app.router.ResetPasswordAPI.Method(), app.router.ResetPasswordAPI.Path(), map[string]interface{}{
+			"email":           params.Get("email"),
+			"token":           params.Get("token"),
+			"password":        newPassword,
+			"confirmPassword": newPassword,
+		}, "")
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.NotEmpty(t, app.login(testOwnerEmail, newPassword))
+
+		// token is single use
+		rec = app.api(app.router.ResetPasswordAPI.Method(), app.router.ResetPasswordAPI.Path(), map[string]interface{}{
+			"email":           params.Get("email"),
+			"token":           params.Get("token"),
+			"password":        testPassword,
+			"confirmPassword": testPassword,
+		}, "")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("password must change", func(t *testing.T) {
+		rec := app.api(app.router.CreateUserAPI.Method(), app.router.CreateUserAPI.Path(), map[string]interface{}{
+			"username": "mustchange",
+			"fullName": "Must Change",
+			"email":    "mustchange@example.com",
+		}, ownerToken)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		params := app.mailLink("mustchange@example.com", app.router.AcceptInvitationWebPage.Path())
+		rec = app.api(app.router.AcceptInvitationAPI.Method(), app.router.AcceptInvitationAPI.Path(), map[string]interface{}{
+			"email":           params.Get("email"),
+			"token":           params.Get("token"),
+			"password":        testPassword,
+			"confirmPassword": testPassword,
+		}, "")
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Nil(t, app.db.Model(databaseentity.User{}).Where("username = ?", "mustchange").Update("password_must_change", true).Error)
+		token := app.login("mustchange@example.com", testPassword)
+
+		rec = app.api(app.router.ListUnitAPI.Method(), app.router.ListUnitAPI.Path(), nil, token)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Equal(t, entity.MustChangePasswordForbidden, errorCode(t, rec))
+
+		newPassword := "Ch4nged#Secret!pass"
+		rec = app.api(app.router.PasswordMustChangeAPI.Method(), app.router.PasswordMustChangeAPI.Path(), map[string]interface{}{
+			"password":        newPassword,
+			"confirmPassword": newPassword,
+		}, token)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		rec = app.api(app.router.ListUnitAPI.Method(), app.router.ListUnitAPI.Path(), nil, app.login("mustchange@example.com", newPassword))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
+
+func Test_HTTP_GuestLoginDisabled(t *testing.T) {
+	app := newTestApp(t, map[string]string{"ENABLE_GUEST_LOGIN": "false"})
+
+	rec := app.api(app.router.GuestLoginAPI.Method(), app.router.GuestLoginAPI.Path(), nil, "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, entity.AttemptGuestLoginForbidden, errorCode(t, rec))
+}
+
+// reset password limit per ip uses connection address, X-Forwarded-For only counts from trusted proxy.
+// httptest request always comes from 192.0.2.1
+func Test_HTTP_ResetLimitClientIP(t *testing.T) {
+	tests := []struct {
+		name           string
+		trustedProxies string
+		status         int
+	}{
+		{name: "spoofed header ignored", trustedProxies: "", status: http.StatusTooManyRequests},
+		{name: "untrusted proxy ignored", trustedProxies: "10.0.0.0/8", status: http.StatusTooManyRequests},
+		{name: "trusted proxy header used", trustedProxies: "192.0.2.0/24", status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t, map[string]string{
+				"HTTP_TRUSTED_PROXIES":       tt.trustedProxies,
+				"RESET_REQUEST_LIMIT_PER_IP": "2",
+			})
+			request := func(i int) *httptest.ResponseRecorder {
+				body := strings.NewReader(fmt.Sprintf(`{"email":"user%d@example.com"}`, i))
+				req := httptest.NewRequest(app.router.RequestResetPasswordAPI.Method(), app.router.RequestResetPasswordAPI.Path(), body)
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				req.Header.Set(echo.HeaderXForwardedFor, fmt.Sprintf("203.0.113.%d", i))
+				return app.serve(req)
+			}
+			for i := 1; i <= 2; i++ {
+				rec := request(i)
+				assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			}
+			rec := request(3)
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+			if tt.status == http.StatusTooManyRequests {
+				assert.Equal(t, entity.ResetPasswordRequestTooMany, errorCode(t, rec))
+			}
+		})
+	}
+}
+
+// every protected api route refuses anonymous request and non guest routes refuse guest
+func Test_HTTP_APIAuthorization(t *testing.T) {
+	app := newTestApp(t, nil)
+	ownerToken := app.setupOwner()
+	guestToken := app.loginGuest()
+	rec := app.api(app.router.CreateUnitAPI.Method(), app.router.CreateUnitAPI.Path(), map[string]interface{}{"name": "gram"}, ownerToken)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	recipe := testdatabase.Recipe(t, app.db, &databaseentity.Recipe{Quantity: 1})
+
+	tests := []struct {
+		method       string
+		path         string
+		guestAllowed bool
+	}{
+		{method: app.router.PasswordMustChangeAPI.Method(), path: app.router.PasswordMustChangeAPI.Path()},
+		{method: app.router.DetailUserAPI.Method(), path: "/api/user/detail/" + testOwnerUsername, guestAllowed: true},
+		{method: app.router.ListUserAPI.Method(), path: app.router.ListUserAPI.Path(), guestAllowed: true},
+		{method: app.router.TotalUserAPI.Method(), path: app.router.TotalUserAPI.Path(), guestAllowed: true},
+		{method: app.router.CreateUserAPI.Method(), path: app.router.CreateUserAPI.Path()},
+		{method: app.router.UpdateUserAPI.Method(), path: app.router.UpdateUserAPI.Path()},
+		{method: app.router.SetStatusUserAPI.Method(), path: app.router.SetStatusUserAPI.Path()},
+		{method: app.router.ResendVerificationAPI.Method(), path: app.router.ResendVerificationAPI.Path()},
+		{method: app.router.ResendInvitationAPI.Method(), path: app.router.ResendInvitationAPI.Path()},
+		{method: app.router.RevokeInvitationAPI.Method(), path: app.router.RevokeInvitationAPI.Path()},
+		{method: app.router.ListUnitAPI.Method(), path: app.router.ListUnitAPI.Path(), guestAllowed: true},
+		{method: app.router.TotalUnitAPI.Method(), path: app.router.TotalUnitAPI.Path(), guestAllowed: true},
+		{method: app.router.CreateUnitAPI.Method(), path: app.router.CreateUnitAPI.Path()},
+		{method: app.router.DeleteUnitAPI.Method(), path: app.router.DeleteUnitAPI.Path()},
+		{method: app.router.DetailUnitAPI.Method(), path: "/api/unit/detail/gram", guestAllowed: true},
+		{method: app.router.RenameUnitAPI.Method(), path: app.router.RenameUnitAPI.Path()},
+		{method: app.router.MergeUnitAPI.Method(), path: app.router.MergeUnitAPI.Path()},
+		{method: app.router.ConvertUnitAPI.Method(), path: app.router.ConvertUnitAPI.Path() + "?quantity=1&from=kg&to=g", guestAllowed: true},
+		{method: app.router.SetWeightIngredientAPI.Method(), path: app.router.SetWeightIngredientAPI.Path()},
+		{method: app.router.CostingRecipeAPI.Method(), path: "/api/recipe/costing/" + recipe.Serial, guestAllowed: true},
+		{method: app.router.ScaleRecipeAPI.Method(), path: "/api/recipe/scale/" + recipe.Serial + "?portions=1", guestAllowed: true},
+		{method: app.router.ProduceRecipeAPI.Method(), path: app.router.ProduceRecipeAPI.Path()},
+		{method: app.router.RefreshCostRecipeAPI.Method(), path: app.router.RefreshCostRecipeAPI.Path()},
+		{method: app.router.CreateRecipeLineAPI.Method(), path: app.router.CreateRecipeLineAPI.Path()},
+		{method: app.router.UpdateRecipeLineAPI.Method(), path: app.router.UpdateRecipeLineAPI.Path()},
+		{method: app.router.ListDeadLetterAPI.Method(), path: app.router.ListDeadLetterAPI.Path()},
+		{method: app.router.TotalDeadLetterAPI.Method(), path: app.router.TotalDeadLetterAPI.Path()},
+		{method: app.router.ResendDeadLetterAPI.Method(), path: app.router.ResendDeadLetterAPI.Path()},
+		{method: app.router.PreviewMailTemplateAPI.Method(), path: app.router.PreviewMailTemplateAPI.Path()},
+		{method: app.router.ListAuditLogAPI.Method(), path: app.router.ListAuditLogAPI.Path()},
+		{method: app.router.TotalAuditLogAPI.Method(), path: app.router.TotalAuditLogAPI.Path()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			rec := app.api(tt.method, tt.path, nil, "")
+			assert.Equal(t, http.StatusUnauthorized, rec.Code, "anonymous")
+
+			rec = app.api(tt.method, tt.path, nil, "invalid token")
+			assert.Equal(t, http.StatusUnauthorized, rec.Code, "invalid token")
+
+			rec = app.api(tt.method, tt.path, nil, guestToken)
+			if tt.guestAllowed {
+				assert.Equal(t, http.StatusOK, rec.Code, "guest")
+			} else {
+				assert.Equal(t, http.StatusForbidden, rec.Code, "guest")
+				assert.Equal(t, entity.GuestTokenForbidden, errorCode(t, rec))
+			}
+		})
+	}
+}
+
+func Test_HTTP_UserAPI(t *testing.T) {
+	app := newTestApp(t, nil)
+	ownerToken := app.setupOwner()
+	const invitedEmail = "invited@example.com"
+
+	t.Run("create user sends invitation", func(t *testing.T) {
+		rec := app.api(app.router.CreateUserAPI.Method(), app.router.CreateUserAPI.Path(), map[string]interface{}{
+			"username": "invited",
+			"fullName": "Invited User",
+			"email":    invitedEmail,
+		}, ownerToken)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		rec = app.api(app.router.CreateUserAPI.Method(), app.router.CreateUserAPI.Path(), map[string]interface{}{
+			"username": "other",
+			"fullName": "Other User",
+			"email":    invitedEmail,
+		}, ownerToken)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, entity.CreateUserEmailDuplicate, errorCode(t, rec))
+	})
+
+	t.Run("resend & revoke invitation", func(t *testing.T) {
+		first := app.mailLink(invitedEmail, app.router.AcceptInvitationWebPage.Path())
+		rec := app.api(app.router.ResendInvitationAPI.Method(), app.router.ResendInvitationAPI.Path(), map[string]interface{}{
+			"username": "invited",
+		}, ownerToken)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		second := app.mailLink(invitedEmail, app.router.AcceptInvitationWebPage.Path())
+		assert.NotEqual(t, first.Get("token"), second.Get("token"))
+
+		rec = app.api(app.router.RevokeInvitationAPI.Method(), app.router.RevokeInvitationAPI.Path(), map[string]interface{}{
+			"username": "invited",
+		}, ownerToken)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		rec = app.api(app.router.AcceptInvitationAPI.Method(), app.router.AcceptInvitationAPI.Path(), map[string]interface{}{
+			"email":           second.Get("email"),
+			"token":           second.Get("token"),
+			"password":        testPassword,
+			"confirmPassword": testPassword,
+		}, "")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, entity.InvitationNotFound, errorCode(t, rec))
+	})
+
+	t.Run("accept invitation", func(t *testing.T) {
+		rec := app.api(app.router.ResendInvitationAPI.Method(), app.router.ResendInvitationAPI.Path(), map[string]interface{}{
+			"username": "invited",
+		}, ownerToken)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		params := app.mailLink(invitedEmail, app.router.AcceptInvitationWebPage.Path())
+		rec = app.api(app.router.AcceptInvitationAPI.Method(), app.router.AcceptInvitationAPI.Path(), map[string]interface{}{
+			"email":           params.Get("email"),
+			"token":           params.Get("token"),
+			"password":        testPassword,
+			"confirmPassword": testPassword,
+		}, "")
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.NotEmpty(t, app.login(invitedEmail, testPassword))
+	})
+
+	t.Run("list, total & detail", func(t *testing.T) {
+		rec := app.api(app.router.ListUserAPI.Method(), app.router.ListUserAPI.Path()+"?sortField=username", nil, ownerToken)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"username":"invited"`)
+		assert.Contains(t, rec.Body.String(), `"username":"owner"`)
+
+		rec = app.api(app.router.TotalUserAPI.Method(), app.router.TotalUserAPI.Path()+"?fullName=invited", nil, ownerToken)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, float64(1), decodeJSON(t, rec)["total"])
+
+		rec = app.api(app.router.DetailUserAPI.Method(), "/api/user/detail/invited", nil, ownerToken)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), invitedEmail)
+
+		rec = app.api(app.router.DetailUserAPI.Method(), "/api/user/detail/nobody", nil, ownerToken)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("update email & verify", func(t *testing.T) {
+		token := app.login(invitedEmail, testPassword)
+		rec := app.api(app.router.UpdateUserAPI.Method(), app.router.UpdateUserAPI.Path(), map[string]interface{}{
+			"email": "changed@example.com",
+		}, token)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		rec = app.api(app.router.ResendVerificationAPI.Method(), app.router.ResendVerificationAPI.Path(), nil, token)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		params := app.mailLink("changed@example.com", app.router.VerifyEmailWebPage.Path())
+		rec = app.api(app.router.VerifyEmailAPI.Method(), app.router.VerifyEmailAPI.Path(), map[string]interface{}{
+			"token": params.Get("token"),
+		}, "")
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		token = app.login("changed@example.com", testPassword)
+
+		rec = app.api(app.router.UpdateUserAPI.Method(), app.router.UpdateUserAPI.Path(), map[string]interface{}{}, token)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("deactivate user", func(t *testing.T) {
+		rec := app.api(app.router.SetStatusUserAPI.Method(), app.router.SetStatusUserAPI.Path(), map[string]interface{}{
+			"username": "invited",
+			"disabled": true,
+		}, ownerToken)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		rec = app.api(app.router.LoginAPI.Method(), app.router.LoginAPI.Path(), map[string]interface{}{
+			"email":    "changed@example.com",
+			"password": testPassword,
+		}, "")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Equal(t, entity.AttemptLoginUserDeactivated, errorCode(t, rec))
+	})
+}
+
+func Test_HTTP_UnitAPI(t *testing.T) {
+	app := newTestApp(t, nil)
+	ownerToken := app.setupOwner()
+	guestToken := app.loginGuest()
+
+	for _, name := range []string{"Gram", "Kilogram", "Liter"} {
+		rec := app.api(app.router.CreateUnitAPI.Method(), app.router.CreateUnitAPI.Path(), map[string]interface{}{"name": name}, ownerToken)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	t.Run("duplicate name", func(t *testing.T) {
+		rec := app.api(app.router.CreateUnitAPI.Method(), app.router.CreateUnitAPI.Path(), map[string]interface{}{"name": "Gram"}, ownerToken)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, entity.CreateUnitNameDuplicate, errorCode(t, rec))
+	})
+
+	t.Run("guest can list", func(t *testing.T) {
+		rec := app.api(app.router.ListUnitAPI.Method(), app.router.ListUnitAPI.Path()+"?name=gram&sortField=name", nil, guestToken)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		body := rec.Body.String()
+		assert.Less(t, strings.Index(body, `"Gram"`), strings.Index(body, `"Kilogram"`))
+		assert.NotContains(t, body, "Liter")
+
+		rec = app.api(app.router.TotalUnitAPI.Method(), app.router.TotalUnitAPI.Path()+"?name=gram", nil, guestToken)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, float64(2), decodeJSON(t, rec)["total"])
+	})
+
+	t.Run("rename", func(t *testing.T) {
+		rec := app.api(app.router.RenameUnitAPI.Method(), app.router.RenameUnitAPI.Path(), map[string]interface{}{"name": "Kilogram", "newName": "Kg"}, ownerToken)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, "Kg", decodeJSON(t, rec)["name"])
+
+		rec = app.api(app.router.RenameUnitAPI.Method(), app.router.RenameUnitAPI.Path(), map[string]interface{}{"name": "Kg", "newName": "Kg"}, ownerToken)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, entity.UpdateUnitNoChange, errorCode(t, rec))
+	})
+
+	t.Run("merge & detail", func(t *testing.T) {
+		rec := app.api(app.router.MergeUnitAPI.Method(), app.router.MergeUnitAPI.Path(), map[string]interface{}{"name": "Kg", "targetName": "Kg"}, ownerToken)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, entity.MergeUnitIntoItself, errorCode(t, rec))
+
+		rec = app.api(app.router.MergeUnitAPI.Method(), app.router.MergeUnitAPI.Path(), map[string]interface{}{"name": "Kg", "targetName": "Gram"}, ownerToken)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, "Gram", decodeJSON(t, rec)["name"])
+
+		rec = app.api(app.router.DetailUnitAPI.Method(), "/api/unit/detail/Kg", nil, guestToken)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, entity.UnitNameNotFound, errorCode(t, rec))
+
+		rec = app.api(app.router.DetailUnitAPI.Method(), "/api/unit/detail/Gram", nil, guestToken)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, []interface{}{}, decodeJSON(t, rec)["ingredients"])
+	})
+
+	t.Run("standard unit & convert", func(t *testing.T) {
+		rec := app.api(app.router.CreateUnitAPI.Method(), app.router.CreateUnitAPI.Path(), map[string]interface{}{"name": "Cup", "dimension": "volume", "factor": 240}, ownerToken)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, "volume", decodeJSON(t, rec)["dimension"])
+
+		rec = app.api(app.router.ConvertUnitAPI.Method(), app.router.ConvertUnitAPI.Path()+"?quantity=2&from=Cup&to=sdm", nil, guestToken)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		body := decodeJSON(t, rec)
+		assert.Equal(t, float64(32), body["result"])
+		assert.Equal(t, "dimension", body["bridge"])
+
+		rec = app.api(app.router.ConvertUnitAPI.Method(), app.router.ConvertUnitAPI.Path()+"?quantity=2&from=Cup&to=g", nil, guestToken)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, entity.UnitNotConvertible, errorCode(t, rec))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		rec := app.api(app.router.DeleteUnitAPI.Method(), app.router.DeleteUnitAPI.Path(), map[string]interface{}{"name": "Liter"}, ownerToken)
+		assert.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+
+		rec = app.api(app.router.DeleteUnitAPI.Method(), app.router.DeleteUnitAPI.Path(), map[string]interface{}{"name": "Liter"}, ownerToken)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, entity.UnitNameNotFound, errorCode(t, rec))
+	})
+}
+
+func Test_HTTP_RecipeCostingAPI(t *testing.T) {
+	app := newTestApp(t, nil)
+	ownerToken := app.setupOwner()
+	guestToken := app.loginGuest()
+	var kg, sdm databaseentity.Unit
+	assert.Nil(t, app.db.Where("name = ?", "kg").First(&kg).Error)
+	assert.Nil(t, app.db.Where("name = ?", "sdm").First(&sdm).Error)
+	flour := testdatabase.Ingredient(t, app.db, &databaseentity.Ingredient{Name: "Flour", UnitID: kg.ID, PricePerUnit: 12000, Stock: 0.05})
+	recipe := testdatabase.Recipe(t, app.db, &databaseentity.Recipe{Quantity: 2, LaborCosts: 1000})
+	testdatabase.RecipeIngredient(t, app.db, &databaseentity.RecipeIngredient{RecipeID: recipe.ID, IngredientID: &flour.ID, UnitID: sdm.ID, Quantity: 4})
+
+	t.Run("volume without density", func(t *testing.T) {
+		rec := app.api(app.router.CostingRecipeAPI.Method(), "/api/recipe/costing/"+recipe.Serial, nil, guestToken)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, entity.RecipeIngredientNotConvertible, errorCode(t, rec))
+	})
+
+	t.Run("set density", func(t *testing.T) {
+		rec := app.api(app.router.SetWeightIngredientAPI.Method(), app.router.SetWeightIngredientAPI.Path(), map[string]interface{}{"serial": flour.Serial, "density": 0.5}, ownerToken)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, 0.5, decodeJSON(t, rec)["density"])
+		// recipe can be costed now, so its saved cost follows
+		var result databaseentity.Recipe
+		assert.Nil(t, app.db.First(&result, recipe.ID).Error)
+		assert.InDelta(t, 360, result.RawMaterialCosts, 0.0001)
+
+		rec = app.api(app.router.SetWeightIngredientAPI.Method(), app.router.SetWeightIngredientAPI.Path(), map[string]interface{}{"serial": "ING404", "density": 0.5}, ownerToken)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, entity.IngredientNotFound, errorCode(t, rec))
+	})
+
+	t.Run("costing through density", func(t *testing.T) {
+		rec := app.api(app.router.CostingRecipeAPI.Method(), "/api/recipe/costing/"+recipe.Serial, nil, guestToken)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		body := decodeJSON(t, rec)
+		// 4 sdm = 60 ml = 30 gram of flour
+		lines := body["lines"].([]interface{})
+		if assert.Len(t, lines, 1) {
+			line := lines[0].(map[string]interface{})
+			assert.Equal(t, "density", line["bridge"])
+			assert.InDelta(t, 0.03, line["ingredientQuantity"], 0.0001)
+			assert.InDelta(t, 360, line["cost"], 0.0001)
+		}
+		assert.InDelta(t, 1360, body["hpp"], 0.0001)
+		assert.InDelta(t, 680, body["hppPerPortion"], 0.0001)
+
+		rec = app.api(app.router.CostingRecipeAPI.Method(), "/api/recipe/costing/RCP404", nil, guestToken)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, entity.RecipeNotFound, errorCode(t, rec))
+	})
+
+	t.Run("scale into portions", func(t *testing.T) {
+		rec := app.api(app.router.ScaleRecipeAPI.Method(), "/api/recipe/scale/"+recipe.Serial+"?portions=5", nil, guestToken)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		body := decodeJSON(t, rec)
+		// 10 sdm = 75 gram of flour, more than 50 gram in stock
+		assert.InDelta(t, 2.5, body["factor"], 0.0001)
+		lines := body["lines"].([]interface{})
+		if assert.Len(t, lines, 1) {
+			line := lines[0].(map[string]interface{})
+			assert.InDelta(t, 10, line["quantity"], 0.0001)
+			// less than 1 kg is bought in gram
+			assert.InDelta(t, 75, line["purchaseQuantity"], 0.0001)
+			assert.Equal(t, "g", line["purchaseUnit"])
+			assert.InDelta(t, 900, line["cost"], 0.0001)
+		}
+		assert.InDelta(t, 3400, body["hpp"], 0.0001)
+		assert.InDelta(t, 680, body["hppPerPortion"], 0.0001)
+		warnings := body["warnings"].([]interface{})
+		if assert.Len(t, warnings, 1) {
+			assert.InDelta(t, 0.025, warnings[0].(map[string]interface{})["shortage"], 0.0001)
+		}
+
+		rec = app.api(app.router.ScaleRecipeAPI.Method(), "/api/recipe/scale/"+recipe.Serial+"?portions=0", nil, guestToken)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, entity.ValidatorBadRequest, errorCode(t, rec))
+	})
+
+	t.Run("merge unit refreshes cost", func(t *testing.T) {
+		spoon := testdatabase.Unit(t, app.db, &databaseentity.Unit{Name: "sendok"})
+		testdatabase.RecipeIngredient(t, app.db, &databaseentity.RecipeIngredient{RecipeID: recipe.ID, IngredientID: &flour.ID, UnitID: spoon.ID, Quantity: 4})
+
+		// sendok becomes sdm, so the new line costs the same as the first one
+		rec := app.api(app.router.MergeUnitAPI.Method(), app.router.MergeUnitAPI.Path(), map[string]interface{}{"name": "sendok", "targetName": "sdm"}, ownerToken)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var result databaseentity.Recipe
+		assert.Nil(t, app.db.First(&result, recipe.ID).Error)
+		assert.InDelta(t, 720, result.RawMaterialCosts, 0.0001)
+	})
+}
+
+func Test_HTTP_SubRecipeAPI(t *testing.T) {
+	app := newTestApp(t, nil)
+	ownerToken := app.setupOwner()
+	var kg, ml, l databaseentity.Unit
+	assert.Nil(t, app.db.Where("name = ?", "kg").First(&kg).Error)
+	assert.Nil(t, app.db.Where("name = ?", "ml").First(&ml).Error)
+	assert.Nil(t, app.db.Where("name = ?", "l").First(&l).Error)
+	flour := testdatabase.Ingredient(t, app.db, &databaseentity.Ingredient{Name: "Flour", UnitID: kg.ID, PricePerUnit: 12000, Stock: 10})
+	tomato := testdatabase.Ingredient(t, app.db, &databaseentity.Ingredient{Name: "Tomato", UnitID: kg.ID, PricePerUnit: 20000, Stock: 5})
+	// sauce yields 2 l & costs 12000 per l, pasta uses 500 ml of it
+	sauce := testdatabase.Recipe(t, app.db, &databaseentity.Recipe{Name: "Sauce", Quantity: 2, UnitID: &l.ID, LaborCosts: 4000, Stock: 0.2})
+	pasta := testdatabase.Recipe(t, app.db, &databaseentity.Recipe{Name: "Pasta", Quantity: 4})
+	sauceLine := testdatabase.RecipeIngredient(t, app.db, &databaseentity.RecipeIngredient{RecipeID: sauce.ID, IngredientID: &tomato.ID, UnitID: kg.ID, Quantity: 1})
+	testdatabase.RecipeIngredient(t, app.db, &databaseentity.RecipeIngredient{RecipeID: pasta.ID, IngredientID: &flour.ID, UnitID: kg.ID, Quantity: 1})
+	testdatabase.RecipeIngredient(t, app.db, &databaseentity.RecipeIngredient{RecipeID: pasta.ID, SubRecipeID: &sauce.ID, UnitID: ml.ID, Quantity: 500})
+
+	t.Run("costing rolls up", func(t *testing.T) {
+		rec := app.api(app.router.CostingRecipeAPI.Method(), "/api/recipe/costing/"+pasta.Serial, nil, ownerToken)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		body := decodeJSON(t, rec)
+		lines := body["lines"].([]interface{})
+		if assert.Len(t, lines, 2) {
+			line := lines[1].(map[string]interface{})
+			assert.Equal(t, sauce.Serial, line["subRecipeSerial"])
+			assert.InDelta(t, 0.5, line["ingredientQuantity"], 0.0001)
+			assert.Equal(t, "l", line["ingredientUnit"])
+			assert.InDelta(t, 12000, line["pricePerUnit"], 0.0001)
+			assert.InDelta(t, 6000, line["cost"], 0.0001)
+			// 0.25 of sauce batch: 5000 of tomato & 1000 of labor, both count as raw material of pasta
+			assert.Equal(t, databaseentity.RecipeLineCostTypeIntermediateProduct, line["costType"])
+			assert.InDelta(t, 5000, line["rawMaterialCost"], 0.0001)
+			assert.InDelta(t, 1000, line["laborCost"], 0.0001)
+			assert.InDelta(t, 0, line["overheadCost"], 0.0001)
+
+			line = lines[0].(map[string]interface{})
+			assert.Equal(t, databaseentity.RecipeLineCostTypeRawMaterial, line["costType"])
+			assert.InDelta(t, line["cost"], line["rawMaterialCost"], 0.0001)
+		}
+		assert.InDelta(t, 18000, body["rawMaterialCosts"], 0.0001)
+	})
+
+	t.Run("produce", func(t *testing.T) {
+		// 8 portions need 1 l of sauce, 0.2 l in stock & 0.8 l is made from 0.4 kg of tomato
+		rec := app.api(app.router.ProduceRecipeAPI.Method(), app.router.ProduceRecipeAPI.Path(), map[string]interface{}{"serial": pasta.Serial, "quantity": 8}, ownerToken)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		body := decodeJSON(t, rec)
+		assert.InDelta(t, 8, body["stock"], 0.0001)
+		assert.Len(t, body["ingredients"], 2)
+		assert.Len(t, body["subRecipes"], 1)
+
+		for _, itm := range []struct {
+			ingredient *databaseentity.Ingredient
+			stock      float32
+		}{{flour, 8}, {tomato, 4.6}} {
+			var result databaseentity.Ingredient
+			assert.Nil(t, app.db.First(&result, itm.ingredient.ID).Error)
+			assert.InDelta(t, itm.stock, result.Stock, 0.0001)
+		}
+		var result databaseentity.Recipe
+		assert.Nil(t, app.db.First(&result, sauce.ID).Error)
+		assert.InDelta(t, 0, result.Stock, 0.0001)
+
+		// flour, tomato & sauce go out, pasta comes in
+		var movements []*databaseentity.StockMovement
+		assert.Nil(t, app.db.Order("id").Find(&movements).Error)
+		if assert.Len(t, movements, 4) {
+			assert.Equal(t, &pasta.ID, movements[3].RecipeID)
+			assert.Equal(t, databaseentity.StockMovementTypeIn, movements[3].MovementType)
+			assert.InDelta(t, 8, movements[3].Quantity, 0.0001)
+		}
+
+		// flour left is 8 kg
+		rec = app.api(app.router.ProduceRecipeAPI.Method(), app.router.ProduceRecipeAPI.Path(), map[string]interface{}{"serial": pasta.Serial, "quantity": 40}, ownerToken)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, entity.ProduceStockNotEnough, errorCode(t, rec))
+	})
+
+	t.Run("refresh cost ripples up", func(t *testing.T) {
+		assert.Nil(t, app.db.Model(tomato).Update("price_per_unit", 30000).Error)
+
+		// sauce costs 17000 per l, so pasta uses 8500 of it
+		rec := app.api(app.router.RefreshCostRecipeAPI.Method(), app.router.RefreshCostRecipeAPI.Path(), map[string]interface{}{"serial": sauce.Serial}, ownerToken)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var body []map[string]interface{}
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		if assert.Len(t, body, 2) {
+			assert.Equal(t, sauce.Serial, body[0]["serial"])
+			assert.Equal(t, pasta.Serial, body[1]["serial"])
+		}
+		var result databaseentity.Recipe
+		assert.Nil(t, app.db.First(&result, pasta.ID).Error)
+		assert.InDelta(t, 20500, result.RawMaterialCosts, 0.0001)
+	})
+
+	t.Run("create & update line", func(t *testing.T) {
+		// 0.25 kg of tomato costs 7500
+		rec := app.api(app.router.CreateRecipeLineAPI.Method(), app.router.CreateRecipeLineAPI.Path(), map[string]interface{}{
+			"serial":           pasta.Serial,
+			"ingredientSerial": tomato.Serial,
+			"unit":             "kg",
+			"quantity":         0.25,
+		}, ownerToken)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		body := decodeJSON(t, rec)
+		assert.Equal(t, pasta.Serial, body["recipeSerial"])
+		assert.Equal(t, tomato.Serial, body["ingredientSerial"])
+		assert.Equal(t, testOwnerUsername, body["updatedBy"])
+		serial := body["serial"].(string)
+		var result databaseentity.Recipe
+		assert.Nil(t, app.db.First(&result, pasta.ID).Error)
+		assert.InDelta(t, 28000, result.RawMaterialCosts, 0.0001)
+
+		// 250 ml of sauce costs 4250
+		rec = app.api(app.router.UpdateRecipeLineAPI.Method(), app.router.UpdateRecipeLineAPI.Path(), map[string]interface{}{
+			"serial":          serial,
+			"subRecipeSerial": sauce.Serial,
+			"unit":            "ml",
+			"quantity":        250,
+		}, ownerToken)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		body = decodeJSON(t, rec)
+		assert.Empty(t, body["ingredientSerial"])
+		assert.Equal(t, sauce.Serial, body["subRecipeSerial"])
+		assert.Nil(t, app.db.First(&result, pasta.ID).Error)
+		assert.InDelta(t, 24750, result.RawMaterialCosts, 0.0001)
+	})
+
+	t.Run("line making cycle is rejected", func(t *testing.T) {
+		// pasta yields in ml so the lines convert & only the cycle rejects them
+		assert.Nil(t, app.db.Model(pasta).Update("unit_id", ml.ID).Error)
+		for _, subRecipe := range []*databaseentity.Recipe{sauce, pasta} {
+			rec := app.api(app.router.CreateRecipeLineAPI.Method(), app.router.CreateRecipeLineAPI.Path(), map[string]interface{}{
+				"serial":          sauce.Serial,
+				"subRecipeSerial": subRecipe.Serial,
+				"unit":            "ml",
+				"quantity":        1,
+			}, ownerToken)
+			assert.Equal(t, http.StatusConflict, rec.Code, subRecipe.Name)
+			assert.Equal(t, entity.SubRecipeCycle, errorCode(t, rec), subRecipe.Name)
+		}
+
+		rec := app.api(app.router.UpdateRecipeLineAPI.Method(), app.router.UpdateRecipeLineAPI.Path(), map[string]interface{}{
+			"serial":          sauceLine.Serial,
+			"subRecipeSerial": pasta.Serial,
+			"unit":            "ml",
+			"quantity":        1,
+		}, ownerToken)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, entity.SubRecipeCycle, errorCode(t, rec))
+
+		var total int64
+		assert.Nil(t, app.db.Model(databaseentity.RecipeIngredient{}).Where("sub_recipe_id = ?", pasta.ID).Count(&total).Error)
+		assert.Zero(t, total)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		testdatabase.RecipeIngredient(t, app.db, &databaseentity.RecipeIngredient{RecipeID: sauce.ID, SubRecipeID: &pasta.ID, UnitID: ml.ID, Quantity: 1})
+
+		rec := app.api(app.router.CostingRecipeAPI.Method(), "/api/recipe/costing/"+pasta.Serial, nil, ownerToken)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, entity.SubRecipeCycle, errorCode(t, rec))
+	})
+}
+
+func Test_HTTP_MailAPI(t *testing.T) {
+	app := newTestApp(t, nil)
+	ownerToken := app.setupOwner()
+	dead := testdatabase.MailOutbox(t, app.db, &databaseentity.MailOutbox{
+		Recipient: "dead@example.com",
+		Status:    databaseentity.MailStatusDead,
+	})
+
+	t.Run("dead letter list & total", func(t *testing.T) {
+		rec := app.api(app.router.ListDeadLetterAPI.Method(), app.router.ListDeadLetterAPI.Path()+"?recipient=dead", nil, ownerToken)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "dead@example.com")
+
+		rec = app.api(app.router.TotalDeadLetterAPI.Method(), app.router.TotalDeadLetterAPI.Path(), nil, ownerToken)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, float64(1), decodeJSON(t, rec)["total"])
+	})
+
+	t.Run("resend dead letter", func(t *testing.T) {
+		rec := app.api(app.router.ResendDeadLetterAPI.Method(), app.router.ResendDeadLetterAPI.Path(), map[string]interface{}{"id": dead.ID}, ownerToken)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		app.sendMails()
+		assert.NotNil(t, app.mails.LastMailTo("dead@example.com"))
+
+		rec = app.api(app.router.ResendDeadLetterAPI.Method(), app.router.ResendDeadLetterAPI.Path(), map[string]interface{}{"id": dead.ID + 100}, ownerToken)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, entity.MailNotFound, errorCode(t, rec))
+	})
+
+	t.Run("preview template", func(t *testing.T) {
+		rec := app.api(app.router.PreviewMailTemplateAPI.Method(), app.router.PreviewMailTemplateAPI.Path()+"?name=welcome&locale=en", nil, ownerToken)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		rec = app.api(app.router.PreviewMailTemplateAPI.Method(), app.router.PreviewMailTemplateAPI.Path()+"?name=unknown", nil, ownerToken)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func Test_HTTP_AuditAPI(t *testing.T) {
+	app := newTestApp(t, nil)
+	ownerToken := app.setupOwner()
+	rec := app.api(app.router.CreateUnitAPI.Method(), app.router.CreateUnitAPI.Path(), map[string]interface{}{"name": "Gram"}, ownerToken)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	requestID := rec.Header().Get("X-Request-Id")
+
+	query := url.Values{"entityType": {"unit"}, "actor": {testOwnerUsername}, "requestId": {requestID}}
+	rec = app.api(app.router.ListAuditLogAPI.Method(), app.router.ListAuditLogAPI.Path()+"?"+query.Encode(), nil, ownerToken)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"serial":"Gram"`)
+
+	rec = app.api(app.router.TotalAuditLogAPI.Method(), app.router.TotalAuditLogAPI.Path()+"?"+query.Encode(), nil, ownerToken)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, float64(1), decodeJSON(t, rec)["total"])
+
+	rec = app.api(app.router.ListAuditLogAPI.Method(), app.router.ListAuditLogAPI.Path()+"?action=unknown", nil, ownerToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func Test_HTTP_Monitoring(t *testing.T) {
+	app := newTestApp(t, map[string]string{"METRICS_TOKEN": "metrics-token"})
+
+	tests := []struct {
+		name   string
+		path   string
+		token  string
+		status int
+		body   string
+	}{
+		{name: "liveness", path: app.router.LivenessEndpoint.Path(), status: http.StatusOK, body: `"status":"ok"`},
+		{name: "readiness", path: app.router.ReadinessEndpoint.Path(), status: http.StatusOK, body: `"database"`},
+		{name: "metrics without token", path: app.router.MetricsEndpoint.Path(), status: http.StatusUnauthorized},
+		{name: "metrics", path: app.router.MetricsEndpoint.Path(), token: "metrics-token", status: http.StatusOK, body: "rapc_"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := app.api(http.MethodGet, tt.path, nil, tt.token)
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+			if tt.body != "" {
+				assert.Contains(t, rec.Body.String(), tt.body)
+			}
+		})
+	}
+
+	t.Run("unknown api route", func(t *testing.T) {
+		rec := app.api(http.MethodGet, "/api/unknown", nil, "")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, http.StatusText(http.StatusNotFound), decodeJSON(t, rec)["message"])
+	})
+}
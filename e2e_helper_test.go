@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	memorysender "rap-c/app/repository/mail/memory-sender"
+	testdatabase "rap-c/app/repository/test-database"
+	"rap-c/app/usecase/contract"
+	"rap-c/config"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+const (
+	testOwnerUsername string = "owner"
+	testOwnerEmail    string = "owner@example.com"
+	testPassword      string = "Sup3r#Secret!pass"
+)
+
+// full http app on top of disposable database, mails are kept in memory instead of sent
+type testApp struct {
+	t      *testing.T
+	cfg    *config.Config
+	router *config.Route
+	db     *gorm.DB
+	echo   *echo.Echo
+	mails  *memorysender.Sender
+	mailUC contract.MailUsecase
+}
+
+var linkPattern = regexp.MustCompile(`https?://[^\s"<>]+`)
+
+// build app the same way serve does, env overrides default test config
+func newTestApp(t *testing.T, env map[string]string) *testApp {
+	t.Helper()
+	payload := map[string]string{
+		"APP_URL":                       "http://localhost:8080",
+		"ENABLE_DEBUG":                  "false",
+		"APP_ENV":                       config.AppEnvDevelopment,
+		"ENABLE_GUEST_LOGIN":            "true",
+		"HTTP_TRUSTED_PROXIES":          "",
+		"RESET_REQUEST_LIMIT_PER_EMAIL": "3",
+		"RESET_REQUEST_LIMIT_PER_IP":    "10",
+		"ENABLE_METRICS":                "true",
+		"METRICS_TOKEN":                 "",
+		"JWT_SECRET":                    "http-test-jwt-secret-0123456789abcdef",
+		"SESSION_KEY":                   "http-test-session-key-0123456789abcdef",
+		"MAIL_TRANSPORT":                config.MailTransportFile,
+		"MAIL_FILE_DIR":                 t.TempDir(),
+		"MAIL_LOCALE":                   "en",
+		"MAIL_SENDER_ADDRESS":           "noreply@example.com",
+	}
+	for key, val := range env {
+		payload[key] = val
+	}
+
+	db := testdatabase.Open(t)
+	cfg := config.InitTestConfig(payload)
+	router := config.InitRoute()
+	seedDB(cfg, db, router)
+
+	mails := memorysender.New()
+	application, err := newBuilder(cfg, db, router).WithMailSender(mails).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	renderer, err := config.NewRenderer(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := application.Server(renderer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testApp{t: t, cfg: cfg, router: router, db: db, echo: e, mails: mails, mailUC: application.Container.MailUsecase}
+}
+
+func (a *testApp) serve(req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	a.echo.ServeHTTP(rec, req)
+	return rec
+}
+
+// send json api request, empty token sends no authorization header
+func (a *testApp) api(method, path string, body interface{}, token string) *httptest.ResponseRecorder {
+	a.t.Helper()
+	var reader *bytes.Reader
+	if body == nil {
+		reader = bytes.NewReader(nil)
+	} else {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			a.t.Fatal(err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if token != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	}
+	return a.serve(req)
+}
+
+// send web page request with browser cookies, form is sent as url encoded body.
+// cookies set by response are saved back into jar, nil jar sends no cookie
+func (a *testApp) web(method, path string, form url.Values, jar http.CookieJar) *httptest.ResponseRecorder {
+	a.t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(form.Encode()))
+	if form != nil {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	}
+	// request url has no scheme & host, which jar needs. session cookie is secure, so https is used
+	jarURL := &url.URL{Scheme: "https", Host: req.Host, Path: "/"}
+	if jar != nil {
+		for _, cookie := range jar.Cookies(jarURL) {
+			req.AddCookie(cookie)
+		}
+	}
+	rec := a.serve(req)
+	if jar != nil {
+		jar.SetCookies(jarURL, rec.Result().Cookies())
+	}
+	return rec
+}
+
+// create owner from setup wizard api, return owner jwt token
+func (a *testApp) setupOwner() string {
+	a.t.Helper()
+	rec := a.api(a.router.SetupAPI.Method(), a.router.SetupAPI.Path(), map[string]interface{}{
+		"username":        testOwnerUsername,
+		"fullName":        "Owner",
+		"email":           testOwnerEmail,
+		"password":        testPassword,
+		"confirmPassword": testPassword,
+	}, "")
+	return a.token(rec)
+}
+
+// login with email & password, return jwt token
+func (a *testApp) login(email, password string) string {
+	a.t.Helper()
+	rec := a.api(a.router.LoginAPI.Method(), a.router.LoginAPI.Path(), map[string]interface{}{
+		"email":    email,
+		"password": password,
+	}, "")
+	return a.token(rec)
+}
+
+// login as seeded guest user, return jwt token
+func (a *testApp) loginGuest() string {
+	a.t.Helper()
+	rec := a.api(a.router.GuestLoginAPI.Method(), a.router.GuestLoginAPI.Path(), nil, "")
+	return a.token(rec)
+}
+
+// browser cookie jar with jwt token saved in web session
+func (a *testApp) session(token string) http.CookieJar {
+	a.t.Helper()
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	rec := a.web(a.router.SubmitTokenSessionWebPage.Method(), a.router.SubmitTokenSessionWebPage.Path(), url.Values{"token": {token}}, jar)
+	if rec.Code != http.StatusFound {
+		a.t.Fatalf("submit token session: status %d, body %s", rec.Code, rec.Body.String())
+	}
+	return jar
+}
+
+func (a *testApp) token(rec *httptest.ResponseRecorder) string {
+	a.t.Helper()
+	if rec.Code != http.StatusOK {
+		a.t.Fatalf("get token: status %d, body %s", rec.Code, rec.Body.String())
+	}
+	token, _ := decodeJSON(a.t, rec)["token"].(string)
+	if token == "" {
+		a.t.Fatalf("get token: empty token in %s", rec.Body.String())
+	}
+	return token
+}
+
+// send queued mails like mail worker does
+func (a *testApp) sendMails() {
+	a.t.Helper()
+	if _, err := a.mailUC.SendPendingMails(context.Background()); err != nil {
+		a.t.Fatal(err)
+	}
+}
+
+// query params of link to given page in last mail sent to recipient, queued mails are sent first
+func (a *testApp) mailLink(recipient string, path string) url.Values {
+	a.t.Helper()
+	a.sendMails()
+	mail := a.mails.LastMailTo(recipient)
+	if mail == nil {
+		a.t.Fatalf("no mail sent to %s", recipient)
+	}
+	for _, link := range linkPattern.FindAllString(mail.TextBody, -1) {
+		parsed, err := url.Parse(link)
+		if err == nil && parsed.Path == path {
+			return parsed.Query()
+		}
+	}
+	a.t.Fatalf("no link to %s in mail %q sent to %s", path, mail.Subject, recipient)
+	return nil
+}
+
+func decodeJSON(t *testing.T, rec *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+	var result map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatalf("decode json: %v, body %s", err, rec.Body.String())
+	}
+	return result
+}
+
+// internal error code of api error response
+func errorCode(t *testing.T, rec *httptest.ResponseRecorder) int {
+	t.Helper()
+	code, _ := decodeJSON(t, rec)["code"].(float64)
+	return int(code)
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	databaseentity "rap-c/app/entity/database-entity"
+	filesender "rap-c/app/repository/mail/file-sender"
+	"rap-c/config"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_HTTP_SetupWebPage(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	t.Run("redirect to setup before owner exists", func(t *testing.T) {
+		rec := app.web(http.MethodGet, "/", nil, nil)
+		assert.Equal(t, http.StatusFound, rec.Code)
+		assert.Equal(t, app.router.LoginWebPage.Path(), rec.Header().Get("Location"))
+
+		rec = app.web(app.router.LoginWebPage.Method(), app.router.LoginWebPage.Path(), nil, nil)
+		assert.Equal(t, http.StatusFound, rec.Code)
+		assert.Equal(t, app.router.SetupWebPage.Path(), rec.Header().Get("Location"))
+
+		rec = app.web(app.router.SetupWebPage.Method(), app.router.SetupWebPage.Path(), nil, nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("setup page disappears after owner created", func(t *testing.T) {
+		app.setupOwner()
+		rec := app.web(app.router.SetupWebPage.Method(), app.router.SetupWebPage.Path(), nil, nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		rec = app.web(app.router.LoginWebPage.Method(), app.router.LoginWebPage.Path(), nil, nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
+
+func Test_HTTP_SessionWebPage(t *testing.T) {
+	app := newTestApp(t, nil)
+	ownerToken := app.setupOwner()
+
+	t.Run("anonymous is redirected to login", func(t *testing.T) {
+		for _, path := range []string{
+			app.router.DashboardWebPage.Path(),
+			app.router.ProfileWebPage.Path(),
+			app.router.AuditWebPage.Path(),
+			app.router.PasswordMustChangeWebPage.Path(),
+		} {
+			jar, _ := cookiejar.New(nil)
+			rec := app.web(http.MethodGet, path, nil, jar)
+			assert.Equal(t, http.StatusFound, rec.Code, path)
+			assert.Equal(t, app.router.LoginWebPage.Path(), rec.Header().Get("Location"), path)
+
+			// expired session message is shown on login page
+			rec = app.web(app.router.LoginWebPage.Method(), app.router.LoginWebPage.Path(), nil, jar)
+			assert.Equal(t, http.StatusOK, rec.Code, path)
+		}
+	})
+
+	t.Run("empty token is refused", func(t *testing.T) {
+		rec := app.web(app.router.SubmitTokenSessionWebPage.Method(), app.router.SubmitTokenSessionWebPage.Path(), url.Values{}, nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("owner pages", func(t *testing.T) {
+		jar := app.session(ownerToken)
+		for _, path := range []string{
+			app.router.DashboardWebPage.Path(),
+			app.router.ProfileWebPage.Path(),
+			app.router.AuditWebPage.Path(),
+		} {
+			rec := app.web(http.MethodGet, path, nil, jar)
+			assert.Equal(t, http.StatusOK, rec.Code, path)
+		}
+
+		// logged in user skip login & forgot password page
+		for _, path := range []string{app.router.LoginWebPage.Path(), app.router.ForgotPasswordWebPage.Path()} {
+			rec := app.web(http.MethodGet, path, nil, jar)
+			assert.Equal(t, http.StatusFound, rec.Code, path)
+		}
+	})
+
+	t.Run("return to previous page after login", func(t *testing.T) {
+		jar, _ := cookiejar.New(nil)
+		rec := app.web(app.router.ProfileWebPage.Method(), app.router.ProfileWebPage.Path(), nil, jar)
+		assert.Equal(t, http.StatusFound, rec.Code)
+
+		rec = app.web(app.router.SubmitTokenSessionWebPage.Method(), app.router.SubmitTokenSessionWebPage.Path(), url.Values{"token": {ownerToken}}, jar)
+		assert.Equal(t, http.StatusFound, rec.Code)
+		assert.Equal(t, app.router.ProfileWebPage.Path(), rec.Header().Get("Location"))
+	})
+
+	t.Run("guest pages", func(t *testing.T) {
+		jar := app.session(app.loginGuest())
+		rec := app.web(app.router.DashboardWebPage.Method(), app.router.DashboardWebPage.Path(), nil, jar)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		// audit page is forbidden for guest
+		rec = app.web(app.router.AuditWebPage.Method(), app.router.AuditWebPage.Path(), nil, jar)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("logout", func(t *testing.T) {
+		jar := app.session(ownerToken)
+		rec := app.web(app.router.LogoutWebPage.Method(), app.router.LogoutWebPage.Path(), url.Values{}, jar)
+		assert.Equal(t, http.StatusFound, rec.Code)
+		assert.Equal(t, app.router.LoginWebPage.Path(), rec.Header().Get("Location"))
+
+		rec = app.web(app.router.DashboardWebPage.Method(), app.router.DashboardWebPage.Path(), nil, jar)
+		assert.Equal(t, http.StatusFound, rec.Code)
+		assert.Equal(t, app.router.LoginWebPage.Path(), rec.Header().Get("Location"))
+	})
+
+	t.Run("password must change", func(t *testing.T) {
+		assert.Nil(t, app.db.Model(databaseentity.User{}).Where("username = ?", testOwnerUsername).Update("password_must_change", true).Error)
+		defer app.db.Model(databaseentity.User{}).Where("username = ?", testOwnerUsername).Update("password_must_change", false)
+
+		jar := app.session(ownerToken)
+		rec := app.web(app.router.DashboardWebPage.Method(), app.router.DashboardWebPage.Path(), nil, jar)
+		assert.Equal(t, http.StatusFound, rec.Code)
+		assert.Equal(t, app.router.PasswordMustChangeWebPage.Path(), rec.Header().Get("Location"))
+
+		rec = app.web(app.router.PasswordMustChangeWebPage.Method(), app.router.PasswordMustChangeWebPage.Path(), nil, jar)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
+
+func Test_HTTP_MailLinkWebPage(t *testing.T) {
+	app := newTestApp(t, nil)
+	ownerToken := app.setupOwner()
+
+	t.Run("forgot & reset password", func(t *testing.T) {
+		rec := app.web(app.router.ForgotPasswordWebPage.Method(), app.router.ForgotPasswordWebPage.Path(), nil, nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		rec = app.api(app.router.RequestResetPasswordAPI.Method(), app.router.RequestResetPasswordAPI.Path(), map[string]interface{}{
+			"email": testOwnerEmail,
+		}, "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		params := app.mailLink(testOwnerEmail, app.router.ResetPasswordWebPage.Path())
+
+		rec = app.web(app.router.ResetPasswordWebPage.Method(), app.router.ResetPasswordWebPage.Path()+"?"+params.Encode(), nil, nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		params.Set("token", "invalid")
+		rec = app.web(app.router.ResetPasswordWebPage.Method(), app.router.ResetPasswordWebPage.Path()+"?"+params.Encode(), nil, nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("accept invitation", func(t *testing.T) {
+		rec := app.api(app.router.CreateUserAPI.Method(), app.router.CreateUserAPI.Path(), map[string]interface{}{
+			"username": "invited",
+			"fullName": "Invited User",
+			"email":    "invited@example.com",
+		}, ownerToken)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		params := app.mailLink("invited@example.com", app.router.AcceptInvitationWebPage.Path())
+
+		rec = app.web(app.router.AcceptInvitationWebPage.Method(), app.router.AcceptInvitationWebPage.Path()+"?"+params.Encode(), nil, nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Invited User")
+	})
+
+	t.Run("verify email", func(t *testing.T) {
+		rec := app.api(app.router.UpdateUserAPI.Method(), app.router.UpdateUserAPI.Path(), map[string]interface{}{
+			"email": "new-owner@example.com",
+		}, ownerToken)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		params := app.mailLink("new-owner@example.com", app.router.VerifyEmailWebPage.Path())
+
+		rec = app.web(app.router.VerifyEmailWebPage.Method(), app.router.VerifyEmailWebPage.Path()+"?"+params.Encode(), nil, nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "new-owner@example.com")
+
+		// link is used once
+		rec = app.web(app.router.VerifyEmailWebPage.Method(), app.router.VerifyEmailWebPage.Path()+"?"+params.Encode(), nil, nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func Test_HTTP_CapturedMailWebPage(t *testing.T) {
+	t.Run("available to non guest in development with file mail transport", func(t *testing.T) {
+		app := newTestApp(t, nil)
+		ownerToken := app.setupOwner()
+		err := filesender.New(app.cfg, app.cfg.MailFileDir()).Send(context.Background(), &databaseentity.MailOutbox{
+			Recipient: "captured@example.com",
+			Subject:   "Captured",
+			TextBody:  "captured text",
+			HTMLBody:  "<p>captured html</p>",
+		})
+		assert.Nil(t, err)
+
+		jar := app.session(ownerToken)
+		rec := app.web(app.router.CapturedMailWebPage.Method(), app.router.CapturedMailWebPage.Path(), nil, jar)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "captured@example.com")
+
+		rec = app.web(app.router.CapturedMailDetailWebPage.Method(), "/dev/mail/unknown.eml", nil, jar)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("login required", func(t *testing.T) {
+		app := newTestApp(t, nil)
+		app.setupOwner()
+		for _, path := range []string{app.router.CapturedMailWebPage.Path(), "/dev/mail/unknown.eml"} {
+			rec := app.web(http.MethodGet, path, nil, nil)
+			assert.Equal(t, http.StatusFound, rec.Code, path)
+			assert.Equal(t, app.router.LoginWebPage.Path(), rec.Header().Get("Location"), path)
+		}
+
+		// guest cannot read other users mail
+		rec := app.web(app.router.CapturedMailWebPage.Method(), app.router.CapturedMailWebPage.Path(), nil, app.session(app.loginGuest()))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("hidden in production", func(t *testing.T) {
+		app := newTestApp(t, map[string]string{"APP_ENV": config.AppEnvProduction})
+		rec := app.web(app.router.CapturedMailWebPage.Method(), app.router.CapturedMailWebPage.Path(), nil, app.session(app.setupOwner()))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("hidden with other mail transport", func(t *testing.T) {
+		app := newTestApp(t, map[string]string{"MAIL_TRANSPORT": "stdout"})
+		rec := app.web(app.router.CapturedMailWebPage.Method(), app.router.CapturedMailWebPage.Path(), nil, app.session(app.setupOwner()))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func Test_HTTP_WebErrorPage(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	rec := app.web(http.MethodGet, "/unknown-page", nil, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/html")
+}
@@ -1,45 +1,28 @@
 package main
 
 import (
-	"flag"
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"rap-c/app"
+	"rap-c/app/entity"
 	databaseentity "rap-c/app/entity/database-entity"
-	"rap-c/app/handler/api"
-	"rap-c/app/handler/middleware"
-	"rap-c/app/handler/web"
+	"rap-c/app/handler/worker"
 	"rap-c/app/helper"
-	authrepository "rap-c/app/repository/mysql/auth-repository"
-	unitrepository "rap-c/app/repository/mysql/unit-repository"
-	userrepository "rap-c/app/repository/mysql/user-repository"
-	authusecase "rap-c/app/usecase/auth-usecase"
-	formatterusecase "rap-c/app/usecase/formatter-usecase"
-	mailusecase "rap-c/app/usecase/mail-usecase"
-	sessionusecase "rap-c/app/usecase/session-usecase"
-	unitusecase "rap-c/app/usecase/unit-usecase"
-	userusecase "rap-c/app/usecase/user-usecase"
 	"rap-c/config"
-	"rap-c/route"
-	"regexp"
+	"syscall"
 	"time"
 
-	"github.com/gorilla/sessions"
 	"github.com/labstack/echo/v4"
-	echomiddleware "github.com/labstack/echo/v4/middleware"
 	"gorm.io/gorm"
 )
 
-var installMode = flag.Bool("install", false, "run for installation .env only")
-
 func main() {
-	flag.Parse()
-
-	if *installMode {
-		config.GenerateDotEnv()
-	} else {
-		serve()
-	}
+	run(os.Args[1:])
 }
 
 func serve() {
@@ -50,139 +33,142 @@ func serve() {
 	// load route config
 	router := config.InitRoute()
 
-	// auto migrate db
-	migrateDB(cfg, db)
-
-	// load mysql repositories
-	authRepo := authrepository.New(db)
-	userRepo := userrepository.New(db)
-	unitRepo := unitrepository.New(db)
-
-	// load session store
-	sessionStore := sessions.NewCookieStore([]byte(cfg.SessionKey()))
-
-	// load modules
-	formatterUsecase := formatterusecase.NewUsecase(cfg, userRepo)
-	authUsecase := authusecase.NewUsecase(cfg, authRepo)
-	userUsecase := userusecase.NewUsecase(cfg, userRepo)
-	mailUsecase := mailusecase.NewUsecase(cfg, router)
-	sessionUsecase := sessionusecase.NewUsecase(cfg, router, sessionStore, authUsecase)
-	unitUsecase := unitusecase.NewUsecase(cfg, unitRepo)
-
-	// load api handler
-	authAPI := api.NewAuthHandler(cfg, router, authUsecase, mailUsecase)
-	userAPI := api.NewUserHandler(cfg, router, userUsecase, formatterUsecase, mailUsecase)
-	unitAPI := api.NewUnitHandler(cfg, router, unitUsecase, formatterUsecase)
-
-	// load web handler
-	authWeb := web.NewAuthPage(cfg, router, authUsecase, sessionUsecase, mailUsecase)
-	userWeb := web.NewUserPage(cfg, router, sessionUsecase, userUsecase, mailUsecase)
-	dashboardWeb := web.NewDashboardPage(cfg, router, sessionUsecase)
-
-	// init echo
-	e := echo.New()
-
-	e.Debug = cfg.EnableDebug()
-	// custom http error handler
-	e.HTTPErrorHandler = func(err error, c echo.Context) {
-		reg := regexp.MustCompile("^/api")
-		if reg.MatchString(c.Request().RequestURI) {
-			route.APIErrorHandler(e, err, c)
-		} else {
-			route.WebErrorHandler(e, err, c)
-		}
+	// set shared logger
+	err := entity.SetupLogger(cfg)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
 	}
-	// set general middleware
-	e.Use(middleware.SetLog(cfg.LogMode(), cfg.EnableWarnFileLog()))
-	e.Use(echomiddleware.Recover())
-	e.Use(echomiddleware.TimeoutWithConfig(echomiddleware.TimeoutConfig{
-		ErrorMessage: "request timeout",
-		Timeout:      time.Second * 30,
-	}))
-
-	// set API route
-	route.SetAPIRoute(e, &route.APIHandler{
-		Config:      cfg,
-		Route:       router,
-		AuthUsecase: authUsecase,
-		AuthAPI:     authAPI,
-		UserAPI:     userAPI,
-		UnitAPI:     unitAPI,
-	})
-	// set web page route
-	route.SetWebRoute(e, &route.WebHandler{
-		Config:         cfg,
-		Route:          router,
-		AuthUsecase:    authUsecase,
-		SessionUsecase: sessionUsecase,
-		AuthPage:       authWeb,
-		UserPage:       userWeb,
-		DashboardPage:  dashboardWeb,
-	})
-
-	// set template renderer
-	var err error
-	e.Renderer, err = config.NewRenderer(cfg.AutoReloadTemplate())
+
+	// refuse to serve outdated schema
+	builder := newBuilder(cfg, db, router)
+	checkSchema(builder)
+
+	// create guest user
+	seedDB(cfg, db, router)
+
+	// load repositories & usecases of every module
+	application := build(builder)
+
+	// load template renderer
+	renderer, err := config.NewRenderer(cfg.AutoReloadTemplate())
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	// init echo with every module route
+	e, err := application.Server(renderer)
+	if err != nil {
+		fmt.Println("Error create server:", err)
+		os.Exit(1)
+	}
+
+	// stop on interrupt or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// run mail outbox worker
+	mailWorker := worker.NewMailWorker(cfg, application.Container.MailUsecase)
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	workerDone := make(chan struct{})
+	go func() {
+		defer close(workerDone)
+		mailWorker.Run(workerCtx)
+	}()
+
 	// run server
-	e.Logger.Fatal(e.Start(fmt.Sprintf(":%d", cfg.Port())))
+	e.HideBanner = true
+	e.Server.ReadTimeout = time.Second * time.Duration(cfg.ReadTimeoutInSeconds())
+	e.Server.WriteTimeout = time.Second * time.Duration(cfg.WriteTimeoutInSeconds())
+	e.Server.IdleTimeout = time.Second * time.Duration(cfg.IdleTimeoutInSeconds())
+	go func() {
+		var err error
+		if cfg.EnableTLS() {
+			log.Printf("Serve https on %s", cfg.ListenAddress())
+			err = e.StartTLS(cfg.ListenAddress(), cfg.TLSCertFile(), cfg.TLSKeyFile())
+		} else {
+			log.Printf("Serve http on %s", cfg.ListenAddress())
+			err = e.Start(cfg.ListenAddress())
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	shutdown(cfg, e, db, stopWorker, workerDone)
+}
+
+// drain http connections, wait running mail batch then close db pool, all within shutdown timeout
+func shutdown(cfg *config.Config, e *echo.Echo, db *gorm.DB, stopWorker context.CancelFunc, workerDone <-chan struct{}) {
+	log.Println("Shutting down")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(cfg.ShutdownTimeoutInSeconds()))
+	defer cancel()
+
+	err := e.Shutdown(ctx)
+	if err != nil {
+		log.Println("Error shutdown http server:", err)
+	}
+
+	stopWorker()
+	select {
+	case <-workerDone:
+	case <-ctx.Done():
+		log.Println("Error stop mail worker:", ctx.Err())
+	}
+
+	sqlDB, err := db.DB()
+	if err == nil {
+		err = sqlDB.Close()
+	}
+	if err != nil {
+		log.Println("Error close database:", err)
+	}
+	log.Println("Server stopped")
 }
 
-func migrateDB(cfg *config.Config, db *gorm.DB) {
-	start := time.Now()
-
-	// migrate tables
-	log.Println("Start migrate db")
-	db.AutoMigrate(&databaseentity.User{})
-	db.AutoMigrate(&databaseentity.PasswordResetToken{})
-	db.AutoMigrate(&databaseentity.Unit{})
-	db.AutoMigrate(&databaseentity.Ingredient{})
-	db.AutoMigrate(&databaseentity.IngredientConvertionUnit{})
-	db.AutoMigrate(&databaseentity.Recipe{})
-	db.AutoMigrate(&databaseentity.RecipeIngredient{})
-	db.AutoMigrate(&databaseentity.StockMovement{})
-	db.AutoMigrate(&databaseentity.Product{})
-	db.AutoMigrate(&databaseentity.Account{})
-	db.AutoMigrate(&databaseentity.Transaction{})
-	log.Printf("Migrate done in %v", time.Since(start))
-
-	// check non guest user
-	log.Println("Check non guest user")
+// every module of the application, shared by http server and cli commands
+func newBuilder(cfg *config.Config, db *gorm.DB, router *config.Route) *app.Builder {
+	return app.New(cfg, db, router).Register(app.DefaultModules()...)
+}
+
+func build(builder *app.Builder) *app.App {
+	application, err := builder.Build()
+	if err != nil {
+		fmt.Println("Error load modules:", err)
+		os.Exit(1)
+	}
+	return application
+}
+
+func checkSchema(builder *app.Builder) {
+	migrator, err := builder.Migrator()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	pending, err := migrator.Pending()
+	if err != nil {
+		fmt.Println("Error check schema migration:", err)
+		os.Exit(1)
+	}
+	if pending > 0 {
+		fmt.Printf("Database schema is behind by %d migration(s), run `rap-c migrate up` first\n", pending)
+		os.Exit(1)
+	}
+}
+
+func seedDB(cfg *config.Config, db *gorm.DB, router *config.Route) {
+	// owner is created from setup wizard, never from config
 	var totalNonGuestUser int64
-	err := db.Model(databaseentity.User{}).Where("is_guest = ? and disabled = ?", false, false).Count(&totalNonGuestUser).Error
+	err := db.Model(databaseentity.User{}).Where("is_guest = ?", false).Count(&totalNonGuestUser).Error
 	if err != nil {
 		fmt.Println("Error get total non guest user:", err)
 		os.Exit(1)
 	}
-	// create non guest user
 	if totalNonGuestUser < 1 {
-		log.Println("Create first non guest user")
-		pass, err := helper.EncryptPassword(cfg.FirstUserPassword())
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-		token, err := helper.GenerateToken(64)
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-		user := databaseentity.User{
-			Username:           cfg.FirstUserUsername(),
-			FullName:           cfg.FirstUserFullName(),
-			Email:              cfg.FirstUserEmail(),
-			Password:           pass,
-			PasswordMustChange: true,
-			Token:              token,
-		}
-		err = db.Save(&user).Error
-		if err != nil {
-			fmt.Println("Error create first user:", err)
-			os.Exit(1)
-		}
+		log.Printf("No user yet, open %s to create owner account", cfg.URL(router.SetupWebPage.Path()))
 	}
 
 	// auto create guest user
@@ -195,26 +181,22 @@ func migrateDB(cfg *config.Config, db *gorm.DB) {
 			os.Exit(1)
 		}
 
-		// create guest user
+		// create guest user, guest login needs no password so password is left empty
 		if totalGuestUser < 1 {
 			log.Println("Create guest user")
-			pass, err := helper.EncryptPassword(config.GuestPassword)
-			if err != nil {
-				fmt.Println(err)
-				os.Exit(1)
-			}
 			token, err := helper.GenerateToken(64)
 			if err != nil {
 				fmt.Println(err)
 				os.Exit(1)
 			}
+			now := time.Now()
 			user := databaseentity.User{
-				Username: config.GuestUsername,
-				FullName: config.GuestUsername,
-				Email:    config.GuestEmail,
-				Password: pass,
-				IsGuest:  true,
-				Token:    token,
+				Username:        config.GuestUsername,
+				FullName:        config.GuestUsername,
+				Email:           config.GuestEmail,
+				EmailVerifiedAt: &now,
+				IsGuest:         true,
+				Token:           token,
 			}
 			err = db.Save(&user).Error
 			if err != nil {
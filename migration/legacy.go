@@ -0,0 +1,48 @@
+package migration
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// version of the initial schema, databases created by gorm auto migrate are upgraded right before it runs
+const baselineVersion int64 = 1
+
+// bring tables created by gorm auto migrate to the shape the initial schema expects,
+// the initial schema only creates missing tables so existing ones must be fixed here
+func upgradeLegacySchema(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	if !migrator.HasTable("users") {
+		return nil
+	}
+
+	if !migrator.HasColumn("users", "pending_email") {
+		err := tx.Exec("ALTER TABLE users ADD COLUMN pending_email varchar(100) NOT NULL DEFAULT ''").Error
+		if err != nil {
+			return fmt.Errorf("add users.pending_email: %v", err)
+		}
+	}
+	if !migrator.HasColumn("users", "email_verified_at") {
+		err := tx.Exec("ALTER TABLE users ADD COLUMN email_verified_at timestamp NULL").Error
+		if err != nil {
+			return fmt.Errorf("add users.email_verified_at: %v", err)
+		}
+		// existing users were created before email verification, trust them once
+		err = tx.Exec("UPDATE users SET email_verified_at = ? WHERE email_verified_at IS NULL", time.Now()).Error
+		if err != nil {
+			return fmt.Errorf("verify existing users email: %v", err)
+		}
+	}
+
+	// old reset tokens were stored in plain text with one row per email, they cannot be checked against
+	// token hash anyway, so the table is recreated by the initial schema with its current indexes
+	if migrator.HasTable("password_reset_tokens") && !migrator.HasColumn("password_reset_tokens", "token_hash") {
+		err := migrator.DropTable("password_reset_tokens")
+		if err != nil {
+			return fmt.Errorf("drop old password_reset_tokens: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+package migration
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// base schema, versioned sql files of each database driver named <version>_<name>.up.sql & <version>_<name>.down.sql,
+// every driver has the same versions. base schema only keeps initial schema & changes of tables no module owns,
+// module migrations are extra sources following the same layout
+//
+//go:embed mysql/*.sql postgres/*.sql sqlite/*.sql
+var Files embed.FS
+
+const migrationTable string = "schema_migrations"
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// single versioned migration
+type Migration struct {
+	Version int64
+	Name    string
+	up      string
+	down    string
+}
+
+// migration with applied time, nil applied time means pending
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// table schema_migrations model
+type schemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"size:255;not null"`
+	AppliedAt time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null"`
+}
+
+func (schemaMigration) TableName() string {
+	return migrationTable
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []*Migration
+}
+
+// load base schema & given sources migrations of connected database driver,
+// versions must be unique across all sources
+func New(db *gorm.DB, sources ...fs.FS) (*Migrator, error) {
+	dir := db.Dialector.Name()
+	var migrations []*Migration
+	versions := make(map[int64]string)
+	for _, files := range append([]fs.FS{Files}, sources...) {
+		if _, err := fs.Stat(files, dir); err != nil {
+			return nil, fmt.Errorf("no migration for database driver %s", dir)
+		}
+		loaded, err := loadMigrations(files, dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, migration := range loaded {
+			if name, ok := versions[migration.Version]; ok {
+				return nil, fmt.Errorf("migration version %d used by %s and %s", migration.Version, name, migration.Name)
+			}
+			versions[migration.Version] = migration.Name
+			migrations = append(migrations, migration)
+		}
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return &Migrator{db, migrations}, nil
+}
+
+// apply all pending migrations in version order, return applied migrations
+func (m *Migrator) Up() ([]*Migration, error) {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		var before func(tx *gorm.DB) error
+		if migration.Version == baselineVersion {
+			before = upgradeLegacySchema
+		}
+		err = m.run(migration.up, before, func(tx *gorm.DB) error {
+			return tx.Create(&schemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return result, fmt.Errorf("migrate up %d_%s: %v", migration.Version, migration.Name, err)
+		}
+		result = append(result, migration)
+	}
+	return result, nil
+}
+
+// revert last applied migration, return nil when nothing to revert
+func (m *Migrator) Down() (*Migration, error) {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		err = m.run(migration.down, nil, func(tx *gorm.DB) error {
+			return tx.Delete(&schemaMigration{Version: migration.Version}).Error
+		})
+		if err != nil {
+			return nil, fmt.Errorf("migrate down %d_%s: %v", migration.Version, migration.Name, err)
+		}
+		return migration, nil
+	}
+	return nil, nil
+}
+
+// all known migrations with applied time
+func (m *Migrator) Status() ([]*MigrationStatus, error) {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*MigrationStatus
+	for _, migration := range m.migrations {
+		status := &MigrationStatus{Migration: *migration}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &appliedAt
+		}
+		result = append(result, status)
+	}
+	return result, nil
+}
+
+// total migrations not yet applied
+func (m *Migrator) Pending() (int, error) {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return 0, err
+	}
+
+	var result int
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			result++
+		}
+	}
+	return result, nil
+}
+
+// applied migration versions & time, create migration table if not exists
+func (m *Migrator) appliedVersions() (map[int64]time.Time, error) {
+	if !m.db.Migrator().HasTable(&schemaMigration{}) {
+		err := m.db.Migrator().CreateTable(&schemaMigration{})
+		if err != nil {
+			return nil, fmt.Errorf("create %s table: %v", migrationTable, err)
+		}
+	}
+
+	var rows []*schemaMigration
+	err := m.db.Order("version").Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("read %s table: %v", migrationTable, err)
+	}
+	result := make(map[int64]time.Time)
+	for _, row := range rows {
+		result[row.Version] = row.AppliedAt
+	}
+	return result, nil
+}
+
+// execute optional before step & sql statements then record version in one transaction,
+// note that mysql commit ddl statement implicitly so failed migration may leave partial change
+func (m *Migrator) run(sql string, before, record func(tx *gorm.DB) error) error {
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		if before != nil {
+			if err := before(tx); err != nil {
+				return err
+			}
+		}
+		for _, statement := range splitStatements(sql) {
+			err := tx.Exec(statement).Error
+			if err != nil {
+				return err
+			}
+		}
+		return record(tx)
+	})
+}
+
+func loadMigrations(files fs.FS, dir string) ([]*Migration, error) {
+	entries, err := fs.ReadDir(files, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %s", entry.Name())
+		}
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		content, err := fs.ReadFile(files, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := migrations[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			migrations[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d used by %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.up = string(content)
+		} else {
+			migration.down = string(content)
+		}
+	}
+
+	var result []*Migration
+	for _, migration := range migrations {
+		if migration.up == "" || migration.down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have up and down file", migration.Version, migration.Name)
+		}
+		result = append(result, migration)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+	return result, nil
+}
+
+// split sql file into statements ended by semicolon at end of line, comment lines are skipped
+func splitStatements(sql string) []string {
+	var result []string
+	var statement strings.Builder
+	for _, line := range strings.Split(sql, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		statement.WriteString(line)
+		statement.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			result = append(result, strings.TrimSpace(statement.String()))
+			statement.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(statement.String()); rest != "" {
+		result = append(result, rest)
+	}
+	return result
+}
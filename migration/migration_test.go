@@ -0,0 +1,105 @@
+package migration_test
+
+import (
+	databaseentity "rap-c/app/entity/database-entity"
+	testdatabase "rap-c/app/repository/test-database"
+	"rap-c/migration"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// table users created by gorm auto migrate before email verification
+type legacyUser struct {
+	ID                 int       `gorm:"primaryKey"`
+	Username           string    `gorm:"unique;size:30;not null"`
+	FullName           string    `gorm:"size:100;not null"`
+	Email              string    `gorm:"unique;size:100;not null"`
+	Password           string    `gorm:"size:255;not null"`
+	PasswordMustChange bool      `gorm:"not null;default:false"`
+	Disabled           bool      `gorm:"not null;default:false"`
+	IsGuest            bool      `gorm:"not null;default:false"`
+	Token              string    `gorm:"not null"`
+	CreatedAt          time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null"`
+	CreatedBy          int       `gorm:"column:created_by;not null;default:0"`
+	UpdatedAt          time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null"`
+	UpdatedBy          int       `gorm:"column:updated_by;not null;default:0"`
+}
+
+func (legacyUser) TableName() string {
+	return "users"
+}
+
+// table password_reset_tokens created by gorm auto migrate with plain token, one row per email
+type legacyPasswordResetToken struct {
+	ID        int       `gorm:"primaryKey"`
+	Email     string    `gorm:"unique;size:255;not null"`
+	Token     string    `gorm:"size:255;null"`
+	CreatedAt time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null"`
+	UpdatedAt time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;not null"`
+}
+
+func (legacyPasswordResetToken) TableName() string {
+	return "password_reset_tokens"
+}
+
+func Test_UpLegacySchema(t *testing.T) {
+	db := testdatabase.OpenEmpty(t)
+	assert.Nil(t, db.AutoMigrate(&legacyUser{}, &legacyPasswordResetToken{}))
+	assert.Nil(t, db.Create(&legacyUser{Username: "owner", FullName: "Owner", Email: "owner@example.com", Password: "secret", Token: "token"}).Error)
+	assert.Nil(t, db.Create(&legacyPasswordResetToken{Email: "owner@example.com", Token: "plain"}).Error)
+
+	migrator, err := migration.New(db)
+	assert.Nil(t, err)
+	_, err = migrator.Up()
+	assert.Nil(t, err)
+	pending, err := migrator.Pending()
+	assert.Nil(t, err)
+	assert.Zero(t, pending)
+
+	t.Run("existing user verified", func(t *testing.T) {
+		var user databaseentity.User
+		assert.Nil(t, db.Where("username = ?", "owner").First(&user).Error)
+		assert.NotNil(t, user.EmailVerifiedAt)
+		assert.Empty(t, user.PendingEmail)
+		assert.Empty(t, user.EmailToVerify())
+	})
+
+	t.Run("reset tokens hashed without unique email", func(t *testing.T) {
+		var total int64
+		assert.Nil(t, db.Model(&databaseentity.PasswordResetToken{}).Count(&total).Error)
+		assert.Zero(t, total)
+		for _, hash := range []string{"hash-1", "hash-2"} {
+			token := &databaseentity.PasswordResetToken{
+				Email:     "owner@example.com",
+				TokenHash: hash,
+				IPAddress: "127.0.0.1",
+				ExpiredAt: time.Now().Add(time.Hour),
+			}
+			assert.Nil(t, db.Create(token).Error)
+		}
+	})
+
+	t.Run("new user not verified", func(t *testing.T) {
+		user := &databaseentity.User{Username: "staff", FullName: "Staff", Email: "staff@example.com", Token: "token"}
+		assert.Nil(t, db.Create(user).Error)
+		assert.Nil(t, db.First(user, user.ID).Error)
+		assert.Equal(t, "staff@example.com", user.EmailToVerify())
+	})
+}
+
+func Test_UpFreshSchema(t *testing.T) {
+	db := testdatabase.OpenEmpty(t)
+	migrator, err := migration.New(db)
+	assert.Nil(t, err)
+	applied, err := migrator.Up()
+	assert.Nil(t, err)
+	assert.NotEmpty(t, applied)
+
+	// nothing left to apply, so running again does not touch the schema
+	applied, err = migrator.Up()
+	assert.Nil(t, err)
+	assert.Empty(t, applied)
+	assert.True(t, db.Migrator().HasColumn(&databaseentity.User{}, "EmailVerifiedAt"))
+}
@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS `transactions`;
+DROP TABLE IF EXISTS `accounts`;
+DROP TABLE IF EXISTS `products`;
+DROP TABLE IF EXISTS `stock_movements`;
+DROP TABLE IF EXISTS `recipe_ingredients`;
+DROP TABLE IF EXISTS `recipes`;
+DROP TABLE IF EXISTS `ingredient_convertion_units`;
+DROP TABLE IF EXISTS `ingredients`;
+DROP TABLE IF EXISTS `units`;
+DROP TABLE IF EXISTS `user_invitations`;
+DROP TABLE IF EXISTS `password_reset_tokens`;
+DROP TABLE IF EXISTS `users`;
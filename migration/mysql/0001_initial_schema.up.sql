@@ -0,0 +1,194 @@
+-- schema of databases created by gorm auto migrate, IF NOT EXISTS keep existing tables, which are upgraded by migration/legacy.go first
+CREATE TABLE IF NOT EXISTS `users` (
+    `id` bigint AUTO_INCREMENT,
+    `username` varchar(30) NOT NULL,
+    `full_name` varchar(100) NOT NULL,
+    `email` varchar(100) NOT NULL,
+    `pending_email` varchar(100) NOT NULL DEFAULT '',
+    `email_verified_at` timestamp NULL,
+    `password` varchar(255) NOT NULL,
+    `password_must_change` boolean NOT NULL DEFAULT false,
+    `disabled` boolean NOT NULL DEFAULT false,
+    `is_guest` boolean NOT NULL DEFAULT false,
+    `token` longtext NOT NULL,
+    `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by` bigint NOT NULL DEFAULT 0,
+    `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_by` bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `uni_users_email` UNIQUE (`email`),
+    CONSTRAINT `uni_users_username` UNIQUE (`username`)
+);
+
+CREATE TABLE IF NOT EXISTS `password_reset_tokens` (
+    `id` bigint AUTO_INCREMENT,
+    `email` varchar(255) NOT NULL,
+    `token_hash` varchar(64) NOT NULL,
+    `ip_address` varchar(45) NOT NULL,
+    `expired_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `used_at` timestamp NULL,
+    `revoked_at` timestamp NULL,
+    `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    INDEX `idx_password_reset_tokens_email` (`email`),
+    INDEX `idx_password_reset_tokens_token_hash` (`token_hash`),
+    INDEX `idx_password_reset_tokens_ip_address` (`ip_address`),
+    INDEX `idx_password_reset_tokens_created_at` (`created_at`)
+);
+
+CREATE TABLE IF NOT EXISTS `user_invitations` (
+    `id` bigint AUTO_INCREMENT,
+    `user_id` bigint NOT NULL,
+    `token_hash` varchar(64) NOT NULL,
+    `expired_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `accepted_at` timestamp NULL,
+    `revoked_at` timestamp NULL,
+    `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by` bigint NOT NULL DEFAULT 0,
+    `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_by` bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    INDEX `idx_user_invitations_user_id` (`user_id`),
+    INDEX `idx_user_invitations_token_hash` (`token_hash`)
+);
+
+CREATE TABLE IF NOT EXISTS `units` (
+    `id` bigint AUTO_INCREMENT,
+    `name` varchar(30) NOT NULL,
+    `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by` bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `uni_units_name` UNIQUE (`name`)
+);
+
+CREATE TABLE IF NOT EXISTS `ingredients` (
+    `id` bigint AUTO_INCREMENT,
+    `serial` varchar(11) NOT NULL,
+    `name` varchar(100) NOT NULL,
+    `unit_id` bigint NOT NULL,
+    `price_per_unit` decimal(10,2) NOT NULL DEFAULT 0,
+    `stock` decimal(10,2) NOT NULL DEFAULT 0,
+    `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by` bigint NOT NULL DEFAULT 0,
+    `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_by` bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `fk_ingredients_unit` FOREIGN KEY (`unit_id`) REFERENCES `units`(`id`),
+    CONSTRAINT `uni_ingredients_serial` UNIQUE (`serial`)
+);
+
+CREATE TABLE IF NOT EXISTS `ingredient_convertion_units` (
+    `id` bigint AUTO_INCREMENT,
+    `serial` varchar(11) NOT NULL,
+    `ingredient_id` bigint NOT NULL,
+    `unit_id` bigint NOT NULL,
+    `value` decimal(10,2) NOT NULL DEFAULT 0,
+    `skip_calculate` boolean NOT NULL DEFAULT false,
+    `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by` bigint NOT NULL DEFAULT 0,
+    `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_by` bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `fk_ingredient_convertion_units_unit` FOREIGN KEY (`unit_id`) REFERENCES `units`(`id`),
+    CONSTRAINT `fk_ingredient_convertion_units_ingredient` FOREIGN KEY (`ingredient_id`) REFERENCES `ingredients`(`id`),
+    CONSTRAINT `uni_ingredient_convertion_units_serial` UNIQUE (`serial`)
+);
+
+CREATE TABLE IF NOT EXISTS `recipes` (
+    `id` bigint AUTO_INCREMENT,
+    `serial` varchar(11) NOT NULL,
+    `name` varchar(100) NOT NULL,
+    `quantity` bigint NOT NULL DEFAULT 0,
+    `description` text,
+    `labor_description` text,
+    `overhead_description` text,
+    `raw_material_costs` decimal(10,2) NOT NULL DEFAULT 0,
+    `labor_costs` decimal(10,2) NOT NULL DEFAULT 0,
+    `overhead_costs` decimal(10,2) NOT NULL DEFAULT 0,
+    `expected_profit` bigint NOT NULL DEFAULT 0,
+    `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by` bigint NOT NULL DEFAULT 0,
+    `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_by` bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `uni_recipes_serial` UNIQUE (`serial`)
+);
+
+CREATE TABLE IF NOT EXISTS `recipe_ingredients` (
+    `id` bigint AUTO_INCREMENT,
+    `serial` varchar(11) NOT NULL,
+    `recipe_id` bigint NOT NULL,
+    `ingredient_id` bigint NOT NULL,
+    `unit_id` bigint NOT NULL,
+    `quantity` decimal(10,2) NOT NULL DEFAULT 0,
+    `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by` bigint NOT NULL DEFAULT 0,
+    `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_by` bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `fk_recipe_ingredients_recipe` FOREIGN KEY (`recipe_id`) REFERENCES `recipes`(`id`),
+    CONSTRAINT `fk_recipe_ingredients_ingredient` FOREIGN KEY (`ingredient_id`) REFERENCES `ingredients`(`id`),
+    CONSTRAINT `fk_recipe_ingredients_unit` FOREIGN KEY (`unit_id`) REFERENCES `units`(`id`),
+    CONSTRAINT `uni_recipe_ingredients_serial` UNIQUE (`serial`)
+);
+
+CREATE TABLE IF NOT EXISTS `stock_movements` (
+    `id` bigint AUTO_INCREMENT,
+    `ingredient_id` bigint NOT NULL,
+    `movement_type` enum('in','out') NOT NULL,
+    `quantity` bigint NOT NULL DEFAULT 0,
+    `description` varchar(100),
+    `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by` bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    INDEX `idx_stock_movements_movement_type` (`movement_type`),
+    CONSTRAINT `fk_stock_movements_ingredient` FOREIGN KEY (`ingredient_id`) REFERENCES `ingredients`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `products` (
+    `id` bigint AUTO_INCREMENT,
+    `serial` varchar(11) NOT NULL,
+    `recipe_id` bigint NOT NULL,
+    `date` date NOT NULL,
+    `quantity` bigint NOT NULL DEFAULT 0,
+    `sold_quantity` bigint NOT NULL DEFAULT 0,
+    `profit_expected` decimal(10,2) NOT NULL DEFAULT 0,
+    `profit_get` decimal(10,2) NOT NULL DEFAULT 0,
+    `status` enum('in production', 'in sales', 'sent to journal') NOT NULL,
+    `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by` bigint NOT NULL DEFAULT 0,
+    `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_by` bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    INDEX `idx_products_status` (`status`),
+    CONSTRAINT `fk_products_recipe` FOREIGN KEY (`recipe_id`) REFERENCES `recipes`(`id`),
+    CONSTRAINT `uni_products_serial` UNIQUE (`serial`)
+);
+
+CREATE TABLE IF NOT EXISTS `accounts` (
+    `id` bigint AUTO_INCREMENT,
+    `name` varchar(100) NOT NULL,
+    `type` enum('asset', 'liability', 'equity', 'revenue', 'expense') NOT NULL,
+    `balance` decimal(10,2) NOT NULL DEFAULT 0,
+    `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by` bigint NOT NULL DEFAULT 0,
+    `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_by` bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    INDEX `idx_accounts_type` (`type`),
+    CONSTRAINT `uni_accounts_name` UNIQUE (`name`)
+);
+
+CREATE TABLE IF NOT EXISTS `transactions` (
+    `id` bigint AUTO_INCREMENT,
+    `account_id` bigint NOT NULL,
+    `type` enum('debit', 'credit') NOT NULL,
+    `amount` decimal(10,2) NOT NULL DEFAULT 0,
+    `description` text,
+    `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by` bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    INDEX `idx_transactions_type` (`type`),
+    CONSTRAINT `fk_transactions_account` FOREIGN KEY (`account_id`) REFERENCES `accounts`(`id`)
+);
@@ -0,0 +1,4 @@
+ALTER TABLE `transactions` MODIFY `type` enum('debit', 'credit') NOT NULL;
+ALTER TABLE `accounts` MODIFY `type` enum('asset', 'liability', 'equity', 'revenue', 'expense') NOT NULL;
+ALTER TABLE `products` MODIFY `status` enum('in production', 'in sales', 'sent to journal') NOT NULL;
+ALTER TABLE `stock_movements` MODIFY `movement_type` enum('in','out') NOT NULL;
@@ -0,0 +1,5 @@
+-- enum is mysql only, allowed values are kept as constants in database entity
+ALTER TABLE `stock_movements` MODIFY `movement_type` varchar(20) NOT NULL;
+ALTER TABLE `products` MODIFY `status` varchar(20) NOT NULL;
+ALTER TABLE `accounts` MODIFY `type` varchar(20) NOT NULL;
+ALTER TABLE `transactions` MODIFY `type` varchar(20) NOT NULL;
@@ -0,0 +1,182 @@
+-- same schema as mysql, postgres creates index with separate statement
+CREATE TABLE IF NOT EXISTS "users" (
+    "id" bigserial PRIMARY KEY,
+    "username" varchar(30) NOT NULL,
+    "full_name" varchar(100) NOT NULL,
+    "email" varchar(100) NOT NULL,
+    "pending_email" varchar(100) NOT NULL DEFAULT '',
+    "email_verified_at" timestamp NULL,
+    "password" varchar(255) NOT NULL,
+    "password_must_change" boolean NOT NULL DEFAULT false,
+    "disabled" boolean NOT NULL DEFAULT false,
+    "is_guest" boolean NOT NULL DEFAULT false,
+    "token" text NOT NULL,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "created_by" bigint NOT NULL DEFAULT 0,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_by" bigint NOT NULL DEFAULT 0,
+    CONSTRAINT "uni_users_email" UNIQUE ("email"),
+    CONSTRAINT "uni_users_username" UNIQUE ("username")
+);
+
+CREATE TABLE IF NOT EXISTS "password_reset_tokens" (
+    "id" bigserial PRIMARY KEY,
+    "email" varchar(255) NOT NULL,
+    "token_hash" varchar(64) NOT NULL,
+    "ip_address" varchar(45) NOT NULL,
+    "expired_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "used_at" timestamp NULL,
+    "revoked_at" timestamp NULL,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_password_reset_tokens_email" ON "password_reset_tokens" ("email");
+CREATE INDEX IF NOT EXISTS "idx_password_reset_tokens_token_hash" ON "password_reset_tokens" ("token_hash");
+CREATE INDEX IF NOT EXISTS "idx_password_reset_tokens_ip_address" ON "password_reset_tokens" ("ip_address");
+CREATE INDEX IF NOT EXISTS "idx_password_reset_tokens_created_at" ON "password_reset_tokens" ("created_at");
+
+CREATE TABLE IF NOT EXISTS "user_invitations" (
+    "id" bigserial PRIMARY KEY,
+    "user_id" bigint NOT NULL,
+    "token_hash" varchar(64) NOT NULL,
+    "expired_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "accepted_at" timestamp NULL,
+    "revoked_at" timestamp NULL,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "created_by" bigint NOT NULL DEFAULT 0,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_by" bigint NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS "idx_user_invitations_user_id" ON "user_invitations" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_user_invitations_token_hash" ON "user_invitations" ("token_hash");
+
+CREATE TABLE IF NOT EXISTS "units" (
+    "id" bigserial PRIMARY KEY,
+    "name" varchar(30) NOT NULL,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "created_by" bigint NOT NULL DEFAULT 0,
+    CONSTRAINT "uni_units_name" UNIQUE ("name")
+);
+
+CREATE TABLE IF NOT EXISTS "ingredients" (
+    "id" bigserial PRIMARY KEY,
+    "serial" varchar(11) NOT NULL,
+    "name" varchar(100) NOT NULL,
+    "unit_id" bigint NOT NULL,
+    "price_per_unit" decimal(10,2) NOT NULL DEFAULT 0,
+    "stock" decimal(10,2) NOT NULL DEFAULT 0,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "created_by" bigint NOT NULL DEFAULT 0,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_by" bigint NOT NULL DEFAULT 0,
+    CONSTRAINT "fk_ingredients_unit" FOREIGN KEY ("unit_id") REFERENCES "units"("id"),
+    CONSTRAINT "uni_ingredients_serial" UNIQUE ("serial")
+);
+
+CREATE TABLE IF NOT EXISTS "ingredient_convertion_units" (
+    "id" bigserial PRIMARY KEY,
+    "serial" varchar(11) NOT NULL,
+    "ingredient_id" bigint NOT NULL,
+    "unit_id" bigint NOT NULL,
+    "value" decimal(10,2) NOT NULL DEFAULT 0,
+    "skip_calculate" boolean NOT NULL DEFAULT false,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "created_by" bigint NOT NULL DEFAULT 0,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_by" bigint NOT NULL DEFAULT 0,
+    CONSTRAINT "fk_ingredient_convertion_units_unit" FOREIGN KEY ("unit_id") REFERENCES "units"("id"),
+    CONSTRAINT "fk_ingredient_convertion_units_ingredient" FOREIGN KEY ("ingredient_id") REFERENCES "ingredients"("id"),
+    CONSTRAINT "uni_ingredient_convertion_units_serial" UNIQUE ("serial")
+);
+
+CREATE TABLE IF NOT EXISTS "recipes" (
+    "id" bigserial PRIMARY KEY,
+    "serial" varchar(11) NOT NULL,
+    "name" varchar(100) NOT NULL,
+    "quantity" bigint NOT NULL DEFAULT 0,
+    "description" text,
+    "labor_description" text,
+    "overhead_description" text,
+    "raw_material_costs" decimal(10,2) NOT NULL DEFAULT 0,
+    "labor_costs" decimal(10,2) NOT NULL DEFAULT 0,
+    "overhead_costs" decimal(10,2) NOT NULL DEFAULT 0,
+    "expected_profit" bigint NOT NULL DEFAULT 0,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "created_by" bigint NOT NULL DEFAULT 0,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_by" bigint NOT NULL DEFAULT 0,
+    CONSTRAINT "uni_recipes_serial" UNIQUE ("serial")
+);
+
+CREATE TABLE IF NOT EXISTS "recipe_ingredients" (
+    "id" bigserial PRIMARY KEY,
+    "serial" varchar(11) NOT NULL,
+    "recipe_id" bigint NOT NULL,
+    "ingredient_id" bigint NOT NULL,
+    "unit_id" bigint NOT NULL,
+    "quantity" decimal(10,2) NOT NULL DEFAULT 0,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "created_by" bigint NOT NULL DEFAULT 0,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_by" bigint NOT NULL DEFAULT 0,
+    CONSTRAINT "fk_recipe_ingredients_recipe" FOREIGN KEY ("recipe_id") REFERENCES "recipes"("id"),
+    CONSTRAINT "fk_recipe_ingredients_ingredient" FOREIGN KEY ("ingredient_id") REFERENCES "ingredients"("id"),
+    CONSTRAINT "fk_recipe_ingredients_unit" FOREIGN KEY ("unit_id") REFERENCES "units"("id"),
+    CONSTRAINT "uni_recipe_ingredients_serial" UNIQUE ("serial")
+);
+
+CREATE TABLE IF NOT EXISTS "stock_movements" (
+    "id" bigserial PRIMARY KEY,
+    "ingredient_id" bigint NOT NULL,
+    "movement_type" varchar(20) NOT NULL,
+    "quantity" bigint NOT NULL DEFAULT 0,
+    "description" varchar(100),
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "created_by" bigint NOT NULL DEFAULT 0,
+    CONSTRAINT "fk_stock_movements_ingredient" FOREIGN KEY ("ingredient_id") REFERENCES "ingredients"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_stock_movements_movement_type" ON "stock_movements" ("movement_type");
+
+CREATE TABLE IF NOT EXISTS "products" (
+    "id" bigserial PRIMARY KEY,
+    "serial" varchar(11) NOT NULL,
+    "recipe_id" bigint NOT NULL,
+    "date" date NOT NULL,
+    "quantity" bigint NOT NULL DEFAULT 0,
+    "sold_quantity" bigint NOT NULL DEFAULT 0,
+    "profit_expected" decimal(10,2) NOT NULL DEFAULT 0,
+    "profit_get" decimal(10,2) NOT NULL DEFAULT 0,
+    "status" varchar(20) NOT NULL,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "created_by" bigint NOT NULL DEFAULT 0,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_by" bigint NOT NULL DEFAULT 0,
+    CONSTRAINT "fk_products_recipe" FOREIGN KEY ("recipe_id") REFERENCES "recipes"("id"),
+    CONSTRAINT "uni_products_serial" UNIQUE ("serial")
+);
+CREATE INDEX IF NOT EXISTS "idx_products_status" ON "products" ("status");
+
+CREATE TABLE IF NOT EXISTS "accounts" (
+    "id" bigserial PRIMARY KEY,
+    "name" varchar(100) NOT NULL,
+    "type" varchar(20) NOT NULL,
+    "balance" decimal(10,2) NOT NULL DEFAULT 0,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "created_by" bigint NOT NULL DEFAULT 0,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_by" bigint NOT NULL DEFAULT 0,
+    CONSTRAINT "uni_accounts_name" UNIQUE ("name")
+);
+CREATE INDEX IF NOT EXISTS "idx_accounts_type" ON "accounts" ("type");
+
+CREATE TABLE IF NOT EXISTS "transactions" (
+    "id" bigserial PRIMARY KEY,
+    "account_id" bigint NOT NULL,
+    "type" varchar(20) NOT NULL,
+    "amount" decimal(10,2) NOT NULL DEFAULT 0,
+    "description" text,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "created_by" bigint NOT NULL DEFAULT 0,
+    CONSTRAINT "fk_transactions_account" FOREIGN KEY ("account_id") REFERENCES "accounts"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_transactions_type" ON "transactions" ("type");
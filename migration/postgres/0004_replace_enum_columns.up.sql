@@ -0,0 +1,2 @@
+-- postgres schema never used enum, kept so migration versions are the same on every driver
+SELECT 1;
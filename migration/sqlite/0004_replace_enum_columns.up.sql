@@ -0,0 +1,2 @@
+-- sqlite schema never used enum, kept so migration versions are the same on every driver
+SELECT 1;
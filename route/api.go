@@ -5,86 +5,120 @@ import (
 	"net/http"
 	"rap-c/app/entity"
 	"rap-c/app/handler/api"
-	"rap-c/app/handler/middleware"
-	"rap-c/app/usecase/contract"
-	"rap-c/config"
 
 	"github.com/labstack/echo/v4"
 )
 
-type APIHandler struct {
-	Config      *config.Config
-	Route       *config.Route
-	AuthUsecase contract.AuthUsecase
-	AuthAPI     api.AuthAPI
-	UserAPI     api.UserAPI
-	UnitAPI     api.UnitAPI
-}
-
-func SetAPIRoute(e *echo.Echo, h *APIHandler) {
-	// all login user group
-	allLoginRole := []echo.MiddlewareFunc{
-		middleware.GetJWT([]byte(h.Config.JwtSecret())),
-		middleware.GetUserFromJWT(h.AuthUsecase, h.Config.EnableGuestLogin()),
-		middleware.PasswordNotChanged(true, h.Route),
-	}
-
-	// non guest only group
-	nonGuestOnly := []echo.MiddlewareFunc{
-		middleware.GetJWT([]byte(h.Config.JwtSecret())),
-		middleware.GetUserFromJWT(h.AuthUsecase, false),
-		middleware.PasswordNotChanged(true, h.Route),
-	}
-
-	// set api
-	h.setAuthAPI(e, nonGuestOnly)
-	h.setUserAPI(e, allLoginRole, nonGuestOnly)
-	h.setUnitAPI(e, allLoginRole, nonGuestOnly)
-}
-
-func (h *APIHandler) setAuthAPI(e *echo.Echo, nonGuestOnly []echo.MiddlewareFunc) {
+// auth, setup & invitation api, no login needed
+func SetAuthAPI(g *Group, authAPI api.AuthAPI, setupAPI api.SetupAPI) {
 	// login routes
-	e.Add(h.Route.LoginAPI.Method(), h.Route.LoginAPI.Path(), h.AuthAPI.Login)
+	g.Echo.Add(g.Route.LoginAPI.Method(), g.Route.LoginAPI.Path(), authAPI.Login)
 	// guest login if enabled
-	e.Add(h.Route.GuestLoginAPI.Method(), h.Route.GuestLoginAPI.Path(), h.AuthAPI.GuestLogin)
+	g.Echo.Add(g.Route.GuestLoginAPI.Method(), g.Route.GuestLoginAPI.Path(), authAPI.GuestLogin)
 	// renew password routes
-	e.Add(h.Route.PasswordMustChangeAPI.Method(), h.Route.PasswordMustChangeAPI.Path(), h.AuthAPI.RenewPassword, nonGuestOnly[:2]...)
+	g.Echo.Add(g.Route.PasswordMustChangeAPI.Method(), g.Route.PasswordMustChangeAPI.Path(), authAPI.RenewPassword, g.APINonGuest[:2]...)
 	// forgot password (request reset password)
-	e.Add(h.Route.RequestResetPasswordAPI.Method(), h.Route.RequestResetPasswordAPI.Path(), h.AuthAPI.RequestResetPassword)
+	g.Echo.Add(g.Route.RequestResetPasswordAPI.Method(), g.Route.RequestResetPasswordAPI.Path(), authAPI.RequestResetPassword)
 	// reset password
-	e.Add(h.Route.ResetPasswordAPI.Method(), h.Route.ResetPasswordAPI.Path(), h.AuthAPI.ResetPassword)
+	g.Echo.Add(g.Route.ResetPasswordAPI.Method(), g.Route.ResetPasswordAPI.Path(), authAPI.ResetPassword)
+	// first run setup, refused once owner exists
+	g.Echo.Add(g.Route.SetupAPI.Method(), g.Route.SetupAPI.Path(), setupAPI.CreateOwner)
+	// accept invitation
+	g.Echo.Add(g.Route.AcceptInvitationAPI.Method(), g.Route.AcceptInvitationAPI.Path(), authAPI.AcceptInvitation)
 }
 
-func (h *APIHandler) setUserAPI(e *echo.Echo, allLoginRole []echo.MiddlewareFunc, nonGuestOnly []echo.MiddlewareFunc) {
+func SetUserAPI(g *Group, userAPI api.UserAPI) {
+	// no login
+	// verify email from verification link
+	g.Echo.Add(g.Route.VerifyEmailAPI.Method(), g.Route.VerifyEmailAPI.Path(), userAPI.VerifyEmail)
+
 	// all user
 	// detail user
-	e.Add(h.Route.DetailUserAPI.Method(), h.Route.DetailUserAPI.Path(), h.UserAPI.GetUserDetailByUsername, allLoginRole...)
+	g.Echo.Add(g.Route.DetailUserAPI.Method(), g.Route.DetailUserAPI.Path(), userAPI.GetUserDetailByUsername, g.APILogin...)
 	// user list
-	e.Add(h.Route.ListUserAPI.Method(), h.Route.ListUserAPI.Path(), h.UserAPI.GetUserList, allLoginRole...)
+	g.Echo.Add(g.Route.ListUserAPI.Method(), g.Route.ListUserAPI.Path(), userAPI.GetUserList, g.APILogin...)
 	// user list total
-	e.Add(h.Route.TotalUserAPI.Method(), h.Route.TotalUserAPI.Path(), h.UserAPI.GetTotalUserList, allLoginRole...)
+	g.Echo.Add(g.Route.TotalUserAPI.Method(), g.Route.TotalUserAPI.Path(), userAPI.GetTotalUserList, g.APILogin...)
 
 	// non guest
 	// create new user
-	e.Add(h.Route.CreateUserAPI.Method(), h.Route.CreateUserAPI.Path(), h.UserAPI.Create, nonGuestOnly...)
+	g.Echo.Add(g.Route.CreateUserAPI.Method(), g.Route.CreateUserAPI.Path(), userAPI.Create, g.APINonGuest...)
 	// update current user
-	e.Add(h.Route.UpdateUserAPI.Method(), h.Route.UpdateUserAPI.Path(), h.UserAPI.Update, nonGuestOnly...)
+	g.Echo.Add(g.Route.UpdateUserAPI.Method(), g.Route.UpdateUserAPI.Path(), userAPI.Update, g.APINonGuest...)
 	// update user active status
-	e.Add(h.Route.SetStatusUserAPI.Method(), h.Route.SetStatusUserAPI.Path(), h.UserAPI.SetActiveStatusUser, nonGuestOnly...)
+	g.Echo.Add(g.Route.SetStatusUserAPI.Method(), g.Route.SetStatusUserAPI.Path(), userAPI.SetActiveStatusUser, g.APINonGuest...)
+	// resend user invitation
+	g.Echo.Add(g.Route.ResendInvitationAPI.Method(), g.Route.ResendInvitationAPI.Path(), userAPI.ResendInvitation, g.APINonGuest...)
+	// revoke user invitation
+	g.Echo.Add(g.Route.RevokeInvitationAPI.Method(), g.Route.RevokeInvitationAPI.Path(), userAPI.RevokeInvitation, g.APINonGuest...)
+	// resend current user email verification
+	g.Echo.Add(g.Route.ResendVerificationAPI.Method(), g.Route.ResendVerificationAPI.Path(), userAPI.ResendEmailVerification, g.APINonGuest...)
 }
 
-func (h *APIHandler) setUnitAPI(e *echo.Echo, allLoginRole []echo.MiddlewareFunc, nonGuestOnly []echo.MiddlewareFunc) {
+func SetUnitAPI(g *Group, unitAPI api.UnitAPI) {
 	// all user
 	// unit list
-	e.Add(h.Route.ListUnitAPI.Method(), h.Route.ListUnitAPI.Path(), h.UnitAPI.GetUnitList, allLoginRole...)
+	g.Echo.Add(g.Route.ListUnitAPI.Method(), g.Route.ListUnitAPI.Path(), unitAPI.GetUnitList, g.APILogin...)
 	// unit list total
-	e.Add(h.Route.TotalUnitAPI.Method(), h.Route.TotalUnitAPI.Path(), h.UnitAPI.GetTotalUnitList, allLoginRole...)
+	g.Echo.Add(g.Route.TotalUnitAPI.Method(), g.Route.TotalUnitAPI.Path(), unitAPI.GetTotalUnitList, g.APILogin...)
+	// unit detail with usage
+	g.Echo.Add(g.Route.DetailUnitAPI.Method(), g.Route.DetailUnitAPI.Path(), unitAPI.GetUnitDetail, g.APILogin...)
+	// convert quantity between units
+	g.Echo.Add(g.Route.ConvertUnitAPI.Method(), g.Route.ConvertUnitAPI.Path(), unitAPI.Convert, g.APILogin...)
 
 	// non guest
 	// create new unit
-	e.Add(h.Route.CreateUnitAPI.Method(), h.Route.CreateUnitAPI.Path(), h.UnitAPI.Create, nonGuestOnly...)
+	g.Echo.Add(g.Route.CreateUnitAPI.Method(), g.Route.CreateUnitAPI.Path(), unitAPI.Create, g.APINonGuest...)
 	// delete not used unit
-	e.Add(h.Route.DeleteUnitAPI.Method(), h.Route.DeleteUnitAPI.Path(), h.UnitAPI.Delete, nonGuestOnly...)
+	g.Echo.Add(g.Route.DeleteUnitAPI.Method(), g.Route.DeleteUnitAPI.Path(), unitAPI.Delete, g.APINonGuest...)
+	// rename unit
+	g.Echo.Add(g.Route.RenameUnitAPI.Method(), g.Route.RenameUnitAPI.Path(), unitAPI.Rename, g.APINonGuest...)
+	// merge unit into other unit
+	g.Echo.Add(g.Route.MergeUnitAPI.Method(), g.Route.MergeUnitAPI.Path(), unitAPI.Merge, g.APINonGuest...)
+}
+
+func SetIngredientAPI(g *Group, ingredientAPI api.IngredientAPI) {
+	// non guest
+	// set density & piece weight
+	g.Echo.Add(g.Route.SetWeightIngredientAPI.Method(), g.Route.SetWeightIngredientAPI.Path(), ingredientAPI.SetWeight, g.APINonGuest...)
+}
+
+func SetRecipeAPI(g *Group, recipeAPI api.RecipeAPI) {
+	// all user
+	// recipe cost breakdown
+	g.Echo.Add(g.Route.CostingRecipeAPI.Method(), g.Route.CostingRecipeAPI.Path(), recipeAPI.GetCosting, g.APILogin...)
+	// recipe prep list for requested portions
+	g.Echo.Add(g.Route.ScaleRecipeAPI.Method(), g.Route.ScaleRecipeAPI.Path(), recipeAPI.Scale, g.APILogin...)
+
+	// non guest
+	// produce recipe from stock
+	g.Echo.Add(g.Route.ProduceRecipeAPI.Method(), g.Route.ProduceRecipeAPI.Path(), recipeAPI.Produce, g.APINonGuest...)
+	// save raw material costs of recipe & every recipe using it
+	g.Echo.Add(g.Route.RefreshCostRecipeAPI.Method(), g.Route.RefreshCostRecipeAPI.Path(), recipeAPI.RefreshCost, g.APINonGuest...)
+	// add ingredient or sub recipe line to recipe
+	g.Echo.Add(g.Route.CreateRecipeLineAPI.Method(), g.Route.CreateRecipeLineAPI.Path(), recipeAPI.CreateLine, g.APINonGuest...)
+	// change ingredient or sub recipe, unit & quantity of recipe line
+	g.Echo.Add(g.Route.UpdateRecipeLineAPI.Method(), g.Route.UpdateRecipeLineAPI.Path(), recipeAPI.UpdateLine, g.APINonGuest...)
+}
+
+func SetMailAPI(g *Group, mailAPI api.MailAPI) {
+	// non guest
+	// dead letter mail list
+	g.Echo.Add(g.Route.ListDeadLetterAPI.Method(), g.Route.ListDeadLetterAPI.Path(), mailAPI.GetDeadLetterList, g.APINonGuest...)
+	// dead letter mail list total
+	g.Echo.Add(g.Route.TotalDeadLetterAPI.Method(), g.Route.TotalDeadLetterAPI.Path(), mailAPI.GetTotalDeadLetterList, g.APINonGuest...)
+	// put dead letter mail back to queue
+	g.Echo.Add(g.Route.ResendDeadLetterAPI.Method(), g.Route.ResendDeadLetterAPI.Path(), mailAPI.ResendDeadLetter, g.APINonGuest...)
+	// preview mail template
+	g.Echo.Add(g.Route.PreviewMailTemplateAPI.Method(), g.Route.PreviewMailTemplateAPI.Path(), mailAPI.PreviewTemplate, g.APINonGuest...)
+}
+
+func SetAuditAPI(g *Group, auditAPI api.AuditAPI) {
+	// non guest
+	// audit log list
+	g.Echo.Add(g.Route.ListAuditLogAPI.Method(), g.Route.ListAuditLogAPI.Path(), auditAPI.GetAuditLogList, g.APINonGuest...)
+	// audit log list total
+	g.Echo.Add(g.Route.TotalAuditLogAPI.Method(), g.Route.TotalAuditLogAPI.Path(), auditAPI.GetTotalAuditLogList, g.APINonGuest...)
 }
 
 // error handler
@@ -114,24 +148,26 @@ func APIErrorHandler(e *echo.Echo, err error, c echo.Context) {
 
 	// Issue #1426
 	message := he.Message
+	// request id to match response with log line
+	requestID := entity.RequestIDFromContext(c.Request().Context())
 
 	switch m := he.Message.(type) {
 	case string:
 		if e.Debug {
-			message = echo.Map{"code": code, "message": m, "error": errMessage}
+			message = echo.Map{"code": code, "message": m, "error": errMessage, "requestId": requestID}
 		} else {
-			message = echo.Map{"code": code, "message": m}
+			message = echo.Map{"code": code, "message": m, "requestId": requestID}
 		}
 	case map[string][]*entity.ValidatorMessage:
 		if e.Debug {
-			message = echo.Map{"code": code, "message": m, "error": errMessage}
+			message = echo.Map{"code": code, "message": m, "error": errMessage, "requestId": requestID}
 		} else {
-			message = echo.Map{"code": code, "message": m}
+			message = echo.Map{"code": code, "message": m, "requestId": requestID}
 		}
 	case json.Marshaler:
 		// do nothing - this type knows how to format itself to JSON
 	case error:
-		message = echo.Map{"code": code, "message": m.Error()}
+		message = echo.Map{"code": code, "message": m.Error(), "requestId": requestID}
 	}
 
 	// Send response
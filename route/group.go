@@ -0,0 +1,50 @@
+package route
+
+import (
+	"rap-c/app/handler/middleware"
+	"rap-c/app/usecase/contract"
+	"rap-c/config"
+
+	"github.com/labstack/echo/v4"
+)
+
+// echo with middleware groups shared by routes of every module
+type Group struct {
+	Echo   *echo.Echo
+	Config *config.Config
+	Route  *config.Route
+	// api of all login user, guest included when guest login enabled
+	APILogin []echo.MiddlewareFunc
+	// api of non guest user only
+	APINonGuest []echo.MiddlewareFunc
+	// web page of all login user, guest included when guest login enabled
+	WebLogin []echo.MiddlewareFunc
+	// web page of non guest user only
+	WebNonGuest []echo.MiddlewareFunc
+}
+
+func NewGroup(e *echo.Echo, cfg *config.Config, router *config.Route, authUsecase contract.AuthUsecase, sessionUsecase contract.SessionUsecase) *Group {
+	return &Group{
+		Echo:   e,
+		Config: cfg,
+		Route:  router,
+		APILogin: []echo.MiddlewareFunc{
+			middleware.GetJWT([]byte(cfg.JwtSecret())),
+			middleware.GetUserFromJWT(authUsecase, cfg.EnableGuestLogin()),
+			middleware.PasswordNotChanged(true, router),
+		},
+		APINonGuest: []echo.MiddlewareFunc{
+			middleware.GetJWT([]byte(cfg.JwtSecret())),
+			middleware.GetUserFromJWT(authUsecase, false),
+			middleware.PasswordNotChanged(true, router),
+		},
+		WebLogin: []echo.MiddlewareFunc{
+			middleware.ValidateJwtTokenFromSession(sessionUsecase, router, cfg.EnableGuestLogin()),
+			middleware.PasswordNotChanged(false, router),
+		},
+		WebNonGuest: []echo.MiddlewareFunc{
+			middleware.ValidateJwtTokenFromSession(sessionUsecase, router, false),
+			middleware.PasswordNotChanged(false, router),
+		},
+	}
+}
@@ -0,0 +1,24 @@
+package route
+
+import (
+	"rap-c/app/handler/api"
+	"rap-c/app/handler/middleware"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// monitoring routes need no login, so load balancer & prometheus can probe them.
+// metrics endpoint is added only when gatherer is not nil
+func SetMonitorRoute(g *Group, gatherer prometheus.Gatherer, healthAPI api.HealthAPI) {
+	g.Echo.Add(g.Route.LivenessEndpoint.Method(), g.Route.LivenessEndpoint.Path(), healthAPI.Liveness)
+	g.Echo.Add(g.Route.ReadinessEndpoint.Method(), g.Route.ReadinessEndpoint.Path(), healthAPI.Readiness)
+
+	if gatherer != nil {
+		// prometheus metrics, failed business gauge does not fail other metrics
+		metrics := promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError})
+		g.Echo.Add(g.Route.MetricsEndpoint.Method(), g.Route.MetricsEndpoint.Path(), echo.WrapHandler(metrics),
+			middleware.BearerToken(g.Config.MetricsToken()))
+	}
+}
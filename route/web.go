@@ -19,67 +19,68 @@ const (
 	favIcon     string = "favicon.ico"
 )
 
-type WebHandler struct {
-	Config         *config.Config
-	Route          *config.Route
-	AuthUsecase    contract.AuthUsecase
-	SessionUsecase contract.SessionUsecase
-	AuthPage       web.AuthPage
-	UserPage       web.UserPage
-	DashboardPage  web.DashboardPage
-}
-
-func SetWebRoute(e *echo.Echo, h *WebHandler) {
+// home redirect & static assets
+func SetHomeWebPage(g *Group) {
 	// home page
-	e.GET("/", func(c echo.Context) error {
-		return c.Redirect(http.StatusFound, h.Route.LoginWebPage.Path())
+	g.Echo.GET("/", func(c echo.Context) error {
+		return c.Redirect(http.StatusFound, g.Route.LoginWebPage.Path())
 	})
 	// asset folder
-	e.Static("/assets", filepath.Join(storagePath, assetPath))
-	e.File("/favicon.ico", filepath.Join(storagePath, assetPath, imagePath, favIcon))
-
-	// all login user group
-	allLoginRole := []echo.MiddlewareFunc{
-		middleware.ValidateJwtTokenFromSession(h.SessionUsecase, h.Route, h.Config.EnableGuestLogin()),
-		middleware.PasswordNotChanged(false, h.Route),
-	}
-
-	// non guest only group
-	nonGuestOnly := []echo.MiddlewareFunc{
-		middleware.ValidateJwtTokenFromSession(h.SessionUsecase, h.Route, false),
-		middleware.PasswordNotChanged(false, h.Route),
-	}
-
-	// user api
-	h.setAuthWebPage(e)
-	h.setMainWebPage(e, allLoginRole, nonGuestOnly)
+	g.Echo.Static("/assets", filepath.Join(storagePath, assetPath))
+	g.Echo.File("/favicon.ico", filepath.Join(storagePath, assetPath, imagePath, favIcon))
 }
 
-func (h *WebHandler) setAuthWebPage(e *echo.Echo) {
-	// login page
-	e.Add(h.Route.LoginWebPage.Method(), h.Route.LoginWebPage.Path(), h.AuthPage.Login)
+func SetAuthWebPage(g *Group, authPage web.AuthPage, setupPage web.SetupPage, userUsecase contract.UserUsecase) {
+	// first run setup wizard
+	g.Echo.Add(g.Route.SetupWebPage.Method(), g.Route.SetupWebPage.Path(), setupPage.Setup)
+	// login page, redirected to setup wizard on first run
+	g.Echo.Add(g.Route.LoginWebPage.Method(), g.Route.LoginWebPage.Path(), authPage.Login,
+		middleware.RedirectToSetup(userUsecase, g.Route))
 	// token session submit page
-	e.Add(h.Route.SubmitTokenSessionWebPage.Method(), h.Route.SubmitTokenSessionWebPage.Path(), h.AuthPage.SubmitToken)
+	g.Echo.Add(g.Route.SubmitTokenSessionWebPage.Method(), g.Route.SubmitTokenSessionWebPage.Path(), authPage.SubmitToken)
 	// logout
-	e.Add(h.Route.LogoutWebPage.Method(), h.Route.LogoutWebPage.Path(), h.AuthPage.Logout)
+	g.Echo.Add(g.Route.LogoutWebPage.Method(), g.Route.LogoutWebPage.Path(), authPage.Logout)
 	// forgot password
-	e.Add(h.Route.ForgotPasswordWebPage.Method(), h.Route.ForgotPasswordWebPage.Path(), h.AuthPage.ForgotPassword)
+	g.Echo.Add(g.Route.ForgotPasswordWebPage.Method(), g.Route.ForgotPasswordWebPage.Path(), authPage.ForgotPassword)
 	// reset password
-	e.Add(h.Route.ResetPasswordWebPage.Method(), h.Route.ResetPasswordWebPage.Path(), h.AuthPage.ResetPassword)
-	// password must change
-	e.Add(h.Route.PasswordMustChangeWebPage.Method(), h.Route.PasswordMustChangeWebPage.Path(),
-		h.AuthPage.PasswordMustChange, middleware.ValidateJwtTokenFromSession(h.SessionUsecase, h.Route, false))
+	g.Echo.Add(g.Route.ResetPasswordWebPage.Method(), g.Route.ResetPasswordWebPage.Path(), authPage.ResetPassword)
+	// accept invitation
+	g.Echo.Add(g.Route.AcceptInvitationWebPage.Method(), g.Route.AcceptInvitationWebPage.Path(), authPage.AcceptInvitation)
+	// password must change, only session is validated so password not changed check is skipped
+	g.Echo.Add(g.Route.PasswordMustChangeWebPage.Method(), g.Route.PasswordMustChangeWebPage.Path(),
+		authPage.PasswordMustChange, g.WebNonGuest[:1]...)
 }
 
-func (h *WebHandler) setMainWebPage(e *echo.Echo, allLoginRole []echo.MiddlewareFunc, nonGuestOnly []echo.MiddlewareFunc) {
+func SetUserWebPage(g *Group, userPage web.UserPage, dashboardPage web.DashboardPage) {
+	// no login
+	// verify email
+	g.Echo.Add(g.Route.VerifyEmailWebPage.Method(), g.Route.VerifyEmailWebPage.Path(), userPage.VerifyEmail)
+
 	// all user
 	// profile page
-	e.Add(h.Route.ProfileWebPage.Method(), h.Route.ProfileWebPage.Path(), h.UserPage.Profile, allLoginRole...)
+	g.Echo.Add(g.Route.ProfileWebPage.Method(), g.Route.ProfileWebPage.Path(), userPage.Profile, g.WebLogin...)
 	// dashboard
-	e.Add(h.Route.DashboardWebPage.Method(), h.Route.DashboardWebPage.Path(), h.DashboardPage.Dashboard, allLoginRole...)
+	g.Echo.Add(g.Route.DashboardWebPage.Method(), g.Route.DashboardWebPage.Path(), dashboardPage.Dashboard, g.WebLogin...)
+}
+
+func SetAuditWebPage(g *Group, auditPage web.AuditPage) {
+	// non guest
+	// audit log
+	g.Echo.Add(g.Route.AuditWebPage.Method(), g.Route.AuditWebPage.Path(), auditPage.AuditLogs, g.WebNonGuest...)
+}
+
+func SetDevWebPage(g *Group, mailPage web.MailPage) {
+	// captured mail only exists when mail written to files, it shows reset password & invitation links
+	// so it is served in development only
+	if g.Config.MailTransport() != config.MailTransportFile || !g.Config.IsDevelopment() {
+		return
+	}
 
-	// // non guest
-	// e.GET("/user", echo.NotFoundHandler, nonGuestOnly...)
+	// non guest
+	// captured mail list
+	g.Echo.Add(g.Route.CapturedMailWebPage.Method(), g.Route.CapturedMailWebPage.Path(), mailPage.CapturedMails, g.WebNonGuest...)
+	// captured mail html
+	g.Echo.Add(g.Route.CapturedMailDetailWebPage.Method(), g.Route.CapturedMailDetailWebPage.Path(), mailPage.CapturedMailDetail, g.WebNonGuest...)
 }
 
 func WebErrorHandler(e *echo.Echo, err error, c echo.Context) {
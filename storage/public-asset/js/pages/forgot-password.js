@@ -57,7 +57,7 @@ function submitForgot(form) {
         data: $(form).serialize(),
         dataType: "json"
     }).done(function (response) {
-        toastInfo("email untuk reset password sudah terkirim")
+        toastInfo("jika email terdaftar, link reset password sudah terkirim")
         // go to submit token page
         setTimeout(function () {
             window.location.href = $('#loginLink').attr('href');
@@ -68,8 +68,8 @@ function submitForgot(form) {
             let response = JSON.parse($jqXHR.responseText);
             if (response.code) {
                 switch (response.code) {
-                    case 404002:
-                        toastInfo("email tidak ditemukan!");
+                    case 429001:
+                        toastInfo("terlalu banyak permintaan reset password, silahkan coba lagi nanti!");
                         break;
                     case 400999:
                         // validator fails
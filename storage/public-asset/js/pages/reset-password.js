@@ -65,7 +65,7 @@ function resetPassword(form) {
             if (response.code) {
                 switch (response.code) {
                     case 404001:
-                        toastInfo("token tidak ditemukan, atau token sudah kadaluwarsa");
+                        toastInfo("token tidak ditemukan, sudah digunakan, atau sudah kadaluwarsa");
                         break;
                     case 404002:
                         toastInfo("email tidak ditemukan");
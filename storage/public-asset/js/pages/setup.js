@@ -0,0 +1,134 @@
+function toastInfo(message) {
+    Toastify({
+        text: message,
+        duration: 3000,
+        close: true,
+        gravity: "top",
+        position: "right",
+        style: {
+            background: "#2c1c19"
+        }
+    }).showToast();
+}
+
+function ajaxLoading() {
+    let form = document.getElementById("formSetup");
+
+    // disable form elements
+    for (let i = 0; i < form.elements.length; i++) {
+        $(form.elements[i]).attr("disabled", true);
+        if ($(form.elements[i]).attr("type") == "submit") {
+            $(form.elements[i]).find("i").
+                removeClass("fa-solid fa-floppy-disk").
+                addClass("spinner-border spinner-border-sm");
+        }
+    }
+}
+
+function ajaxDone() {
+    let form = document.getElementById("formSetup");
+
+    // disable form login
+    for (let i = 0; i < form.elements.length; i++) {
+        $(form.elements[i]).removeAttr("disabled");
+        if ($(form.elements[i]).attr("type") == "submit") {
+            $(form.elements[i]).find("i").
+                removeClass("spinner-border spinner-border-sm").
+                addClass("fa-solid fa-floppy-disk");
+        }
+    }
+}
+
+function createOwner(form) {
+    $.ajax({
+        type: $(form).attr('method'),
+        url: $(form).attr('action'),
+        cache: false,
+        beforeSend: function (xhr) {
+            xhr.setRequestHeader('Accept', '*/*');
+            ajaxLoading();
+        },
+        data: $(form).serialize(),
+        dataType: "json"
+    }).done(function (response) {
+        toastInfo("akun pemilik tersimpan");
+
+        // go to submit token page
+        setTimeout(function () {
+            $('#formSubmitToken input[name="token"]').val(response.token);
+            $('#formSubmitToken').submit();
+        }, 1500);
+    }).fail(function ($jqXHR) {
+        ajaxDone();
+        try {
+            let response = JSON.parse($jqXHR.responseText);
+            if (response.code) {
+                switch (response.code) {
+                    case 403005:
+                        toastInfo("setup sudah dilakukan, silahkan login");
+                        setTimeout(function () {
+                            window.location.href = "/login";
+                        }, 1500);
+                        break;
+                    case 400001:
+                        toastInfo("email sudah digunakan!");
+                        break;
+                    case 400002:
+                        toastInfo("username sudah digunakan!");
+                        break;
+                    case 400999:
+                        // validator fails
+                        for (let x in response.message) {
+                            if (x == "username") {
+                                for (let y in response.message[x]) {
+                                    if (response.message[x][y].tag == "required") {
+                                        toastInfo("username wajib diisi!");
+                                    } else if (response.message[x][y].tag == "max") {
+                                        toastInfo("username maksimal 30 karakter!");
+                                    } else {
+                                        toastInfo("username hanya boleh berisi huruf, angka, titik, strip & underscore!");
+                                    }
+                                }
+                            } else if (x == "fullName") {
+                                toastInfo("nama lengkap wajib diisi!");
+                            } else if (x == "email") {
+                                for (let y in response.message[x]) {
+                                    if (response.message[x][y].tag == "required") {
+                                        toastInfo("email wajib diisi!");
+                                    } else {
+                                        toastInfo("email tidak valid!");
+                                    }
+                                }
+                            } else if (x == "password") {
+                                for (let y in response.message[x]) {
+                                    if (response.message[x][y].tag == "required") {
+                                        toastInfo("password wajib diisi!");
+                                    } else if (response.message[x][y].tag == "min") {
+                                        toastInfo("password minimal 8 karakter!");
+                                    }
+                                }
+                            } else if (x == "confirmPassword") {
+                                for (let y in response.message[x]) {
+                                    if (response.message[x][y].tag == "required") {
+                                        toastInfo("konfirmasi password wajib diisi!");
+                                    } else if (response.message[x][y].tag == "eqfield") {
+                                        toastInfo("konfirmasi password tidak sama!");
+                                    }
+                                }
+                            } else {
+                                toastInfo("data yang diinput tidak valid");
+                            }
+                        }
+                        break
+                    default:
+                        toastInfo("ada kesalahan teknis, error #" + response.code);
+                        break;
+                }
+            }
+        } catch (error) {
+            toastInfo("ada kesalahan teknis");
+            console.log(error);
+        }
+        return;
+    });
+}
\ No newline at end of file